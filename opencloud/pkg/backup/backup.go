@@ -59,8 +59,22 @@ func NewConsistency() *Consistency {
 	}
 }
 
+// ConsistencyOptions configures the consistency check
+type ConsistencyOptions struct {
+	// Fail exits with a non-zero status if inconsistencies are left
+	Fail bool
+	// Repair fixes the inconsistencies which can be repaired safely
+	Repair bool
+	// DryRun only reports the repairs without applying them
+	DryRun bool
+	// QuarantinePath is the directory orphaned nodes are moved to
+	QuarantinePath string
+	// JSON prints the results as json
+	JSON bool
+}
+
 // CheckProviderConsistency checks the consistency of a space
-func CheckProviderConsistency(storagepath string, lbs ListBlobstore, fail bool) error {
+func CheckProviderConsistency(storagepath string, lbs ListBlobstore, opts ConsistencyOptions) error {
 	fsys := os.DirFS(storagepath)
 
 	p := NewProvider(fsys, storagepath, lbs)
@@ -71,7 +85,26 @@ func CheckProviderConsistency(storagepath string, lbs ListBlobstore, fail bool)
 	c := NewConsistency()
 	c.GatherData(p.Events)
 
-	return c.PrintResults(storagepath, fail)
+	var repairs []Repair
+	if opts.Repair {
+		repairs = c.Repair(storagepath, opts.QuarantinePath, opts.DryRun)
+	}
+
+	if opts.JSON {
+		if err := c.PrintJSON(storagepath, repairs); err != nil {
+			return err
+		}
+	} else {
+		if err := c.PrintResults(storagepath, false); err != nil {
+			return err
+		}
+		PrintRepairs(repairs, opts.DryRun)
+	}
+
+	if opts.Fail && c.Unrepaired(repairs) {
+		os.Exit(1)
+	}
+	return nil
 }
 
 // GatherData gathers and evaluates data produced by the DataProvider
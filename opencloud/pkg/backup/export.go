@@ -0,0 +1,670 @@
+package backup
+
+import (
+	"archive/tar"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/opencloud-eu/reva/v2/pkg/storage/pkg/decomposedfs/lookup"
+	"github.com/opencloud-eu/reva/v2/pkg/storage/pkg/decomposedfs/node"
+	"github.com/pkg/xattr"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	// ArchiveVersion is the version of the archive format written by ExportSpace
+	ArchiveVersion = 1
+
+	_manifestName = "manifest.json"
+	_spacePrefix  = "space/"
+	_blobPrefix   = "blobs/"
+	_xattrPrefix  = "SCHILY.xattr."
+)
+
+// the space id indexes of the decomposedfs
+var _spaceIndexes = []string{"by-type", "by-user-id", "by-group-id"}
+
+var (
+	// ErrUnsupportedStorage is returned for storages which are not a decomposedfs
+	ErrUnsupportedStorage = errors.New("not a decomposedfs storage, spaces of posixfs storages can't be exported")
+	// ErrSharedResources is returned if resources of the space are shared, the shares of the sharing service
+	// are not part of the archive
+	ErrSharedResources = errors.New("resources of the space are shared, the shares of the sharing service can't be exported")
+)
+
+// Blobstore is needed to export and import blobs which are not stored in the space directory
+type Blobstore interface {
+	Upload(node *node.Node, source, copyTarget string) error
+	Download(node *node.Node) (io.ReadCloser, error)
+	Delete(node *node.Node) error
+}
+
+// ExportOptions configures the export of a space
+type ExportOptions struct {
+	// MetadataBackend is the metadata backend of the storage, 'messagepack' or 'xattrs'. Defaults to 'messagepack'.
+	MetadataBackend string
+	// IgnoreShares exports spaces with shared resources. The grants of the shares are part of the node metadata
+	// and kept, but the shares are not listed for the recipients after importing.
+	IgnoreShares bool
+}
+
+// Manifest describes the content of a space archive. It is the first entry of the archive.
+type Manifest struct {
+	Version int       `json:"version"`
+	SpaceID string    `json:"space_id"`
+	Created time.Time `json:"created"`
+	// Indexes holds the space id index entries of the space by index name and index, e.g. "by-type" -> "project" -> target
+	Indexes map[string]map[string]string `json:"indexes"`
+	// Files is the number of files, directories and symlinks of the space directory
+	Files int `json:"files"`
+	// Blobs is the number of blobs archived separately, it is 0 if the blobs are stored in the space directory
+	Blobs int `json:"blobs"`
+}
+
+// ExportSpace writes the space of a decomposedfs storage as tar archive to w. The archive contains the space
+// directory with its nodes, revisions, trash and blobs of the 'decomposed' blobstore, the extended attributes
+// and the space id index entries. The grants are part of the node metadata. If bs is set, the blobs are
+// downloaded and archived separately. Spaces with shared resources are rejected unless opts.IgnoreShares is set.
+func ExportSpace(storagepath, spaceID string, bs Blobstore, w io.Writer, opts ExportOptions) (Manifest, error) {
+	if _, err := os.Stat(filepath.Join(storagepath, "spaces")); err != nil {
+		return Manifest{}, fmt.Errorf("%w: %w", ErrUnsupportedStorage, err)
+	}
+
+	spaceID = trimStorageID(spaceID)
+	spaceDir := filepath.Join(storagepath, "spaces", lookup.Pathify(spaceID, 1, 2))
+	if _, err := os.Stat(filepath.Join(spaceDir, "nodes")); err != nil {
+		return Manifest{}, fmt.Errorf("space '%s' not found in '%s': %w", spaceID, storagepath, err)
+	}
+
+	readMetadata, err := newMetadataReader(opts.MetadataBackend)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	// the metadata of the space root must be readable with the backend, otherwise the blobs and shares can't be found
+	rootPath := filepath.Join(spaceDir, "nodes", lookup.Pathify(spaceID, 4, 2))
+	md, err := readMetadata(rootPath)
+	if err == nil && len(md) == 0 {
+		err = errors.New("no metadata found")
+	}
+	if err != nil {
+		return Manifest{}, fmt.Errorf("cannot read the metadata of the space root with the '%s' metadata backend: %w", cmp.Or(opts.MetadataBackend, "messagepack"), err)
+	}
+
+	m := Manifest{
+		Version: ArchiveVersion,
+		SpaceID: spaceID,
+		Created: time.Now().UTC(),
+	}
+
+	if m.Indexes, err = readSpaceIndexes(storagepath, spaceID); err != nil {
+		return m, err
+	}
+
+	// the manifest comes first, so count the entries beforehand
+	var blobs []*node.Node
+	err = filepath.WalkDir(spaceDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		m.Files++
+
+		nodePath, ok := metadataNode(p, d, opts.MetadataBackend)
+		if !ok {
+			return nil
+		}
+		md, err := readMetadata(nodePath)
+		if err != nil {
+			return err
+		}
+		if nodePath != rootPath && !opts.IgnoreShares && hasGrants(md) {
+			return fmt.Errorf("%w: '%s' is shared", ErrSharedResources, nodePath)
+		}
+		if bid := string(md["user.oc.blobid"]); bs != nil && bid != "" {
+			blobs = append(blobs, &node.Node{BaseNode: node.BaseNode{SpaceID: spaceID}, BlobID: bid})
+		}
+		return nil
+	})
+	if err != nil {
+		return m, err
+	}
+	m.Blobs = len(blobs)
+
+	tw := tar.NewWriter(w)
+	if err := writeManifest(tw, m); err != nil {
+		return m, err
+	}
+
+	err = filepath.WalkDir(spaceDir, func(p string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(spaceDir, p)
+		if err != nil {
+			return err
+		}
+		return writeFile(tw, p, path.Join(_spacePrefix, filepath.ToSlash(rel)))
+	})
+	if err != nil {
+		return m, err
+	}
+
+	for _, n := range blobs {
+		if err := writeBlob(tw, bs, n); err != nil {
+			return m, err
+		}
+	}
+
+	return m, tw.Close()
+}
+
+// ImportOptions configures the import of a space archive
+type ImportOptions struct {
+	// SkipIndexes does not restore the space id index entries, the space is not listed until the indexes are rebuilt
+	SkipIndexes bool
+}
+
+// ImportSpace restores a space archive written by ExportSpace into a decomposedfs storage. The space must not
+// exist in the target storage. Blobs archived separately are uploaded to bs. If the import fails, the space
+// directory, the uploaded blobs and the index entries are removed again.
+func ImportSpace(storagepath string, bs Blobstore, r io.Reader, opts ImportOptions) (m Manifest, err error) {
+	tr := tar.NewReader(r)
+
+	m, err = readManifest(tr)
+	if err != nil {
+		return m, err
+	}
+	if m.Blobs > 0 && bs == nil {
+		return m, errors.New("the archive contains blobs, a blobstore is needed to import them")
+	}
+
+	spaceDir := filepath.Join(storagepath, "spaces", lookup.Pathify(m.SpaceID, 1, 2))
+	if _, err := os.Lstat(spaceDir); err == nil {
+		return m, fmt.Errorf("space '%s' already exists in '%s'", m.SpaceID, storagepath)
+	}
+	if err := os.MkdirAll(filepath.Dir(spaceDir), 0700); err != nil {
+		return m, err
+	}
+
+	var uploaded []*node.Node
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, cleanupImport(storagepath, spaceDir, bs, uploaded, m))
+		}
+	}()
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return m, err
+		}
+
+		switch {
+		case strings.HasPrefix(hdr.Name, _spacePrefix):
+			target, err := archivePath(spaceDir, strings.TrimPrefix(hdr.Name, _spacePrefix))
+			if err != nil {
+				return m, err
+			}
+			if err := extractFile(tr, hdr, target); err != nil {
+				return m, fmt.Errorf("cannot extract '%s': %w", hdr.Name, err)
+			}
+		case strings.HasPrefix(hdr.Name, _blobPrefix):
+			if bs == nil {
+				return m, errors.New("the archive contains blobs, a blobstore is needed to import them")
+			}
+			n := &node.Node{BaseNode: node.BaseNode{SpaceID: m.SpaceID}, BlobID: path.Base(hdr.Name), Blobsize: hdr.Size}
+			if err := uploadBlob(tr, bs, n); err != nil {
+				return m, fmt.Errorf("cannot upload blob '%s': %w", n.BlobID, err)
+			}
+			uploaded = append(uploaded, n)
+		default:
+			return m, fmt.Errorf("unexpected archive entry '%s'", hdr.Name)
+		}
+	}
+
+	if opts.SkipIndexes {
+		return m, nil
+	}
+	return m, writeSpaceIndexes(storagepath, m)
+}
+
+// cleanupImport removes what a failed import left behind, so the import can be run again
+func cleanupImport(storagepath, spaceDir string, bs Blobstore, uploaded []*node.Node, m Manifest) error {
+	var errs []error
+	if err := os.RemoveAll(spaceDir); err != nil {
+		errs = append(errs, fmt.Errorf("cannot remove the space directory: %w", err))
+	}
+	for _, n := range uploaded {
+		if err := bs.Delete(n); err != nil {
+			errs = append(errs, fmt.Errorf("cannot remove blob '%s': %w", n.BlobID, err))
+		}
+	}
+	if err := removeSpaceIndexes(storagepath, m); err != nil {
+		errs = append(errs, fmt.Errorf("cannot remove the index entries: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func trimStorageID(spaceID string) string {
+	if _, id, ok := strings.Cut(spaceID, "$"); ok {
+		return id
+	}
+	return spaceID
+}
+
+func readSpaceIndexes(storagepath, spaceID string) (map[string]map[string]string, error) {
+	indexes := make(map[string]map[string]string)
+	for _, name := range _spaceIndexes {
+		files, err := filepath.Glob(filepath.Join(storagepath, "indexes", name, "*.mpk"))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			links := map[string]string{}
+			if err := msgpack.Unmarshal(b, &links); err != nil {
+				return nil, fmt.Errorf("cannot read index '%s': %w", f, err)
+			}
+			if target, ok := links[spaceID]; ok {
+				if indexes[name] == nil {
+					indexes[name] = make(map[string]string)
+				}
+				indexes[name][strings.TrimSuffix(filepath.Base(f), ".mpk")] = target
+			}
+		}
+	}
+	return indexes, nil
+}
+
+func writeSpaceIndexes(storagepath string, m Manifest) error {
+	for name, entries := range m.Indexes {
+		if !slices.Contains(_spaceIndexes, name) {
+			return fmt.Errorf("unknown index '%s'", name)
+		}
+		if err := os.MkdirAll(filepath.Join(storagepath, "indexes", name), 0700); err != nil {
+			return err
+		}
+		for index, target := range entries {
+			if index == "" || index != filepath.Base(index) || index == ".." {
+				return fmt.Errorf("invalid index '%s/%s'", name, index)
+			}
+			if err := updateSpaceIndex(filepath.Join(storagepath, "indexes", name, index+".mpk"), m.SpaceID, target); err != nil {
+				return fmt.Errorf("cannot update index '%s/%s': %w", name, index, err)
+			}
+		}
+	}
+	return nil
+}
+
+// removeSpaceIndexes removes the index entries of the space which point to the archived targets
+func removeSpaceIndexes(storagepath string, m Manifest) error {
+	for name, entries := range m.Indexes {
+		if !slices.Contains(_spaceIndexes, name) {
+			continue
+		}
+		for index, target := range entries {
+			if index == "" || index != filepath.Base(index) || index == ".." {
+				continue
+			}
+			indexPath := filepath.Join(storagepath, "indexes", name, index+".mpk")
+			b, err := os.ReadFile(indexPath)
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				continue
+			case err != nil:
+				return err
+			}
+
+			links := map[string]string{}
+			if err := msgpack.Unmarshal(b, &links); err != nil {
+				return err
+			}
+			if links[m.SpaceID] != target {
+				continue
+			}
+			delete(links, m.SpaceID)
+			if b, err = msgpack.Marshal(links); err != nil {
+				return err
+			}
+			if err := os.WriteFile(indexPath, b, 0600); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// updateSpaceIndex adds an entry to an index file. The storage must not be in use while importing.
+func updateSpaceIndex(indexPath, spaceID, target string) error {
+	links := map[string]string{}
+	b, err := os.ReadFile(indexPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	case len(b) > 0:
+		if err := msgpack.Unmarshal(b, &links); err != nil {
+			return err
+		}
+	}
+
+	links[spaceID] = target
+	if b, err = msgpack.Marshal(links); err != nil {
+		return err
+	}
+	return os.WriteFile(indexPath, b, 0600)
+}
+
+// metadataReader reads the metadata of a node with the metadata backend of the storage
+type metadataReader func(nodePath string) (map[string][]byte, error)
+
+func newMetadataReader(backend string) (metadataReader, error) {
+	switch backend {
+	case "", "messagepack":
+		return readMessagepackMetadata, nil
+	case "xattrs":
+		return readXattrsMetadata, nil
+	default:
+		return nil, fmt.Errorf("unsupported metadata backend '%s', supported values are 'messagepack' and 'xattrs'", backend)
+	}
+}
+
+// metadataNode returns the path of the node whose metadata is stored in p
+func metadataNode(p string, d fs.DirEntry, backend string) (string, bool) {
+	if backend == "xattrs" {
+		// symlinks don't carry metadata
+		if d.Type()&fs.ModeSymlink != 0 {
+			return "", false
+		}
+		return p, true
+	}
+	if d.IsDir() || filepath.Ext(p) != ".mpk" {
+		return "", false
+	}
+	return strings.TrimSuffix(p, ".mpk"), true
+}
+
+func readMessagepackMetadata(nodePath string) (map[string][]byte, error) {
+	b, err := os.ReadFile(nodePath + ".mpk")
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string][]byte{}
+	if err := msgpack.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("cannot read metadata '%s.mpk': %w", nodePath, err)
+	}
+	return m, nil
+}
+
+func readXattrsMetadata(nodePath string) (map[string][]byte, error) {
+	attrs, err := xattr.LList(nodePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list extended attributes of '%s': %w", nodePath, err)
+	}
+
+	m := map[string][]byte{}
+	for _, a := range attrs {
+		if !strings.HasPrefix(a, "user.oc.") {
+			continue
+		}
+		if m[a], err = xattr.LGet(nodePath, a); err != nil {
+			return nil, fmt.Errorf("cannot read extended attribute '%s' of '%s': %w", a, nodePath, err)
+		}
+	}
+	return m, nil
+}
+
+// hasGrants checks if the metadata contains grants, grants of nodes other than the space root are shares
+func hasGrants(md map[string][]byte) bool {
+	for k := range md {
+		if strings.HasPrefix(k, "user.oc.grant.") {
+			return true
+		}
+	}
+	return false
+}
+
+func writeManifest(tw *tar.Writer, m Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     _manifestName,
+		Size:     int64(len(b)),
+		Mode:     0600,
+		ModTime:  m.Created,
+	}); err != nil {
+		return err
+	}
+	_, err = tw.Write(b)
+	return err
+}
+
+func readManifest(tr *tar.Reader) (Manifest, error) {
+	var m Manifest
+	hdr, err := tr.Next()
+	if err != nil {
+		return m, fmt.Errorf("cannot read archive: %w", err)
+	}
+	if hdr.Name != _manifestName {
+		return m, errors.New("not a space archive, the manifest is missing")
+	}
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return m, fmt.Errorf("cannot read manifest: %w", err)
+	}
+	if m.Version != ArchiveVersion {
+		return m, fmt.Errorf("unsupported archive version %d", m.Version)
+	}
+	if m.SpaceID == "" || strings.ContainsAny(m.SpaceID, "/\\") || m.SpaceID == "." || m.SpaceID == ".." {
+		return m, fmt.Errorf("invalid space id '%s'", m.SpaceID)
+	}
+	return m, nil
+}
+
+// writeFile adds a file, directory or symlink including its extended attributes to the archive
+func writeFile(tw *tar.Writer, p, name string) error {
+	info, err := os.Lstat(p)
+	if err != nil {
+		return err
+	}
+
+	var link string
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(p); err != nil {
+			return err
+		}
+	}
+
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+	hdr.Uname, hdr.Gname = "", ""
+	hdr.Format = tar.FormatPAX
+
+	if link == "" {
+		attrs, err := xattr.LList(p)
+		// filesystems without support for extended attributes are used with the messagepack metadata backend
+		if err != nil && !errors.Is(err, xattr.ENOATTR) && !errors.Is(err, syscall.ENOTSUP) {
+			return fmt.Errorf("cannot list extended attributes of '%s': %w", p, err)
+		}
+		for _, a := range attrs {
+			v, err := xattr.LGet(p, a)
+			if err != nil {
+				return fmt.Errorf("cannot read extended attribute '%s' of '%s': %w", a, p, err)
+			}
+			if hdr.PAXRecords == nil {
+				hdr.PAXRecords = make(map[string]string)
+			}
+			hdr.PAXRecords[_xattrPrefix+a] = string(v)
+		}
+	}
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func writeBlob(tw *tar.Writer, bs Blobstore, n *node.Node) error {
+	rc, err := bs.Download(n)
+	if err != nil {
+		return fmt.Errorf("cannot download blob '%s': %w", n.BlobID, err)
+	}
+	defer rc.Close()
+
+	// the size is needed for the header, so buffer the blob in a temporary file
+	tmp, err := os.CreateTemp("", "opencloud-blob-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, rc)
+	if err != nil {
+		return fmt.Errorf("cannot download blob '%s': %w", n.BlobID, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     _blobPrefix + n.BlobID,
+		Size:     size,
+		Mode:     0600,
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, tmp)
+	return err
+}
+
+func uploadBlob(r io.Reader, bs Blobstore, n *node.Node) error {
+	tmp, err := os.CreateTemp("", "opencloud-blob-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return bs.Upload(n, tmp.Name(), "")
+}
+
+// archivePath returns the path of an archive entry below root. Entries escaping root are rejected.
+func archivePath(root, name string) (string, error) {
+	clean := path.Clean("/" + name)
+	if clean == "/" {
+		return root, nil
+	}
+	target := filepath.Join(root, filepath.FromSlash(clean))
+
+	// symlinks extracted before must not redirect the entry out of root
+	parent, err := filepath.EvalSymlinks(filepath.Dir(target))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return target, nil
+		}
+		return "", err
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	if err == nil && parent != realRoot && !strings.HasPrefix(parent, realRoot+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry '%s' escapes the space directory", name)
+	}
+	return target, nil
+}
+
+func extractFile(r io.Reader, hdr *tar.Header, target string) error {
+	mode := hdr.FileInfo().Mode().Perm()
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(target, 0700); err != nil {
+			return err
+		}
+	case tar.TypeSymlink:
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return err
+		}
+		// symlinks don't carry extended attributes
+		return os.Symlink(hdr.Linkname, target)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported file type %q", hdr.Typeflag)
+	}
+
+	for k, v := range hdr.PAXRecords {
+		if !strings.HasPrefix(k, _xattrPrefix) {
+			continue
+		}
+		if err := xattr.LSet(target, strings.TrimPrefix(k, _xattrPrefix), []byte(v)); err != nil {
+			return fmt.Errorf("cannot set extended attribute: %w", err)
+		}
+	}
+
+	if hdr.Typeflag == tar.TypeDir {
+		if err := os.Chmod(target, mode); err != nil {
+			return err
+		}
+	}
+	return os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+}
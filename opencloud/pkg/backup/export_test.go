@@ -0,0 +1,199 @@
+package backup_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencloud-eu/opencloud/opencloud/pkg/backup"
+	decomposednode "github.com/opencloud-eu/reva/v2/pkg/storage/pkg/decomposedfs/node"
+	"github.com/test-go/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type memoryBlobstore map[string][]byte
+
+func (bs memoryBlobstore) Upload(n *decomposednode.Node, source, _ string) error {
+	b, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	bs[n.SpaceID+"/"+n.BlobID] = b
+	return nil
+}
+
+func (bs memoryBlobstore) Delete(n *decomposednode.Node) error {
+	delete(bs, n.SpaceID+"/"+n.BlobID)
+	return nil
+}
+
+func (bs memoryBlobstore) Download(n *decomposednode.Node) (io.ReadCloser, error) {
+	b, ok := bs[n.SpaceID+"/"+n.BlobID]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+const _spaceID = "a8e5d981-41e4-4468-b532-258d5fb457d3"
+
+func createSpace(t *testing.T, base string) {
+	space := filepath.Join(base, "spaces/a8/e5d981-41e4-4468-b532-258d5fb457d3")
+	nodes := filepath.Join(space, "nodes/a8/e5/d9/81/-41e4-4468-b532-258d5fb457d3")
+	file := filepath.Join(space, "nodes/2d/08/8d/24/-d1a1-4d8b-a9d0-6d0d3b3b1c4e")
+	require.NoError(t, os.MkdirAll(nodes, 0700))
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+
+	writeMetadata(t, nodes+".mpk", map[string][]byte{"user.oc.grant.u:einstein": []byte("g:rw")})
+	require.NoError(t, os.WriteFile(file, nil, 0600))
+	writeMetadata(t, file+".mpk", map[string][]byte{"user.oc.blobid": []byte("blob-1")})
+	require.NoError(t, os.Symlink("../../../../../../nodes/2d/08/8d/24/-d1a1-4d8b-a9d0-6d0d3b3b1c4e", filepath.Join(nodes, "file.txt")))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "indexes/by-type"), 0700))
+	writeIndex(t, filepath.Join(base, "indexes/by-type/project.mpk"), map[string]string{
+		_spaceID:  "../../../spaces/a8/e5d981-41e4-4468-b532-258d5fb457d3/nodes/a8/e5/d9/81/-41e4-4468-b532-258d5fb457d3",
+		"another": "../../../spaces/an/other/nodes/an/ot/he/r",
+	})
+}
+
+func writeMetadata(t *testing.T, p string, m map[string][]byte) {
+	b, err := msgpack.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(p, b, 0600))
+}
+
+func writeIndex(t *testing.T, p string, m map[string]string) {
+	b, err := msgpack.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(p, b, 0600))
+}
+
+func readIndex(t *testing.T, p string) map[string]string {
+	b, err := os.ReadFile(p)
+	require.NoError(t, err)
+	m := map[string]string{}
+	require.NoError(t, msgpack.Unmarshal(b, &m))
+	return m
+}
+
+func TestExportImportSpace(t *testing.T) {
+	source, target := t.TempDir(), t.TempDir()
+	createSpace(t, source)
+
+	sourceBlobs := memoryBlobstore{_spaceID + "/blob-1": []byte("content")}
+	var archive bytes.Buffer
+	m, err := backup.ExportSpace(source, "storage-1$"+_spaceID, sourceBlobs, &archive, backup.ExportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, _spaceID, m.SpaceID)
+	require.Equal(t, 1, m.Blobs)
+	require.Equal(t, map[string]map[string]string{
+		"by-type": {"project": "../../../spaces/a8/e5d981-41e4-4468-b532-258d5fb457d3/nodes/a8/e5/d9/81/-41e4-4468-b532-258d5fb457d3"},
+	}, m.Indexes)
+
+	targetBlobs := memoryBlobstore{}
+	imported, err := backup.ImportSpace(target, targetBlobs, bytes.NewReader(archive.Bytes()), backup.ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, m.Files, imported.Files)
+
+	// the space directory is restored
+	link, err := os.Readlink(filepath.Join(target, "spaces/a8/e5d981-41e4-4468-b532-258d5fb457d3/nodes/a8/e5/d9/81/-41e4-4468-b532-258d5fb457d3/file.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "../../../../../../nodes/2d/08/8d/24/-d1a1-4d8b-a9d0-6d0d3b3b1c4e", link)
+	mpk, err := os.ReadFile(filepath.Join(target, "spaces/a8/e5d981-41e4-4468-b532-258d5fb457d3/nodes/a8/e5/d9/81/-41e4-4468-b532-258d5fb457d3.mpk"))
+	require.NoError(t, err)
+	md := map[string][]byte{}
+	require.NoError(t, msgpack.Unmarshal(mpk, &md))
+	require.Equal(t, []byte("g:rw"), md["user.oc.grant.u:einstein"])
+
+	// blobs and index entries are restored
+	require.Equal(t, []byte("content"), targetBlobs[_spaceID+"/blob-1"])
+	require.Equal(t, m.Indexes["by-type"]["project"], readIndex(t, filepath.Join(target, "indexes/by-type/project.mpk"))[_spaceID])
+
+	// the space exists now
+	_, err = backup.ImportSpace(target, targetBlobs, bytes.NewReader(archive.Bytes()), backup.ImportOptions{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already exists")
+}
+
+func TestImportSpaceRejectsEscapingEntries(t *testing.T) {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	manifest := []byte(`{"version":1,"space_id":"` + _spaceID + `"}`)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "manifest.json", Typeflag: tar.TypeReg, Size: int64(len(manifest)), Mode: 0600}))
+	_, err := tw.Write(manifest)
+	require.NoError(t, err)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "space/", Typeflag: tar.TypeDir, Mode: 0700}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "space/escape", Typeflag: tar.TypeSymlink, Linkname: "../../../..", Mode: 0700}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "space/escape/file", Typeflag: tar.TypeReg, Mode: 0600}))
+	require.NoError(t, tw.Close())
+
+	_, err = backup.ImportSpace(t.TempDir(), nil, &archive, backup.ImportOptions{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "escapes the space directory")
+}
+
+func TestExportSpaceRejectsUnsupportedSpaces(t *testing.T) {
+	source := t.TempDir()
+	createSpace(t, source)
+
+	_, err := backup.ExportSpace(source, _spaceID, nil, io.Discard, backup.ExportOptions{MetadataBackend: "hybrid"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported metadata backend")
+
+	// the metadata is stored in messagepack files
+	_, err = backup.ExportSpace(source, _spaceID, nil, io.Discard, backup.ExportOptions{MetadataBackend: "xattrs"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot read the metadata of the space root")
+
+	_, err = backup.ExportSpace(t.TempDir(), _spaceID, nil, io.Discard, backup.ExportOptions{})
+	require.True(t, errors.Is(err, backup.ErrUnsupportedStorage), err)
+
+	// share the file
+	file := filepath.Join(source, "spaces/a8/e5d981-41e4-4468-b532-258d5fb457d3/nodes/2d/08/8d/24/-d1a1-4d8b-a9d0-6d0d3b3b1c4e")
+	writeMetadata(t, file+".mpk", map[string][]byte{"user.oc.blobid": []byte("blob-1"), "user.oc.grant.u:marie": []byte("g:r")})
+	_, err = backup.ExportSpace(source, _spaceID, nil, io.Discard, backup.ExportOptions{})
+	require.True(t, errors.Is(err, backup.ErrSharedResources), err)
+	_, err = backup.ExportSpace(source, _spaceID, nil, io.Discard, backup.ExportOptions{IgnoreShares: true})
+	require.NoError(t, err)
+}
+
+type failingBlobstore struct {
+	memoryBlobstore
+}
+
+func (bs failingBlobstore) Upload(n *decomposednode.Node, source, target string) error {
+	if n.BlobID == "blob-2" {
+		return errors.New("blobstore is full")
+	}
+	return bs.memoryBlobstore.Upload(n, source, target)
+}
+
+func TestImportSpaceCleansUpOnFailure(t *testing.T) {
+	source, target := t.TempDir(), t.TempDir()
+	createSpace(t, source)
+	second := filepath.Join(source, "spaces/a8/e5d981-41e4-4468-b532-258d5fb457d3/nodes/2d/08/8d/24/-d1a1-4d8b-a9d0-6d0d3b3b1c4f")
+	require.NoError(t, os.WriteFile(second, nil, 0600))
+	writeMetadata(t, second+".mpk", map[string][]byte{"user.oc.blobid": []byte("blob-2")})
+
+	var archive bytes.Buffer
+	_, err := backup.ExportSpace(source, _spaceID, memoryBlobstore{_spaceID + "/blob-1": []byte("1"), _spaceID + "/blob-2": []byte("2")}, &archive, backup.ExportOptions{})
+	require.NoError(t, err)
+
+	targetBlobs := failingBlobstore{memoryBlobstore{}}
+	_, err = backup.ImportSpace(target, targetBlobs, bytes.NewReader(archive.Bytes()), backup.ImportOptions{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "blobstore is full")
+
+	_, err = os.Lstat(filepath.Join(target, "spaces/a8/e5d981-41e4-4468-b532-258d5fb457d3"))
+	require.True(t, errors.Is(err, os.ErrNotExist), err)
+	require.Empty(t, targetBlobs.memoryBlobstore)
+
+	// the import can be run again
+	imported, err := backup.ImportSpace(target, memoryBlobstore{}, bytes.NewReader(archive.Bytes()), backup.ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, imported.Blobs)
+}
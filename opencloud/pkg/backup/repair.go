@@ -0,0 +1,235 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// RepairAction describes the action taken to repair an inconsistency
+type RepairAction string
+
+var (
+	// RepairRemoveSymlink removes a symlink pointing to a non-existing node
+	RepairRemoveSymlink RepairAction = "remove symlink"
+	// RepairQuarantineNode moves a node which is not referenced by any symlink and its metadata files to the quarantine directory
+	RepairQuarantineNode RepairAction = "quarantine node"
+	// RepairReportBlob reports a blob which is not referenced by any node. Blobs are never deleted by the repair,
+	// so this repair is never applied.
+	RepairReportBlob RepairAction = "report unreferenced blob"
+)
+
+// Repair describes a repair of an inconsistency
+type Repair struct {
+	Action RepairAction `json:"action"`
+	Path   string       `json:"path"`
+	// Target is the quarantine path of a node or the missing node of a symlink
+	Target  string `json:"target,omitempty"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Finding describes the inconsistencies of a path
+type Finding struct {
+	Path            string          `json:"path"`
+	Inconsistencies []Inconsistency `json:"inconsistencies"`
+	// Reference is the missing node of a symlink or the node referencing a missing blob
+	Reference string `json:"reference,omitempty"`
+}
+
+// Report is the machine readable result of a consistency check
+type Report struct {
+	Path           string    `json:"path"`
+	Consistent     bool      `json:"consistent"`
+	Nodes          []Finding `json:"nodes"`
+	Links          []Finding `json:"links"`
+	Blobs          []Finding `json:"blobs"`
+	BlobReferences []Finding `json:"blob_references"`
+	Repairs        []Repair  `json:"repairs,omitempty"`
+}
+
+// Consistent returns true if no inconsistency was found
+func (c *Consistency) Consistent() bool {
+	return len(c.Nodes) == 0 && len(c.LinkedNodes) == 0 && len(c.Blobs) == 0 && len(c.BlobReferences) == 0
+}
+
+// Report returns the results of the evaluation
+func (c *Consistency) Report(discpath string) Report {
+	findings := func(incs map[string][]Inconsistency, path func(string) string, reference func(string) string) []Finding {
+		f := make([]Finding, 0, len(incs))
+		for _, k := range sortedKeys(incs) {
+			f = append(f, Finding{Path: path(k), Inconsistencies: incs[k], Reference: reference(k)})
+		}
+		return f
+	}
+	self := func(s string) string { return s }
+	none := func(string) string { return "" }
+
+	return Report{
+		Path:           discpath,
+		Consistent:     c.Consistent(),
+		Nodes:          findings(c.Nodes, self, none),
+		Links:          findings(c.LinkedNodes, func(n string) string { return c.nodeToLink[n] }, self),
+		Blobs:          findings(c.Blobs, self, none),
+		BlobReferences: findings(c.BlobReferences, self, func(b string) string { return c.blobToNode[b] }),
+	}
+}
+
+// PrintJSON prints the results of the evaluation and the repairs as json
+func (c *Consistency) PrintJSON(discpath string, repairs []Repair) error {
+	r := c.Report(discpath)
+	r.Repairs = repairs
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// Repair fixes the inconsistencies which can be repaired safely. Symlinks pointing to non-existing nodes
+// are removed and nodes not referenced by any symlink are moved to the quarantine directory together
+// with their metadata files. Orphaned blobs are only reported. Nothing is changed if dryRun is set.
+func (c *Consistency) Repair(discpath, quarantine string, dryRun bool) []Repair {
+	var repairs []Repair
+
+	for _, n := range sortedKeys(c.LinkedNodes) {
+		if !slices.Contains(c.LinkedNodes[n], InconsistencyNodeMissing) {
+			continue
+		}
+		r := Repair{Action: RepairRemoveSymlink, Path: c.nodeToLink[n], Target: n}
+		if !dryRun {
+			r.setResult(removeSymlink(r.Path))
+		}
+		repairs = append(repairs, r)
+	}
+
+	for _, n := range sortedKeys(c.Nodes) {
+		if !slices.Contains(c.Nodes[n], InconsistencySymlinkMissing) {
+			continue
+		}
+		rel, err := filepath.Rel(discpath, n)
+		if err != nil {
+			repairs = append(repairs, Repair{Action: RepairQuarantineNode, Path: n, Error: err.Error()})
+			continue
+		}
+		r := Repair{Action: RepairQuarantineNode, Path: n, Target: filepath.Join(quarantine, rel)}
+		if !dryRun {
+			r.setResult(quarantineNode(r.Path, r.Target))
+		}
+		repairs = append(repairs, r)
+	}
+
+	for _, b := range sortedKeys(c.Blobs) {
+		repairs = append(repairs, Repair{Action: RepairReportBlob, Path: b})
+	}
+
+	return repairs
+}
+
+// PrintRepairs prints the repairs
+func PrintRepairs(repairs []Repair, dryRun bool) {
+	if len(repairs) == 0 {
+		return
+	}
+
+	fmt.Println("\n🔧 Repairs:")
+	for _, r := range repairs {
+		switch {
+		case r.Action == RepairReportBlob:
+			fmt.Printf("\t🗑️ unreferenced blob, can be deleted: %s\n", r.Path)
+		case r.Error != "":
+			fmt.Printf("\t❌ %s failed: %s\n\t\t\t\terror: %s\n", r.Action, r.Path, r.Error)
+		case dryRun:
+			fmt.Printf("\t📝 would %s: %s\n", r.Action, r.Path)
+		default:
+			fmt.Printf("\t✅ %s: %s\n", r.Action, r.Path)
+		}
+	}
+}
+
+// Unrepaired returns true if not all inconsistencies were repaired. Orphaned blobs are never repaired,
+// so it returns true if there are any, even if all the other repairs were applied.
+func (c *Consistency) Unrepaired(repairs []Repair) bool {
+	applied := 0
+	for _, r := range repairs {
+		if r.Applied {
+			applied++
+		}
+	}
+	return applied < len(c.Nodes)+len(c.LinkedNodes)+len(c.Blobs)+len(c.BlobReferences)
+}
+
+func (r *Repair) setResult(err error) {
+	if err != nil {
+		r.Error = err.Error()
+		return
+	}
+	r.Applied = true
+}
+
+func removeSymlink(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return errors.New("not a symlink")
+	}
+	return os.Remove(path)
+}
+
+// quarantineNode moves the node and its metadata files like .mpk or .mlock to target.
+// Revisions and trashed nodes are separate nodes and stay in place.
+func quarantineNode(path, target string) error {
+	files, err := filepath.Glob(globEscape(path) + ".*")
+	if err != nil {
+		return err
+	}
+	files = slices.DeleteFunc(files, func(f string) bool {
+		name := filepath.Base(f)
+		return _versionRegex.MatchString(name) || _trashRegex.MatchString(name)
+	})
+	if _, err := os.Lstat(path); err == nil {
+		files = append(files, path)
+	}
+	if len(files) == 0 {
+		return errors.New("no files found")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Rename(f, target+f[len(path):]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func globEscape(path string) string {
+	b := make([]byte, 0, len(path))
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '*', '?', '[', '\\':
+			b = append(b, '\\')
+		}
+		b = append(b, path[i])
+	}
+	return string(b)
+}
+
+func sortedKeys(m map[string][]Inconsistency) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
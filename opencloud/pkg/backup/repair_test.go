@@ -0,0 +1,77 @@
+package backup_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencloud-eu/opencloud/opencloud/pkg/backup"
+	"github.com/test-go/testify/require"
+)
+
+func TestRepair(t *testing.T) {
+	base := t.TempDir()
+	quarantine := filepath.Join(t.TempDir(), "quarantine")
+	nodes := filepath.Join(base, "spaces/a8/e5d981/nodes/2d/08/8d/24")
+	require.NoError(t, os.MkdirAll(filepath.Join(nodes, "parent"), 0700))
+
+	// dangling symlink
+	danglingLink := filepath.Join(nodes, "parent", "dangling")
+	require.NoError(t, os.Symlink("../missing", danglingLink))
+
+	// orphaned node with its metadata and a revision which is kept
+	orphan := filepath.Join(nodes, "orphan")
+	for _, f := range []string{orphan, orphan + ".mpk", orphan + ".mlock", orphan + ".REV.2024-05-22T07:32:53.89969726Z"} {
+		require.NoError(t, os.WriteFile(f, []byte("x"), 0600))
+	}
+
+	events := make(chan interface{})
+	go func() {
+		events <- linkData(danglingLink, filepath.Join(nodes, "missing"))
+		events <- nodeData(orphan, "", true)
+		events <- blobData("orphanedblob")
+		close(events)
+	}()
+
+	c := backup.NewConsistency()
+	c.GatherData(events)
+
+	// dry run
+	repairs := c.Repair(base, quarantine, true)
+	require.Equal(t, []backup.Repair{
+		{Action: backup.RepairRemoveSymlink, Path: danglingLink, Target: filepath.Join(nodes, "missing")},
+		{Action: backup.RepairQuarantineNode, Path: orphan, Target: filepath.Join(quarantine, "spaces/a8/e5d981/nodes/2d/08/8d/24/orphan")},
+		{Action: backup.RepairReportBlob, Path: "orphanedblob"},
+	}, repairs)
+	require.True(t, c.Unrepaired(repairs))
+	_, err := os.Lstat(danglingLink)
+	require.NoError(t, err)
+	_, err = os.Stat(orphan)
+	require.NoError(t, err)
+
+	// repair
+	repairs = c.Repair(base, quarantine, false)
+	require.True(t, repairs[0].Applied)
+	require.True(t, repairs[1].Applied)
+	require.False(t, repairs[2].Applied)
+	require.Empty(t, repairs[0].Error+repairs[1].Error)
+
+	_, err = os.Lstat(danglingLink)
+	require.True(t, os.IsNotExist(err))
+	for _, f := range []string{orphan, orphan + ".mpk", orphan + ".mlock"} {
+		_, err = os.Stat(f)
+		require.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(quarantine, "spaces/a8/e5d981/nodes/2d/08/8d/24", filepath.Base(f)))
+		require.NoError(t, err)
+	}
+	_, err = os.Stat(orphan + ".REV.2024-05-22T07:32:53.89969726Z")
+	require.NoError(t, err)
+
+	// the orphaned blob is left
+	require.True(t, c.Unrepaired(repairs))
+
+	r := c.Report(base)
+	require.False(t, r.Consistent)
+	require.Equal(t, []backup.Finding{{Path: danglingLink, Inconsistencies: []backup.Inconsistency{backup.InconsistencyNodeMissing}, Reference: filepath.Join(nodes, "missing")}}, r.Links)
+	require.Equal(t, []backup.Finding{{Path: "orphanedblob", Inconsistencies: []backup.Inconsistency{backup.InconsistencyBlobOrphaned}}}, r.Blobs)
+}
@@ -3,6 +3,8 @@ package command
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/opencloud-eu/opencloud/opencloud/pkg/backup"
 	"github.com/opencloud-eu/opencloud/opencloud/pkg/register"
@@ -21,6 +23,8 @@ func BackupCommand(cfg *config.Config) *cli.Command {
 		Usage: "OpenCloud backup functionality",
 		Subcommands: []*cli.Command{
 			ConsistencyCommand(cfg),
+			ExportCommand(cfg),
+			ImportCommand(cfg),
 		},
 		Before: func(c *cli.Context) error {
 			return configlog.ReturnError(parser.ParseConfig(cfg, true))
@@ -52,7 +56,23 @@ func ConsistencyCommand(cfg *config.Config) *cli.Command {
 			},
 			&cli.BoolFlag{
 				Name:  "fail",
-				Usage: "exit with non-zero status if consistency check fails",
+				Usage: "exit with non-zero status if consistency check fails. With --repair, only inconsistencies left after the repair are considered, orphaned blobs are never repaired",
+			},
+			&cli.BoolFlag{
+				Name:  "repair",
+				Usage: "remove dangling symlinks and move orphaned nodes to the quarantine directory. Orphaned blobs are only reported",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "only print the repairs of --repair without applying them",
+			},
+			&cli.StringFlag{
+				Name:  "quarantine",
+				Usage: "the directory orphaned nodes are moved to. Default '<basepath>/quarantine'",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output as json",
 			},
 		},
 		Action: func(c *cli.Context) error {
@@ -87,7 +107,18 @@ func ConsistencyCommand(cfg *config.Config) *cli.Command {
 				fmt.Println(err)
 				return err
 			}
-			if err := backup.CheckProviderConsistency(basePath, bs, c.Bool("fail")); err != nil {
+			quarantine := c.String("quarantine")
+			if quarantine == "" {
+				quarantine = filepath.Join(basePath, "quarantine")
+			}
+			opts := backup.ConsistencyOptions{
+				Fail:           c.Bool("fail"),
+				Repair:         c.Bool("repair"),
+				DryRun:         c.Bool("dry-run"),
+				QuarantinePath: quarantine,
+				JSON:           c.Bool("json"),
+			}
+			if err := backup.CheckProviderConsistency(basePath, bs, opts); err != nil {
 				fmt.Println(err)
 				return err
 			}
@@ -97,6 +128,152 @@ func ConsistencyCommand(cfg *config.Config) *cli.Command {
 	}
 }
 
+// ExportCommand is the entrypoint for the export command
+func ExportCommand(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "export a space of a decomposedfs storage with its metadata, revisions, trash and blobs as tar archive",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "basepath",
+				Aliases:  []string{"p"},
+				Usage:    "the basepath of the decomposedfs (e.g. /var/tmp/opencloud/storage/users)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "space",
+				Aliases:  []string{"s"},
+				Usage:    "the id of the space to export",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "blobstore",
+				Aliases: []string{"b"},
+				Usage:   "the blobstore type. Can be (decomposed, decomposeds3). Default decomposed",
+				Value:   "decomposed",
+			},
+			&cli.StringFlag{
+				Name:    "metadata-backend",
+				Aliases: []string{"m"},
+				Usage:   "the metadata backend of the storage. Can be (messagepack, xattrs). Default messagepack",
+				Value:   "messagepack",
+			},
+			&cli.BoolFlag{
+				Name:  "ignore-shares",
+				Usage: "export spaces with shared resources. The grants are kept, but the shares are not listed for the recipients after importing",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "the file to write the archive to, '-' writes to stdout",
+				Value:   "-",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			bs, err := archiveBlobstore(cfg, c.String("blobstore"))
+			if err != nil {
+				return err
+			}
+
+			out := os.Stdout
+			if o := c.String("output"); o != "-" {
+				if out, err = os.OpenFile(o, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600); err != nil {
+					return err
+				}
+				defer out.Close()
+			}
+
+			m, err := backup.ExportSpace(c.String("basepath"), c.String("space"), bs, out, backup.ExportOptions{
+				MetadataBackend: c.String("metadata-backend"),
+				IgnoreShares:    c.Bool("ignore-shares"),
+			})
+			if err != nil {
+				return err
+			}
+			if out != os.Stdout {
+				if err := out.Close(); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(os.Stderr, "exported space '%s' with %d files and %d blobs\n", m.SpaceID, m.Files, m.Blobs)
+			return nil
+		},
+	}
+}
+
+// ImportCommand is the entrypoint for the import command
+func ImportCommand(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "import",
+		Usage: "import a space archive created by the export command into a decomposedfs storage. The storage-users service should be stopped",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "basepath",
+				Aliases:  []string{"p"},
+				Usage:    "the basepath of the target decomposedfs (e.g. /var/tmp/opencloud/storage/users)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "blobstore",
+				Aliases: []string{"b"},
+				Usage:   "the blobstore type. Can be (decomposed, decomposeds3). Default decomposed",
+				Value:   "decomposed",
+			},
+			&cli.StringFlag{
+				Name:    "input",
+				Aliases: []string{"i"},
+				Usage:   "the file to read the archive from, '-' reads from stdin",
+				Value:   "-",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-indexes",
+				Usage: "don't add the space to the space indexes of the target storage",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			bs, err := archiveBlobstore(cfg, c.String("blobstore"))
+			if err != nil {
+				return err
+			}
+
+			in := os.Stdin
+			if i := c.String("input"); i != "-" {
+				if in, err = os.Open(i); err != nil {
+					return err
+				}
+				defer in.Close()
+			}
+
+			m, err := backup.ImportSpace(c.String("basepath"), bs, in, backup.ImportOptions{SkipIndexes: c.Bool("skip-indexes")})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("imported space '%s' with %d files and %d blobs\n", m.SpaceID, m.Files, m.Blobs)
+			return nil
+		},
+	}
+}
+
+// archiveBlobstore returns the blobstore for blobs which are not stored in the space directory
+func archiveBlobstore(cfg *config.Config, kind string) (backup.Blobstore, error) {
+	switch kind {
+	case "decomposed":
+		// the blobs are part of the space directory
+		return nil, nil
+	case "decomposeds3":
+		return decomposeds3bs.New(
+			cfg.StorageUsers.Drivers.DecomposedS3.Endpoint,
+			cfg.StorageUsers.Drivers.DecomposedS3.Region,
+			cfg.StorageUsers.Drivers.DecomposedS3.Bucket,
+			cfg.StorageUsers.Drivers.DecomposedS3.AccessKey,
+			cfg.StorageUsers.Drivers.DecomposedS3.SecretKey,
+			decomposeds3bs.Options{},
+		)
+	default:
+		return nil, errors.New("blobstore type not supported")
+	}
+}
+
 func init() {
 	register.AddCommand(BackupCommand)
 }
@@ -0,0 +1,249 @@
+// Package apptoken defines the restrictions of app tokens. The restrictions are stored next to the
+// owner scope in the token scope when the token is created and enforced by the proxy.
+package apptoken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	authpb "github.com/cs3org/go-cs3apis/cs3/auth/provider/v1beta1"
+	types "github.com/cs3org/go-cs3apis/cs3/types/v1beta1"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ScopeKey is the key of the restrictions in the token scope. Reva ignores scopes it doesn't know.
+const ScopeKey = "apptoken-restrictions"
+
+// The APIs an app token can be restricted to
+const (
+	APIWebDAV = "webdav"
+	APIGraph  = "graph"
+	APIOCS    = "ocs"
+)
+
+var (
+	// ErrInvalidRestrictions is returned when restrictions can't be applied
+	ErrInvalidRestrictions = errors.New("invalid app token restrictions")
+	// ErrForbidden is returned when a request is not allowed by the restrictions
+	ErrForbidden = errors.New("forbidden by app token restrictions")
+
+	_apiPrefixes = map[string][]string{
+		APIWebDAV: {"/remote.php/webdav", "/remote.php/dav/", "/webdav", "/dav/"},
+		APIGraph:  {"/graph/"},
+		APIOCS:    {"/ocs/"},
+	}
+
+	_spacePrefixes = []string{"/remote.php/dav/spaces/", "/dav/spaces/"}
+
+	_readOnlyMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, "PROPFIND", "REPORT"}
+)
+
+// Restrictions limit what an app token can be used for. The zero value doesn't restrict anything.
+type Restrictions struct {
+	// ReadOnly only allows requests which don't change anything
+	ReadOnly bool `json:"read_only,omitempty"`
+	// SpaceID only allows WebDAV requests to the space with this id
+	SpaceID string `json:"space_id,omitempty"`
+	// Path only allows WebDAV requests to this folder of the space and below
+	Path string `json:"path,omitempty"`
+	// APIs only allows requests to these APIs
+	APIs []string `json:"apis,omitempty"`
+	// AllowedIPs only allows requests from these IP addresses or networks
+	AllowedIPs []string `json:"allowed_ips,omitempty"`
+}
+
+// IsZero returns true if the restrictions don't restrict anything
+func (r Restrictions) IsZero() bool {
+	return !r.ReadOnly && r.SpaceID == "" && r.Path == "" && len(r.APIs) == 0 && len(r.AllowedIPs) == 0
+}
+
+// Validate checks if the restrictions can be applied
+func (r Restrictions) Validate() error {
+	if r.Path != "" {
+		if r.SpaceID == "" {
+			return fmt.Errorf("%w: a path requires a space id", ErrInvalidRestrictions)
+		}
+		if !strings.HasPrefix(r.Path, "/") {
+			return fmt.Errorf("%w: the path must be absolute", ErrInvalidRestrictions)
+		}
+	}
+	if r.SpaceID != "" && strings.Contains(r.SpaceID, "/") {
+		return fmt.Errorf("%w: invalid space id %s", ErrInvalidRestrictions, r.SpaceID)
+	}
+	for _, api := range r.APIs {
+		if _, ok := _apiPrefixes[api]; !ok {
+			return fmt.Errorf("%w: unknown api %s", ErrInvalidRestrictions, api)
+		}
+	}
+	if r.SpaceID != "" && len(r.APIs) != 0 && !slices.Contains(r.APIs, APIWebDAV) {
+		return fmt.Errorf("%w: a space can only be accessed via webdav", ErrInvalidRestrictions)
+	}
+	for _, ip := range r.AllowedIPs {
+		if _, err := parsePrefix(ip); err != nil {
+			return fmt.Errorf("%w: invalid ip address or network %s", ErrInvalidRestrictions, ip)
+		}
+	}
+	return nil
+}
+
+// AddScope adds the restrictions to the token scope, nothing is added for the zero value.
+func AddScope(r Restrictions, scopes map[string]*authpb.Scope) (map[string]*authpb.Scope, error) {
+	if r.IsZero() {
+		return scopes, nil
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	if r.Path != "" {
+		r.Path = path.Clean(r.Path)
+	}
+	val, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	if scopes == nil {
+		scopes = make(map[string]*authpb.Scope)
+	}
+	scopes[ScopeKey] = &authpb.Scope{
+		Resource: &types.OpaqueEntry{
+			Decoder: "json",
+			Value:   val,
+		},
+		Role: authpb.Role_ROLE_OWNER,
+	}
+	return scopes, nil
+}
+
+// FromScopes returns the restrictions stored in the token scope, the zero value is returned if there are none.
+func FromScopes(scopes map[string]*authpb.Scope) (Restrictions, error) {
+	var r Restrictions
+	s, ok := scopes[ScopeKey]
+	if !ok {
+		return r, nil
+	}
+
+	err := json.Unmarshal(s.GetResource().GetValue(), &r)
+	return r, err
+}
+
+// tokenClaims are the claims of the reva token needed to read the restrictions
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Scope map[string]*authpb.Scope `json:"scope"`
+}
+
+// FromToken returns the restrictions stored in the scope of a reva token. The signature is not verified,
+// the token must come from a trusted source like the gateway.
+func FromToken(token string) (Restrictions, error) {
+	claims := &tokenClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return Restrictions{}, err
+	}
+	return FromScopes(claims.Scope)
+}
+
+// Check returns ErrForbidden if the request is not allowed by the restrictions. The client ip has to be
+// determined by the caller, because the forwarding headers of the request can be spoofed.
+func (r Restrictions) Check(req *http.Request, clientIP netip.Addr) error {
+	if len(r.AllowedIPs) != 0 && !r.allowsIP(clientIP) {
+		return fmt.Errorf("%w: ip address not allowed", ErrForbidden)
+	}
+
+	if r.ReadOnly && !slices.Contains(_readOnlyMethods, req.Method) {
+		return fmt.Errorf("%w: read-only token", ErrForbidden)
+	}
+
+	if len(r.APIs) != 0 && !slices.Contains(r.APIs, api(req.URL.Path)) {
+		return fmt.Errorf("%w: api not allowed", ErrForbidden)
+	}
+
+	if r.SpaceID != "" {
+		// a search returns results from the whole space, not only from the requested folder
+		if req.Method == "REPORT" && path.Clean(r.Path) != "/" && r.Path != "" {
+			return fmt.Errorf("%w: search not allowed", ErrForbidden)
+		}
+		if !r.allowsPath(req.URL.Path) {
+			return fmt.Errorf("%w: resource not allowed", ErrForbidden)
+		}
+		// moving and copying must stay inside of the allowed resource as well
+		if dst := req.Header.Get("Destination"); dst != "" {
+			u, err := url.Parse(dst)
+			if err != nil || !r.allowsPath(u.Path) {
+				return fmt.Errorf("%w: destination not allowed", ErrForbidden)
+			}
+		}
+	}
+	return nil
+}
+
+// allowsIP checks if the client address is in one of the allowed networks
+func (r Restrictions) allowsIP(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, ip := range r.AllowedIPs {
+		if prefix, err := parsePrefix(ip); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsPath checks if the request path is a WebDAV path of the allowed space and folder
+func (r Restrictions) allowsPath(p string) bool {
+	for _, prefix := range _spacePrefixes {
+		rest, ok := strings.CutPrefix(p, prefix)
+		if !ok {
+			continue
+		}
+
+		spaceID, resource, _ := strings.Cut(rest, "/")
+		if spaceID != r.SpaceID {
+			return false
+		}
+		if r.Path == "" {
+			return true
+		}
+
+		resource = path.Clean("/" + resource)
+		allowed := path.Clean(r.Path)
+		return allowed == "/" || resource == allowed || strings.HasPrefix(resource, allowed+"/")
+	}
+	return false
+}
+
+// api returns the api the path belongs to
+func api(p string) string {
+	for name, prefixes := range _apiPrefixes {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(p, prefix) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// parsePrefix parses an ip address or a network in CIDR notation
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,132 @@
+package apptoken_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/opencloud-eu/opencloud/pkg/apptoken"
+)
+
+func TestScopeRoundTrip(t *testing.T) {
+	r := apptoken.Restrictions{
+		ReadOnly:   true,
+		SpaceID:    "storage-users-1$some-space",
+		Path:       "/ci/builds/",
+		APIs:       []string{apptoken.APIWebDAV},
+		AllowedIPs: []string{"10.0.0.0/8"},
+	}
+
+	scopes, err := apptoken.AddScope(r, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := apptoken.FromScopes(scopes)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.Path != "/ci/builds" || got.SpaceID != r.SpaceID || !got.ReadOnly {
+		t.Errorf("unexpected restrictions: %+v", got)
+	}
+}
+
+func TestAddScopeWithoutRestrictions(t *testing.T) {
+	scopes, err := apptoken.AddScope(apptoken.Restrictions{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(scopes) != 0 {
+		t.Errorf("expected no scope, got %v", scopes)
+	}
+
+	r, err := apptoken.FromScopes(scopes)
+	if err != nil || !r.IsZero() {
+		t.Errorf("expected no restrictions, got %+v, %v", r, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		r     apptoken.Restrictions
+		valid bool
+	}{
+		{"empty", apptoken.Restrictions{}, true},
+		{"space and path", apptoken.Restrictions{SpaceID: "a$b", Path: "/folder"}, true},
+		{"path without space", apptoken.Restrictions{Path: "/folder"}, false},
+		{"relative path", apptoken.Restrictions{SpaceID: "a$b", Path: "folder"}, false},
+		{"unknown api", apptoken.Restrictions{APIs: []string{"caldav"}}, false},
+		{"space via graph", apptoken.Restrictions{SpaceID: "a$b", APIs: []string{apptoken.APIGraph}}, false},
+		{"ip and network", apptoken.Restrictions{AllowedIPs: []string{"192.168.1.1", "2001:db8::/32"}}, true},
+		{"invalid ip", apptoken.Restrictions{AllowedIPs: []string{"192.168.1"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.r.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+			if !tt.valid && !errors.Is(err, apptoken.ErrInvalidRestrictions) {
+				t.Errorf("expected ErrInvalidRestrictions, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	const space = "storage-users-1$some-space"
+
+	tests := []struct {
+		name    string
+		r       apptoken.Restrictions
+		method  string
+		target  string
+		header  http.Header
+		remote  string
+		allowed bool
+	}{
+		{"no restrictions", apptoken.Restrictions{}, "DELETE", "/graph/v1.0/me/drives", nil, "", true},
+		{"read-only propfind", apptoken.Restrictions{ReadOnly: true}, "PROPFIND", "/dav/spaces/" + space, nil, "", true},
+		{"read-only put", apptoken.Restrictions{ReadOnly: true}, "PUT", "/dav/spaces/" + space + "/file.txt", nil, "", false},
+		{"webdav only allows webdav", apptoken.Restrictions{APIs: []string{apptoken.APIWebDAV}}, "GET", "/remote.php/webdav/file.txt", nil, "", true},
+		{"webdav only denies graph", apptoken.Restrictions{APIs: []string{apptoken.APIWebDAV}}, "GET", "/graph/v1.0/me", nil, "", false},
+		{"graph only denies webdav", apptoken.Restrictions{APIs: []string{apptoken.APIGraph}}, "PROPFIND", "/dav/spaces/" + space, nil, "", false},
+		{"space root", apptoken.Restrictions{SpaceID: space}, "PROPFIND", "/remote.php/dav/spaces/" + space, nil, "", true},
+		{"other space", apptoken.Restrictions{SpaceID: space}, "PROPFIND", "/dav/spaces/storage-users-1$other", nil, "", false},
+		{"space denies graph", apptoken.Restrictions{SpaceID: space}, "GET", "/graph/v1.0/me/drives", nil, "", false},
+		{"folder", apptoken.Restrictions{SpaceID: space, Path: "/ci"}, "PUT", "/dav/spaces/" + space + "/ci/build.zip", nil, "", true},
+		{"folder itself", apptoken.Restrictions{SpaceID: space, Path: "/ci"}, "PROPFIND", "/dav/spaces/" + space + "/ci", nil, "", true},
+		{"sibling folder", apptoken.Restrictions{SpaceID: space, Path: "/ci"}, "GET", "/dav/spaces/" + space + "/cid/file", nil, "", false},
+		{"folder traversal", apptoken.Restrictions{SpaceID: space, Path: "/ci"}, "GET", "/dav/spaces/" + space + "/ci/../secret", nil, "", false},
+		{"move out of folder", apptoken.Restrictions{SpaceID: space, Path: "/ci"}, "MOVE", "/dav/spaces/" + space + "/ci/file", http.Header{"Destination": {"https://cloud.example.com/dav/spaces/" + space + "/file"}}, "", false},
+		{"move inside folder", apptoken.Restrictions{SpaceID: space, Path: "/ci"}, "MOVE", "/dav/spaces/" + space + "/ci/file", http.Header{"Destination": {"https://cloud.example.com/dav/spaces/" + space + "/ci/old/file"}}, "", true},
+		{"search in folder", apptoken.Restrictions{SpaceID: space, Path: "/ci"}, "REPORT", "/dav/spaces/" + space + "/ci", nil, "", false},
+		{"search in space", apptoken.Restrictions{SpaceID: space}, "REPORT", "/dav/spaces/" + space, nil, "", true},
+		{"allowed ip", apptoken.Restrictions{AllowedIPs: []string{"10.0.0.0/8"}}, "GET", "/graph/v1.0/me", nil, "10.1.2.3", true},
+		{"allowed single ip", apptoken.Restrictions{AllowedIPs: []string{"10.1.2.3"}}, "GET", "/graph/v1.0/me", nil, "10.1.2.3", true},
+		{"denied ip", apptoken.Restrictions{AllowedIPs: []string{"10.0.0.0/8"}}, "GET", "/graph/v1.0/me", nil, "192.168.1.1", false},
+		{"unknown ip", apptoken.Restrictions{AllowedIPs: []string{"10.0.0.0/8"}}, "GET", "/graph/v1.0/me", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+			clientIP, _ := netip.ParseAddr(tt.remote)
+
+			err := tt.r.Check(req, clientIP)
+			if tt.allowed && err != nil {
+				t.Errorf("expected request to be allowed, got %s", err)
+			}
+			if !tt.allowed && !errors.Is(err, apptoken.ErrForbidden) {
+				t.Errorf("expected ErrForbidden, got %v", err)
+			}
+		})
+	}
+}
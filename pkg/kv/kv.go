@@ -0,0 +1,97 @@
+// Package kv provides key value stores with optimistic concurrency control. Every value has a
+// revision and updates only succeed if the revision didn't change in the meantime, so several
+// instances of a service can safely change the same keys.
+//
+// Unlike the go-micro stores, the values don't have an individual expiry. Values expire after
+// the TTL of the store, counted from the last write.
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned if the key doesn't exist
+	ErrNotFound = errors.New("key not found")
+	// ErrConflict is returned if the key was changed concurrently
+	ErrConflict = errors.New("key was changed concurrently")
+)
+
+// _maxRetries is the number of times Modify tries to apply a change
+const _maxRetries = 16
+
+// Store is a key value store with optimistic concurrency control
+type Store interface {
+	// Get returns the value of the key and its revision
+	Get(key string) ([]byte, uint64, error)
+	// Create stores the value if the key doesn't exist, otherwise it returns ErrConflict
+	Create(key string, value []byte) (uint64, error)
+	// Update stores the value if the key still has the given revision, otherwise it returns ErrConflict
+	Update(key string, value []byte, revision uint64) (uint64, error)
+	// Put stores the value regardless of the revision of the key
+	Put(key string, value []byte) (uint64, error)
+	// Delete removes the key, it doesn't fail if the key doesn't exist
+	Delete(key string) error
+	// Keys returns the keys starting with the prefix
+	Keys(prefix string) ([]string, error)
+}
+
+// Options configure a store
+type Options struct {
+	// Store is the type of the store, 'memory' or 'nats-js-kv'
+	Store string
+	// Nodes are the addresses of the nats servers
+	Nodes []string
+	// Database is the name of the nats key value bucket
+	Database string
+	// TTL is the time values are kept after the last write, 0 keeps them forever
+	TTL time.Duration
+	// AuthUsername is the username to authenticate with the nats servers
+	AuthUsername string
+	// AuthPassword is the password to authenticate with the nats servers
+	AuthPassword string
+}
+
+// New creates a store of the configured type
+func New(o Options) (Store, error) {
+	switch o.Store {
+	case "memory":
+		return NewMemoryStore(o.TTL), nil
+	case "nats-js-kv":
+		return NewNATSStore(o)
+	default:
+		return nil, fmt.Errorf("unsupported store type '%s', supported values are 'memory' and 'nats-js-kv'", o.Store)
+	}
+}
+
+// Modify changes the value of the key with fn and stores the result. fn is called with nil if the key
+// doesn't exist. If the key was changed concurrently, fn is called again with the new value. If fn
+// returns a nil value, nothing is stored.
+func Modify(s Store, key string, fn func(value []byte) ([]byte, error)) error {
+	for range _maxRetries {
+		value, revision, err := s.Get(key)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			value = nil
+		case err != nil:
+			return err
+		}
+
+		changed, err := fn(value)
+		if err != nil || changed == nil {
+			return err
+		}
+
+		if value == nil {
+			_, err = s.Create(key, changed)
+		} else {
+			_, err = s.Update(key, changed, revision)
+		}
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+	}
+	return ErrConflict
+}
@@ -0,0 +1,80 @@
+package kv_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+)
+
+func TestMemoryStoreRevisions(t *testing.T) {
+	s := kv.NewMemoryStore(0)
+
+	if _, _, err := s.Get("key"); !errors.Is(err, kv.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	rev, err := s.Create("key", []byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("key", []byte("b")); !errors.Is(err, kv.ErrConflict) {
+		t.Fatalf("expected creating an existing key to conflict, got %v", err)
+	}
+
+	if _, err := s.Update("key", []byte("b"), rev); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Update("key", []byte("c"), rev); !errors.Is(err, kv.ErrConflict) {
+		t.Fatalf("expected updating an old revision to conflict, got %v", err)
+	}
+
+	value, _, err := s.Get("key")
+	if err != nil || string(value) != "b" {
+		t.Fatalf("expected b, got %q, %v", value, err)
+	}
+}
+
+func TestMemoryStoreTTL(t *testing.T) {
+	s := kv.NewMemoryStore(10 * time.Millisecond)
+	if _, err := s.Put("key", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, _, err := s.Get("key"); !errors.Is(err, kv.ErrNotFound) {
+		t.Fatalf("expected the key to expire, got %v", err)
+	}
+	if keys, _ := s.Keys(""); len(keys) != 0 {
+		t.Fatalf("expected no keys, got %v", keys)
+	}
+}
+
+func TestModifyConcurrently(t *testing.T) {
+	s := kv.NewMemoryStore(0)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := kv.Modify(s, "counter", func(value []byte) ([]byte, error) {
+				n, _ := strconv.Atoi(string(value))
+				return []byte(strconv.Itoa(n + 1)), nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, _, err := s.Get("counter")
+	if err != nil || string(value) != "8" {
+		t.Fatalf("expected no lost updates, got %q, %v", value, err)
+	}
+}
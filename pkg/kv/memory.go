@@ -0,0 +1,131 @@
+package kv
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// _sweepInterval is the number of writes after which expired values are removed
+const _sweepInterval = 1024
+
+// memoryEntry is a value of the memory store
+type memoryEntry struct {
+	value    []byte
+	revision uint64
+	written  time.Time
+}
+
+// MemoryStore keeps the values in memory. It can only be used by a single instance of a service.
+type MemoryStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	revision uint64
+	writes   int
+	entries  map[string]memoryEntry
+}
+
+// NewMemoryStore creates a new MemoryStore. Values are removed after the ttl, 0 keeps them forever.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		ttl:     ttl,
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Get returns the value of the key and its revision
+func (s *MemoryStore) Get(key string) ([]byte, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.get(key)
+	if !ok {
+		return nil, 0, ErrNotFound
+	}
+	return e.value, e.revision, nil
+}
+
+// Create stores the value if the key doesn't exist, otherwise it returns ErrConflict
+func (s *MemoryStore) Create(key string, value []byte) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.get(key); ok {
+		return 0, ErrConflict
+	}
+	return s.put(key, value), nil
+}
+
+// Update stores the value if the key still has the given revision, otherwise it returns ErrConflict
+func (s *MemoryStore) Update(key string, value []byte, revision uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.get(key); !ok || e.revision != revision {
+		return 0, ErrConflict
+	}
+	return s.put(key, value), nil
+}
+
+// Put stores the value regardless of the revision of the key
+func (s *MemoryStore) Put(key string, value []byte) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.put(key, value), nil
+}
+
+// Delete removes the key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// Keys returns the keys starting with the prefix
+func (s *MemoryStore) Keys(prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.entries))
+	for k := range s.entries {
+		if _, ok := s.get(k); ok && strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (s *MemoryStore) get(key string) (memoryEntry, bool) {
+	e, ok := s.entries[key]
+	if ok && s.expired(e) {
+		delete(s.entries, key)
+		return memoryEntry{}, false
+	}
+	return e, ok
+}
+
+func (s *MemoryStore) put(key string, value []byte) uint64 {
+	s.revision++
+	s.entries[key] = memoryEntry{
+		value:    value,
+		revision: s.revision,
+		written:  time.Now(),
+	}
+
+	s.writes++
+	if s.ttl > 0 && s.writes%_sweepInterval == 0 {
+		for k, e := range s.entries {
+			if s.expired(e) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	return s.revision
+}
+
+func (s *MemoryStore) expired(e memoryEntry) bool {
+	return s.ttl > 0 && time.Since(e.written) > s.ttl
+}
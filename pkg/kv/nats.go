@@ -0,0 +1,126 @@
+package kv
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	perrors "github.com/pkg/errors"
+)
+
+// NATSStore keeps the values in a nats key value bucket, so it can be shared by several instances
+// of a service. The TTL is the max age of the bucket.
+type NATSStore struct {
+	kv nats.KeyValue
+}
+
+// NewNATSStore connects to the nats servers and creates the bucket if it doesn't exist yet. The TTL
+// of an existing bucket isn't changed.
+func NewNATSStore(o Options) (*NATSStore, error) {
+	natsOptions := nats.Options{
+		Servers:  o.Nodes,
+		User:     o.AuthUsername,
+		Password: o.AuthPassword,
+	}
+	conn, err := natsOptions.Connect()
+	if err != nil {
+		return nil, perrors.Wrap(err, "could not connect to nats")
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := js.KeyValue(o.Database)
+	if err != nil {
+		if !errors.Is(err, nats.ErrBucketNotFound) {
+			return nil, perrors.Wrapf(err, "failed to get bucket (%s)", o.Database)
+		}
+
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket: o.Database,
+			TTL:    o.TTL,
+		})
+		if err != nil {
+			return nil, perrors.Wrapf(err, "failed to create bucket (%s)", o.Database)
+		}
+	}
+
+	return &NATSStore{kv: kv}, nil
+}
+
+// Get returns the value of the key and its revision
+func (s *NATSStore) Get(key string) ([]byte, uint64, error) {
+	e, err := s.kv.Get(encodeKey(key))
+	if err != nil {
+		return nil, 0, natsError(err)
+	}
+	return e.Value(), e.Revision(), nil
+}
+
+// Create stores the value if the key doesn't exist, otherwise it returns ErrConflict
+func (s *NATSStore) Create(key string, value []byte) (uint64, error) {
+	revision, err := s.kv.Create(encodeKey(key), value)
+	return revision, natsError(err)
+}
+
+// Update stores the value if the key still has the given revision, otherwise it returns ErrConflict
+func (s *NATSStore) Update(key string, value []byte, revision uint64) (uint64, error) {
+	revision, err := s.kv.Update(encodeKey(key), value, revision)
+	return revision, natsError(err)
+}
+
+// Put stores the value regardless of the revision of the key
+func (s *NATSStore) Put(key string, value []byte) (uint64, error) {
+	revision, err := s.kv.Put(encodeKey(key), value)
+	return revision, natsError(err)
+}
+
+// Delete removes the key
+func (s *NATSStore) Delete(key string) error {
+	return natsError(s.kv.Delete(encodeKey(key)))
+}
+
+// Keys returns the keys starting with the prefix
+func (s *NATSStore) Keys(prefix string) ([]string, error) {
+	encoded, err := s.kv.Keys()
+	switch {
+	case errors.Is(err, nats.ErrNoKeysFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(encoded))
+	for _, e := range encoded {
+		k, err := base64.RawURLEncoding.DecodeString(e)
+		if err != nil {
+			// not written by this store
+			continue
+		}
+		if strings.HasPrefix(string(k), prefix) {
+			keys = append(keys, string(k))
+		}
+	}
+	return keys, nil
+}
+
+// encodeKey encodes the key, nats only allows a few characters in keys
+func encodeKey(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func natsError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, nats.ErrKeyNotFound):
+		return ErrNotFound
+	case errors.Is(err, nats.ErrKeyExists):
+		return ErrConflict
+	default:
+		return err
+	}
+}
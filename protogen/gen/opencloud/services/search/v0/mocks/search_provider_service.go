@@ -39,6 +39,89 @@ func (_m *SearchProviderService) EXPECT() *SearchProviderService_Expecter {
 	return &SearchProviderService_Expecter{mock: &_m.Mock}
 }
 
+// GetReindexStatus provides a mock function for the type SearchProviderService
+func (_mock *SearchProviderService) GetReindexStatus(ctx context.Context, in *v0.GetReindexStatusRequest, opts ...client.CallOption) (*v0.GetReindexStatusResponse, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, in, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, in)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReindexStatus")
+	}
+
+	var r0 *v0.GetReindexStatusResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v0.GetReindexStatusRequest, ...client.CallOption) (*v0.GetReindexStatusResponse, error)); ok {
+		return returnFunc(ctx, in, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v0.GetReindexStatusRequest, ...client.CallOption) *v0.GetReindexStatusResponse); ok {
+		r0 = returnFunc(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v0.GetReindexStatusResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v0.GetReindexStatusRequest, ...client.CallOption) error); ok {
+		r1 = returnFunc(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// SearchProviderService_GetReindexStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReindexStatus'
+type SearchProviderService_GetReindexStatus_Call struct {
+	*mock.Call
+}
+
+// GetReindexStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *v0.GetReindexStatusRequest
+//   - opts ...client.CallOption
+func (_e *SearchProviderService_Expecter) GetReindexStatus(ctx interface{}, in interface{}, opts ...interface{}) *SearchProviderService_GetReindexStatus_Call {
+	return &SearchProviderService_GetReindexStatus_Call{Call: _e.mock.On("GetReindexStatus",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *SearchProviderService_GetReindexStatus_Call) Run(run func(ctx context.Context, in *v0.GetReindexStatusRequest, opts ...client.CallOption)) *SearchProviderService_GetReindexStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v0.GetReindexStatusRequest
+		if args[1] != nil {
+			arg1 = args[1].(*v0.GetReindexStatusRequest)
+		}
+		var arg2 []client.CallOption
+		var variadicArgs []client.CallOption
+		if len(args) > 2 {
+			variadicArgs = args[2].([]client.CallOption)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *SearchProviderService_GetReindexStatus_Call) Return(getReindexStatusResponse *v0.GetReindexStatusResponse, err error) *SearchProviderService_GetReindexStatus_Call {
+	_c.Call.Return(getReindexStatusResponse, err)
+	return _c
+}
+
+func (_c *SearchProviderService_GetReindexStatus_Call) RunAndReturn(run func(ctx context.Context, in *v0.GetReindexStatusRequest, opts ...client.CallOption) (*v0.GetReindexStatusResponse, error)) *SearchProviderService_GetReindexStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IndexSpace provides a mock function for the type SearchProviderService
 func (_mock *SearchProviderService) IndexSpace(ctx context.Context, in *v0.IndexSpaceRequest, opts ...client.CallOption) (*v0.IndexSpaceResponse, error) {
 	var tmpRet mock.Arguments
@@ -122,6 +205,89 @@ func (_c *SearchProviderService_IndexSpace_Call) RunAndReturn(run func(ctx conte
 	return _c
 }
 
+// Reindex provides a mock function for the type SearchProviderService
+func (_mock *SearchProviderService) Reindex(ctx context.Context, in *v0.ReindexRequest, opts ...client.CallOption) (*v0.ReindexResponse, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ctx, in, opts)
+	} else {
+		tmpRet = _mock.Called(ctx, in)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reindex")
+	}
+
+	var r0 *v0.ReindexResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v0.ReindexRequest, ...client.CallOption) (*v0.ReindexResponse, error)); ok {
+		return returnFunc(ctx, in, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *v0.ReindexRequest, ...client.CallOption) *v0.ReindexResponse); ok {
+		r0 = returnFunc(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v0.ReindexResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *v0.ReindexRequest, ...client.CallOption) error); ok {
+		r1 = returnFunc(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// SearchProviderService_Reindex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reindex'
+type SearchProviderService_Reindex_Call struct {
+	*mock.Call
+}
+
+// Reindex is a helper method to define mock.On call
+//   - ctx context.Context
+//   - in *v0.ReindexRequest
+//   - opts ...client.CallOption
+func (_e *SearchProviderService_Expecter) Reindex(ctx interface{}, in interface{}, opts ...interface{}) *SearchProviderService_Reindex_Call {
+	return &SearchProviderService_Reindex_Call{Call: _e.mock.On("Reindex",
+		append([]interface{}{ctx, in}, opts...)...)}
+}
+
+func (_c *SearchProviderService_Reindex_Call) Run(run func(ctx context.Context, in *v0.ReindexRequest, opts ...client.CallOption)) *SearchProviderService_Reindex_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *v0.ReindexRequest
+		if args[1] != nil {
+			arg1 = args[1].(*v0.ReindexRequest)
+		}
+		var arg2 []client.CallOption
+		var variadicArgs []client.CallOption
+		if len(args) > 2 {
+			variadicArgs = args[2].([]client.CallOption)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *SearchProviderService_Reindex_Call) Return(reindexResponse *v0.ReindexResponse, err error) *SearchProviderService_Reindex_Call {
+	_c.Call.Return(reindexResponse, err)
+	return _c
+}
+
+func (_c *SearchProviderService_Reindex_Call) RunAndReturn(run func(ctx context.Context, in *v0.ReindexRequest, opts ...client.CallOption) (*v0.ReindexResponse, error)) *SearchProviderService_Reindex_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Search provides a mock function for the type SearchProviderService
 func (_mock *SearchProviderService) Search(ctx context.Context, in *v0.SearchRequest, opts ...client.CallOption) (*v0.SearchResponse, error) {
 	var tmpRet mock.Arguments
@@ -47,6 +47,18 @@ func NewSearchProviderEndpoints() []*api.Endpoint {
 			Method:  []string{"POST"},
 			Handler: "rpc",
 		},
+		{
+			Name:    "SearchProvider.Reindex",
+			Path:    []string{"/api/v0/search/reindex"},
+			Method:  []string{"POST"},
+			Handler: "rpc",
+		},
+		{
+			Name:    "SearchProvider.GetReindexStatus",
+			Path:    []string{"/api/v0/search/reindex-status"},
+			Method:  []string{"POST"},
+			Handler: "rpc",
+		},
 	}
 }
 
@@ -55,6 +67,8 @@ func NewSearchProviderEndpoints() []*api.Endpoint {
 type SearchProviderService interface {
 	Search(ctx context.Context, in *SearchRequest, opts ...client.CallOption) (*SearchResponse, error)
 	IndexSpace(ctx context.Context, in *IndexSpaceRequest, opts ...client.CallOption) (*IndexSpaceResponse, error)
+	Reindex(ctx context.Context, in *ReindexRequest, opts ...client.CallOption) (*ReindexResponse, error)
+	GetReindexStatus(ctx context.Context, in *GetReindexStatusRequest, opts ...client.CallOption) (*GetReindexStatusResponse, error)
 }
 
 type searchProviderService struct {
@@ -89,17 +103,41 @@ func (c *searchProviderService) IndexSpace(ctx context.Context, in *IndexSpaceRe
 	return out, nil
 }
 
+func (c *searchProviderService) Reindex(ctx context.Context, in *ReindexRequest, opts ...client.CallOption) (*ReindexResponse, error) {
+	req := c.c.NewRequest(c.name, "SearchProvider.Reindex", in)
+	out := new(ReindexResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *searchProviderService) GetReindexStatus(ctx context.Context, in *GetReindexStatusRequest, opts ...client.CallOption) (*GetReindexStatusResponse, error) {
+	req := c.c.NewRequest(c.name, "SearchProvider.GetReindexStatus", in)
+	out := new(GetReindexStatusResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for SearchProvider service
 
 type SearchProviderHandler interface {
 	Search(context.Context, *SearchRequest, *SearchResponse) error
 	IndexSpace(context.Context, *IndexSpaceRequest, *IndexSpaceResponse) error
+	Reindex(context.Context, *ReindexRequest, *ReindexResponse) error
+	GetReindexStatus(context.Context, *GetReindexStatusRequest, *GetReindexStatusResponse) error
 }
 
 func RegisterSearchProviderHandler(s server.Server, hdlr SearchProviderHandler, opts ...server.HandlerOption) error {
 	type searchProvider interface {
 		Search(ctx context.Context, in *SearchRequest, out *SearchResponse) error
 		IndexSpace(ctx context.Context, in *IndexSpaceRequest, out *IndexSpaceResponse) error
+		Reindex(ctx context.Context, in *ReindexRequest, out *ReindexResponse) error
+		GetReindexStatus(ctx context.Context, in *GetReindexStatusRequest, out *GetReindexStatusResponse) error
 	}
 	type SearchProvider struct {
 		searchProvider
@@ -117,6 +155,18 @@ func RegisterSearchProviderHandler(s server.Server, hdlr SearchProviderHandler,
 		Method:  []string{"POST"},
 		Handler: "rpc",
 	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "SearchProvider.Reindex",
+		Path:    []string{"/api/v0/search/reindex"},
+		Method:  []string{"POST"},
+		Handler: "rpc",
+	}))
+	opts = append(opts, api.WithEndpoint(&api.Endpoint{
+		Name:    "SearchProvider.GetReindexStatus",
+		Path:    []string{"/api/v0/search/reindex-status"},
+		Method:  []string{"POST"},
+		Handler: "rpc",
+	}))
 	return s.Handle(s.NewHandler(&SearchProvider{h}, opts...))
 }
 
@@ -132,6 +182,14 @@ func (h *searchProviderHandler) IndexSpace(ctx context.Context, in *IndexSpaceRe
 	return h.SearchProviderHandler.IndexSpace(ctx, in, out)
 }
 
+func (h *searchProviderHandler) Reindex(ctx context.Context, in *ReindexRequest, out *ReindexResponse) error {
+	return h.SearchProviderHandler.Reindex(ctx, in, out)
+}
+
+func (h *searchProviderHandler) GetReindexStatus(ctx context.Context, in *GetReindexStatusRequest, out *GetReindexStatusResponse) error {
+	return h.SearchProviderHandler.GetReindexStatus(ctx, in, out)
+}
+
 // Api Endpoints for IndexProvider service
 
 func NewIndexProviderEndpoints() []*api.Endpoint {
@@ -75,6 +75,58 @@ func (h *webSearchProviderHandler) IndexSpace(w http.ResponseWriter, r *http.Req
 	render.JSON(w, r, resp)
 }
 
+func (h *webSearchProviderHandler) Reindex(w http.ResponseWriter, r *http.Request) {
+	req := &ReindexRequest{}
+	resp := &ReindexResponse{}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	if err := h.h.Reindex(
+		r.Context(),
+		req,
+		resp,
+	); err != nil {
+		if merr, ok := merrors.As(err); ok && merr.Code == http.StatusNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+func (h *webSearchProviderHandler) GetReindexStatus(w http.ResponseWriter, r *http.Request) {
+	req := &GetReindexStatusRequest{}
+	resp := &GetReindexStatusResponse{}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	if err := h.h.GetReindexStatus(
+		r.Context(),
+		req,
+		resp,
+	); err != nil {
+		if merr, ok := merrors.As(err); ok && merr.Code == http.StatusNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
 func RegisterSearchProviderWeb(r chi.Router, i SearchProviderHandler, middlewares ...func(http.Handler) http.Handler) {
 	handler := &webSearchProviderHandler{
 		r: r,
@@ -83,6 +135,8 @@ func RegisterSearchProviderWeb(r chi.Router, i SearchProviderHandler, middleware
 
 	r.MethodFunc("POST", "/api/v0/search/search", handler.Search)
 	r.MethodFunc("POST", "/api/v0/search/index-space", handler.IndexSpace)
+	r.MethodFunc("POST", "/api/v0/search/reindex", handler.Reindex)
+	r.MethodFunc("POST", "/api/v0/search/reindex-status", handler.GetReindexStatus)
 }
 
 type webIndexProviderHandler struct {
@@ -344,3 +398,147 @@ func (m *IndexSpaceResponse) UnmarshalJSON(b []byte) error {
 }
 
 var _ json.Unmarshaler = (*IndexSpaceResponse)(nil)
+
+// ReindexRequestJSONMarshaler describes the default jsonpb.Marshaler used by all
+// instances of ReindexRequest. This struct is safe to replace or modify but
+// should not be done so concurrently.
+var ReindexRequestJSONMarshaler = new(jsonpb.Marshaler)
+
+// MarshalJSON satisfies the encoding/json Marshaler interface. This method
+// uses the more correct jsonpb package to correctly marshal the message.
+func (m *ReindexRequest) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return json.Marshal(nil)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := ReindexRequestJSONMarshaler.Marshal(buf, m); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+var _ json.Marshaler = (*ReindexRequest)(nil)
+
+// ReindexRequestJSONUnmarshaler describes the default jsonpb.Unmarshaler used by all
+// instances of ReindexRequest. This struct is safe to replace or modify but
+// should not be done so concurrently.
+var ReindexRequestJSONUnmarshaler = new(jsonpb.Unmarshaler)
+
+// UnmarshalJSON satisfies the encoding/json Unmarshaler interface. This method
+// uses the more correct jsonpb package to correctly unmarshal the message.
+func (m *ReindexRequest) UnmarshalJSON(b []byte) error {
+	return ReindexRequestJSONUnmarshaler.Unmarshal(bytes.NewReader(b), m)
+}
+
+var _ json.Unmarshaler = (*ReindexRequest)(nil)
+
+// ReindexResponseJSONMarshaler describes the default jsonpb.Marshaler used by all
+// instances of ReindexResponse. This struct is safe to replace or modify but
+// should not be done so concurrently.
+var ReindexResponseJSONMarshaler = new(jsonpb.Marshaler)
+
+// MarshalJSON satisfies the encoding/json Marshaler interface. This method
+// uses the more correct jsonpb package to correctly marshal the message.
+func (m *ReindexResponse) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return json.Marshal(nil)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := ReindexResponseJSONMarshaler.Marshal(buf, m); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+var _ json.Marshaler = (*ReindexResponse)(nil)
+
+// ReindexResponseJSONUnmarshaler describes the default jsonpb.Unmarshaler used by all
+// instances of ReindexResponse. This struct is safe to replace or modify but
+// should not be done so concurrently.
+var ReindexResponseJSONUnmarshaler = new(jsonpb.Unmarshaler)
+
+// UnmarshalJSON satisfies the encoding/json Unmarshaler interface. This method
+// uses the more correct jsonpb package to correctly unmarshal the message.
+func (m *ReindexResponse) UnmarshalJSON(b []byte) error {
+	return ReindexResponseJSONUnmarshaler.Unmarshal(bytes.NewReader(b), m)
+}
+
+var _ json.Unmarshaler = (*ReindexResponse)(nil)
+
+// GetReindexStatusRequestJSONMarshaler describes the default jsonpb.Marshaler used by all
+// instances of GetReindexStatusRequest. This struct is safe to replace or modify but
+// should not be done so concurrently.
+var GetReindexStatusRequestJSONMarshaler = new(jsonpb.Marshaler)
+
+// MarshalJSON satisfies the encoding/json Marshaler interface. This method
+// uses the more correct jsonpb package to correctly marshal the message.
+func (m *GetReindexStatusRequest) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return json.Marshal(nil)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := GetReindexStatusRequestJSONMarshaler.Marshal(buf, m); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+var _ json.Marshaler = (*GetReindexStatusRequest)(nil)
+
+// GetReindexStatusRequestJSONUnmarshaler describes the default jsonpb.Unmarshaler used by all
+// instances of GetReindexStatusRequest. This struct is safe to replace or modify but
+// should not be done so concurrently.
+var GetReindexStatusRequestJSONUnmarshaler = new(jsonpb.Unmarshaler)
+
+// UnmarshalJSON satisfies the encoding/json Unmarshaler interface. This method
+// uses the more correct jsonpb package to correctly unmarshal the message.
+func (m *GetReindexStatusRequest) UnmarshalJSON(b []byte) error {
+	return GetReindexStatusRequestJSONUnmarshaler.Unmarshal(bytes.NewReader(b), m)
+}
+
+var _ json.Unmarshaler = (*GetReindexStatusRequest)(nil)
+
+// GetReindexStatusResponseJSONMarshaler describes the default jsonpb.Marshaler used by all
+// instances of GetReindexStatusResponse. This struct is safe to replace or modify but
+// should not be done so concurrently.
+var GetReindexStatusResponseJSONMarshaler = new(jsonpb.Marshaler)
+
+// MarshalJSON satisfies the encoding/json Marshaler interface. This method
+// uses the more correct jsonpb package to correctly marshal the message.
+func (m *GetReindexStatusResponse) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return json.Marshal(nil)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := GetReindexStatusResponseJSONMarshaler.Marshal(buf, m); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+var _ json.Marshaler = (*GetReindexStatusResponse)(nil)
+
+// GetReindexStatusResponseJSONUnmarshaler describes the default jsonpb.Unmarshaler used by all
+// instances of GetReindexStatusResponse. This struct is safe to replace or modify but
+// should not be done so concurrently.
+var GetReindexStatusResponseJSONUnmarshaler = new(jsonpb.Unmarshaler)
+
+// UnmarshalJSON satisfies the encoding/json Unmarshaler interface. This method
+// uses the more correct jsonpb package to correctly unmarshal the message.
+func (m *GetReindexStatusResponse) UnmarshalJSON(b []byte) error {
+	return GetReindexStatusResponseJSONUnmarshaler.Unmarshal(bytes.NewReader(b), m)
+}
+
+var _ json.Unmarshaler = (*GetReindexStatusResponse)(nil)
@@ -13,6 +13,10 @@ const (
 	ScannerTypeClamAV ScannerType = "clamav"
 	// ScannerTypeICap defines that icap is used
 	ScannerTypeICap ScannerType = "icap"
+	// ScannerTypeCommand defines that a local executable is used
+	ScannerTypeCommand ScannerType = "command"
+	// ScannerTypeHTTP defines that a http/json scan service is used
+	ScannerTypeHTTP ScannerType = "http"
 )
 
 // MaxScanSizeMode defines the mode of handling files that exceed the maximum scan size
@@ -83,10 +87,12 @@ type Events struct {
 
 // Scanner provides configuration options for the virus scanner
 type Scanner struct {
-	Type ScannerType `yaml:"type" env:"ANTIVIRUS_SCANNER_TYPE" desc:"The antivirus scanner to use. Supported values are 'clamav' and 'icap'." introductionVersion:"1.0.0"`
+	Type ScannerType `yaml:"type" env:"ANTIVIRUS_SCANNER_TYPE" desc:"The antivirus scanner to use. Supported values are 'clamav', 'icap', 'command' and 'http'." introductionVersion:"1.0.0"`
 
-	ClamAV ClamAV // only if Type == clamav
-	ICAP   ICAP   // only if Type == icap
+	ClamAV  ClamAV  // only if Type == clamav
+	ICAP    ICAP    // only if Type == icap
+	Command Command // only if Type == command
+	HTTP    HTTP    // only if Type == http
 }
 
 // ClamAV provides configuration option for clamav
@@ -101,3 +107,18 @@ type ICAP struct {
 	URL     string        `yaml:"url" env:"ANTIVIRUS_ICAP_URL" desc:"URL of the ICAP server." introductionVersion:"1.0.0"`
 	Service string        `yaml:"service" env:"ANTIVIRUS_ICAP_SERVICE" desc:"The name of the ICAP service." introductionVersion:"1.0.0"`
 }
+
+// Command provides configuration options for the command scanner
+type Command struct {
+	Path              string        `yaml:"path" env:"ANTIVIRUS_COMMAND_PATH" desc:"Path to the executable which is used to scan files. The file content is passed to the executable via stdin." introductionVersion:"%%NEXT%%"`
+	Args              []string      `yaml:"args" env:"ANTIVIRUS_COMMAND_ARGS" desc:"A list of arguments which are passed to the executable. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	InfectedExitCodes []int         `yaml:"infected_exit_codes" env:"ANTIVIRUS_COMMAND_INFECTED_EXIT_CODES" desc:"A list of exit codes the executable uses to signal that the file is infected. The exit code 0 always means the file is clean, all other exit codes are treated as errors. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Timeout           time.Duration `yaml:"scan_timeout" env:"ANTIVIRUS_COMMAND_SCAN_TIMEOUT" desc:"Scan timeout for the executable. The process is killed when the timeout is reached. Defaults to '5m' (5 minutes). See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+}
+
+// HTTP provides configuration options for the http scanner
+type HTTP struct {
+	URL      string        `yaml:"url" env:"ANTIVIRUS_HTTP_URL" desc:"URL of the scan endpoint. The file content is sent as request body of a POST request, the response must be a JSON object containing the verdict." introductionVersion:"%%NEXT%%"`
+	Timeout  time.Duration `yaml:"scan_timeout" env:"ANTIVIRUS_HTTP_SCAN_TIMEOUT" desc:"Scan timeout for the http client. Defaults to '5m' (5 minutes). See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Insecure bool          `yaml:"insecure" env:"OC_INSECURE;ANTIVIRUS_HTTP_INSECURE" desc:"Ignore untrusted SSL certificates when connecting to the scan endpoint." introductionVersion:"%%NEXT%%"`
+}
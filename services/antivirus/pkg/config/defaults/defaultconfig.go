@@ -45,6 +45,13 @@ func DefaultConfig() *config.Config {
 				Service: "avscan",
 				Timeout: 5 * time.Minute,
 			},
+			Command: config.Command{
+				InfectedExitCodes: []int{1},
+				Timeout:           5 * time.Minute,
+			},
+			HTTP: config.HTTP{
+				Timeout: 5 * time.Minute,
+			},
 		},
 	}
 }
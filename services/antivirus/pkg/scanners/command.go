@@ -0,0 +1,95 @@
+package scanners
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// waitDelay is the time to wait for the output of the command after it was
+// killed. Wrapper scripts might leave processes behind which keep the output open.
+var waitDelay = 5 * time.Second
+
+// NewCommand returns a Scanner which pipes the file to a local executable
+func NewCommand(path string, args []string, infectedExitCodes []int, timeout time.Duration) (*Command, error) {
+	executable, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrScannerNotReachable, err)
+	}
+
+	return &Command{
+		path:              executable,
+		args:              args,
+		infectedExitCodes: infectedExitCodes,
+		timeout:           timeout,
+	}, nil
+}
+
+// Command is a Scanner based on a local executable,
+// the file is passed via stdin and the verdict is derived from the exit code.
+type Command struct {
+	path              string
+	args              []string
+	infectedExitCodes []int
+	timeout           time.Duration
+}
+
+// Scan to fulfill Scanner interface
+func (s Command) Scan(in Input) (Result, error) {
+	ctx := context.Background()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.path, s.args...)
+	cmd.Stdin = in.Body
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+	// kill all processes started by the command when the scan times out
+	setProcessGroup(cmd)
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return Result{}, fmt.Errorf("%w: %s", ErrScanTimeout, in.Url)
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return Result{
+			ScanTime: time.Now(),
+		}, nil
+	case errors.As(err, &exitErr) && slices.Contains(s.infectedExitCodes, exitErr.ExitCode()):
+		return Result{
+			Infected:    true,
+			Description: firstLine(stdout.String()),
+			ScanTime:    time.Now(),
+		}, nil
+	case errors.As(err, &exitErr):
+		return Result{}, fmt.Errorf("scanner exited with code %d: %s", exitErr.ExitCode(), firstLine(stderr.String()))
+	default:
+		return Result{}, err
+	}
+}
+
+// firstLine returns the first non-empty line of the given output
+func firstLine(s string) string {
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			return line
+		}
+	}
+
+	return ""
+}
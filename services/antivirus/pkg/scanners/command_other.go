@@ -0,0 +1,9 @@
+//go:build !unix
+
+package scanners
+
+import "os/exec"
+
+// setProcessGroup does nothing, only the command itself is killed when the
+// scan times out.
+func setProcessGroup(_ *exec.Cmd) {}
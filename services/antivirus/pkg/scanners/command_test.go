@@ -0,0 +1,84 @@
+package scanners_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencloud-eu/opencloud/services/antivirus/pkg/scanners"
+)
+
+func newScript(t testing.TB, content string) string {
+	p := filepath.Join(t.TempDir(), "scan.sh")
+	require.NoError(t, os.WriteFile(p, []byte("#!/bin/sh\n"+content), 0700))
+	return p
+}
+
+func TestNewCommand(t *testing.T) {
+	t.Run("returns a scanner", func(t *testing.T) {
+		_, err := scanners.NewCommand(newScript(t, "exit 0"), nil, []int{1}, time.Second)
+		assert.NoError(t, err)
+	})
+
+	t.Run("fails if the executable does not exist", func(t *testing.T) {
+		_, err := scanners.NewCommand(filepath.Join(t.TempDir(), "missing"), nil, []int{1}, time.Second)
+		assert.ErrorIs(t, err, scanners.ErrScannerNotReachable)
+	})
+}
+
+func TestCommand_Scan(t *testing.T) {
+	t.Run("passes the body via stdin and the args", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out")
+		scanner, err := scanners.NewCommand(newScript(t, `cat > "$1"`), []string{out}, []int{1}, time.Second)
+		require.NoError(t, err)
+
+		result, err := scanner.Scan(scanners.Input{Body: strings.NewReader("DATA")})
+		assert.NoError(t, err)
+		assert.False(t, result.Infected)
+
+		b, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "DATA", string(b))
+	})
+
+	t.Run("reports infected files", func(t *testing.T) {
+		scanner, err := scanners.NewCommand(newScript(t, "cat > /dev/null\necho\necho 'Win.Test.EICAR_HDB-1'\nexit 3"), nil, []int{1, 3}, time.Second)
+		require.NoError(t, err)
+
+		result, err := scanner.Scan(scanners.Input{Body: strings.NewReader("DATA")})
+		assert.NoError(t, err)
+		assert.True(t, result.Infected)
+		assert.Equal(t, "Win.Test.EICAR_HDB-1", result.Description)
+	})
+
+	t.Run("fails on unknown exit codes", func(t *testing.T) {
+		scanner, err := scanners.NewCommand(newScript(t, "echo 'broken database' >&2\nexit 2"), nil, []int{1}, time.Second)
+		require.NoError(t, err)
+
+		_, err = scanner.Scan(scanners.Input{Body: strings.NewReader("DATA")})
+		assert.ErrorContains(t, err, "broken database")
+	})
+
+	t.Run("aborts after a certain time", func(t *testing.T) {
+		scanner, err := scanners.NewCommand(newScript(t, "exec sleep 10"), nil, []int{1}, 100*time.Millisecond)
+		require.NoError(t, err)
+
+		_, err = scanner.Scan(scanners.Input{Body: strings.NewReader("DATA")})
+		assert.ErrorIs(t, err, scanners.ErrScanTimeout)
+	})
+
+	t.Run("aborts wrappers whose children keep the output open", func(t *testing.T) {
+		scanner, err := scanners.NewCommand(newScript(t, "sleep 10 &\nwait"), nil, []int{1}, 100*time.Millisecond)
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = scanner.Scan(scanners.Input{Body: strings.NewReader("DATA")})
+		assert.ErrorIs(t, err, scanners.ErrScanTimeout)
+		assert.Less(t, time.Since(start), 3*time.Second)
+	})
+}
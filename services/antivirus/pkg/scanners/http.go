@@ -0,0 +1,96 @@
+package scanners
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	rmime "github.com/opencloud-eu/reva/v2/pkg/mime"
+)
+
+// HTTPClient is the interface that wraps the basic Do method
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HTTPVerdict is the JSON document the scan endpoint must respond with
+type HTTPVerdict struct {
+	Infected    bool   `json:"infected"`
+	Description string `json:"description"`
+}
+
+// NewHTTP returns a Scanner talking to a http/json scan endpoint
+func NewHTTP(scanURL string, timeout time.Duration, insecure bool) (HTTP, error) {
+	if _, err := url.ParseRequestURI(scanURL); err != nil {
+		return HTTP{}, err
+	}
+
+	return HTTP{
+		Client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: insecure, //nolint:gosec
+				},
+			},
+		},
+		URL: scanURL,
+	}, nil
+}
+
+// HTTP is responsible for scanning files using a http/json scan endpoint,
+// the file is sent as POST body and the endpoint responds with a HTTPVerdict.
+type HTTP struct {
+	Client HTTPClient
+	URL    string
+}
+
+// Scan scans a file using the http endpoint
+func (s HTTP) Scan(in Input) (Result, error) {
+	req, err := http.NewRequest(http.MethodPost, s.URL, in.Body)
+	if err != nil {
+		return Result{}, err
+	}
+
+	req.ContentLength = in.Size
+	req.Header.Set("Accept", "application/json")
+	if mt := rmime.Detect(path.Ext(in.Name) == "", in.Name); mt != "" {
+		req.Header.Set("Content-Type", mt)
+	}
+	if in.Name != "" {
+		req.Header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": in.Name}))
+	}
+
+	res, err := s.Client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return Result{}, fmt.Errorf("%w: %s", ErrScanTimeout, in.Url)
+		}
+		return Result{}, fmt.Errorf("%w: %w", ErrScannerNotReachable, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("unexpected status code from scanner %v", res.StatusCode)
+	}
+
+	verdict := HTTPVerdict{}
+	if err := json.NewDecoder(res.Body).Decode(&verdict); err != nil {
+		return Result{}, fmt.Errorf("could not decode scanner response: %w", err)
+	}
+
+	return Result{
+		Infected:    verdict.Infected,
+		Description: verdict.Description,
+		ScanTime:    time.Now(),
+	}, nil
+}
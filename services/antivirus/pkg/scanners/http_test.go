@@ -0,0 +1,91 @@
+package scanners_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencloud-eu/opencloud/services/antivirus/pkg/scanners"
+)
+
+func TestHTTP_Scan(t *testing.T) {
+	t.Run("it sends the file with all the details", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.EqualValues(t, 4, r.ContentLength)
+			assert.Equal(t, "application/pdf", r.Header.Get("Content-Type"))
+			assert.Equal(t, `attachment; filename=report.pdf`, r.Header.Get("Content-Disposition"))
+
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, "DATA", string(b))
+
+			_, _ = w.Write([]byte(`{"infected": false}`))
+		}))
+		defer srv.Close()
+
+		scanner, err := scanners.NewHTTP(srv.URL, time.Second, false)
+		require.NoError(t, err)
+
+		result, err := scanner.Scan(scanners.Input{Body: strings.NewReader("DATA"), Size: 4, Name: "report.pdf"})
+		assert.NoError(t, err)
+		assert.False(t, result.Infected)
+	})
+
+	t.Run("it handles virus scan results", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"infected": true, "description": "Win.Test.EICAR_HDB-1"}`))
+		}))
+		defer srv.Close()
+
+		scanner, err := scanners.NewHTTP(srv.URL, time.Second, false)
+		require.NoError(t, err)
+
+		result, err := scanner.Scan(scanners.Input{Body: strings.NewReader("DATA")})
+		assert.NoError(t, err)
+		assert.True(t, result.Infected)
+		assert.Equal(t, "Win.Test.EICAR_HDB-1", result.Description)
+	})
+
+	t.Run("it fails on unexpected responses", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/broken" {
+				_, _ = w.Write([]byte(`<html></html>`))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		scanner, err := scanners.NewHTTP(srv.URL, time.Second, false)
+		require.NoError(t, err)
+
+		_, err = scanner.Scan(scanners.Input{Body: strings.NewReader("DATA")})
+		assert.Error(t, err)
+
+		scanner, err = scanners.NewHTTP(srv.URL+"/broken", time.Second, false)
+		require.NoError(t, err)
+
+		_, err = scanner.Scan(scanners.Input{Body: strings.NewReader("DATA")})
+		assert.Error(t, err)
+	})
+
+	t.Run("aborts after a certain time", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(500 * time.Millisecond)
+		}))
+		defer srv.Close()
+
+		scanner, err := scanners.NewHTTP(srv.URL, 100*time.Millisecond, false)
+		require.NoError(t, err)
+
+		_, err = scanner.Scan(scanners.Input{Body: strings.NewReader("DATA")})
+		assert.ErrorIs(t, err, scanners.ErrScanTimeout)
+	})
+}
@@ -48,6 +48,10 @@ func NewAntivirus(cfg *config.Config, logger log.Logger, tracerProvider trace.Tr
 		scanner, err = scanners.NewClamAV(cfg.Scanner.ClamAV.Socket, cfg.Scanner.ClamAV.Timeout)
 	case config.ScannerTypeICap:
 		scanner, err = scanners.NewICAP(cfg.Scanner.ICAP.URL, cfg.Scanner.ICAP.Service, cfg.Scanner.ICAP.Timeout)
+	case config.ScannerTypeCommand:
+		scanner, err = scanners.NewCommand(cfg.Scanner.Command.Path, cfg.Scanner.Command.Args, cfg.Scanner.Command.InfectedExitCodes, cfg.Scanner.Command.Timeout)
+	case config.ScannerTypeHTTP:
+		scanner, err = scanners.NewHTTP(cfg.Scanner.HTTP.URL, cfg.Scanner.HTTP.Timeout, cfg.Scanner.HTTP.Insecure)
 	}
 	if err != nil {
 		return Antivirus{}, err
@@ -232,6 +236,7 @@ func (av Antivirus) process(ev events.StartPostprocessingStep) (scanners.Result,
 	}
 
 	headers := make(map[string]string)
+	size := ev.Filesize
 	switch {
 	case av.maxScanSize == 0:
 		// there is no size limit
@@ -244,6 +249,7 @@ func (av Antivirus) process(ev events.StartPostprocessingStep) (scanners.Result,
 	case av.config.MaxScanSizeMode == config.MaxScanSizeModePartial && ev.Filesize > av.maxScanSize:
 		// set the range header to only download the first maxScanSize bytes
 		headers["Range"] = fmt.Sprintf("bytes=0-%d", av.maxScanSize-1)
+		size = av.maxScanSize
 	}
 
 	var err error
@@ -265,7 +271,9 @@ func (av Antivirus) process(ev events.StartPostprocessingStep) (scanners.Result,
 
 	av.log.Debug().Str("uploadid", ev.UploadID).Msg("Downloaded file successfully, starting virusscan")
 
-	res, err := av.scanner.Scan(scanners.Input{Body: rrc, Size: int64(ev.Filesize), Url: ev.URL, Name: ev.Filename})
+	// the download might ignore the range header, the scanners must not get more than size bytes
+	body := io.LimitReader(rrc, int64(size))
+	res, err := av.scanner.Scan(scanners.Input{Body: body, Size: int64(size), Url: ev.URL, Name: ev.Filename})
 	if err != nil {
 		av.log.Error().Err(err).Str("uploadid", ev.UploadID).Msg("error scanning file")
 	}
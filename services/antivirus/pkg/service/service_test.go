@@ -0,0 +1,70 @@
+package service
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opencloud-eu/reva/v2/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/antivirus/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/antivirus/pkg/scanners"
+)
+
+func TestAntivirus_Process(t *testing.T) {
+	content := []byte("DATA-AND-MORE")
+
+	scanServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(b), r.ContentLength)
+		assert.Equal(t, "DATA", string(b))
+
+		_, _ = w.Write([]byte(`{"infected": true, "description": "partial"}`))
+	}))
+	defer scanServer.Close()
+
+	scanner, err := scanners.NewHTTP(scanServer.URL, time.Second, false)
+	require.NoError(t, err)
+
+	tests := map[string]http.HandlerFunc{
+		"it scans the requested range of files bigger than the max scan size": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "bytes=0-3", r.Header.Get("Range"))
+			http.ServeContent(w, r, "file.txt", time.Now(), bytes.NewReader(content))
+		},
+		"it truncates downloads which ignore the range": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(content)
+		},
+	}
+
+	for name, download := range tests {
+		t.Run(name, func(t *testing.T) {
+			downloadServer := httptest.NewServer(download)
+			defer downloadServer.Close()
+
+			av := Antivirus{
+				config:      &config.Config{MaxScanSizeMode: config.MaxScanSizeModePartial},
+				log:         log.NopLogger(),
+				scanner:     scanner,
+				maxScanSize: 4,
+				client:      http.DefaultClient,
+			}
+
+			res, err := av.process(events.StartPostprocessingStep{
+				UploadID: "upload-id",
+				URL:      downloadServer.URL,
+				Filename: "file.txt",
+				Filesize: uint64(len(content)),
+			})
+			require.NoError(t, err)
+			assert.True(t, res.Infected)
+			assert.Equal(t, "partial", res.Description)
+		})
+	}
+}
@@ -46,8 +46,7 @@ func Server(cfg *config.Config) *cli.Command {
 			}
 
 			gr.Add(func() error {
-				svc.AuditLoggerFromConfig(ctx, cfg.Auditlog, evts, logger)
-				return nil
+				return svc.AuditLoggerFromConfig(ctx, cfg.Auditlog, evts, logger)
 			}, func(err error) {
 				if err == nil {
 					logger.Info().
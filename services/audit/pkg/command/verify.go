@@ -0,0 +1,136 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
+	"github.com/opencloud-eu/opencloud/services/audit/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/audit/pkg/config/parser"
+	svc "github.com/opencloud-eu/opencloud/services/audit/pkg/service"
+)
+
+// Verify is the entrypoint for the verify command.
+func Verify(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "verify",
+		Usage:     "verify the hash chain of audit log files and report the first broken link",
+		ArgsUsage: "[file...]",
+		Description: "Verifies the given files as one continuous hash chain, rotated files have to be passed from the oldest to the newest. " +
+			"Files with the '.gz' extension are decompressed. The chain has to start with its first record, unless '--after' is given. " +
+			"Without arguments, the configured AUDIT_FILEPATH and the files it was rotated to are verified and the chain has to end " +
+			"with the head persisted in AUDIT_HASH_CHAIN_STATE_FILE.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "key",
+				Usage: "the key used to sign the hash chain, defaults to AUDIT_HASH_CHAIN_KEY",
+			},
+			&cli.StringFlag{
+				Name:  "after",
+				Usage: "start the verification after the record '<seq>:<hash>', if the files with the start of the chain were removed",
+			},
+			&cli.StringFlag{
+				Name:  "state-file",
+				Usage: "check that the chain ends with the head persisted in this file, defaults to AUDIT_HASH_CHAIN_STATE_FILE without arguments",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output as json",
+			},
+		},
+		Before: func(c *cli.Context) error {
+			return configlog.ReturnFatal(parser.ParseConfig(cfg))
+		},
+		Action: func(c *cli.Context) error {
+			files := c.Args().Slice()
+			stateFile := c.String("state-file")
+			if len(files) == 0 {
+				if cfg.Auditlog.FilePath == "" {
+					return errors.New("no file given and AUDIT_FILEPATH is not set")
+				}
+				rotated, err := svc.RotatedFiles(cfg.Auditlog.FilePath)
+				if err != nil {
+					return err
+				}
+				files = append(rotated, cfg.Auditlog.FilePath)
+				if !c.IsSet("state-file") {
+					stateFile = cfg.Auditlog.HashChainStateFile
+				}
+			}
+
+			var head *svc.ChainHead
+			if stateFile != "" {
+				var err error
+				if head, err = svc.ReadChainHead(stateFile); err != nil {
+					return err
+				}
+				if head == nil {
+					return fmt.Errorf("the hash chain state file '%s' does not exist", stateFile)
+				}
+			}
+
+			key := cfg.Auditlog.HashChainKey
+			if c.IsSet("key") {
+				key = c.String("key")
+			}
+
+			v := svc.NewVerifier(key)
+			if after := c.String("after"); after != "" {
+				seq, hash, ok := strings.Cut(after, ":")
+				n, err := strconv.ParseUint(seq, 10, 64)
+				if !ok || err != nil || hash == "" {
+					return fmt.Errorf("invalid record '%s', expected '<seq>:<hash>'", after)
+				}
+				v.StartAfter(svc.ChainHead{Seq: n, Hash: hash})
+			}
+
+			var verr error
+			for _, f := range files {
+				if verr = v.VerifyFile(f); verr != nil {
+					break
+				}
+			}
+			if verr == nil && head != nil {
+				verr = v.VerifyHead(*head)
+			}
+
+			var chainErr *svc.ChainError
+			if verr != nil && !errors.As(verr, &chainErr) {
+				return verr
+			}
+
+			first, last := v.Range()
+			if c.Bool("json") {
+				b, err := json.Marshal(struct {
+					Valid    bool            `json:"valid"`
+					Records  int             `json:"records"`
+					FirstSeq uint64          `json:"first_seq"`
+					LastSeq  uint64          `json:"last_seq"`
+					Error    *svc.ChainError `json:"error,omitempty"`
+				}{
+					Valid:    verr == nil,
+					Records:  v.Records(),
+					FirstSeq: first,
+					LastSeq:  last,
+					Error:    chainErr,
+				})
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+			} else {
+				fmt.Printf("verified %d records with sequence numbers %d to %d\n", v.Records(), first, last)
+			}
+
+			if verr != nil {
+				return cli.Exit(verr.Error(), 1)
+			}
+			return nil
+		},
+	}
+}
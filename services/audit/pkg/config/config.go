@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"github.com/opencloud-eu/opencloud/pkg/shared"
 )
@@ -35,10 +36,50 @@ type Events struct {
 
 // Auditlog holds audit log information
 type Auditlog struct {
-	LogToConsole bool   `yaml:"log_to_console" env:"AUDIT_LOG_TO_CONSOLE" desc:"Logs to stdout if set to 'true'. Independent of the LOG_TO_FILE option." introductionVersion:"1.0.0"`
-	LogToFile    bool   `yaml:"log_to_file" env:"AUDIT_LOG_TO_FILE" desc:"Logs to file if set to 'true'. Independent of the LOG_TO_CONSOLE option." introductionVersion:"1.0.0"`
-	FilePath     string `yaml:"filepath" env:"AUDIT_FILEPATH" desc:"Filepath of the logfile. Mandatory if LOG_TO_FILE is set to 'true'." introductionVersion:"1.0.0"`
-	Format       string `yaml:"format" env:"AUDIT_FORMAT" desc:"Log format. Supported values are '' (empty) and 'json'. Using 'json' is advised, '' (empty) renders the 'minimal' format. See the text description for more details." introductionVersion:"1.0.0"`
+	LogToConsole       bool   `yaml:"log_to_console" env:"AUDIT_LOG_TO_CONSOLE" desc:"Logs to stdout if set to 'true'. Independent of the LOG_TO_FILE option." introductionVersion:"1.0.0"`
+	LogToFile          bool   `yaml:"log_to_file" env:"AUDIT_LOG_TO_FILE" desc:"Logs to file if set to 'true'. Independent of the LOG_TO_CONSOLE option." introductionVersion:"1.0.0"`
+	FilePath           string `yaml:"filepath" env:"AUDIT_FILEPATH" desc:"Filepath of the logfile. Mandatory if LOG_TO_FILE is set to 'true'." introductionVersion:"1.0.0"`
+	Format             string `yaml:"format" env:"AUDIT_FORMAT" desc:"Log format. Supported values are '' (empty) and 'json'. Using 'json' is advised, '' (empty) renders the 'minimal' format. See the text description for more details." introductionVersion:"1.0.0"`
+	LogToSyslog        bool   `yaml:"log_to_syslog" env:"AUDIT_LOG_TO_SYSLOG" desc:"Logs to a syslog server using the RFC 5424 format if set to 'true'. Independent of the other log options." introductionVersion:"%%NEXT%%"`
+	LogToWebhook       bool   `yaml:"log_to_webhook" env:"AUDIT_LOG_TO_WEBHOOK" desc:"Sends the audit log in batches to a HTTP(S) webhook if set to 'true'. Independent of the other log options." introductionVersion:"%%NEXT%%"`
+	HashChain          bool   `yaml:"hash_chain" env:"AUDIT_HASH_CHAIN" desc:"Makes the audit log tamper-evident if set to 'true'. Every record carries a sequence number and a hash chained to the previous record. Requires the 'json' format. See the text description for more details." introductionVersion:"%%NEXT%%"`
+	HashChainKey       string `yaml:"hash_chain_key" env:"AUDIT_HASH_CHAIN_KEY" desc:"An optional secret used to sign the hash chain with HMAC-SHA256. The same key is needed to verify the audit log." introductionVersion:"%%NEXT%%"`
+	HashChainStateFile string `yaml:"hash_chain_state_file" env:"AUDIT_HASH_CHAIN_STATE_FILE" desc:"Path of the file storing the last record of the hash chain. The chain is continued from it after a restart, independent of the configured outputs. If not defined, the root directory derives from $OC_BASE_DATA_PATH/audit." introductionVersion:"%%NEXT%%"`
+
+	FileRotation FileRotation `yaml:"file_rotation"`
+	Syslog       Syslog       `yaml:"syslog"`
+	Webhook      Webhook      `yaml:"webhook"`
+}
+
+// FileRotation configures the rotation of the log file
+type FileRotation struct {
+	MaxSize    string        `yaml:"max_size" env:"AUDIT_FILE_ROTATION_MAX_SIZE" desc:"The size after which the log file is rotated. 0 disables the size based rotation. Usable common abbreviations: [KB, KiB, MB, MiB, GB, GiB, TB, TiB, PB, PiB, EB, EiB], example: 100MB." introductionVersion:"%%NEXT%%"`
+	MaxAge     time.Duration `yaml:"max_age" env:"AUDIT_FILE_ROTATION_MAX_AGE" desc:"The time after which the log file is rotated. 0 disables the time based rotation. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	MaxBackups int           `yaml:"max_backups" env:"AUDIT_FILE_ROTATION_MAX_BACKUPS" desc:"The maximum number of rotated log files to keep. 0 keeps all rotated log files." introductionVersion:"%%NEXT%%"`
+	Compress   bool          `yaml:"compress" env:"AUDIT_FILE_ROTATION_COMPRESS" desc:"Compress rotated log files using gzip." introductionVersion:"%%NEXT%%"`
+}
+
+// Syslog configures the syslog output
+type Syslog struct {
+	Network              string `yaml:"network" env:"AUDIT_SYSLOG_NETWORK" desc:"The network used to connect to the syslog server. Supported values are 'udp', 'tcp' and 'tls'." introductionVersion:"%%NEXT%%"`
+	Address              string `yaml:"address" env:"AUDIT_SYSLOG_ADDRESS" desc:"The address of the syslog server like 'syslog.example.com:514'. Mandatory if AUDIT_LOG_TO_SYSLOG is set to 'true'." introductionVersion:"%%NEXT%%"`
+	Facility             string `yaml:"facility" env:"AUDIT_SYSLOG_FACILITY" desc:"The syslog facility. Supported values are 'user', 'daemon', 'auth', 'authpriv' and 'local0' to 'local7'." introductionVersion:"%%NEXT%%"`
+	AppName              string `yaml:"app_name" env:"AUDIT_SYSLOG_APP_NAME" desc:"The APP-NAME field of the syslog messages." introductionVersion:"%%NEXT%%"`
+	TLSInsecure          bool   `yaml:"tls_insecure" env:"OC_INSECURE;AUDIT_SYSLOG_TLS_INSECURE" desc:"Whether to skip the verification of the syslog server certificate when using 'tls'." introductionVersion:"%%NEXT%%"`
+	TLSRootCACertificate string `yaml:"tls_root_ca_certificate" env:"AUDIT_SYSLOG_TLS_ROOT_CA_CERTIFICATE" desc:"The root CA certificate used to validate the syslog server certificate when using 'tls'." introductionVersion:"%%NEXT%%"`
+}
+
+// Webhook configures the webhook output
+type Webhook struct {
+	URL           string        `yaml:"url" env:"AUDIT_WEBHOOK_URL" desc:"The URL the batches of audit events are posted to. Mandatory if AUDIT_LOG_TO_WEBHOOK is set to 'true'." introductionVersion:"%%NEXT%%"`
+	Authorization string        `yaml:"authorization" env:"AUDIT_WEBHOOK_AUTHORIZATION" desc:"The value of the Authorization header sent to the webhook, e.g. 'Bearer <token>'." introductionVersion:"%%NEXT%%"`
+	BatchSize     int           `yaml:"batch_size" env:"AUDIT_WEBHOOK_BATCH_SIZE" desc:"The maximum number of audit events sent in one request." introductionVersion:"%%NEXT%%"`
+	QueueSize     int           `yaml:"queue_size" env:"AUDIT_WEBHOOK_QUEUE_SIZE" desc:"The maximum number of audit events waiting to be sent. Further events are dropped and the number of dropped events is logged." introductionVersion:"%%NEXT%%"`
+	FlushInterval time.Duration `yaml:"flush_interval" env:"AUDIT_WEBHOOK_FLUSH_INTERVAL" desc:"The maximum time audit events are collected before they are sent. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	MaxRetries    int           `yaml:"max_retries" env:"AUDIT_WEBHOOK_MAX_RETRIES" desc:"The maximum number of retries for a failed request. The batch is dropped and an error is logged afterwards." introductionVersion:"%%NEXT%%"`
+	RetryBackoff  time.Duration `yaml:"retry_backoff" env:"AUDIT_WEBHOOK_RETRY_BACKOFF" desc:"The base for the exponential backoff duration before retrying a failed request. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Timeout       time.Duration `yaml:"timeout" env:"AUDIT_WEBHOOK_TIMEOUT" desc:"The timeout of a single request. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Insecure      bool          `yaml:"insecure" env:"OC_INSECURE;AUDIT_WEBHOOK_INSECURE" desc:"Whether to skip the verification of the webhook server certificate." introductionVersion:"%%NEXT%%"`
 }
 
 // Tracing defines the available tracing configuration.
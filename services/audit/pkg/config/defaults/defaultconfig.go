@@ -1,6 +1,10 @@
 package defaults
 
 import (
+	"path/filepath"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/config/defaults"
 	"github.com/opencloud-eu/opencloud/services/audit/pkg/config"
 )
 
@@ -29,8 +33,22 @@ func DefaultConfig() *config.Config {
 			EnableTLS: false,
 		},
 		Auditlog: config.Auditlog{
-			LogToConsole: true,
-			Format:       "json",
+			LogToConsole:       true,
+			Format:             "json",
+			HashChainStateFile: filepath.Join(defaults.BaseDataPath(), "audit", "hash_chain.json"),
+			Syslog: config.Syslog{
+				Network:  "udp",
+				Facility: "local0",
+				AppName:  "opencloud-audit",
+			},
+			Webhook: config.Webhook{
+				BatchSize:     100,
+				QueueSize:     10000,
+				FlushInterval: 5 * time.Second,
+				MaxRetries:    5,
+				RetryBackoff:  time.Second,
+				Timeout:       10 * time.Second,
+			},
 		},
 	}
 }
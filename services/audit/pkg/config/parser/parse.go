@@ -34,5 +34,14 @@ func ParseConfig(cfg *config.Config) error {
 
 // Validate validates the configuration
 func Validate(cfg *config.Config) error {
+	if cfg.Auditlog.LogToSyslog && cfg.Auditlog.Syslog.Address == "" {
+		return errors.New("the syslog address has to be set when logging to syslog, set AUDIT_SYSLOG_ADDRESS")
+	}
+	if cfg.Auditlog.LogToWebhook && cfg.Auditlog.Webhook.URL == "" {
+		return errors.New("the webhook url has to be set when logging to a webhook, set AUDIT_WEBHOOK_URL")
+	}
+	if cfg.Auditlog.HashChain && cfg.Auditlog.Format != "json" {
+		return errors.New("the hash chain requires the 'json' format, set AUDIT_FORMAT=json")
+	}
 	return nil
 }
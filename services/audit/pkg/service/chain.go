@@ -0,0 +1,313 @@
+package svc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ChainRecord is a record of the hash chained audit log. The hash covers the sequence number,
+// the hash of the previous record and the event. It is a HMAC-SHA256 if the record is signed.
+type ChainRecord struct {
+	Seq      uint64
+	PrevHash string
+	Hash     string
+	Signed   bool
+	Event    json.RawMessage
+}
+
+// ChainHead identifies the last record of a hash chain
+type ChainHead struct {
+	Seq  uint64
+	Hash string
+}
+
+// Chain links the audit events to a tamper-evident hash chain
+type Chain struct {
+	key []byte
+
+	mu        sync.Mutex
+	seq       uint64
+	hash      string
+	statePath string
+}
+
+// NewChain returns a new Chain, the records are signed if a key is given
+func NewChain(key string) *Chain {
+	return &Chain{key: []byte(key)}
+}
+
+// PersistHead stores the head of the chain in the file at path after every record.
+// If the file exists, the chain continues after the head stored in it.
+func (c *Chain) PersistHead(path string) error {
+	head, err := ReadChainHead(path)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if head != nil {
+		c.seq, c.hash = head.Seq, head.Hash
+	}
+	c.statePath = path
+	return nil
+}
+
+// Marshaller returns a Marshaller wrapping the events marshalled by m into chained records
+func (c *Chain) Marshaller(m Marshaller) Marshaller {
+	return func(ev interface{}) ([]byte, error) {
+		b, err := m(ev)
+		if err != nil {
+			return nil, err
+		}
+		// use the same representation of the event as the marshalled record
+		event, err := json.Marshal(json.RawMessage(b))
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		r := ChainRecord{
+			Seq:      c.seq + 1,
+			PrevHash: c.hash,
+			Signed:   len(c.key) > 0,
+			Event:    event,
+		}
+		r.Hash = chainHash(c.key, r)
+
+		out, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		if c.statePath != "" {
+			// the record is dropped if the head can't be stored, the chain would fork after a restart otherwise
+			if err := writeChainHead(c.statePath, ChainHead{Seq: r.Seq, Hash: r.Hash}); err != nil {
+				return nil, fmt.Errorf("cannot persist the head of the hash chain: %w", err)
+			}
+		}
+		c.seq, c.hash = r.Seq, r.Hash
+		return out, nil
+	}
+}
+
+func chainHash(key []byte, r ChainRecord) string {
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	_, _ = fmt.Fprintf(h, "%d\n%s\n", r.Seq, r.PrevHash)
+	_, _ = h.Write(r.Event)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ChainError describes the first broken link of a hash chain
+type ChainError struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Seq    uint64 `json:"seq"`
+	Reason string `json:"reason"`
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("broken hash chain in '%s' at line %d (sequence number %d): %s", e.File, e.Line, e.Seq, e.Reason)
+}
+
+// Verifier checks the links of hash chained audit logs. Subsequent calls
+// to Verify continue the chain, so rotated files can be verified in order.
+// The chain has to start with the first record ever written, unless the
+// verification is anchored to a later record with StartAfter.
+type Verifier struct {
+	key []byte
+
+	prev     ChainHead
+	records  int
+	firstSeq uint64
+	file     string
+	line     int
+}
+
+// NewVerifier returns a new Verifier, the key is needed for signed records
+func NewVerifier(key string) *Verifier {
+	return &Verifier{key: []byte(key)}
+}
+
+// StartAfter anchors the verification to the given record, which is needed if the
+// files with the start of the chain were removed.
+func (v *Verifier) StartAfter(head ChainHead) {
+	v.prev = head
+}
+
+// Records returns the number of verified records
+func (v *Verifier) Records() int {
+	return v.records
+}
+
+// Range returns the first and last verified sequence numbers
+func (v *Verifier) Range() (uint64, uint64) {
+	if v.records == 0 {
+		return 0, 0
+	}
+	return v.firstSeq, v.prev.Seq
+}
+
+// VerifyHead checks that the verified chain ends with the given head, so records removed
+// from the end of the log are detected.
+func (v *Verifier) VerifyHead(head ChainHead) error {
+	if v.prev != head {
+		return &ChainError{File: v.file, Line: v.line, Seq: v.prev.Seq, Reason: fmt.Sprintf("the chain does not end with the persisted head, record %d", head.Seq)}
+	}
+	return nil
+}
+
+// VerifyFile verifies the records of a file, files with the '.gz' extension are decompressed
+func (v *Verifier) VerifyFile(path string) error {
+	r, err := openLogFile(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return v.Verify(r, path)
+}
+
+// Verify walks the records read from r and returns a *ChainError for the first broken link.
+func (v *Verifier) Verify(r io.Reader, name string) error {
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(b)) > 0 {
+			if err := v.verifyRecord(b, name, line); err != nil {
+				return err
+			}
+		}
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		}
+	}
+}
+
+func (v *Verifier) verifyRecord(b []byte, name string, line int) error {
+	var r ChainRecord
+	if err := json.Unmarshal(b, &r); err != nil || r.Seq == 0 || r.Hash == "" {
+		return &ChainError{File: name, Line: line, Reason: "not a hash chained record"}
+	}
+
+	broken := func(reason string, args ...interface{}) error {
+		return &ChainError{File: name, Line: line, Seq: r.Seq, Reason: fmt.Sprintf(reason, args...)}
+	}
+	switch {
+	case r.Signed && len(v.key) == 0:
+		return broken("the record is signed, the key is needed to verify it")
+	case !r.Signed && len(v.key) > 0:
+		return broken("the record is not signed")
+	case r.Seq != v.prev.Seq+1:
+		return broken("expected sequence number %d", v.prev.Seq+1)
+	case r.PrevHash != v.prev.Hash:
+		return broken("the previous hash does not match record %d", v.prev.Seq)
+	case !hmac.Equal([]byte(chainHash(v.key, r)), []byte(r.Hash)):
+		return broken("the hash does not match the record")
+	}
+
+	if v.records == 0 {
+		v.firstSeq = r.Seq
+	}
+	v.prev = ChainHead{Seq: r.Seq, Hash: r.Hash}
+	v.file, v.line = name, line
+	v.records++
+	return nil
+}
+
+// RotatedFiles returns the files the log file at path was rotated to, from the oldest to the newest
+func RotatedFiles(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	files, err := filepath.Glob(strings.TrimSuffix(path, ext) + "-*" + ext + "*")
+	if err != nil {
+		return nil, err
+	}
+	// the names contain the time of the rotation
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadChainHead returns the head of a hash chain persisted in the file at path.
+// It returns nil if the file doesn't exist.
+func ReadChainHead(path string) (*ChainHead, error) {
+	b, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var head ChainHead
+	if err := json.Unmarshal(b, &head); err != nil {
+		return nil, fmt.Errorf("invalid hash chain head in '%s': %w", path, err)
+	}
+	return &head, nil
+}
+
+// writeChainHead replaces the head persisted in the file at path
+func writeChainHead(path string, head ChainHead) error {
+	b, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// openLogFile opens a log file, files with the '.gz' extension are decompressed
+func openLogFile(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) != ".gz" {
+		return f, nil
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return gzipFile{Reader: gz, file: f}, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g gzipFile) Close() error {
+	_ = g.Reader.Close()
+	return g.file.Close()
+}
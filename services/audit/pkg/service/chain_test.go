@@ -0,0 +1,170 @@
+package svc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func chainedLog(t *testing.T, c *Chain, actions ...string) []string {
+	m := c.Marshaller(json.Marshal)
+	lines := make([]string, 0, len(actions))
+	for _, a := range actions {
+		b, err := m(map[string]string{"Action": a, "Message": "<" + a + ">"})
+		require.NoError(t, err)
+		lines = append(lines, string(b))
+	}
+	return lines
+}
+
+func TestChain(t *testing.T) {
+	lines := chainedLog(t, NewChain("secret"), "a", "b", "c", "d")
+
+	var first ChainRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.Equal(t, uint64(1), first.Seq)
+	require.Empty(t, first.PrevHash)
+	require.True(t, first.Signed)
+	require.JSONEq(t, `{"Action":"a","Message":"<a>"}`, string(first.Event))
+
+	tests := []struct {
+		name   string
+		key    string
+		lines  func([]string) []string
+		line   int
+		reason string
+	}{
+		{
+			name:  "valid",
+			key:   "secret",
+			lines: func(l []string) []string { return l },
+		},
+		{
+			name:   "modified event",
+			key:    "secret",
+			lines:  func(l []string) []string { l[2] = strings.Replace(l[2], `"Action":"c"`, `"Action":"x"`, 1); return l },
+			line:   3,
+			reason: "the hash does not match the record",
+		},
+		{
+			name:   "removed record",
+			key:    "secret",
+			lines:  func(l []string) []string { return append(l[:1], l[2:]...) },
+			line:   2,
+			reason: "expected sequence number 2",
+		},
+		{
+			name:   "removed first record",
+			key:    "secret",
+			lines:  func(l []string) []string { return l[1:] },
+			line:   1,
+			reason: "expected sequence number 1",
+		},
+		{
+			name:   "wrong key",
+			key:    "other",
+			lines:  func(l []string) []string { return l },
+			line:   1,
+			reason: "the hash does not match the record",
+		},
+		{
+			name:   "missing key",
+			lines:  func(l []string) []string { return l },
+			line:   1,
+			reason: "the record is signed, the key is needed to verify it",
+		},
+		{
+			name:   "not chained",
+			key:    "secret",
+			lines:  func(l []string) []string { return append(l, `{"Action":"e"}`) },
+			line:   5,
+			reason: "not a hash chained record",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := tt.lines(append([]string(nil), lines...))
+
+			v := NewVerifier(tt.key)
+			err := v.Verify(strings.NewReader(strings.Join(l, "\n")+"\n"), "audit.log")
+			if tt.reason == "" {
+				require.NoError(t, err)
+				require.Equal(t, 4, v.Records())
+				return
+			}
+
+			var chainErr *ChainError
+			require.ErrorAs(t, err, &chainErr)
+			require.Equal(t, tt.line, chainErr.Line)
+			require.Equal(t, tt.reason, chainErr.Reason)
+		})
+	}
+}
+
+func TestChainPersistHead(t *testing.T) {
+	dir := t.TempDir()
+	state := filepath.Join(dir, "state", "hash_chain.json")
+
+	head, err := ReadChainHead(state)
+	require.NoError(t, err)
+	require.Nil(t, head)
+
+	c := NewChain("")
+	require.NoError(t, c.PersistHead(state))
+	first := chainedLog(t, c, "a", "b")
+
+	head, err = ReadChainHead(state)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), head.Seq)
+
+	// the chain continues after a restart, no matter where the records were written to
+	resumed := NewChain("")
+	require.NoError(t, resumed.PersistHead(state))
+	second := chainedLog(t, resumed, "c", "d")
+
+	head, err = ReadChainHead(state)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), head.Seq)
+
+	v := NewVerifier("")
+	require.NoError(t, v.Verify(strings.NewReader(strings.Join(first, "\n")), "audit-2024-01-01T00-00-00.000.log"))
+	require.NoError(t, v.Verify(strings.NewReader(strings.Join(second, "\n")), "audit.log"))
+	require.NoError(t, v.VerifyHead(*head))
+	firstSeq, lastSeq := v.Range()
+	require.Equal(t, uint64(1), firstSeq)
+	require.Equal(t, uint64(4), lastSeq)
+
+	// removing the last record is detected with the persisted head
+	v = NewVerifier("")
+	require.NoError(t, v.Verify(strings.NewReader(strings.Join(append(first, second[0]), "\n")), "audit.log"))
+	var chainErr *ChainError
+	require.ErrorAs(t, v.VerifyHead(*head), &chainErr)
+	require.Equal(t, "the chain does not end with the persisted head, record 4", chainErr.Reason)
+
+	// a file without the start of the chain can be verified after a known record
+	var last ChainRecord
+	require.NoError(t, json.Unmarshal([]byte(first[1]), &last))
+	v = NewVerifier("")
+	v.StartAfter(ChainHead{Seq: last.Seq, Hash: last.Hash})
+	require.NoError(t, v.Verify(bytes.NewReader([]byte(second[0])), "audit.log"))
+}
+
+func TestRotatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"audit.log", "audit-2024-01-02T00-00-00.000.log.gz", "audit-2024-01-01T00-00-00.000.log", "other.log"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+
+	files, err := RotatedFiles(filepath.Join(dir, "audit.log"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "audit-2024-01-01T00-00-00.000.log"),
+		filepath.Join(dir, "audit-2024-01-02T00-00-00.000.log.gz"),
+	}, files)
+}
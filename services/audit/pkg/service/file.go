@@ -0,0 +1,184 @@
+package svc
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opencloud-eu/reva/v2/pkg/bytesize"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/audit/pkg/config"
+)
+
+// _backupTimeFormat is used in the names of rotated files, it sorts lexically
+const _backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// WriteToRotatingFile returns a Log function writing to a file which is rotated
+// when it exceeds the configured size or age. Rotated files are renamed to
+// '<name>-<timestamp><ext>' and optionally compressed.
+func WriteToRotatingFile(path string, cfg config.FileRotation, log log.Logger) (Log, error) {
+	r := &rotatingFile{
+		path:       path,
+		maxAge:     cfg.MaxAge,
+		maxBackups: cfg.MaxBackups,
+		compress:   cfg.Compress,
+		now:        time.Now,
+	}
+
+	if cfg.MaxSize != "" {
+		size, err := bytesize.Parse(cfg.MaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max size of the audit log file: %w", err)
+		}
+		r.maxSize = size.Bytes()
+	}
+
+	return func(content []byte) {
+		if err := r.write(append(content[:len(content):len(content)], '\n')); err != nil {
+			log.Error().Err(err).Msgf("error writing to file '%s'", path)
+		}
+	}, nil
+}
+
+type rotatingFile struct {
+	path       string
+	maxSize    uint64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	now        func() time.Time
+
+	file     *os.File
+	size     uint64
+	openedAt time.Time
+}
+
+func (r *rotatingFile) write(b []byte) error {
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return err
+		}
+	}
+
+	if r.needsRotation(uint64(len(b))) {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.file.Write(b)
+	r.size += uint64(n)
+	return err
+}
+
+func (r *rotatingFile) needsRotation(next uint64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.maxSize > 0 && r.size+next > r.maxSize {
+		return true
+	}
+	return r.maxAge > 0 && r.now().Sub(r.openedAt) >= r.maxAge
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	r.file = f
+	r.size = uint64(info.Size())
+	r.openedAt = r.now()
+	return nil
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	ext := filepath.Ext(r.path)
+	backup := strings.TrimSuffix(r.path, ext) + "-" + r.now().UTC().Format(_backupTimeFormat) + ext
+	if err := os.Rename(r.path, backup); err != nil {
+		return err
+	}
+
+	if r.compress {
+		if err := compressFile(backup); err != nil {
+			return err
+		}
+	}
+
+	if err := r.removeBackups(); err != nil {
+		return err
+	}
+
+	return r.open()
+}
+
+// removeBackups removes the oldest rotated files exceeding the configured number of backups
+func (r *rotatingFile) removeBackups() error {
+	if r.maxBackups <= 0 {
+		return nil
+	}
+
+	ext := filepath.Ext(r.path)
+	backups, err := filepath.Glob(strings.TrimSuffix(r.path, ext) + "-*" + ext + "*")
+	if err != nil {
+		return err
+	}
+	if len(backups) <= r.maxBackups {
+		return nil
+	}
+
+	sort.Strings(backups)
+	for _, b := range backups[:len(backups)-r.maxBackups] {
+		if err := os.Remove(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compressFile replaces the file with a gzip compressed copy
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
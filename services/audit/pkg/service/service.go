@@ -19,7 +19,7 @@ type Log func([]byte)
 type Marshaller func(interface{}) ([]byte, error)
 
 // AuditLoggerFromConfig will start a new AuditLogger generated from the config
-func AuditLoggerFromConfig(ctx context.Context, cfg config.Auditlog, ch <-chan events.Event, log log.Logger) {
+func AuditLoggerFromConfig(ctx context.Context, cfg config.Auditlog, ch <-chan events.Event, log log.Logger) error {
 	var logs []Log
 
 	if cfg.LogToConsole {
@@ -27,11 +27,40 @@ func AuditLoggerFromConfig(ctx context.Context, cfg config.Auditlog, ch <-chan e
 	}
 
 	if cfg.LogToFile {
-		logs = append(logs, WriteToFile(cfg.FilePath, log))
+		if cfg.FileRotation.MaxSize != "" && cfg.FileRotation.MaxSize != "0" || cfg.FileRotation.MaxAge > 0 {
+			l, err := WriteToRotatingFile(cfg.FilePath, cfg.FileRotation, log)
+			if err != nil {
+				return err
+			}
+			logs = append(logs, l)
+		} else {
+			logs = append(logs, WriteToFile(cfg.FilePath, log))
+		}
 	}
 
-	StartAuditLogger(ctx, ch, log, Marshal(cfg.Format, log), logs...)
+	if cfg.LogToSyslog {
+		l, err := WriteToSyslog(cfg.Syslog, log)
+		if err != nil {
+			return err
+		}
+		logs = append(logs, l)
+	}
+
+	if cfg.LogToWebhook {
+		logs = append(logs, WriteToWebhook(ctx, cfg.Webhook, log))
+	}
+
+	marshaller := Marshal(cfg.Format, log)
+	if cfg.HashChain {
+		chain := NewChain(cfg.HashChainKey)
+		if err := chain.PersistHead(cfg.HashChainStateFile); err != nil {
+			return fmt.Errorf("cannot resume the hash chain: %w", err)
+		}
+		marshaller = chain.Marshaller(marshaller)
+	}
 
+	StartAuditLogger(ctx, ch, log, marshaller, logs...)
+	return nil
 }
 
 // StartAuditLogger will block. run in separate go routine
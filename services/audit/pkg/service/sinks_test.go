@@ -0,0 +1,264 @@
+package svc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/audit/pkg/config"
+)
+
+func TestSyslogFormat(t *testing.T) {
+	s := &syslogWriter{
+		network:  "udp",
+		priority: 16*8 + _severityNotice,
+		hostname: "host",
+		appName:  nilValue("opencloud-audit", 48),
+		procID:   "42",
+	}
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+
+	msg := s.format([]byte(`{"Action":"file_delete"}`), ts)
+	require.Equal(t, `<133>1 2024-01-02T03:04:05.000006Z host opencloud-audit 42 file_delete - {"Action":"file_delete"}`, string(msg))
+
+	msg = s.format([]byte("file_delete)\n   user trashed file"), ts)
+	require.Equal(t, "<133>1 2024-01-02T03:04:05.000006Z host opencloud-audit 42 - - file_delete)\n   user trashed file", string(msg))
+
+	s.network = "tcp"
+	msg = s.format([]byte(`x`), ts)
+	require.True(t, strings.HasPrefix(string(msg), "64 <133>1 "))
+	require.Len(t, msg, 64+len("64 "))
+}
+
+func TestWriteToSyslogTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		length, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+		var n int
+		_, _ = fmt.Sscanf(length, "%d ", &n)
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err == nil {
+			received <- string(b)
+		}
+	}()
+
+	write, err := WriteToSyslog(config.Syslog{
+		Network:  "tcp",
+		Address:  l.Addr().String(),
+		Facility: "auth",
+		AppName:  "audit",
+	}, log.NopLogger())
+	require.NoError(t, err)
+
+	write([]byte(`{"Action":"user_created"}`))
+
+	select {
+	case msg := <-received:
+		require.True(t, strings.HasPrefix(msg, "<37>1 "))
+		require.Contains(t, msg, " audit ")
+		require.True(t, strings.HasSuffix(msg, ` user_created - {"Action":"user_created"}`))
+	case <-time.After(5 * time.Second):
+		t.Fatal("no syslog message received")
+	}
+}
+
+func TestWriteToSyslogInvalidConfig(t *testing.T) {
+	_, err := WriteToSyslog(config.Syslog{Network: "udp", Facility: "unknown"}, log.NopLogger())
+	require.Error(t, err)
+
+	_, err = WriteToSyslog(config.Syslog{Network: "unix", Facility: "local0"}, log.NopLogger())
+	require.Error(t, err)
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := &rotatingFile{
+		path:       path,
+		maxSize:    10,
+		maxAge:     time.Hour,
+		maxBackups: 2,
+		compress:   true,
+		now:        func() time.Time { return now },
+	}
+
+	// rotated by size
+	require.NoError(t, r.write([]byte("12345678\n")))
+	now = now.Add(time.Second)
+	require.NoError(t, r.write([]byte("abcdefgh\n")))
+
+	// rotated by age
+	now = now.Add(time.Hour)
+	require.NoError(t, r.write([]byte("ABCDEFGH\n")))
+
+	// exceeds the number of backups
+	now = now.Add(time.Second)
+	require.NoError(t, r.write([]byte("zzzzzzzz\n")))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "zzzzzzzz\n", string(content))
+
+	backups, err := filepath.Glob(filepath.Join(dir, "audit-*.log.gz"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "audit-2024-01-01T01-00-01.000.log.gz"),
+		filepath.Join(dir, "audit-2024-01-01T01-00-02.000.log.gz"),
+	}, backups)
+
+	f, err := os.Open(backups[1])
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err = io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, "ABCDEFGH\n", string(content))
+}
+
+func TestWriteToWebhook(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		batches  [][]json.RawMessage
+		failures = 1
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var batch []json.RawMessage
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		batches = append(batches, batch)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	write := WriteToWebhook(ctx, config.Webhook{
+		URL:           srv.URL,
+		Authorization: "Bearer secret",
+		BatchSize:     2,
+		QueueSize:     10,
+		FlushInterval: time.Hour,
+		MaxRetries:    2,
+		RetryBackoff:  time.Millisecond,
+		Timeout:       5 * time.Second,
+	}, log.NopLogger())
+
+	write([]byte(`{"Action":"a"}`))
+	write([]byte("minimal"))
+	write([]byte(`{"Action":"c"}`))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(batches) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the remaining event is sent on shutdown
+	cancel()
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(batches) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.JSONEq(t, `[{"Action":"a"},"minimal"]`, string(mustMarshal(t, batches[0])))
+	require.JSONEq(t, `[{"Action":"c"}]`, string(mustMarshal(t, batches[1])))
+}
+
+func TestWebhookDropsEventsWhenFull(t *testing.T) {
+	blocked := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-blocked
+	}))
+	defer srv.Close()
+	defer close(blocked)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := newWebhook(ctx, config.Webhook{
+		URL:           srv.URL,
+		BatchSize:     1,
+		QueueSize:     2,
+		FlushInterval: time.Hour,
+		MaxRetries:    5,
+		RetryBackoff:  time.Hour,
+		Timeout:       time.Hour,
+	}, log.NopLogger())
+
+	// the webhook doesn't respond, but the caller is never blocked
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			w.enqueue([]byte(`{"Action":"a"}`))
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("enqueueing blocked")
+	}
+	require.Positive(t, w.dropped.Load())
+}
+
+func TestSyslogWriteDeadline(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+
+	// nobody reads from the server side, the write has to time out
+	s := &syslogWriter{network: "tcp", address: "127.0.0.1:0", timeout: 50 * time.Millisecond, conn: client}
+	err := s.write([]byte("x"), time.Now())
+	require.Error(t, err)
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,183 @@
+package svc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/audit/pkg/config"
+)
+
+// syslog severity of the audit messages
+const _severityNotice = 5
+
+// _syslogTimeout limits connecting to the syslog server and writing a message
+const _syslogTimeout = 10 * time.Second
+
+var _facilities = map[string]int{
+	"user":     1,
+	"daemon":   3,
+	"auth":     4,
+	"authpriv": 10,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// WriteToSyslog returns a Log function sending RFC 5424 messages to a syslog server.
+// Messages sent via 'tcp' and 'tls' are framed using octet counting as described in RFC 6587.
+func WriteToSyslog(cfg config.Syslog, log log.Logger) (Log, error) {
+	facility, ok := _facilities[cfg.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility '%s'", cfg.Facility)
+	}
+
+	hostname, _ := os.Hostname()
+	s := &syslogWriter{
+		network:  cfg.Network,
+		address:  cfg.Address,
+		priority: facility*8 + _severityNotice,
+		hostname: nilValue(hostname, 255),
+		appName:  nilValue(cfg.AppName, 48),
+		procID:   strconv.Itoa(os.Getpid()),
+		timeout:  _syslogTimeout,
+	}
+
+	switch cfg.Network {
+	case "udp", "tcp":
+	case "tls":
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cfg.TLSInsecure, //nolint:gosec
+		}
+		if cfg.TLSRootCACertificate != "" {
+			pem, err := os.ReadFile(cfg.TLSRootCACertificate)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read the syslog root ca certificate: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("cannot parse the syslog root ca certificate '%s'", cfg.TLSRootCACertificate)
+			}
+			tlsConfig.RootCAs = pool
+			tlsConfig.InsecureSkipVerify = false
+		}
+		s.tlsConfig = tlsConfig
+	default:
+		return nil, fmt.Errorf("unknown syslog network '%s'", cfg.Network)
+	}
+
+	return func(content []byte) {
+		if err := s.write(content, time.Now()); err != nil {
+			log.Error().Err(err).Msgf("error writing to syslog '%s'", cfg.Address)
+		}
+	}, nil
+}
+
+type syslogWriter struct {
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	priority  int
+	hostname  string
+	appName   string
+	procID    string
+	timeout   time.Duration
+
+	conn net.Conn
+}
+
+// write sends the message, the connection is reestablished once if sending fails
+func (s *syslogWriter) write(content []byte, t time.Time) error {
+	msg := s.format(content, t)
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if s.conn, err = s.dial(); err != nil {
+				return err
+			}
+		}
+
+		// a stalled server must not block the audit service
+		if err = s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err == nil {
+			if _, err = s.conn.Write(msg); err == nil {
+				return nil
+			}
+		}
+
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+func (s *syslogWriter) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	if s.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, "tcp", s.address, s.tlsConfig)
+	}
+	return dialer.Dial(s.network, s.address)
+}
+
+// format renders the RFC 5424 message: <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *syslogWriter) format(content []byte, t time.Time) []byte {
+	msg := fmt.Sprintf("<%d>1 %s %s %s %s %s - %s",
+		s.priority,
+		t.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		s.hostname,
+		s.appName,
+		s.procID,
+		msgID(content),
+		content,
+	)
+
+	if s.network == "udp" {
+		return []byte(msg)
+	}
+	return []byte(strconv.Itoa(len(msg)) + " " + msg)
+}
+
+// msgID uses the action of json formatted audit events as MSGID
+func msgID(content []byte) string {
+	var ev struct {
+		Action string
+		Event  struct {
+			Action string
+		}
+	}
+	if err := json.Unmarshal(content, &ev); err != nil {
+		return "-"
+	}
+	if ev.Action == "" {
+		// hash chained record
+		return nilValue(ev.Event.Action, 32)
+	}
+	return nilValue(ev.Action, 32)
+}
+
+// nilValue returns the syslog NILVALUE for empty header fields,
+// other values are limited to printable ascii characters and the given length
+func nilValue(s string, maxLen int) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < maxLen; i++ {
+		if s[i] > 32 && s[i] < 127 {
+			b = append(b, s[i])
+		}
+	}
+	if len(b) == 0 {
+		return "-"
+	}
+	return string(b)
+}
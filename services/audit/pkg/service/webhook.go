@@ -0,0 +1,217 @@
+package svc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/audit/pkg/config"
+)
+
+// WriteToWebhook returns a Log function sending the audit events in batches to a webhook.
+// A batch is posted as JSON array once it is full or the flush interval passed, failed
+// requests are retried with exponential backoff. Events are queued without blocking the
+// caller, they are dropped and counted if the queue is full. The remaining events are sent
+// when ctx is done.
+func WriteToWebhook(ctx context.Context, cfg config.Webhook, log log.Logger) Log {
+	return newWebhook(ctx, cfg, log).enqueue
+}
+
+type webhook struct {
+	cfg    config.Webhook
+	client *http.Client
+	log    log.Logger
+
+	// queue holds the events until they are batched
+	queue chan []byte
+	// batches holds the batches until they are sent, retries happen on the sending side
+	batches chan [][]byte
+	// dropped counts the events dropped since it was last reported
+	dropped atomic.Uint64
+}
+
+func newWebhook(ctx context.Context, cfg config.Webhook, log log.Logger) *webhook {
+	queueSize := max(cfg.QueueSize, 1)
+	w := &webhook{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					MinVersion:         tls.VersionTLS12,
+					InsecureSkipVerify: cfg.Insecure, //nolint:gosec
+				},
+			},
+		},
+		log:     log,
+		queue:   make(chan []byte, queueSize),
+		batches: make(chan [][]byte, max(queueSize/max(cfg.BatchSize, 1), 1)),
+	}
+
+	go w.collect(ctx)
+	go w.deliver(ctx)
+	return w
+}
+
+// enqueue adds the event to the queue, it never blocks
+func (w *webhook) enqueue(content []byte) {
+	select {
+	case w.queue <- content:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// collect groups the queued events into batches
+func (w *webhook) collect(ctx context.Context) {
+	defer close(w.batches)
+
+	batchSize := max(w.cfg.BatchSize, 1)
+	interval := w.cfg.FlushInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		select {
+		case w.batches <- batch:
+		default:
+			// the webhook can't keep up
+			w.dropped.Add(uint64(len(batch)))
+		}
+		batch = make([][]byte, 0, batchSize)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// hand over what is left, the sender doesn't retry anymore
+			for {
+				select {
+				case content := <-w.queue:
+					batch = append(batch, content)
+				default:
+					if len(batch) > 0 {
+						w.batches <- batch
+					}
+					w.reportDropped()
+					return
+				}
+			}
+		case content := <-w.queue:
+			batch = append(batch, content)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			w.reportDropped()
+		}
+	}
+}
+
+// deliver sends the batches, failed requests are retried until ctx is done
+func (w *webhook) deliver(ctx context.Context) {
+	for batch := range w.batches {
+		var err error
+		if ctx.Err() == nil {
+			err = w.send(ctx, batch, w.cfg.MaxRetries)
+		} else {
+			// shutting down, send what is left without waiting for retries
+			sendCtx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+			err = w.send(sendCtx, batch, 0)
+			cancel()
+		}
+		if err != nil {
+			w.log.Error().Err(err).Int("events", len(batch)).Msgf("error sending audit events to webhook '%s'", w.cfg.URL)
+		}
+	}
+}
+
+// reportDropped logs the number of events dropped since the last report
+func (w *webhook) reportDropped() {
+	if n := w.dropped.Swap(0); n > 0 {
+		w.log.Error().Uint64("events", n).Msgf("the queue of webhook '%s' is full, dropped audit events", w.cfg.URL)
+	}
+}
+
+// send posts the batch, retrying failed requests until retries is reached or ctx is done
+func (w *webhook) send(ctx context.Context, batch [][]byte, retries int) error {
+	body, err := marshalBatch(batch)
+	if err != nil {
+		return err
+	}
+
+	backoff := w.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err = w.post(ctx, body)
+		if err == nil || attempt >= retries {
+			return err
+		}
+
+		w.log.Debug().Err(err).Int("attempt", attempt+1).Msg("sending audit events to webhook failed, retrying")
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (w *webhook) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.cfg.Authorization != "" {
+		req.Header.Set("Authorization", w.cfg.Authorization)
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code from webhook %d", res.StatusCode)
+	}
+	return nil
+}
+
+// marshalBatch renders the batch as JSON array, events which are not valid JSON
+// like the ones in the 'minimal' format are added as strings
+func marshalBatch(batch [][]byte) ([]byte, error) {
+	items := make([]json.RawMessage, 0, len(batch))
+	for _, content := range batch {
+		if json.Valid(content) {
+			items = append(items, content)
+			continue
+		}
+
+		s, err := json.Marshal(string(content))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, s)
+	}
+	return json.Marshal(items)
+}
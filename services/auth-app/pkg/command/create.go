@@ -14,6 +14,7 @@ import (
 	userpb "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
 	rpc "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
 	typesv1beta1 "github.com/cs3org/go-cs3apis/cs3/types/v1beta1"
+	"github.com/opencloud-eu/opencloud/pkg/apptoken"
 	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
 	"github.com/opencloud-eu/opencloud/pkg/registry"
 	"github.com/opencloud-eu/opencloud/pkg/tracing"
@@ -42,6 +43,26 @@ func Create(cfg *config.Config) *cli.Command {
 				Value: "72h",
 				Usage: "expiration of the app password, e.g. 72h, 1h, 1m, 1s. Default is 72h.",
 			},
+			&cli.BoolFlag{
+				Name:  "read-only",
+				Usage: "only allow requests which don't change anything",
+			},
+			&cli.StringFlag{
+				Name:  "space-id",
+				Usage: "only allow WebDAV access to the space with this id",
+			},
+			&cli.StringFlag{
+				Name:  "path",
+				Usage: "only allow WebDAV access to this folder of the space, requires --space-id",
+			},
+			&cli.StringSliceFlag{
+				Name:  "api",
+				Usage: "only allow access to this api, can be 'webdav', 'graph' or 'ocs'. Can be repeated.",
+			},
+			&cli.StringSliceFlag{
+				Name:  "allowed-ip",
+				Usage: "only allow requests from this ip address or network, e.g. 10.0.0.0/8. Can be repeated.",
+			},
 		},
 		Before: func(_ *cli.Context) error {
 			return configlog.ReturnError(parser.ParseConfig(cfg))
@@ -97,6 +118,17 @@ func Create(cfg *config.Config) *cli.Command {
 				return err
 			}
 
+			scopes, err = apptoken.AddScope(apptoken.Restrictions{
+				ReadOnly:   c.Bool("read-only"),
+				SpaceID:    c.String("space-id"),
+				Path:       c.String("path"),
+				APIs:       c.StringSlice("api"),
+				AllowedIPs: c.StringSlice("allowed-ip"),
+			}, scopes)
+			if err != nil {
+				return err
+			}
+
 			expiry, err := time.ParseDuration(c.String("expiration"))
 			if err != nil {
 				return err
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	applications "github.com/cs3org/go-cs3apis/cs3/auth/applications/v1beta1"
@@ -14,6 +17,7 @@ import (
 	userpb "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
 	rpc "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
 	"github.com/go-chi/chi/v5"
+	"github.com/opencloud-eu/opencloud/pkg/apptoken"
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	"github.com/opencloud-eu/opencloud/pkg/roles"
 	"github.com/opencloud-eu/opencloud/services/auth-app/pkg/config"
@@ -30,10 +34,12 @@ var ErrBadRequest = errors.New("bad request")
 
 // AuthAppToken represents an app token.
 type AuthAppToken struct {
-	Token          string    `json:"token"`
-	ExpirationDate time.Time `json:"expiration_date"`
-	CreatedDate    time.Time `json:"created_date"`
-	Label          string    `json:"label"`
+	Token          string                 `json:"token"`
+	ExpirationDate time.Time              `json:"expiration_date"`
+	CreatedDate    time.Time              `json:"created_date"`
+	LastUsedDate   *time.Time             `json:"last_used_date,omitempty"`
+	Label          string                 `json:"label"`
+	Restrictions   *apptoken.Restrictions `json:"restrictions,omitempty"`
 }
 
 // AuthAppService defines the service interface.
@@ -84,6 +90,12 @@ func (a *AuthAppService) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	ctx := getContext(r)
 	sublog := a.log.With().Str("actor", ctxpkg.ContextMustGetUser(ctx).GetId().GetOpaqueId()).Logger()
 
+	if err := checkUnrestricted(r); err != nil {
+		sublog.Info().Err(err).Msg("restricted app tokens can't manage app tokens")
+		http.Error(w, "restricted app tokens can't manage app tokens", http.StatusForbidden)
+		return
+	}
+
 	gwc, err := a.gws.Next()
 	if err != nil {
 		sublog.Error().Err(err).Msg("error getting gateway client")
@@ -104,6 +116,13 @@ func (a *AuthAppService) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		label = "Generated via API"
 	}
 
+	restrictions, err := restrictionsFromQuery(q)
+	if err != nil {
+		sublog.Info().Err(err).Msg("error parsing restrictions")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Impersonated request
 	userID, userName := q.Get("userID"), q.Get("userName")
 	if userID != "" || userName != "" {
@@ -144,6 +163,13 @@ func (a *AuthAppService) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	scopes, err = apptoken.AddScope(restrictions, scopes)
+	if err != nil {
+		sublog.Error().Err(err).Msg("error adding restrictions scope")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	res, err := gwc.GenerateAppPassword(ctx, &applications.GenerateAppPasswordRequest{
 		TokenScope: scopes,
 		Label:      label,
@@ -223,6 +249,12 @@ func (a *AuthAppService) HandleDelete(w http.ResponseWriter, r *http.Request) {
 	ctx := getContext(r)
 	sublog := a.log.With().Str("actor", ctxpkg.ContextMustGetUser(ctx).GetId().GetOpaqueId()).Logger()
 
+	if err := checkUnrestricted(r); err != nil {
+		sublog.Info().Err(err).Msg("restricted app tokens can't manage app tokens")
+		http.Error(w, "restricted app tokens can't manage app tokens", http.StatusForbidden)
+		return
+	}
+
 	gwc, err := a.gws.Next()
 	if err != nil {
 		sublog.Error().Err(err).Msg("error getting gateway client")
@@ -276,6 +308,19 @@ func (a *AuthAppService) authenticateUser(userID, userName string, gwc gateway.G
 	return metadata.AppendToOutgoingContext(ctx, ctxpkg.TokenHeader, authRes.GetToken()), nil
 }
 
+// checkUnrestricted returns an error if the request was authenticated with a restricted app token.
+// Otherwise such a token could be used to create a token without the restrictions.
+func checkUnrestricted(r *http.Request) error {
+	restrictions, err := apptoken.FromToken(r.Header.Get(ctxpkg.TokenHeader))
+	if err != nil {
+		return err
+	}
+	if !restrictions.IsZero() {
+		return apptoken.ErrForbidden
+	}
+	return nil
+}
+
 func getContext(r *http.Request) context.Context {
 	ctx := r.Context()
 	return metadata.AppendToOutgoingContext(ctx, ctxpkg.TokenHeader, r.Header.Get(ctxpkg.TokenHeader))
@@ -323,11 +368,53 @@ func isAdmin(ctx context.Context, rm *roles.Manager) (bool, error) {
 	return rm.FindPermissionByID(ctx, roleIDs, settings.AccountManagementPermissionID) != nil, nil
 }
 
+// restrictionsFromQuery parses the optional restrictions of a new app token
+func restrictionsFromQuery(q url.Values) (apptoken.Restrictions, error) {
+	r := apptoken.Restrictions{
+		SpaceID:    q.Get("spaceID"),
+		Path:       q.Get("path"),
+		APIs:       splitList(q.Get("apis")),
+		AllowedIPs: splitList(q.Get("allowedIPs")),
+	}
+
+	if v := q.Get("readOnly"); v != "" {
+		readOnly, err := strconv.ParseBool(v)
+		if err != nil {
+			return r, fmt.Errorf("%w: readOnly must be true or false", apptoken.ErrInvalidRestrictions)
+		}
+		r.ReadOnly = readOnly
+	}
+
+	return r, r.Validate()
+}
+
+// splitList splits a comma separated list and drops empty entries
+func splitList(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
 func convert(ap *applications.AppPassword) AuthAppToken {
-	return AuthAppToken{
+	t := AuthAppToken{
 		Token:          ap.GetPassword(),
 		ExpirationDate: utils.TSToTime(ap.GetExpiration()),
 		CreatedDate:    utils.TSToTime(ap.GetCtime()),
 		Label:          ap.GetLabel(),
 	}
+
+	// the update time is set to the creation time and refreshed when the token is used
+	if ap.GetUtime() != nil && utils.TSToUnixNano(ap.GetUtime()) != utils.TSToUnixNano(ap.GetCtime()) {
+		lastUsed := utils.TSToTime(ap.GetUtime())
+		t.LastUsedDate = &lastUsed
+	}
+
+	if r, err := apptoken.FromScopes(ap.GetTokenScope()); err == nil && !r.IsZero() {
+		t.Restrictions = &r
+	}
+	return t
 }
@@ -36,6 +36,52 @@ func (_m *ConnectorService) EXPECT() *ConnectorService_Expecter {
 	return &ConnectorService_Expecter{mock: &_m.Mock}
 }
 
+// GetContainerConnector provides a mock function for the type ConnectorService
+func (_mock *ConnectorService) GetContainerConnector() connector.ContainerConnectorService {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetContainerConnector")
+	}
+
+	var r0 connector.ContainerConnectorService
+	if returnFunc, ok := ret.Get(0).(func() connector.ContainerConnectorService); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(connector.ContainerConnectorService)
+		}
+	}
+	return r0
+}
+
+// ConnectorService_GetContainerConnector_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetContainerConnector'
+type ConnectorService_GetContainerConnector_Call struct {
+	*mock.Call
+}
+
+// GetContainerConnector is a helper method to define mock.On call
+func (_e *ConnectorService_Expecter) GetContainerConnector() *ConnectorService_GetContainerConnector_Call {
+	return &ConnectorService_GetContainerConnector_Call{Call: _e.mock.On("GetContainerConnector")}
+}
+
+func (_c *ConnectorService_GetContainerConnector_Call) Run(run func()) *ConnectorService_GetContainerConnector_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ConnectorService_GetContainerConnector_Call) Return(containerConnectorService connector.ContainerConnectorService) *ConnectorService_GetContainerConnector_Call {
+	_c.Call.Return(containerConnectorService)
+	return _c
+}
+
+func (_c *ConnectorService_GetContainerConnector_Call) RunAndReturn(run func() connector.ContainerConnectorService) *ConnectorService_GetContainerConnector_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetContentConnector provides a mock function for the type ConnectorService
 func (_mock *ConnectorService) GetContentConnector() connector.ContentConnectorService {
 	ret := _mock.Called()
@@ -82,6 +128,52 @@ func (_c *ConnectorService_GetContentConnector_Call) RunAndReturn(run func() con
 	return _c
 }
 
+// GetEcosystemConnector provides a mock function for the type ConnectorService
+func (_mock *ConnectorService) GetEcosystemConnector() connector.EcosystemConnectorService {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEcosystemConnector")
+	}
+
+	var r0 connector.EcosystemConnectorService
+	if returnFunc, ok := ret.Get(0).(func() connector.EcosystemConnectorService); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(connector.EcosystemConnectorService)
+		}
+	}
+	return r0
+}
+
+// ConnectorService_GetEcosystemConnector_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEcosystemConnector'
+type ConnectorService_GetEcosystemConnector_Call struct {
+	*mock.Call
+}
+
+// GetEcosystemConnector is a helper method to define mock.On call
+func (_e *ConnectorService_Expecter) GetEcosystemConnector() *ConnectorService_GetEcosystemConnector_Call {
+	return &ConnectorService_GetEcosystemConnector_Call{Call: _e.mock.On("GetEcosystemConnector")}
+}
+
+func (_c *ConnectorService_GetEcosystemConnector_Call) Run(run func()) *ConnectorService_GetEcosystemConnector_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ConnectorService_GetEcosystemConnector_Call) Return(ecosystemConnectorService connector.EcosystemConnectorService) *ConnectorService_GetEcosystemConnector_Call {
+	_c.Call.Return(ecosystemConnectorService)
+	return _c
+}
+
+func (_c *ConnectorService_GetEcosystemConnector_Call) RunAndReturn(run func() connector.EcosystemConnectorService) *ConnectorService_GetEcosystemConnector_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetFileConnector provides a mock function for the type ConnectorService
 func (_mock *ConnectorService) GetFileConnector() connector.FileConnectorService {
 	ret := _mock.Called()
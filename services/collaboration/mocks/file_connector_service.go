@@ -545,6 +545,74 @@ func (_c *FileConnectorService_RefreshLock_Call) RunAndReturn(run func(ctx conte
 	return _c
 }
 
+// PutUserInfo provides a mock function for the type FileConnectorService
+func (_mock *FileConnectorService) PutUserInfo(ctx context.Context, userInfo string) (*connector.ConnectorResponse, error) {
+	ret := _mock.Called(ctx, userInfo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutUserInfo")
+	}
+
+	var r0 *connector.ConnectorResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*connector.ConnectorResponse, error)); ok {
+		return returnFunc(ctx, userInfo)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *connector.ConnectorResponse); ok {
+		r0 = returnFunc(ctx, userInfo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*connector.ConnectorResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userInfo)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// FileConnectorService_PutUserInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutUserInfo'
+type FileConnectorService_PutUserInfo_Call struct {
+	*mock.Call
+}
+
+// PutUserInfo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userInfo string
+func (_e *FileConnectorService_Expecter) PutUserInfo(ctx interface{}, userInfo interface{}) *FileConnectorService_PutUserInfo_Call {
+	return &FileConnectorService_PutUserInfo_Call{Call: _e.mock.On("PutUserInfo", ctx, userInfo)}
+}
+
+func (_c *FileConnectorService_PutUserInfo_Call) Run(run func(ctx context.Context, userInfo string)) *FileConnectorService_PutUserInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *FileConnectorService_PutUserInfo_Call) Return(connectorResponse *connector.ConnectorResponse, err error) *FileConnectorService_PutUserInfo_Call {
+	_c.Call.Return(connectorResponse, err)
+	return _c
+}
+
+func (_c *FileConnectorService_PutUserInfo_Call) RunAndReturn(run func(ctx context.Context, userInfo string) (*connector.ConnectorResponse, error)) *FileConnectorService_PutUserInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RenameFile provides a mock function for the type FileConnectorService
 func (_mock *FileConnectorService) RenameFile(ctx context.Context, lockID string, target string) (*connector.ConnectorResponse, error) {
 	ret := _mock.Called(ctx, lockID, target)
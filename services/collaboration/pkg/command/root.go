@@ -12,6 +12,7 @@ import (
 func GetCommands(cfg *config.Config) cli.Commands {
 	return []*cli.Command{
 		Server(cfg),
+		Sessions(cfg),
 		Health(cfg),
 		Version(cfg),
 	}
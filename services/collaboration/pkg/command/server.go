@@ -11,6 +11,7 @@ import (
 	microstore "go-micro.dev/v4/store"
 
 	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
+	"github.com/opencloud-eu/opencloud/pkg/kv"
 	"github.com/opencloud-eu/opencloud/pkg/registry"
 	"github.com/opencloud-eu/opencloud/pkg/tracing"
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
@@ -18,9 +19,11 @@ import (
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/connector"
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/helpers"
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/logging"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/server/admin"
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/server/debug"
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/server/grpc"
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/server/http"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/sessions"
 	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
 	"github.com/opencloud-eu/reva/v2/pkg/store"
 )
@@ -89,6 +92,40 @@ func Server(cfg *config.Config) *cli.Command {
 				store.Authentication(cfg.Store.AuthUsername, cfg.Store.AuthPassword),
 			)
 
+			userInfoStore := store.Create(
+				store.Store(cfg.UserInfoStore.Store),
+				microstore.Nodes(cfg.UserInfoStore.Nodes...),
+				microstore.Database(cfg.UserInfoStore.Database),
+				microstore.Table(cfg.UserInfoStore.Table),
+				store.Authentication(cfg.UserInfoStore.AuthUsername, cfg.UserInfoStore.AuthPassword),
+			)
+
+			sessionStore, err := kv.New(kv.Options{
+				Store:        cfg.SessionStore.Store,
+				Nodes:        cfg.SessionStore.Nodes,
+				Database:     cfg.SessionStore.Database,
+				TTL:          cfg.SessionStore.TTL,
+				AuthUsername: cfg.SessionStore.AuthUsername,
+				AuthPassword: cfg.SessionStore.AuthPassword,
+			})
+			if err != nil {
+				logger.Error().Err(err).Msg("could not create the session store")
+				return err
+			}
+			revocationStore, err := kv.New(kv.Options{
+				Store:        cfg.SessionStore.Store,
+				Nodes:        cfg.SessionStore.Nodes,
+				Database:     cfg.SessionStore.Database + "-revoked",
+				TTL:          cfg.SessionStore.RevocationTTL,
+				AuthUsername: cfg.SessionStore.AuthUsername,
+				AuthPassword: cfg.SessionStore.AuthPassword,
+			})
+			if err != nil {
+				logger.Error().Err(err).Msg("could not create the revocation store")
+				return err
+			}
+			sessionRegistry := sessions.NewRegistry(sessionStore, revocationStore, cfg.App.Name, cfg.SessionStore.TTL)
+
 			// start GRPC server
 			grpcServer, teardown, err := grpc.Server(
 				grpc.AppURLs(appUrls),
@@ -144,12 +181,13 @@ func Server(cfg *config.Config) *cli.Command {
 
 			// start HTTP server
 			httpServer, err := http.Server(
-				http.Adapter(connector.NewHttpAdapter(gatewaySelector, cfg, st)),
+				http.Adapter(connector.NewHttpAdapter(gatewaySelector, cfg, st, userInfoStore)),
 				http.Logger(logger),
 				http.Config(cfg),
 				http.Context(ctx),
 				http.TracerProvider(traceProvider),
 				http.Store(st),
+				http.Sessions(sessionRegistry),
 			)
 			if err != nil {
 				logger.Error().Err(err).Str("transport", "http").Msg("Failed to initialize server")
@@ -159,6 +197,27 @@ func Server(cfg *config.Config) *cli.Command {
 				cancel()
 			})
 
+			// start admin server
+			if cfg.Admin.Token == "" {
+				logger.Warn().Str("transport", "admin").Msg("COLLABORATION_ADMIN_TOKEN is not set, the admin API is not started")
+			} else {
+				adminServer, err := admin.Server(
+					admin.Logger(logger),
+					admin.Context(ctx),
+					admin.Config(cfg),
+					admin.WithAdmin(sessions.NewAdmin(gatewaySelector, cfg, sessionRegistry)),
+				)
+				if err != nil {
+					logger.Error().Err(err).Str("transport", "admin").Msg("Failed to initialize server")
+					return err
+				}
+
+				gr.Add(adminServer.ListenAndServe, func(_ error) {
+					_ = adminServer.Shutdown(ctx)
+					cancel()
+				})
+			}
+
 			return gr.Run()
 		},
 	}
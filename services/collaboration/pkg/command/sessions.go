@@ -0,0 +1,161 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/urfave/cli/v2"
+
+	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config/parser"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/server/admin"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/sessions"
+)
+
+// Sessions is the entry point for the sessions command
+func Sessions(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "sessions",
+		Usage: "manage the active WOPI sessions",
+		Subcommands: []*cli.Command{
+			ListSessions(cfg),
+			CloseSession(cfg),
+		},
+	}
+}
+
+// ListSessions cli command to list the active WOPI sessions
+func ListSessions(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "list the active WOPI sessions with their users and locks",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output as json",
+			},
+		},
+		Before: func(c *cli.Context) error {
+			return configlog.ReturnFatal(parser.ParseConfig(cfg))
+		},
+		Action: func(c *cli.Context) error {
+			var list []sessions.Session
+			if err := adminRequest(c.Context, cfg, http.MethodGet, "/api/v0/sessions", &list); err != nil {
+				return err
+			}
+
+			if c.Bool("json") {
+				b, err := json.Marshal(list)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+				return nil
+			}
+
+			table := tablewriter.NewTable(os.Stdout, tablewriter.WithHeaderAutoFormat(tw.Off))
+			table.Header([]string{"Session ID", "Resource ID", "App", "Users", "Lock ID", "Lock holder", "Last activity"})
+			for _, s := range list {
+				_ = table.Append([]string{
+					s.ID,
+					s.ResourceID,
+					s.App,
+					users(s),
+					s.LockID,
+					s.LockHolder,
+					since(s.LastActivity),
+				})
+			}
+			return table.Render()
+		},
+	}
+}
+
+// CloseSession cli command to force-close a WOPI session
+func CloseSession(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "close",
+		Usage: "force-close a WOPI session and release the lock of the file. The users have to open the file again.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "session-id",
+				Aliases:  []string{"s"},
+				Usage:    "the id of the session",
+				Required: true,
+			},
+		},
+		Before: func(c *cli.Context) error {
+			return configlog.ReturnFatal(parser.ParseConfig(cfg))
+		},
+		Action: func(c *cli.Context) error {
+			var result admin.CloseResult
+			if err := adminRequest(c.Context, cfg, http.MethodDelete, "/api/v0/sessions/"+url.PathEscape(c.String("session-id")), &result); err != nil {
+				return err
+			}
+			if result.Closed && !result.Unlocked {
+				return fmt.Errorf("the session was closed, but the lock of the file couldn't be released: %s", result.Error)
+			}
+			return nil
+		},
+	}
+}
+
+// adminRequest sends a request to the admin API of the running collaboration service and decodes the response into v
+func adminRequest(ctx context.Context, cfg *config.Config, method, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, "http://"+cfg.Admin.Addr+path, nil)
+	if err != nil {
+		return err
+	}
+	if cfg.Admin.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.Admin.Token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach the collaboration service: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("collaboration service responded with %d: %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if v == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func users(s sessions.Session) string {
+	names := make([]string, 0, len(s.Users))
+	for _, u := range s.Users {
+		name := u.DisplayName
+		if name == "" {
+			name = u.ID
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ",") + " (" + strconv.Itoa(len(names)) + ")"
+}
+
+func since(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return time.Since(t).Round(time.Second).String() + " ago"
+}
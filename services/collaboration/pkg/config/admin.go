@@ -0,0 +1,7 @@
+package config
+
+// Admin defines the available configuration of the admin API.
+type Admin struct {
+	Addr  string `yaml:"addr" env:"COLLABORATION_ADMIN_ADDR" desc:"The bind address of the HTTP admin API which is used to inspect and force-close the active WOPI sessions." introductionVersion:"%%NEXT%%"`
+	Token string `yaml:"token" env:"COLLABORATION_ADMIN_TOKEN" desc:"Token to secure the HTTP admin API. Requests must send it as bearer token in the Authorization header. The admin API is not started if no token is set. The collaboration CLI commands use the same token." introductionVersion:"%%NEXT%%"`
+}
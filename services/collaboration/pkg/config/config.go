@@ -15,11 +15,16 @@ type Config struct {
 	App     App     `yaml:"app"`
 	Store   Store   `yaml:"store"`
 
+	UserInfoStore UserInfoStore `yaml:"user_info_store"`
+	SessionStore  SessionStore  `yaml:"session_store"`
+
 	TokenManager *TokenManager `yaml:"token_manager"`
 
 	GRPC GRPC `yaml:"grpc"`
 	HTTP HTTP `yaml:"http"`
 
+	Admin Admin `yaml:"admin"`
+
 	Wopi   Wopi   `yaml:"wopi"`
 	CS3Api CS3Api `yaml:"cs3api"`
 
@@ -40,6 +40,18 @@ func DefaultConfig() *config.Config {
 			Table:    "",
 			TTL:      30 * time.Minute,
 		},
+		UserInfoStore: config.UserInfoStore{
+			Store:    "nats-js-kv",
+			Nodes:    []string{"127.0.0.1:9233"},
+			Database: "collaboration-userinfo",
+		},
+		SessionStore: config.SessionStore{
+			Store:         "nats-js-kv",
+			Nodes:         []string{"127.0.0.1:9233"},
+			Database:      "collaboration-sessions",
+			TTL:           30 * time.Minute,
+			RevocationTTL: 24 * time.Hour,
+		},
 		GRPC: config.GRPC{
 			Addr:      "127.0.0.1:9301",
 			Protocol:  "tcp",
@@ -49,6 +61,9 @@ func DefaultConfig() *config.Config {
 			Addr:      "127.0.0.1:9300",
 			Namespace: "eu.opencloud.web",
 		},
+		Admin: config.Admin{
+			Addr: "127.0.0.1:9302",
+		},
 		Debug: config.Debug{
 			Addr:   "127.0.0.1:9304",
 			Token:  "",
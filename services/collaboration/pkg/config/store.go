@@ -12,3 +12,26 @@ type Store struct {
 	AuthUsername string        `yaml:"username" env:"OC_PERSISTENT_STORE_AUTH_USERNAME;COLLABORATION_STORE_AUTH_USERNAME" desc:"The username to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"1.0.0"`
 	AuthPassword string        `yaml:"password" env:"OC_PERSISTENT_STORE_AUTH_PASSWORD;COLLABORATION_STORE_AUTH_PASSWORD" desc:"The password to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"1.0.0"`
 }
+
+// UserInfoStore configures the store for the user info the office applications save via PutUserInfo.
+// The data is kept until it is overwritten, so there is no TTL.
+type UserInfoStore struct {
+	Store        string   `yaml:"store" env:"OC_PERSISTENT_STORE;COLLABORATION_USERINFO_STORE" desc:"The type of the store for the user info. Supported values are: 'memory', 'nats-js-kv', 'redis-sentinel', 'noop'. See the text description for details." introductionVersion:"%%NEXT%%"`
+	Nodes        []string `yaml:"nodes" env:"OC_PERSISTENT_STORE_NODES;COLLABORATION_USERINFO_STORE_NODES" desc:"A list of nodes to access the configured store. This has no effect when 'memory' store is configured. Note that the behaviour how nodes are used is dependent on the library of the configured store. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Database     string   `yaml:"database" env:"COLLABORATION_USERINFO_STORE_DATABASE" desc:"The database name the configured store should use." introductionVersion:"%%NEXT%%"`
+	Table        string   `yaml:"table" env:"COLLABORATION_USERINFO_STORE_TABLE" desc:"The database table the store should use." introductionVersion:"%%NEXT%%"`
+	AuthUsername string   `yaml:"username" env:"OC_PERSISTENT_STORE_AUTH_USERNAME;COLLABORATION_USERINFO_STORE_AUTH_USERNAME" desc:"The username to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+	AuthPassword string   `yaml:"password" env:"OC_PERSISTENT_STORE_AUTH_PASSWORD;COLLABORATION_USERINFO_STORE_AUTH_PASSWORD" desc:"The password to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+}
+
+// SessionStore configures the store for the WOPI sessions and the revoked access tokens. Several
+// instances of the service can only share it with the 'nats-js-kv' store.
+type SessionStore struct {
+	Store         string        `yaml:"store" env:"COLLABORATION_SESSION_STORE" desc:"The type of the store for the WOPI sessions. Supported values are: 'memory' and 'nats-js-kv'. Use 'nats-js-kv' if several instances of the service are running." introductionVersion:"%%NEXT%%"`
+	Nodes         []string      `yaml:"nodes" env:"OC_PERSISTENT_STORE_NODES;COLLABORATION_SESSION_STORE_NODES" desc:"A list of nodes to access the configured store. This has no effect when 'memory' store is configured. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Database      string        `yaml:"database" env:"COLLABORATION_SESSION_STORE_DATABASE" desc:"The prefix of the buckets the sessions and the revoked access tokens are stored in." introductionVersion:"%%NEXT%%"`
+	TTL           time.Duration `yaml:"ttl" env:"COLLABORATION_SESSION_STORE_TTL" desc:"Time after which a session expires if nobody worked on the file. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	RevocationTTL time.Duration `yaml:"revocation_ttl" env:"COLLABORATION_SESSION_STORE_REVOCATION_TTL" desc:"Time the access tokens of force-closed sessions are kept revoked. It must not be shorter than the lifetime of the access tokens. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	AuthUsername  string        `yaml:"username" env:"OC_PERSISTENT_STORE_AUTH_USERNAME;COLLABORATION_SESSION_STORE_AUTH_USERNAME" desc:"The username to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+	AuthPassword  string        `yaml:"password" env:"OC_PERSISTENT_STORE_AUTH_PASSWORD;COLLABORATION_SESSION_STORE_AUTH_PASSWORD" desc:"The password to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+}
@@ -150,9 +150,13 @@ func NewConnectorError(code int, msg string) *ConnectorError {
 // divided into multiple endpoints.
 // The IFileConnector will implement the "File" endpoint
 // The IContentConnector will implement the "File content" endpoint
+// The IContainerConnector will implement the "Containers" endpoint
+// The IEcosystemConnector will implement the "Ecosystem" endpoint
 type ConnectorService interface {
 	GetFileConnector() FileConnectorService
 	GetContentConnector() ContentConnectorService
+	GetContainerConnector() ContainerConnectorService
+	GetEcosystemConnector() EcosystemConnectorService
 }
 
 // Connector will implement the WOPI operations.
@@ -162,18 +166,24 @@ type ConnectorService interface {
 // Available endpoints:
 // * "Files" -> GetFileConnector()
 // * "File contents" -> GetContentConnector()
+// * "Containers" -> GetContainerConnector()
+// * "Ecosystem" -> GetEcosystemConnector()
 //
 // Other endpoints aren't available for now.
 type Connector struct {
-	fileConnector    FileConnectorService
-	contentConnector ContentConnectorService
+	fileConnector      FileConnectorService
+	contentConnector   ContentConnectorService
+	containerConnector ContainerConnectorService
+	ecosystemConnector EcosystemConnectorService
 }
 
 // NewConnector creates a new connector
-func NewConnector(fc FileConnectorService, cc ContentConnectorService) *Connector {
+func NewConnector(fc FileConnectorService, cc ContentConnectorService, ctc ContainerConnectorService, ec EcosystemConnectorService) *Connector {
 	return &Connector{
-		fileConnector:    fc,
-		contentConnector: cc,
+		fileConnector:      fc,
+		contentConnector:   cc,
+		containerConnector: ctc,
+		ecosystemConnector: ec,
 	}
 }
 
@@ -187,6 +197,16 @@ func (c *Connector) GetContentConnector() ContentConnectorService {
 	return c.contentConnector
 }
 
+// GetContainerConnector gets the container connector service associated to this connector
+func (c *Connector) GetContainerConnector() ContainerConnectorService {
+	return c.containerConnector
+}
+
+// GetEcosystemConnector gets the ecosystem connector service associated to this connector
+func (c *Connector) GetEcosystemConnector() EcosystemConnectorService {
+	return c.ecosystemConnector
+}
+
 // getVersion returns a string representation of the timestamp
 func getVersion(timestamp *types.Timestamp) string {
 	return "v" + strconv.FormatUint(timestamp.GetSeconds(), 10) +
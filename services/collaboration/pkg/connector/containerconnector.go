@@ -0,0 +1,527 @@
+package connector
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	appproviderv1beta1 "github.com/cs3org/go-cs3apis/cs3/app/provider/v1beta1"
+	gatewayv1beta1 "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	rpcv1beta1 "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
+	providerv1beta1 "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/helpers"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/wopisrc"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
+	"github.com/opencloud-eu/reva/v2/pkg/storagespace"
+	"github.com/opencloud-eu/reva/v2/pkg/utils"
+	"github.com/rs/zerolog"
+	microstore "go-micro.dev/v4/store"
+)
+
+// ContainerConnectorService is the interface to implement the "Containers"
+// endpoint. Containers are the folders in the spaces, they're used by the
+// WOPI app to browse the files and to create new ones.
+// All operations need a context containing a WOPI context and, optionally,
+// a zerolog logger.
+// Target container is within the WOPI context
+type ContainerConnectorService interface {
+	// CheckContainerInfo will return the information of the target container
+	CheckContainerInfo(ctx context.Context) (*ConnectorResponse, error)
+	// EnumerateChildren will return the files inside the target container.
+	// If extensions are provided, only the files with one of these
+	// extensions will be returned.
+	EnumerateChildren(ctx context.Context, extensions []string) (*ConnectorResponse, error)
+	// CreateChildFileSuggested will create a new empty file in the target
+	// container. The name of the file will be adjusted if a file with the
+	// same name already exists.
+	// The target must be UTF8-encoded.
+	CreateChildFileSuggested(ctx context.Context, target string) (*ConnectorResponse, error)
+	// CreateChildFileRelative will create a new empty file with the exact
+	// name in the target container. If the file already exists, a valid
+	// name will be returned in the "X-WOPI-ValidRelativeTarget" header.
+	// The target must be UTF8-encoded.
+	CreateChildFileRelative(ctx context.Context, target string) (*ConnectorResponse, error)
+}
+
+// ContainerInfo is the response of the CheckContainerInfo operation
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/containers/checkcontainerinfo
+type ContainerInfo struct {
+	// The name of the container without a path
+	Name string `json:"Name"`
+	// A URI to a webpage for the container
+	HostURL string `json:"HostUrl,omitempty"`
+	// A URI to a webpage that allows the user to share the container
+	SharingURL string `json:"SharingUrl,omitempty"`
+	// A Boolean value that indicates the user has permission to create a new container in the container
+	UserCanCreateChildContainer bool `json:"UserCanCreateChildContainer"`
+	// A Boolean value that indicates the user has permission to create a new file in the container
+	UserCanCreateChildFile bool `json:"UserCanCreateChildFile"`
+	// A Boolean value that indicates the user has permission to delete the container
+	UserCanDelete bool `json:"UserCanDelete"`
+	// A Boolean value that indicates the user has permission to rename the container
+	UserCanRename bool `json:"UserCanRename"`
+}
+
+// ChildFile is a file in the response of the EnumerateChildren operation
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/containers/enumeratechildren
+type ChildFile struct {
+	// The name of the file, including the extension
+	Name string `json:"Name"`
+	// The WOPI URL of the file, including the access token
+	URL string `json:"Url"`
+	// The last time the file was modified, in ISO 8601 round-trip format
+	LastModifiedTime string `json:"LastModifiedTime"`
+	// The size of the file in bytes
+	Size int64 `json:"Size"`
+	// The current version of the file
+	Version string `json:"Version"`
+}
+
+// maxShortTokenChildren is the maximum number of files returned by
+// EnumerateChildren if short tokens are used
+const maxShortTokenChildren = 100
+
+// ContainerConnector implements the "Containers" endpoint.
+// Currently, it handles getting the container info, listing its files and
+// creating new files.
+// Note that operations might return any kind of error, not just ConnectorError
+type ContainerConnector struct {
+	gws   pool.Selectable[gatewayv1beta1.GatewayAPIClient]
+	cfg   *config.Config
+	store microstore.Store
+}
+
+// NewContainerConnector creates a new container connector
+func NewContainerConnector(gws pool.Selectable[gatewayv1beta1.GatewayAPIClient], cfg *config.Config, st microstore.Store) *ContainerConnector {
+	return &ContainerConnector{
+		gws:   gws,
+		cfg:   cfg,
+		store: st,
+	}
+}
+
+// CheckContainerInfo returns information about the requested container
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/containers/checkcontainerinfo
+//
+// The context MUST have a WOPI context, otherwise an error will be returned.
+// You can pass a pre-configured zerologger instance through the context that
+// will be used to log messages.
+//
+// If the target isn't a container, a 404 response will be returned.
+// Creating, deleting and renaming containers isn't supported.
+func (c *ContainerConnector) CheckContainerInfo(ctx context.Context) (*ConnectorResponse, error) {
+	wopiContext, err := middleware.WopiContextFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.Ctx(ctx)
+
+	info, response, err := c.statContainer(ctx, wopiContext.FileReference, *logger)
+	if err != nil || response != nil {
+		return response, err
+	}
+
+	ocURL, err := url.Parse(c.cfg.Commons.OpenCloudURL)
+	if err != nil {
+		return nil, err
+	}
+	privateLinkURL := &url.URL{}
+	*privateLinkURL = *ocURL
+	privateLinkURL.Path = path.Join(ocURL.Path, "f", storagespace.FormatResourceID(info.GetId()))
+
+	containerInfo := &ContainerInfo{
+		Name:                   containerName(info),
+		HostURL:                privateLinkURL.String(),
+		SharingURL:             createShareUrl(privateLinkURL),
+		UserCanCreateChildFile: canCreateFiles(wopiContext, info),
+	}
+
+	logger.Debug().Interface("ContainerInfo", containerInfo).Msg("CheckContainerInfo: success")
+	return NewResponseSuccessBody(containerInfo), nil
+}
+
+// EnumerateChildren returns the files in the requested container
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/containers/enumeratechildren
+//
+// The context MUST have a WOPI context, otherwise an error will be returned.
+// You can pass a pre-configured zerologger instance through the context that
+// will be used to log messages.
+//
+// Only files are returned, the containers inside the requested container
+// are skipped. The extensions are compared case-insensitive and with or
+// without the leading dot. Each file gets its own access token in the URL,
+// whose view mode depends on the permissions of the user on that file.
+// Files the user can't download are skipped, because they can only be opened
+// with a view-only token, which can't be issued here.
+//
+// If short tokens are used, every access token is kept in the store, so at
+// most maxShortTokenChildren files are returned.
+func (c *ContainerConnector) EnumerateChildren(ctx context.Context, extensions []string) (*ConnectorResponse, error) {
+	wopiContext, err := middleware.WopiContextFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.Ctx(ctx)
+
+	gwc, err := c.gws.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	listRes, err := gwc.ListContainer(ctx, &providerv1beta1.ListContainerRequest{
+		Ref: wopiContext.FileReference,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("EnumerateChildren: list container failed")
+		return nil, err
+	}
+
+	if listRes.GetStatus().GetCode() != rpcv1beta1.Code_CODE_OK {
+		logger.Error().
+			Str("StatusCode", listRes.GetStatus().GetCode().String()).
+			Str("StatusMsg", listRes.GetStatus().GetMessage()).
+			Msg("EnumerateChildren: list container failed with unexpected status")
+
+		if listRes.GetStatus().GetCode() == rpcv1beta1.Code_CODE_NOT_FOUND {
+			return NewResponse(404), nil
+		}
+		return NewResponse(500), nil
+	}
+
+	children := make([]ChildFile, 0, len(listRes.GetInfos()))
+	for _, info := range listRes.GetInfos() {
+		if info.GetType() != providerv1beta1.ResourceType_RESOURCE_TYPE_FILE {
+			continue
+		}
+
+		name := path.Base(info.GetPath())
+		if !hasExtension(name, extensions) {
+			continue
+		}
+
+		viewMode, ok := childViewMode(wopiContext.ViewMode, info.GetPermissionSet())
+		if !ok {
+			continue
+		}
+
+		if c.cfg.Wopi.ShortTokens && len(children) >= maxShortTokenChildren {
+			logger.Warn().Int("Limit", maxShortTokenChildren).Msg("EnumerateChildren: too many files, the listing is truncated")
+			break
+		}
+
+		childContext := wopiContext
+		childContext.FileReference = &providerv1beta1.Reference{
+			ResourceId: info.GetId(),
+		}
+		childContext.TemplateReference = nil
+		childContext.ViewMode = viewMode
+		childContext.ViewOnlyToken = ""
+
+		wopiSrcURL, err := generateWOPISrc(childContext, c.cfg, c.store, *logger)
+		if err != nil {
+			logger.Error().Err(err).Msg("EnumerateChildren: error generating the WOPISrc parameter")
+			return nil, err
+		}
+
+		children = append(children, ChildFile{
+			Name:             name,
+			URL:              wopiSrcURL.String(),
+			LastModifiedTime: utils.TSToTime(info.GetMtime()).UTC().Format(time.RFC3339),
+			Size:             int64(info.GetSize()),
+			Version:          getVersion(info.GetMtime()),
+		})
+	}
+
+	logger.Debug().Int("Children", len(children)).Msg("EnumerateChildren: success")
+	return NewResponseSuccessBody(map[string]interface{}{
+		"Children": children,
+	}), nil
+}
+
+// CreateChildFileSuggested creates a new empty file in the requested container
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/containers/createchildfile
+//
+// The CreateChildFile operation has 2 variants based on the
+// "X-WOPI-SuggestedTarget" and "X-WOPI-RelativeTarget" headers. This method
+// only implements the first one.
+//
+// The "target" filename must be UTF8-encoded. The conversion between UTF7 and
+// UTF8 must happen outside this function. If the target is just an extension,
+// the file will be named "Untitled" with that extension.
+//
+// The context MUST have a WOPI context, otherwise an error will be returned.
+// You can pass a pre-configured zerologger instance through the context that
+// will be used to log messages.
+//
+// If a file with the suggested name already exists, the name will be prefixed
+// with a random string.
+func (c *ContainerConnector) CreateChildFileSuggested(ctx context.Context, target string) (*ConnectorResponse, error) {
+	if strings.HasPrefix(target, ".") {
+		target = "Untitled" + target
+	}
+	return c.createChildFile(ctx, target, true)
+}
+
+// CreateChildFileRelative creates a new empty file with the exact name in
+// the requested container
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/containers/createchildfile
+//
+// The CreateChildFile operation has 2 variants based on the
+// "X-WOPI-SuggestedTarget" and "X-WOPI-RelativeTarget" headers. This method
+// only implements the second one.
+//
+// The "target" filename must be UTF8-encoded. The conversion between UTF7 and
+// UTF8 must happen outside this function.
+//
+// The context MUST have a WOPI context, otherwise an error will be returned.
+// You can pass a pre-configured zerologger instance through the context that
+// will be used to log messages.
+//
+// Existing files are never overwritten. If a file with the name already
+// exists, a 409 response with a valid name in the
+// "X-WOPI-ValidRelativeTarget" header will be returned.
+func (c *ContainerConnector) CreateChildFileRelative(ctx context.Context, target string) (*ConnectorResponse, error) {
+	return c.createChildFile(ctx, target, false)
+}
+
+func (c *ContainerConnector) createChildFile(ctx context.Context, target string, suggested bool) (*ConnectorResponse, error) {
+	wopiContext, err := middleware.WopiContextFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.Ctx(ctx).With().
+		Str("CreateTarget", target).
+		Logger()
+
+	if wopiContext.ViewMode != appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE {
+		// the access token doesn't allow to create files
+		logger.Error().Str("ViewMode", wopiContext.ViewMode.String()).Msg("CreateChildFile: not allowed in this view mode")
+		return NewResponse(401), nil
+	}
+
+	containerInfo, response, err := c.statContainer(ctx, wopiContext.FileReference, logger)
+	if err != nil || response != nil {
+		return response, err
+	}
+
+	gwc, err := c.gws.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	finalTarget := target
+	for isDone := false; !isDone; {
+		touchRes, err := gwc.TouchFile(ctx, &providerv1beta1.TouchFileRequest{
+			Ref: &providerv1beta1.Reference{
+				ResourceId: containerInfo.GetId(),
+				Path:       utils.MakeRelativePath(finalTarget),
+			},
+		})
+		if err != nil {
+			logger.Error().Err(err).Msg("CreateChildFile: touch file failed")
+			return nil, err
+		}
+
+		switch touchRes.GetStatus().GetCode() {
+		case rpcv1beta1.Code_CODE_OK:
+			isDone = true
+		case rpcv1beta1.Code_CODE_ALREADY_EXISTS:
+			actualFilename, _ := extractFilenameAndPrefix(target)
+			validTarget := generatePrefix() + " " + actualFilename
+			if !suggested {
+				logger.Debug().Msg("CreateChildFile: file already exists")
+				return &ConnectorResponse{
+					Status: 409,
+					Headers: map[string]string{
+						HeaderWopiValidRT: validTarget,
+					},
+				}, nil
+			}
+			// if conflict use a different name and retry.
+			// this should happen only once
+			finalTarget = validTarget
+		case rpcv1beta1.Code_CODE_PERMISSION_DENIED:
+			logger.Error().Msg("CreateChildFile: permission denied")
+			return NewResponse(401), nil
+		default:
+			logger.Error().
+				Str("StatusCode", touchRes.GetStatus().GetCode().String()).
+				Str("StatusMsg", touchRes.GetStatus().GetMessage()).
+				Msg("CreateChildFile: touch file failed with unexpected status")
+			return NewResponse(500), nil
+		}
+	}
+
+	// the new file is referenced by its resource id only
+	newStatRes, err := gwc.Stat(ctx, &providerv1beta1.StatRequest{
+		Ref: &providerv1beta1.Reference{
+			ResourceId: containerInfo.GetId(),
+			Path:       utils.MakeRelativePath(finalTarget),
+		},
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("CreateChildFile: stat failed")
+		return nil, err
+	}
+	if newStatRes.GetStatus().GetCode() != rpcv1beta1.Code_CODE_OK {
+		logger.Error().
+			Str("StatusCode", newStatRes.GetStatus().GetCode().String()).
+			Str("StatusMsg", newStatRes.GetStatus().GetMessage()).
+			Msg("CreateChildFile: stat failed with unexpected status")
+		return NewResponse(500), nil
+	}
+
+	newInfo := newStatRes.GetInfo()
+	wopiContext.FileReference = &providerv1beta1.Reference{
+		ResourceId: newInfo.GetId(),
+	}
+	wopiContext.TemplateReference = nil
+
+	wopiSrcURL, err := generateWOPISrc(wopiContext, c.cfg, c.store, logger)
+	if err != nil {
+		logger.Error().Err(err).Msg("CreateChildFile: error generating the WOPISrc parameter")
+		return nil, err
+	}
+
+	webURL, err := url.Parse(c.cfg.Commons.OpenCloudURL)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug().
+		Str("FinalReference", wopiContext.FileReference.String()).
+		Msg("CreateChildFile: success")
+
+	return NewResponseSuccessBodyNameUrl(
+		finalTarget,
+		wopiSrcURL.String(),
+		createHostUrl("write", webURL, strings.ToLower(c.cfg.App.Name), newInfo),
+		createHostUrl("view", webURL, strings.ToLower(c.cfg.App.Name), newInfo),
+	), nil
+}
+
+// statContainer stats the referenced resource. If the resource can't be
+// found or isn't a container, the response to send will be returned instead.
+func (c *ContainerConnector) statContainer(ctx context.Context, ref *providerv1beta1.Reference, logger zerolog.Logger) (*providerv1beta1.ResourceInfo, *ConnectorResponse, error) {
+	gwc, err := c.gws.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statRes, err := gwc.Stat(ctx, &providerv1beta1.StatRequest{
+		Ref: ref,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("stat failed")
+		return nil, nil, err
+	}
+
+	if statRes.GetStatus().GetCode() != rpcv1beta1.Code_CODE_OK {
+		logger.Error().
+			Str("StatusCode", statRes.GetStatus().GetCode().String()).
+			Str("StatusMsg", statRes.GetStatus().GetMessage()).
+			Msg("stat failed with unexpected status")
+
+		switch statRes.GetStatus().GetCode() {
+		case rpcv1beta1.Code_CODE_NOT_FOUND, rpcv1beta1.Code_CODE_PERMISSION_DENIED:
+			return nil, NewResponse(404), nil
+		}
+		return nil, NewResponse(500), nil
+	}
+
+	if statRes.GetInfo().GetType() != providerv1beta1.ResourceType_RESOURCE_TYPE_CONTAINER {
+		logger.Error().Str("Type", statRes.GetInfo().GetType().String()).Msg("resource is not a container")
+		return nil, NewResponse(404), nil
+	}
+
+	return statRes.GetInfo(), nil, nil
+}
+
+// generateContainerSrc generates the WOPI URL including a new access token
+// for the container referenced in the WOPI context. The reference must only
+// contain the resource id of the container.
+func generateContainerSrc(wopiContext middleware.WopiContext, cfg *config.Config, st microstore.Store, logger zerolog.Logger) (*url.URL, error) {
+	accessToken, _, err := middleware.GenerateWopiToken(wopiContext, cfg, st)
+	if err != nil {
+		logger.Error().Err(err).Msg("generateContainerSrc: failed to generate access token for the container")
+		return nil, err
+	}
+
+	containerRef := helpers.HashResourceId(wopiContext.FileReference.GetResourceId())
+	containerURL, err := wopisrc.GenerateContainerSrc(containerRef, cfg)
+	if err != nil {
+		logger.Error().Err(err).Msg("generateContainerSrc: failed to generate the URL for the container")
+		return nil, err
+	}
+	q := containerURL.Query()
+	q.Add("access_token", accessToken)
+	containerURL.RawQuery = q.Encode()
+	return containerURL, nil
+}
+
+// containerName returns the name of the container. The root of a space is
+// named like the space.
+func containerName(info *providerv1beta1.ResourceInfo) string {
+	if name := path.Base(info.GetPath()); name != "." && name != "/" {
+		return name
+	}
+	if name := info.GetSpace().GetName(); name != "" {
+		return name
+	}
+	return info.GetName()
+}
+
+// canCreateFiles checks if new files can be created in the container
+func canCreateFiles(wopiContext middleware.WopiContext, info *providerv1beta1.ResourceInfo) bool {
+	return wopiContext.ViewMode == appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE &&
+		info.GetPermissionSet().GetInitiateFileUpload()
+}
+
+// childViewMode returns the view mode for a file in the container. It never
+// exceeds the view mode of the container. Files which can't be downloaded
+// aren't accessible without a view-only token, false is returned for them.
+func childViewMode(viewMode appproviderv1beta1.ViewMode, perms *providerv1beta1.ResourcePermissions) (appproviderv1beta1.ViewMode, bool) {
+	if !perms.GetInitiateFileDownload() {
+		return appproviderv1beta1.ViewMode_VIEW_MODE_INVALID, false
+	}
+
+	switch viewMode {
+	case appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE:
+		if perms.GetInitiateFileUpload() {
+			return appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE, true
+		}
+		return appproviderv1beta1.ViewMode_VIEW_MODE_READ_ONLY, true
+	default:
+		return viewMode, true
+	}
+}
+
+// hasExtension checks if the filename has one of the extensions. All
+// filenames match if there are no extensions.
+func hasExtension(filename string, extensions []string) bool {
+	if len(extensions) == 0 {
+		return true
+	}
+
+	ext := strings.ToLower(path.Ext(filename))
+	for _, e := range extensions {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" {
+			continue
+		}
+		if !strings.HasPrefix(e, ".") {
+			e = "." + e
+		}
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
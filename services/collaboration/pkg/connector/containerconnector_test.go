@@ -0,0 +1,380 @@
+package connector_test
+
+import (
+	"context"
+	"net/url"
+
+	appproviderv1beta1 "github.com/cs3org/go-cs3apis/cs3/app/provider/v1beta1"
+	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	userv1beta1 "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	providerv1beta1 "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	typesv1beta1 "github.com/cs3org/go-cs3apis/cs3/types/v1beta1"
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencloud-eu/opencloud/pkg/shared"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/connector"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/helpers"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/graph/mocks"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/status"
+	rjwt "github.com/opencloud-eu/reva/v2/pkg/token/manager/jwt"
+	cs3mocks "github.com/opencloud-eu/reva/v2/tests/cs3mocks/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("ContainerConnector", func() {
+	var (
+		cc              *connector.ContainerConnector
+		gatewayClient   *cs3mocks.GatewayAPIClient
+		gatewaySelector *mocks.Selectable[gateway.GatewayAPIClient]
+		cfg             *config.Config
+		wopiCtx         middleware.WopiContext
+		containerID     *providerv1beta1.ResourceId
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			Commons: &shared.Commons{
+				OpenCloudURL: "https://cloud.opencloud.test",
+			},
+			App: config.App{
+				Name:    "test",
+				Product: "Microsoft",
+			},
+			Wopi: config.Wopi{
+				WopiSrc: "https://wopi.opencloud.test",
+				Secret:  "topsecret",
+			},
+			TokenManager: &config.TokenManager{JWTSecret: "secret"},
+		}
+
+		gatewayClient = cs3mocks.NewGatewayAPIClient(GinkgoT())
+
+		gatewaySelector = mocks.NewSelectable[gateway.GatewayAPIClient](GinkgoT())
+		gatewaySelector.On("Next").Return(gatewayClient, nil)
+		cc = connector.NewContainerConnector(gatewaySelector, cfg, nil)
+
+		tokenManager, err := rjwt.New(map[string]interface{}{
+			"secret":  cfg.TokenManager.JWTSecret,
+			"expires": int64(60 * 60),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		token, err := tokenManager.MintToken(context.Background(), &userv1beta1.User{
+			Id: &userv1beta1.UserId{Idp: "example.com", OpaqueId: "aabbcc"},
+		}, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		containerID = &providerv1beta1.ResourceId{
+			StorageId: "storageid",
+			OpaqueId:  "folderid",
+			SpaceId:   "spaceid",
+		}
+		wopiCtx = middleware.WopiContext{
+			AccessToken: token,
+			FileReference: &providerv1beta1.Reference{
+				ResourceId: containerID,
+			},
+			ViewMode: appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE,
+		}
+	})
+
+	Describe("CheckContainerInfo", func() {
+		It("No valid context", func() {
+			gatewaySelector.EXPECT().Next().Unset()
+			response, err := cc.CheckContainerInfo(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(response).To(BeNil())
+		})
+
+		It("Stat fails status not found", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			gatewayClient.On("Stat", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewNotFound(ctx, "not found"),
+			}, nil)
+
+			response, err := cc.CheckContainerInfo(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(404))
+		})
+
+		It("Not a container", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			gatewayClient.On("Stat", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info: &providerv1beta1.ResourceInfo{
+					Type: providerv1beta1.ResourceType_RESOURCE_TYPE_FILE,
+					Id:   containerID,
+					Path: "/path/to/test.docx",
+				},
+			}, nil)
+
+			response, err := cc.CheckContainerInfo(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(404))
+		})
+
+		It("Success", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			gatewayClient.On("Stat", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info: &providerv1beta1.ResourceInfo{
+					Type: providerv1beta1.ResourceType_RESOURCE_TYPE_CONTAINER,
+					Id:   containerID,
+					Path: "/path/to/folder",
+					PermissionSet: &providerv1beta1.ResourcePermissions{
+						InitiateFileUpload: true,
+					},
+				},
+			}, nil)
+
+			response, err := cc.CheckContainerInfo(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+			Expect(response.Body).To(Equal(&connector.ContainerInfo{
+				Name:                   "folder",
+				HostURL:                "https://cloud.opencloud.test/f/storageid$spaceid%21folderid",
+				SharingURL:             "https://cloud.opencloud.test/f/storageid$spaceid%21folderid?details=sharing",
+				UserCanCreateChildFile: true,
+			}))
+		})
+
+		It("Space root in read only mode", func() {
+			wopiCtx.ViewMode = appproviderv1beta1.ViewMode_VIEW_MODE_READ_ONLY
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			gatewayClient.On("Stat", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info: &providerv1beta1.ResourceInfo{
+					Type:  providerv1beta1.ResourceType_RESOURCE_TYPE_CONTAINER,
+					Id:    containerID,
+					Path:  ".",
+					Space: &providerv1beta1.StorageSpace{Name: "Marketing"},
+					PermissionSet: &providerv1beta1.ResourcePermissions{
+						InitiateFileUpload: true,
+					},
+				},
+			}, nil)
+
+			response, err := cc.CheckContainerInfo(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+			info := response.Body.(*connector.ContainerInfo)
+			Expect(info.Name).To(Equal("Marketing"))
+			Expect(info.UserCanCreateChildFile).To(BeFalse())
+		})
+	})
+
+	Describe("EnumerateChildren", func() {
+		It("No valid context", func() {
+			gatewaySelector.EXPECT().Next().Unset()
+			response, err := cc.EnumerateChildren(context.Background(), nil)
+			Expect(err).To(HaveOccurred())
+			Expect(response).To(BeNil())
+		})
+
+		It("List fails status not found", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			gatewayClient.On("ListContainer", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.ListContainerResponse{
+				Status: status.NewNotFound(ctx, "not found"),
+			}, nil)
+
+			response, err := cc.EnumerateChildren(ctx, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(404))
+		})
+
+		It("Success with filter", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			docID := &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "docid"}
+			gatewayClient.On("ListContainer", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.ListContainerResponse{
+				Status: status.NewOK(ctx),
+				Infos: []*providerv1beta1.ResourceInfo{
+					{
+						Type:  providerv1beta1.ResourceType_RESOURCE_TYPE_FILE,
+						Id:    docID,
+						Path:  "/path/to/folder/Report.DOCX",
+						Size:  uint64(1234),
+						Mtime: &typesv1beta1.Timestamp{Seconds: uint64(1700000000)},
+						PermissionSet: &providerv1beta1.ResourcePermissions{
+							InitiateFileDownload: true,
+						},
+					},
+					{
+						Type: providerv1beta1.ResourceType_RESOURCE_TYPE_FILE,
+						Id:   &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "imageid"},
+						Path: "/path/to/folder/image.png",
+					},
+					{
+						Type: providerv1beta1.ResourceType_RESOURCE_TYPE_CONTAINER,
+						Id:   &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "subfolderid"},
+						Path: "/path/to/folder/sub.docx",
+					},
+				},
+			}, nil)
+
+			response, err := cc.EnumerateChildren(ctx, []string{".docx", "xlsx"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+
+			children := response.Body.(map[string]interface{})["Children"].([]connector.ChildFile)
+			Expect(children).To(HaveLen(1))
+			Expect(children[0].Name).To(Equal("Report.DOCX"))
+			Expect(children[0].Size).To(Equal(int64(1234)))
+			Expect(children[0].LastModifiedTime).To(Equal("2023-11-14T22:13:20Z"))
+			Expect(children[0].Version).To(Equal("v17000000000"))
+
+			childURL, err := url.Parse(children[0].URL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(childURL.Path).To(Equal("/wopi/files/" + helpers.HashResourceId(docID)))
+			Expect(childURL.Query().Get("access_token")).ToNot(BeEmpty())
+		})
+
+		It("Uses the permissions of each file", func() {
+			wopiCtx.ViewOnlyToken = "viewonlytoken"
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			gatewayClient.On("ListContainer", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.ListContainerResponse{
+				Status: status.NewOK(ctx),
+				Infos: []*providerv1beta1.ResourceInfo{
+					{
+						Type: providerv1beta1.ResourceType_RESOURCE_TYPE_FILE,
+						Id:   &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "writable"},
+						Path: "/path/to/folder/writable.docx",
+						PermissionSet: &providerv1beta1.ResourcePermissions{
+							InitiateFileDownload: true,
+							InitiateFileUpload:   true,
+						},
+					},
+					{
+						Type: providerv1beta1.ResourceType_RESOURCE_TYPE_FILE,
+						Id:   &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "readonly"},
+						Path: "/path/to/folder/readonly.docx",
+						PermissionSet: &providerv1beta1.ResourcePermissions{
+							InitiateFileDownload: true,
+						},
+					},
+					{
+						Type: providerv1beta1.ResourceType_RESOURCE_TYPE_FILE,
+						Id:   &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "viewonly"},
+						Path: "/path/to/folder/viewonly.docx",
+						PermissionSet: &providerv1beta1.ResourcePermissions{
+							Stat: true,
+						},
+					},
+				},
+			}, nil)
+
+			response, err := cc.EnumerateChildren(ctx, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+
+			children := response.Body.(map[string]interface{})["Children"].([]connector.ChildFile)
+			Expect(children).To(HaveLen(2))
+
+			viewModes := make([]appproviderv1beta1.ViewMode, 0, len(children))
+			for _, child := range children {
+				childURL, err := url.Parse(child.URL)
+				Expect(err).ToNot(HaveOccurred())
+
+				claims := &middleware.Claims{}
+				_, err = jwt.ParseWithClaims(childURL.Query().Get("access_token"), claims, func(*jwt.Token) (interface{}, error) {
+					return []byte(cfg.Wopi.Secret), nil
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(claims.WopiContext.ViewOnlyToken).To(BeEmpty())
+				viewModes = append(viewModes, claims.WopiContext.ViewMode)
+			}
+			Expect(viewModes).To(Equal([]appproviderv1beta1.ViewMode{
+				appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE,
+				appproviderv1beta1.ViewMode_VIEW_MODE_READ_ONLY,
+			}))
+		})
+	})
+
+	Describe("CreateChildFile", func() {
+		var folderInfo *providerv1beta1.ResourceInfo
+
+		BeforeEach(func() {
+			folderInfo = &providerv1beta1.ResourceInfo{
+				Type: providerv1beta1.ResourceType_RESOURCE_TYPE_CONTAINER,
+				Id:   containerID,
+				Path: "/path/to/folder",
+			}
+		})
+
+		It("Not allowed in read only mode", func() {
+			gatewaySelector.EXPECT().Next().Unset()
+			wopiCtx.ViewMode = appproviderv1beta1.ViewMode_VIEW_MODE_READ_ONLY
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			response, err := cc.CreateChildFileRelative(ctx, "new.docx")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(401))
+		})
+
+		It("Relative conflict", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			gatewayClient.On("Stat", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info:   folderInfo,
+			}, nil)
+			gatewayClient.On("TouchFile", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.TouchFileResponse{
+				Status: status.NewAlreadyExists(ctx, nil, "already exists"),
+			}, nil)
+
+			response, err := cc.CreateChildFileRelative(ctx, "new.docx")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(409))
+			Expect(response.Headers[connector.HeaderWopiValidRT]).To(MatchRegexp(`^[a-zA-Z0-9_-]+ new\.docx$`))
+		})
+
+		It("Suggested conflict", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+			newID := &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "newid"}
+
+			gatewayClient.On("Stat", mock.Anything, mock.MatchedBy(func(req *providerv1beta1.StatRequest) bool {
+				return req.GetRef().GetPath() == ""
+			})).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info:   folderInfo,
+			}, nil)
+			gatewayClient.On("TouchFile", mock.Anything, mock.MatchedBy(func(req *providerv1beta1.TouchFileRequest) bool {
+				return req.GetRef().GetPath() == "./Untitled.docx"
+			})).Times(1).Return(&providerv1beta1.TouchFileResponse{
+				Status: status.NewAlreadyExists(ctx, nil, "already exists"),
+			}, nil)
+			gatewayClient.On("TouchFile", mock.Anything, mock.MatchedBy(func(req *providerv1beta1.TouchFileRequest) bool {
+				return req.GetRef().GetPath() != "./Untitled.docx"
+			})).Times(1).Return(&providerv1beta1.TouchFileResponse{
+				Status: status.NewOK(ctx),
+			}, nil)
+			gatewayClient.On("Stat", mock.Anything, mock.MatchedBy(func(req *providerv1beta1.StatRequest) bool {
+				return req.GetRef().GetPath() != ""
+			})).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info: &providerv1beta1.ResourceInfo{
+					Type: providerv1beta1.ResourceType_RESOURCE_TYPE_FILE,
+					Id:   newID,
+					Path: "/path/to/folder/new.docx",
+				},
+			}, nil)
+
+			response, err := cc.CreateChildFileSuggested(ctx, ".docx")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+
+			body := response.Body.(map[string]interface{})
+			Expect(body["Name"]).To(MatchRegexp(`^[a-zA-Z0-9_-]+ Untitled\.docx$`))
+			Expect(body["Url"]).To(HavePrefix("https://wopi.opencloud.test/wopi/files/" + helpers.HashResourceId(newID) + "?access_token="))
+			Expect(body["HostEditUrl"]).To(ContainSubstring("view_mode=write"))
+		})
+	})
+})
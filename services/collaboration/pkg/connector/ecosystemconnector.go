@@ -0,0 +1,197 @@
+package connector
+
+import (
+	"context"
+
+	gatewayv1beta1 "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	rpcv1beta1 "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
+	providerv1beta1 "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/wopisrc"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
+	"github.com/rs/zerolog"
+	microstore "go-micro.dev/v4/store"
+)
+
+// EcosystemConnectorService is the interface to implement the "Ecosystem"
+// endpoint. The ecosystem is the entry point of the WOPI app to find the
+// containers of the user.
+// All operations need a context containing a WOPI context and, optionally,
+// a zerolog logger.
+// The WOPI context can point to a file or to a container.
+type EcosystemConnectorService interface {
+	// CheckEcosystem will return the capabilities of the ecosystem
+	CheckEcosystem(ctx context.Context) (*ConnectorResponse, error)
+	// GetEcosystem will return the URL of the ecosystem, including an
+	// access token
+	GetEcosystem(ctx context.Context) (*ConnectorResponse, error)
+	// GetRootContainer will return the name and URL of the root container,
+	// which is the root of the space containing the resource of the WOPI
+	// context
+	GetRootContainer(ctx context.Context) (*ConnectorResponse, error)
+}
+
+// EcosystemConnector implements the "Ecosystem" endpoint and the GetEcosystem
+// operation of the "Files" and "Containers" endpoints.
+// Note that operations might return any kind of error, not just ConnectorError
+type EcosystemConnector struct {
+	gws   pool.Selectable[gatewayv1beta1.GatewayAPIClient]
+	cfg   *config.Config
+	store microstore.Store
+}
+
+// NewEcosystemConnector creates a new ecosystem connector
+func NewEcosystemConnector(gws pool.Selectable[gatewayv1beta1.GatewayAPIClient], cfg *config.Config, st microstore.Store) *EcosystemConnector {
+	return &EcosystemConnector{
+		gws:   gws,
+		cfg:   cfg,
+		store: st,
+	}
+}
+
+// CheckEcosystem returns the capabilities of the ecosystem
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/ecosystem/checkecosystem
+//
+// The context MUST have a WOPI context, otherwise an error will be returned.
+func (e *EcosystemConnector) CheckEcosystem(ctx context.Context) (*ConnectorResponse, error) {
+	if _, err := middleware.WopiContextFromCtx(ctx); err != nil {
+		return nil, err
+	}
+
+	return NewResponseSuccessBody(map[string]interface{}{
+		"SupportsContainers": true,
+	}), nil
+}
+
+// GetEcosystem returns the URL of the ecosystem
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/files/getecosystem
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/containers/getecosystem
+//
+// The context MUST have a WOPI context, otherwise an error will be returned.
+// You can pass a pre-configured zerologger instance through the context that
+// will be used to log messages.
+//
+// The access token in the URL is generated for the resource of the WOPI
+// context, so the resource can be found again from the ecosystem.
+func (e *EcosystemConnector) GetEcosystem(ctx context.Context) (*ConnectorResponse, error) {
+	wopiContext, err := middleware.WopiContextFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.Ctx(ctx)
+
+	accessToken, _, err := middleware.GenerateWopiToken(wopiContext, e.cfg, e.store)
+	if err != nil {
+		logger.Error().Err(err).Msg("GetEcosystem: failed to generate access token for the ecosystem")
+		return nil, err
+	}
+
+	ecosystemURL, err := wopisrc.GenerateEcosystemSrc(e.cfg)
+	if err != nil {
+		logger.Error().Err(err).Msg("GetEcosystem: failed to generate the URL for the ecosystem")
+		return nil, err
+	}
+	q := ecosystemURL.Query()
+	q.Add("access_token", accessToken)
+	ecosystemURL.RawQuery = q.Encode()
+
+	logger.Debug().Msg("GetEcosystem: success")
+	return NewResponseSuccessBody(map[string]interface{}{
+		"Url": ecosystemURL.String(),
+	}), nil
+}
+
+// GetRootContainer returns the root container
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/ecosystem/getrootcontainer
+//
+// The context MUST have a WOPI context, otherwise an error will be returned.
+// You can pass a pre-configured zerologger instance through the context that
+// will be used to log messages.
+//
+// The root container is the root of the space containing the resource of the
+// WOPI context. A new access token will be generated for it.
+func (e *EcosystemConnector) GetRootContainer(ctx context.Context) (*ConnectorResponse, error) {
+	wopiContext, err := middleware.WopiContextFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.Ctx(ctx)
+
+	gwc, err := e.gws.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	// stat the resource to get the space it is part of
+	statRes, err := gwc.Stat(ctx, &providerv1beta1.StatRequest{
+		Ref: wopiContext.FileReference,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("GetRootContainer: stat failed")
+		return nil, err
+	}
+	if statRes.GetStatus().GetCode() != rpcv1beta1.Code_CODE_OK {
+		logger.Error().
+			Str("StatusCode", statRes.GetStatus().GetCode().String()).
+			Str("StatusMsg", statRes.GetStatus().GetMessage()).
+			Msg("GetRootContainer: stat failed with unexpected status")
+
+		switch statRes.GetStatus().GetCode() {
+		case rpcv1beta1.Code_CODE_NOT_FOUND, rpcv1beta1.Code_CODE_PERMISSION_DENIED:
+			return NewResponse(404), nil
+		}
+		return NewResponse(500), nil
+	}
+
+	id := statRes.GetInfo().GetId()
+	rootRef := &providerv1beta1.Reference{
+		ResourceId: &providerv1beta1.ResourceId{
+			StorageId: id.GetStorageId(),
+			SpaceId:   id.GetSpaceId(),
+			OpaqueId:  id.GetSpaceId(),
+		},
+	}
+
+	rootStatRes, err := gwc.Stat(ctx, &providerv1beta1.StatRequest{
+		Ref: rootRef,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("GetRootContainer: stat of the space root failed")
+		return nil, err
+	}
+	if rootStatRes.GetStatus().GetCode() != rpcv1beta1.Code_CODE_OK {
+		logger.Error().
+			Str("StatusCode", rootStatRes.GetStatus().GetCode().String()).
+			Str("StatusMsg", rootStatRes.GetStatus().GetMessage()).
+			Msg("GetRootContainer: stat of the space root failed with unexpected status")
+
+		// share recipients aren't allowed to access the space root
+		switch rootStatRes.GetStatus().GetCode() {
+		case rpcv1beta1.Code_CODE_NOT_FOUND, rpcv1beta1.Code_CODE_PERMISSION_DENIED:
+			return NewResponse(404), nil
+		}
+		return NewResponse(500), nil
+	}
+
+	wopiContext.FileReference = rootRef
+	wopiContext.TemplateReference = nil
+
+	containerURL, err := generateContainerSrc(wopiContext, e.cfg, e.store, *logger)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug().
+		Str("RootReference", rootRef.String()).
+		Msg("GetRootContainer: success")
+
+	return NewResponseSuccessBody(map[string]interface{}{
+		"ContainerPointer": map[string]interface{}{
+			"Name": containerName(rootStatRes.GetInfo()),
+			"Url":  containerURL.String(),
+		},
+	}), nil
+}
@@ -0,0 +1,195 @@
+package connector_test
+
+import (
+	"context"
+	"net/url"
+
+	appproviderv1beta1 "github.com/cs3org/go-cs3apis/cs3/app/provider/v1beta1"
+	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	userv1beta1 "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	providerv1beta1 "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencloud-eu/opencloud/pkg/shared"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/connector"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/helpers"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/graph/mocks"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/status"
+	rjwt "github.com/opencloud-eu/reva/v2/pkg/token/manager/jwt"
+	cs3mocks "github.com/opencloud-eu/reva/v2/tests/cs3mocks/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("EcosystemConnector", func() {
+	var (
+		ec              *connector.EcosystemConnector
+		gatewayClient   *cs3mocks.GatewayAPIClient
+		gatewaySelector *mocks.Selectable[gateway.GatewayAPIClient]
+		cfg             *config.Config
+		wopiCtx         middleware.WopiContext
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			Commons: &shared.Commons{
+				OpenCloudURL: "https://cloud.opencloud.test",
+			},
+			App: config.App{
+				Name:    "test",
+				Product: "Microsoft",
+			},
+			Wopi: config.Wopi{
+				WopiSrc: "https://wopi.opencloud.test",
+				Secret:  "topsecret",
+			},
+			TokenManager: &config.TokenManager{JWTSecret: "secret"},
+		}
+
+		gatewayClient = cs3mocks.NewGatewayAPIClient(GinkgoT())
+
+		gatewaySelector = mocks.NewSelectable[gateway.GatewayAPIClient](GinkgoT())
+		ec = connector.NewEcosystemConnector(gatewaySelector, cfg, nil)
+
+		tokenManager, err := rjwt.New(map[string]interface{}{
+			"secret":  cfg.TokenManager.JWTSecret,
+			"expires": int64(60 * 60),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		token, err := tokenManager.MintToken(context.Background(), &userv1beta1.User{
+			Id: &userv1beta1.UserId{Idp: "example.com", OpaqueId: "aabbcc"},
+		}, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		wopiCtx = middleware.WopiContext{
+			AccessToken: token,
+			FileReference: &providerv1beta1.Reference{
+				ResourceId: &providerv1beta1.ResourceId{
+					StorageId: "storageid",
+					OpaqueId:  "fileid",
+					SpaceId:   "spaceid",
+				},
+				Path: ".",
+			},
+			ViewMode: appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE,
+		}
+	})
+
+	Describe("CheckEcosystem", func() {
+		It("No valid context", func() {
+			response, err := ec.CheckEcosystem(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(response).To(BeNil())
+		})
+
+		It("Success", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			response, err := ec.CheckEcosystem(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+			Expect(response.Body).To(HaveKeyWithValue("SupportsContainers", true))
+		})
+	})
+
+	Describe("GetEcosystem", func() {
+		It("Success", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			response, err := ec.GetEcosystem(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+
+			ecosystemURL, err := url.Parse(response.Body.(map[string]interface{})["Url"].(string))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ecosystemURL.Host).To(Equal("wopi.opencloud.test"))
+			Expect(ecosystemURL.Path).To(Equal("/wopi/ecosystem"))
+			Expect(ecosystemURL.Query().Get("access_token")).ToNot(BeEmpty())
+		})
+	})
+
+	Describe("GetRootContainer", func() {
+		BeforeEach(func() {
+			gatewaySelector.On("Next").Return(gatewayClient, nil)
+		})
+
+		It("Stat fails status not found", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			gatewayClient.On("Stat", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewNotFound(ctx, "not found"),
+			}, nil)
+
+			response, err := ec.GetRootContainer(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(404))
+		})
+
+		It("Space root not accessible", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+
+			gatewayClient.On("Stat", mock.Anything, mock.MatchedBy(func(req *providerv1beta1.StatRequest) bool {
+				return req.GetRef().GetResourceId().GetOpaqueId() == "fileid"
+			})).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info: &providerv1beta1.ResourceInfo{
+					Type: providerv1beta1.ResourceType_RESOURCE_TYPE_FILE,
+					Id:   wopiCtx.FileReference.GetResourceId(),
+					Path: "/path/to/test.docx",
+				},
+			}, nil)
+			gatewayClient.On("Stat", mock.Anything, mock.MatchedBy(func(req *providerv1beta1.StatRequest) bool {
+				return req.GetRef().GetResourceId().GetOpaqueId() == "spaceid"
+			})).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewPermissionDenied(ctx, nil, "permission denied"),
+			}, nil)
+
+			response, err := ec.GetRootContainer(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(404))
+		})
+
+		It("Success", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+			rootID := &providerv1beta1.ResourceId{
+				StorageId: "storageid",
+				OpaqueId:  "spaceid",
+				SpaceId:   "spaceid",
+			}
+
+			gatewayClient.On("Stat", mock.Anything, mock.MatchedBy(func(req *providerv1beta1.StatRequest) bool {
+				return req.GetRef().GetResourceId().GetOpaqueId() == "fileid"
+			})).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info: &providerv1beta1.ResourceInfo{
+					Type: providerv1beta1.ResourceType_RESOURCE_TYPE_FILE,
+					Id:   wopiCtx.FileReference.GetResourceId(),
+					Path: "/path/to/test.docx",
+				},
+			}, nil)
+			gatewayClient.On("Stat", mock.Anything, mock.MatchedBy(func(req *providerv1beta1.StatRequest) bool {
+				return req.GetRef().GetResourceId().GetOpaqueId() == "spaceid"
+			})).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info: &providerv1beta1.ResourceInfo{
+					Type:  providerv1beta1.ResourceType_RESOURCE_TYPE_CONTAINER,
+					Id:    rootID,
+					Path:  ".",
+					Space: &providerv1beta1.StorageSpace{Name: "Personal"},
+				},
+			}, nil)
+
+			response, err := ec.GetRootContainer(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+
+			pointer := response.Body.(map[string]interface{})["ContainerPointer"].(map[string]interface{})
+			Expect(pointer["Name"]).To(Equal("Personal"))
+			containerURL, err := url.Parse(pointer["Url"].(string))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(containerURL.Path).To(Equal("/wopi/containers/" + helpers.HashResourceId(rootID)))
+			Expect(containerURL.Query().Get("access_token")).ToNot(BeEmpty())
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -94,23 +95,30 @@ type FileConnectorService interface {
 	// In case of conflict, this method will return the actual lockId in
 	// the file as second return value.
 	RenameFile(ctx context.Context, lockID, target string) (*ConnectorResponse, error)
+	// PutUserInfo will store the user info sent by the WOPI app for the
+	// current user. It will be returned in the CheckFileInfo response of
+	// any file the user opens with the same app.
+	PutUserInfo(ctx context.Context, userInfo string) (*ConnectorResponse, error)
 }
 
 // FileConnector implements the "File" endpoint.
 // Currently, it handles file locks and getting the file info.
 // Note that operations might return any kind of error, not just ConnectorError
 type FileConnector struct {
-	gws   pool.Selectable[gatewayv1beta1.GatewayAPIClient]
-	cfg   *config.Config
-	store microstore.Store
+	gws           pool.Selectable[gatewayv1beta1.GatewayAPIClient]
+	cfg           *config.Config
+	store         microstore.Store
+	userInfoStore microstore.Store
 }
 
-// NewFileConnector creates a new file connector
-func NewFileConnector(gws pool.Selectable[gatewayv1beta1.GatewayAPIClient], cfg *config.Config, st microstore.Store) *FileConnector {
+// NewFileConnector creates a new file connector. The user info store is
+// optional, PutUserInfo isn't supported without it.
+func NewFileConnector(gws pool.Selectable[gatewayv1beta1.GatewayAPIClient], cfg *config.Config, st microstore.Store, userInfoStore microstore.Store) *FileConnector {
 	return &FileConnector{
-		gws:   gws,
-		cfg:   cfg,
-		store: st,
+		gws:           gws,
+		cfg:           cfg,
+		store:         st,
+		userInfoStore: userInfoStore,
 	}
 }
 
@@ -710,8 +718,8 @@ func (f *FileConnector) PutRelativeFileSuggested(ctx context.Context, ccs Conten
 		case 409:
 			// if conflict generate a different name and retry.
 			// this should happen only once
-			actualFilename, _ := f.extractFilenameAndPrefix(target)
-			finalTarget = f.generatePrefix() + " " + actualFilename
+			actualFilename, _ := extractFilenameAndPrefix(target)
+			finalTarget = generatePrefix() + " " + actualFilename
 		default:
 			// TODO: code 400 might happen, what to do?
 			// in other cases, just return the error
@@ -725,7 +733,7 @@ func (f *FileConnector) PutRelativeFileSuggested(ctx context.Context, ccs Conten
 		return nil, err
 	}
 
-	wopiSrcURL, err := f.generateWOPISrc(wopiContext, newLogger)
+	wopiSrcURL, err := generateWOPISrc(wopiContext, f.cfg, f.store, newLogger)
 	if err != nil {
 		logger.Error().Err(err).Msg("PutRelativeFileSuggested: error generating the WOPISrc parameter")
 		return nil, err
@@ -840,7 +848,7 @@ func (f *FileConnector) PutRelativeFileRelative(ctx context.Context, ccs Content
 		}
 		// if conflict generate a different name and retry.
 		// this should happen only once
-		wopiSrcURL, err2 := f.generateWOPISrc(wopiContext, newLogger)
+		wopiSrcURL, err2 := generateWOPISrc(wopiContext, f.cfg, f.store, newLogger)
 		if err2 != nil {
 			newLogger.Error().
 				Err(err2).
@@ -849,8 +857,8 @@ func (f *FileConnector) PutRelativeFileRelative(ctx context.Context, ccs Content
 			return nil, err2
 		}
 
-		actualFilename, _ := f.extractFilenameAndPrefix(target)
-		finalTarget := f.generatePrefix() + " " + actualFilename
+		actualFilename, _ := extractFilenameAndPrefix(target)
+		finalTarget := generatePrefix() + " " + actualFilename
 
 		newLogger.Error().
 			Str("LockID", lockID).
@@ -882,7 +890,7 @@ func (f *FileConnector) PutRelativeFileRelative(ctx context.Context, ccs Content
 		return nil, err
 	}
 
-	wopiSrcURL, err := f.generateWOPISrc(wopiContext, newLogger)
+	wopiSrcURL, err := generateWOPISrc(wopiContext, f.cfg, f.store, newLogger)
 	if err != nil {
 		newLogger.Error().Err(err).Msg("PutRelativeFileRelative: error generating the WOPISrc parameter")
 		return nil, err
@@ -1104,8 +1112,8 @@ func (f *FileConnector) RenameFile(ctx context.Context, lockID, target string) (
 
 			if moveRes.GetStatus().GetCode() == rpcv1beta1.Code_CODE_ALREADY_EXISTS {
 				// try to generate a different name. This should happen only once
-				actualFilename, _ := f.extractFilenameAndPrefix(targetWithExt)
-				finalTarget = f.generatePrefix() + " " + actualFilename
+				actualFilename, _ := extractFilenameAndPrefix(targetWithExt)
+				finalTarget = generatePrefix() + " " + actualFilename
 			} else {
 				// TODO: code 400 might happen, what to do?
 				// in other cases, just return the error
@@ -1265,6 +1273,8 @@ func (f *FileConnector) CheckFileInfo(ctx context.Context) (*ConnectorResponse,
 		fileinfo.KeySupportsUpdate:             true,
 		fileinfo.KeySupportsDeleteFile:         true,
 		fileinfo.KeySupportsRename:             true,
+		fileinfo.KeySupportsContainers:         true,
+		fileinfo.KeySupportsEcosystem:          true,
 
 		fileinfo.KeyIsAnonymousUser:  isAnonymousUser,
 		fileinfo.KeyUserFriendlyName: userFriendlyName,
@@ -1276,6 +1286,20 @@ func (f *FileConnector) CheckFileInfo(ctx context.Context) (*ConnectorResponse,
 		fileinfo.KeyUserCanNotWriteRelative: false,
 	}
 
+	if f.userInfoStore != nil {
+		infoMap[fileinfo.KeySupportsUserInfo] = true
+		if !isAnonymousUser {
+			userInfo, err := f.readUserInfo(userId)
+			if err != nil {
+				// the file can be opened without the user info
+				logger.Error().Err(err).Msg("CheckFileInfo: failed to read the user info")
+			}
+			if userInfo != "" {
+				infoMap[fileinfo.KeyUserInfo] = userInfo
+			}
+		}
+	}
+
 	switch wopiContext.ViewMode {
 	case appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE:
 		infoMap[fileinfo.KeyUserCanWrite] = true
@@ -1306,6 +1330,69 @@ func (f *FileConnector) CheckFileInfo(ctx context.Context) (*ConnectorResponse,
 	return NewResponseSuccessBody(info), nil
 }
 
+// PutUserInfo stores the user info for the current user and app
+// https://learn.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/files/putuserinfo
+//
+// The context MUST have a WOPI context, otherwise an error will be returned.
+// You can pass a pre-configured zerologger instance through the context that
+// will be used to log messages.
+//
+// The user info is an opaque string which is returned in the "UserInfo"
+// property of the CheckFileInfo response. Guests and users of public links
+// can't store any user info, a 501 response will be returned for them.
+func (f *FileConnector) PutUserInfo(ctx context.Context, userInfo string) (*ConnectorResponse, error) {
+	if _, err := middleware.WopiContextFromCtx(ctx); err != nil {
+		return nil, err
+	}
+
+	logger := zerolog.Ctx(ctx)
+
+	if f.userInfoStore == nil {
+		logger.Debug().Msg("PutUserInfo: no user info store configured")
+		return NewResponse(501), nil
+	}
+
+	user, ok := ctxpkg.ContextGetUser(ctx)
+	if !ok || user.GetId().GetOpaqueId() == "" || utils.ExistsInOpaque(user.GetOpaque(), "public-share-role") {
+		logger.Debug().Msg("PutUserInfo: anonymous users can't store user info")
+		return NewResponse(501), nil
+	}
+
+	userId := hex.EncodeToString([]byte(user.GetId().GetOpaqueId() + "@" + user.GetId().GetIdp()))
+	err := f.userInfoStore.Write(&microstore.Record{
+		Key:   f.userInfoKey(userId),
+		Value: []byte(userInfo),
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("PutUserInfo: failed to store the user info")
+		return nil, err
+	}
+
+	logger.Debug().Msg("PutUserInfo: success")
+	return NewResponse(200), nil
+}
+
+// readUserInfo returns the user info of the user with the given WOPI user id,
+// an empty string is returned if there is none.
+func (f *FileConnector) readUserInfo(userId string) (string, error) {
+	records, err := f.userInfoStore.Read(f.userInfoKey(userId))
+	switch {
+	case errors.Is(err, microstore.ErrNotFound):
+		return "", nil
+	case err != nil:
+		return "", err
+	case len(records) == 0:
+		return "", nil
+	}
+	return string(records[0].Value), nil
+}
+
+// userInfoKey returns the store key of the user info. The user info is kept
+// per app because every app uses its own format.
+func (f *FileConnector) userInfoKey(userId string) string {
+	return f.cfg.App.Name + ":" + userId
+}
+
 // createDownloadURL will create a download URL for the template file.
 // It uses a new wopi context with the template reference set as the file reference
 // and a reva access token to download the file.
@@ -1385,7 +1472,7 @@ func (f *FileConnector) watermarkText(user *userv1beta1.User) string {
 // as second value. If the provided filename doesn't have a valid prefix, the
 // whole filename will be returned as first parameter, and the second will be
 // the empty string.
-func (f *FileConnector) extractFilenameAndPrefix(filename string) (string, string) {
+func extractFilenameAndPrefix(filename string) (string, string) {
 	before, after, found := strings.Cut(filename, " ")
 	if !found {
 		return filename, ""
@@ -1427,7 +1514,7 @@ func (f *FileConnector) extractFilenameAndPrefix(filename string) (string, strin
 
 // generatePrefix will generate a short unique prefix based on the current
 // time. This prefix can be used as part of a filename
-func (f *FileConnector) generatePrefix() string {
+func generatePrefix() string {
 	byteArray := binary.BigEndian.AppendUint64([]byte{}, uint64(time.Now().UnixMilli()))
 	return base64.RawURLEncoding.EncodeToString(bytes.TrimLeft(byteArray, "\x00"))
 }
@@ -1436,9 +1523,9 @@ func (f *FileConnector) generatePrefix() string {
 // contains the resource id of the target file without the path
 // (storage, opaque and space points directly to the file). The path component
 // will be ignored
-func (f *FileConnector) generateWOPISrc(wopiContext middleware.WopiContext, logger zerolog.Logger) (*url.URL, error) {
+func generateWOPISrc(wopiContext middleware.WopiContext, cfg *config.Config, st microstore.Store, logger zerolog.Logger) (*url.URL, error) {
 	// get the WOPI token for the new file
-	accessToken, _, err := middleware.GenerateWopiToken(wopiContext, f.cfg, f.store)
+	accessToken, _, err := middleware.GenerateWopiToken(wopiContext, cfg, st)
 	if err != nil {
 		logger.Error().Err(err).Msg("generateWOPISrc: failed to generate access token for the new file")
 		return nil, err
@@ -1448,7 +1535,7 @@ func (f *FileConnector) generateWOPISrc(wopiContext middleware.WopiContext, logg
 	fileRef := helpers.HashResourceId(wopiContext.FileReference.GetResourceId())
 
 	// generate the URL for the WOPI app to access the new created file
-	wopiSrcURL, err := wopisrc.GenerateWopiSrc(fileRef, f.cfg)
+	wopiSrcURL, err := wopisrc.GenerateWopiSrc(fileRef, cfg)
 	if err != nil {
 		logger.Error().Err(err).Msg("generateWOPISrc: failed to generate WOPISrc URL for the new file")
 		return nil, err
@@ -30,6 +30,7 @@ import (
 	"github.com/opencloud-eu/reva/v2/pkg/utils"
 	cs3mocks "github.com/opencloud-eu/reva/v2/tests/cs3mocks/mocks"
 	"github.com/stretchr/testify/mock"
+	microstore "go-micro.dev/v4/store"
 	"google.golang.org/grpc"
 )
 
@@ -64,7 +65,7 @@ var _ = Describe("FileConnector", func() {
 
 		gatewaySelector = mocks.NewSelectable[gateway.GatewayAPIClient](GinkgoT())
 		gatewaySelector.On("Next").Return(gatewayClient, nil)
-		fc = connector.NewFileConnector(gatewaySelector, cfg, nil)
+		fc = connector.NewFileConnector(gatewaySelector, cfg, nil, nil)
 
 		wopiCtx = middleware.WopiContext{
 			// a real token is needed for the PutRelativeFileSuggested tests
@@ -1712,6 +1713,8 @@ var _ = Describe("FileConnector", func() {
 				SupportsUpdate:             true,
 				SupportsDeleteFile:         true,
 				SupportsRename:             true,
+				SupportsContainers:         true,
+				SupportsEcosystem:          true,
 				UserCanWrite:               true,
 				UserCanRename:              true,
 				UserID:                     "61646d696e40637573746f6d496470", // hex of admin@customIdp
@@ -2068,4 +2071,132 @@ var _ = Describe("FileConnector", func() {
 			Expect(templateSource).To(HavePrefix(expectedTemplateSource))
 		})
 	})
+
+	Describe("PutUserInfo", func() {
+		var (
+			userInfoStore microstore.Store
+			user          *userv1beta1.User
+		)
+
+		BeforeEach(func() {
+			userInfoStore = microstore.NewMemoryStore()
+			fc = connector.NewFileConnector(gatewaySelector, cfg, nil, userInfoStore)
+			user = &userv1beta1.User{
+				Id: &userv1beta1.UserId{
+					Idp:      "customIdp",
+					OpaqueId: "admin",
+				},
+				DisplayName: "Pet Shaft",
+			}
+		})
+
+		It("No valid context", func() {
+			gatewaySelector.EXPECT().Next().Unset()
+			response, err := fc.PutUserInfo(context.Background(), "some user info")
+			Expect(err).To(HaveOccurred())
+			Expect(response).To(BeNil())
+		})
+
+		It("No user info store", func() {
+			gatewaySelector.EXPECT().Next().Unset()
+			fc = connector.NewFileConnector(gatewaySelector, cfg, nil, nil)
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+			ctx = ctxpkg.ContextSetUser(ctx, user)
+
+			response, err := fc.PutUserInfo(ctx, "some user info")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(501))
+		})
+
+		It("Public link", func() {
+			gatewaySelector.EXPECT().Next().Unset()
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+			user.Opaque = &typesv1beta1.Opaque{
+				Map: map[string]*typesv1beta1.OpaqueEntry{
+					"public-share-role": {
+						Decoder: "plain",
+						Value:   []byte("viewer"),
+					},
+				},
+			}
+			ctx = ctxpkg.ContextSetUser(ctx, user)
+
+			response, err := fc.PutUserInfo(ctx, "some user info")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(501))
+
+			records, err := userInfoStore.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(BeEmpty())
+		})
+
+		It("Success and returned by CheckFileInfo", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+			ctx = ctxpkg.ContextSetUser(ctx, user)
+
+			response, err := fc.PutUserInfo(ctx, "some user info")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+
+			gatewayClient.On("Stat", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info: &providerv1beta1.ResourceInfo{
+					Owner: &userv1beta1.UserId{
+						Idp:      "customIdp",
+						OpaqueId: "aabbcc",
+						Type:     userv1beta1.UserType_USER_TYPE_PRIMARY,
+					},
+					Size: uint64(998877),
+					Mtime: &typesv1beta1.Timestamp{
+						Seconds: uint64(16273849),
+					},
+					Path: "/path/to/test.txt",
+					Id: &providerv1beta1.ResourceId{
+						StorageId: "storageid",
+						OpaqueId:  "opaqueid",
+						SpaceId:   "spaceid",
+					},
+					ParentId: &providerv1beta1.ResourceId{
+						StorageId: "storageid",
+						OpaqueId:  "parentopaqueid",
+						SpaceId:   "spaceid",
+					},
+				},
+			}, nil)
+
+			response, err = fc.CheckFileInfo(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+			info := response.Body.(*fileinfo.Microsoft)
+			Expect(info.SupportsUserInfo).To(BeTrue())
+			Expect(info.UserInfo).To(Equal("some user info"))
+		})
+
+		It("Kept per app", func() {
+			ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+			ctx = ctxpkg.ContextSetUser(ctx, user)
+
+			response, err := fc.PutUserInfo(ctx, "some user info")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+
+			otherCfg := *cfg
+			otherCfg.App.Name = "other"
+			fc = connector.NewFileConnector(gatewaySelector, &otherCfg, nil, userInfoStore)
+
+			gatewayClient.On("Stat", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.StatResponse{
+				Status: status.NewOK(ctx),
+				Info: &providerv1beta1.ResourceInfo{
+					Path: "/path/to/test.txt",
+				},
+			}, nil)
+
+			response, err = fc.CheckFileInfo(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status).To(Equal(200))
+			info := response.Body.(*fileinfo.Microsoft)
+			Expect(info.SupportsUserInfo).To(BeTrue())
+			Expect(info.UserInfo).To(BeEmpty())
+		})
+	})
 })
@@ -3,8 +3,10 @@ package connector
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	gatewayv1beta1 "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
@@ -28,8 +30,12 @@ const (
 	HeaderContentLength         string = "Content-Length"
 	HeaderContentType           string = "Content-Type"
 	HeaderWopiVersion           string = "X-WOPI-ItemVersion"
+	HeaderWopiExtensionFilter   string = "X-WOPI-FileExtensionFilterList"
 )
 
+// maxUserInfoLength is the maximum length of the user info sent with PutUserInfo
+const maxUserInfoLength = 1024
+
 // HttpAdapter will adapt the responses from the connector to HTTP.
 //
 // The adapter will use the request's context for the connector operations,
@@ -45,11 +51,13 @@ type HttpAdapter struct {
 
 // NewHttpAdapter will create a new HTTP adapter. A new connector using the
 // provided gateway API client and configuration will be used in the adapter
-func NewHttpAdapter(gws pool.Selectable[gatewayv1beta1.GatewayAPIClient], cfg *config.Config, st microstore.Store) *HttpAdapter {
+func NewHttpAdapter(gws pool.Selectable[gatewayv1beta1.GatewayAPIClient], cfg *config.Config, st microstore.Store, userInfoStore microstore.Store) *HttpAdapter {
 	httpAdapter := &HttpAdapter{
 		con: NewConnector(
-			NewFileConnector(gws, cfg, st),
+			NewFileConnector(gws, cfg, st, userInfoStore),
 			NewContentConnector(gws, cfg),
+			NewContainerConnector(gws, cfg, st),
+			NewEcosystemConnector(gws, cfg, st),
 		),
 	}
 
@@ -301,6 +309,158 @@ func (h *HttpAdapter) RenameFile(w http.ResponseWriter, r *http.Request) {
 	h.writeConnectorResponse(w, r, response)
 }
 
+// PutUserInfo will store the user info sent in the request body for the
+// current user.
+// The user info must have less than 1024 bytes, otherwise the request will
+// fail.
+func (h *HttpAdapter) PutUserInfo(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := io.ReadAll(io.LimitReader(r.Body, maxUserInfoLength+1))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if len(userInfo) > maxUserInfoLength {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	fileCon := h.con.GetFileConnector()
+	response, err := fileCon.PutUserInfo(r.Context(), string(userInfo))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeConnectorResponse(w, r, response)
+}
+
+// CheckContainerInfo will retrieve the information of the container in json
+// format.
+// Only the request's context is needed in order to extract the WOPI context.
+// The operation's response will be sent through the response writer and
+// the headers according to the spec
+func (h *HttpAdapter) CheckContainerInfo(w http.ResponseWriter, r *http.Request) {
+	containerCon := h.con.GetContainerConnector()
+	response, err := containerCon.CheckContainerInfo(r.Context())
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeConnectorResponse(w, r, response)
+}
+
+// EnumerateChildren will list the files of the container in json format.
+// The files can be filtered by their extension with the
+// "X-WOPI-FileExtensionFilterList" header.
+func (h *HttpAdapter) EnumerateChildren(w http.ResponseWriter, r *http.Request) {
+	var extensions []string
+	for _, ext := range strings.Split(r.Header.Get(HeaderWopiExtensionFilter), ",") {
+		if ext = strings.TrimSpace(ext); ext != "" {
+			extensions = append(extensions, ext)
+		}
+	}
+
+	containerCon := h.con.GetContainerConnector()
+	response, err := containerCon.EnumerateChildren(r.Context(), extensions)
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeConnectorResponse(w, r, response)
+}
+
+// CreateChildFile will create a new empty file in the container. The name
+// might be automatically adjusted depending on the request headers.
+// Note that this method will also send a json body in the response.
+// It has 2 mutually exclusive operation methods that are used based on the
+// provided headers in the request.
+//
+// The file name must be encoded in utf7. This method will decode the utf7 name
+// into utf8. The utf8 (not utf7) name must have less than 512 bytes, otherwise
+// the request will fail.
+func (h *HttpAdapter) CreateChildFile(w http.ResponseWriter, r *http.Request) {
+	relativeTarget := r.Header.Get(HeaderWopiRT)
+	suggestedTarget := r.Header.Get(HeaderWopiST)
+
+	if (relativeTarget != "") == (suggestedTarget != "") {
+		// headers are mutually exclusive, but one of them is required
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	target := suggestedTarget
+	if relativeTarget != "" {
+		target = relativeTarget
+	}
+	utf8Target, decErr := utf7.DecodeString(target)
+	if decErr != nil || len(utf8Target) > 512 {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var response *ConnectorResponse
+	var err error
+	containerCon := h.con.GetContainerConnector()
+	if suggestedTarget != "" {
+		response, err = containerCon.CreateChildFileSuggested(r.Context(), utf8Target)
+	} else {
+		response, err = containerCon.CreateChildFileRelative(r.Context(), utf8Target)
+	}
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeConnectorResponse(w, r, response)
+}
+
+// CheckEcosystem will retrieve the capabilities of the ecosystem in json
+// format.
+func (h *HttpAdapter) CheckEcosystem(w http.ResponseWriter, r *http.Request) {
+	ecosystemCon := h.con.GetEcosystemConnector()
+	response, err := ecosystemCon.CheckEcosystem(r.Context())
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeConnectorResponse(w, r, response)
+}
+
+// GetEcosystem will retrieve the URL of the ecosystem in json format. It is
+// used for both, files and containers.
+func (h *HttpAdapter) GetEcosystem(w http.ResponseWriter, r *http.Request) {
+	ecosystemCon := h.con.GetEcosystemConnector()
+	response, err := ecosystemCon.GetEcosystem(r.Context())
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeConnectorResponse(w, r, response)
+}
+
+// GetRootContainer will retrieve the name and URL of the root container in
+// json format.
+func (h *HttpAdapter) GetRootContainer(w http.ResponseWriter, r *http.Request) {
+	ecosystemCon := h.con.GetEcosystemConnector()
+	response, err := ecosystemCon.GetRootContainer(r.Context())
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeConnectorResponse(w, r, response)
+}
+
 func (h *HttpAdapter) writeConnectorResponse(w http.ResponseWriter, r *http.Request, response *ConnectorResponse) {
 	jsonBody := []byte{}
 	if response.Body != nil {
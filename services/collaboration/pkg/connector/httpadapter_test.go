@@ -20,6 +20,7 @@ var _ = Describe("HttpAdapter", func() {
 	var (
 		fc          *mocks.FileConnectorService
 		cc          *mocks.ContentConnectorService
+		ctc         *mocks.ContainerConnectorService
 		con         *mocks.ConnectorService
 		locks       *mocks.LockParser
 		httpAdapter *connector.HttpAdapter
@@ -28,10 +29,12 @@ var _ = Describe("HttpAdapter", func() {
 	BeforeEach(func() {
 		fc = &mocks.FileConnectorService{}
 		cc = &mocks.ContentConnectorService{}
+		ctc = &mocks.ContainerConnectorService{}
 
 		con = &mocks.ConnectorService{}
 		con.On("GetContentConnector").Return(cc)
 		con.On("GetFileConnector").Return(fc)
+		con.On("GetContainerConnector").Return(ctc)
 
 		locks = &mocks.LockParser{}
 		locks.EXPECT().ParseLock(mock.Anything).RunAndReturn(func(id string) string {
@@ -597,4 +600,147 @@ var _ = Describe("HttpAdapter", func() {
 			Expect(resp.Header.Get(connector.HeaderWopiVersion)).To(Equal("v1234567"))
 		})
 	})
+
+	Describe("PutUserInfo", func() {
+		It("Too long", func() {
+			req := httptest.NewRequest("POST", "/wopi/files/abcdef", strings.NewReader(strings.Repeat("a", 1025)))
+			req.Header.Set("X-WOPI-Override", "PUT_USER_INFO")
+
+			w := httptest.NewRecorder()
+
+			httpAdapter.PutUserInfo(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(400))
+			fc.AssertNotCalled(GinkgoT(), "PutUserInfo", mock.Anything, mock.Anything)
+		})
+
+		It("General error", func() {
+			req := httptest.NewRequest("POST", "/wopi/files/abcdef", strings.NewReader("some user info"))
+			req.Header.Set("X-WOPI-Override", "PUT_USER_INFO")
+
+			w := httptest.NewRecorder()
+
+			fc.On("PutUserInfo", mock.Anything, "some user info").Times(1).Return(nil, errors.New("Something happened"))
+
+			httpAdapter.PutUserInfo(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(500))
+		})
+
+		It("Success", func() {
+			req := httptest.NewRequest("POST", "/wopi/files/abcdef", strings.NewReader("some user info"))
+			req.Header.Set("X-WOPI-Override", "PUT_USER_INFO")
+
+			w := httptest.NewRecorder()
+
+			fc.On("PutUserInfo", mock.Anything, "some user info").Times(1).Return(connector.NewResponse(200), nil)
+
+			httpAdapter.PutUserInfo(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(200))
+		})
+	})
+
+	Describe("EnumerateChildren", func() {
+		It("No filter", func() {
+			req := httptest.NewRequest("GET", "/wopi/containers/abcdef/children", nil)
+
+			w := httptest.NewRecorder()
+
+			ctc.On("EnumerateChildren", mock.Anything, []string(nil)).Times(1).Return(connector.NewResponseSuccessBody(map[string]interface{}{
+				"Children": []connector.ChildFile{},
+			}), nil)
+
+			httpAdapter.EnumerateChildren(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(200))
+		})
+
+		It("With filter", func() {
+			req := httptest.NewRequest("GET", "/wopi/containers/abcdef/children", nil)
+			req.Header.Set("X-WOPI-FileExtensionFilterList", ".docx, .xlsx,,")
+
+			w := httptest.NewRecorder()
+
+			ctc.On("EnumerateChildren", mock.Anything, []string{".docx", ".xlsx"}).Times(1).Return(connector.NewResponseSuccessBody(map[string]interface{}{
+				"Children": []connector.ChildFile{},
+			}), nil)
+
+			httpAdapter.EnumerateChildren(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(200))
+		})
+
+		It("General error", func() {
+			req := httptest.NewRequest("GET", "/wopi/containers/abcdef/children", nil)
+
+			w := httptest.NewRecorder()
+
+			ctc.On("EnumerateChildren", mock.Anything, mock.Anything).Times(1).Return(nil, errors.New("Something happened"))
+
+			httpAdapter.EnumerateChildren(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(500))
+		})
+	})
+
+	Describe("CreateChildFile", func() {
+		It("No target", func() {
+			req := httptest.NewRequest("POST", "/wopi/containers/abcdef", nil)
+			req.Header.Set("X-WOPI-Override", "CREATE_CHILD_FILE")
+
+			w := httptest.NewRecorder()
+
+			httpAdapter.CreateChildFile(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(400))
+		})
+
+		It("Both targets", func() {
+			req := httptest.NewRequest("POST", "/wopi/containers/abcdef", nil)
+			req.Header.Set("X-WOPI-Override", "CREATE_CHILD_FILE")
+			req.Header.Set("X-WOPI-SuggestedTarget", ".docx")
+			req.Header.Set("X-WOPI-RelativeTarget", "new.docx")
+
+			w := httptest.NewRecorder()
+
+			httpAdapter.CreateChildFile(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(400))
+		})
+
+		It("Suggested target", func() {
+			req := httptest.NewRequest("POST", "/wopi/containers/abcdef", nil)
+			req.Header.Set("X-WOPI-Override", "CREATE_CHILD_FILE")
+			req.Header.Set("X-WOPI-SuggestedTarget", ".docx")
+
+			w := httptest.NewRecorder()
+
+			ctc.On("CreateChildFileSuggested", mock.Anything, ".docx").Times(1).Return(connector.NewResponseSuccessBodyName("Untitled.docx"), nil)
+
+			httpAdapter.CreateChildFile(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(200))
+		})
+
+		It("Relative target conflict", func() {
+			req := httptest.NewRequest("POST", "/wopi/containers/abcdef", nil)
+			req.Header.Set("X-WOPI-Override", "CREATE_CHILD_FILE")
+			req.Header.Set("X-WOPI-RelativeTarget", "new.docx")
+
+			w := httptest.NewRecorder()
+
+			ctc.On("CreateChildFileRelative", mock.Anything, "new.docx").Times(1).Return(&connector.ConnectorResponse{
+				Status: 409,
+				Headers: map[string]string{
+					connector.HeaderWopiValidRT: "abc new.docx",
+				},
+			}, nil)
+
+			httpAdapter.CreateChildFile(w, req)
+			resp := w.Result()
+			Expect(resp.StatusCode).To(Equal(409))
+			Expect(resp.Header.Get(connector.HeaderWopiValidRT)).To(Equal("abc new.docx"))
+		})
+	})
 })
@@ -49,7 +49,21 @@ type WopiContext struct {
 // * The created WopiContext for the request
 // * A contextual zerologger containing information about the request
 // and the WopiContext
+//
+// The file or container id in the URL must match the reference inside the
+// access token.
 func WopiContextAuthMiddleware(cfg *config.Config, st microstore.Store, next http.Handler) http.Handler {
+	return wopiContextAuth(cfg, st, next, true)
+}
+
+// WopiEcosystemAuthMiddleware works like the WopiContextAuthMiddleware, but
+// it is meant for the ecosystem endpoint, which doesn't have an id in the
+// URL. The access token of any file or container is accepted.
+func WopiEcosystemAuthMiddleware(cfg *config.Config, st microstore.Store, next http.Handler) http.Handler {
+	return wopiContextAuth(cfg, st, next, false)
+}
+
+func wopiContextAuth(cfg *config.Config, st microstore.Store, next http.Handler, checkReference bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -143,6 +157,11 @@ func WopiContextAuthMiddleware(cfg *config.Config, st microstore.Store, next htt
 			Logger()
 		ctx = wopiLogger.WithContext(ctx)
 
+		if !checkReference {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		hashedRef := helpers.HashResourceId(claims.WopiContext.FileReference.GetResourceId())
 		fileID := parseWopiFileID(cfg, r.URL.Path)
 		if claims.WopiContext.TemplateReference != nil {
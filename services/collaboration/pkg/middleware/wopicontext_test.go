@@ -287,4 +287,66 @@ var _ = Describe("Wopi Context Middleware", func() {
 		mw.ServeHTTP(resp, req)
 		Expect(resp.Code).To(Equal(http.StatusOK))
 	})
+	It("Should authorize successful for a container", func() {
+		src.Path = path.Join("wopi", "containers", helpers.HashResourceId(rid), "children")
+		req := httptest.NewRequest("GET", src.String(), nil).WithContext(ctx)
+		token, err := tknMngr.MintToken(ctx, user, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		wopiContext := middleware.WopiContext{
+			AccessToken: token,
+			ViewMode:    appprovider.ViewMode_VIEW_MODE_READ_WRITE,
+			FileReference: &providerv1beta1.Reference{
+				ResourceId: rid,
+			},
+		}
+		wopiToken, _, err := middleware.GenerateWopiToken(wopiContext, cfg, nil)
+		Expect(err).ToNot(HaveOccurred())
+		q := req.URL.Query()
+		q.Add("access_token", wopiToken)
+		req.URL.RawQuery = q.Encode()
+		resp := httptest.NewRecorder()
+
+		mw.ServeHTTP(resp, req)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+	})
+	Describe("Ecosystem", func() {
+		BeforeEach(func() {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			mw = middleware.WopiEcosystemAuthMiddleware(cfg, nil, next)
+			src.Path = path.Join("wopi", "ecosystem", "root_container_pointer")
+		})
+		It("Should not authorize with empty access token", func() {
+			req := httptest.NewRequest("GET", src.String(), nil).WithContext(ctx)
+			resp := httptest.NewRecorder()
+
+			mw.ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusUnauthorized))
+		})
+		It("Should authorize successful with the token of any file", func() {
+			req := httptest.NewRequest("GET", src.String(), nil).WithContext(ctx)
+			token, err := tknMngr.MintToken(ctx, user, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			wopiContext := middleware.WopiContext{
+				AccessToken: token,
+				ViewMode:    appprovider.ViewMode_VIEW_MODE_READ_WRITE,
+				FileReference: &providerv1beta1.Reference{
+					ResourceId: rid,
+					Path:       ".",
+				},
+			}
+			wopiToken, _, err := middleware.GenerateWopiToken(wopiContext, cfg, nil)
+			Expect(err).ToNot(HaveOccurred())
+			q := req.URL.Query()
+			q.Add("access_token", wopiToken)
+			req.URL.RawQuery = q.Encode()
+			resp := httptest.NewRecorder()
+
+			mw.ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+		})
+	})
 })
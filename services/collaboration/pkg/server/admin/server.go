@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"github.com/opencloud-eu/opencloud/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/sessions"
+	graphMiddleware "github.com/opencloud-eu/opencloud/services/graph/pkg/middleware"
+)
+
+// Admin inspects and force-closes the active WOPI sessions
+type Admin interface {
+	List() ([]sessions.Session, error)
+	Get(id string) (sessions.Session, error)
+	Close(ctx context.Context, id string) error
+}
+
+// CloseResult is returned if a session was closed, but the lock of the file couldn't be released
+type CloseResult struct {
+	Closed   bool   `json:"closed"`
+	Unlocked bool   `json:"unlocked"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Server initializes the http server of the admin API.
+func Server(opts ...Option) (*http.Server, error) {
+	options := newOptions(opts...)
+
+	if options.Config.Admin.Token == "" {
+		return nil, errors.New("the admin API requires a token")
+	}
+
+	baseCtx := options.Context
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+
+	return &http.Server{
+		Addr: options.Config.Admin.Addr,
+		BaseContext: func(_ net.Listener) context.Context {
+			return baseCtx
+		},
+		Handler: NewHandler(options.Admin, options.Config.Admin.Token),
+	}, nil
+}
+
+// NewHandler returns the handler of the admin API
+//
+//	GET    /api/v0/sessions
+//	GET    /api/v0/sessions/{sessionID}
+//	DELETE /api/v0/sessions/{sessionID}
+func NewHandler(admin Admin, token string) http.Handler {
+	mux := chi.NewMux()
+	mux.Use(chimiddleware.RealIP)
+	mux.Use(chimiddleware.RequestID)
+	mux.Use(middleware.NoCache)
+	mux.Use(graphMiddleware.Token(token))
+
+	mux.Route("/api/v0/sessions", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			list, err := admin.List()
+			if err != nil {
+				renderError(w, r, err)
+				return
+			}
+			render.JSON(w, r, list)
+		})
+		r.Route("/{sessionID}", func(r chi.Router) {
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				session, err := admin.Get(chi.URLParam(r, "sessionID"))
+				if err != nil {
+					renderError(w, r, err)
+					return
+				}
+				render.JSON(w, r, session)
+			})
+			r.Delete("/", func(w http.ResponseWriter, r *http.Request) {
+				err := admin.Close(r.Context(), chi.URLParam(r, "sessionID"))
+				switch {
+				case errors.Is(err, sessions.ErrUnlockFailed):
+					// the session is closed anyway, only the lock is left
+					render.JSON(w, r, CloseResult{Closed: true, Unlocked: false, Error: err.Error()})
+				case err != nil:
+					renderError(w, r, err)
+				default:
+					w.WriteHeader(http.StatusNoContent)
+				}
+			})
+		})
+	})
+
+	return mux
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, sessions.ErrNotFound):
+		render.Status(r, http.StatusNotFound)
+	default:
+		render.Status(r, http.StatusInternalServerError)
+	}
+	render.PlainText(w, r, err.Error())
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
 	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/connector"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/sessions"
 	microstore "go-micro.dev/v4/store"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -21,6 +22,7 @@ type Options struct {
 	Config         *config.Config
 	TracerProvider trace.TracerProvider
 	Store          microstore.Store
+	Sessions       *sessions.Registry
 }
 
 // newOptions initializes the available default options.
@@ -75,3 +77,10 @@ func Store(val microstore.Store) Option {
 		o.Store = val
 	}
 }
+
+// Sessions provides a function to set the Sessions option
+func Sessions(val *sessions.Registry) Option {
+	return func(o *Options) {
+		o.Sessions = val
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/opencloud-eu/opencloud/pkg/tracing"
 	"github.com/opencloud-eu/opencloud/pkg/version"
 	colabmiddleware "github.com/opencloud-eu/opencloud/services/collaboration/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/sessions"
 	"github.com/riandyrn/otelchi"
 	"go-micro.dev/v4"
 )
@@ -131,10 +132,21 @@ func prepareRoutes(r *chi.Mux, options Options) {
 				})
 			}
 
+			// keep track of the active sessions
+			if options.Sessions != nil {
+				r.Use(func(h stdhttp.Handler) stdhttp.Handler {
+					return sessions.Middleware(options.Config, options.Sessions, h)
+				})
+			}
+
 			r.Get("/", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 				adapter.CheckFileInfo(w, r)
 			})
 
+			r.Get("/ecosystem_pointer", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+				adapter.GetEcosystem(w, r)
+			})
+
 			r.Post("/", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 				action := r.Header.Get("X-WOPI-Override")
 				switch action {
@@ -150,8 +162,7 @@ func prepareRoutes(r *chi.Mux, options Options) {
 					adapter.UnLock(w, r)
 
 				case "PUT_USER_INFO":
-					// https://docs.microsoft.com/en-us/microsoft-365/cloud-storage-partner-program/rest/files/putuserinfo
-					stdhttp.Error(w, stdhttp.StatusText(stdhttp.StatusNotImplemented), stdhttp.StatusNotImplemented)
+					adapter.PutUserInfo(w, r)
 				case "PUT_RELATIVE":
 					adapter.PutRelativeFile(w, r)
 				case "RENAME_FILE":
@@ -182,6 +193,70 @@ func prepareRoutes(r *chi.Mux, options Options) {
 				})
 			})
 		})
+		r.Route("/containers/{containerid}", func(r chi.Router) {
+			r.Use(
+				func(h stdhttp.Handler) stdhttp.Handler {
+					// authentication and wopi context
+					return colabmiddleware.WopiContextAuthMiddleware(options.Config, options.Store, h)
+				},
+				colabmiddleware.CollaborationTracingMiddleware,
+			)
+
+			// check whether we should check for proof keys
+			if !options.Config.App.ProofKeys.Disable {
+				r.Use(func(h stdhttp.Handler) stdhttp.Handler {
+					return colabmiddleware.ProofKeysMiddleware(options.Config, h)
+				})
+			}
+
+			r.Get("/", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+				adapter.CheckContainerInfo(w, r)
+			})
+
+			r.Post("/", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+				action := r.Header.Get("X-WOPI-Override")
+				switch action {
+
+				case "CREATE_CHILD_FILE":
+					adapter.CreateChildFile(w, r)
+
+				default:
+					stdhttp.Error(w, stdhttp.StatusText(stdhttp.StatusNotImplemented), stdhttp.StatusNotImplemented)
+				}
+			})
+
+			r.Get("/children", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+				adapter.EnumerateChildren(w, r)
+			})
+
+			r.Get("/ecosystem_pointer", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+				adapter.GetEcosystem(w, r)
+			})
+		})
+		r.Route("/ecosystem", func(r chi.Router) {
+			r.Use(
+				func(h stdhttp.Handler) stdhttp.Handler {
+					// authentication and wopi context, there is no resource id in the URL
+					return colabmiddleware.WopiEcosystemAuthMiddleware(options.Config, options.Store, h)
+				},
+				colabmiddleware.CollaborationTracingMiddleware,
+			)
+
+			// check whether we should check for proof keys
+			if !options.Config.App.ProofKeys.Disable {
+				r.Use(func(h stdhttp.Handler) stdhttp.Handler {
+					return colabmiddleware.ProofKeysMiddleware(options.Config, h)
+				})
+			}
+
+			r.Get("/", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+				adapter.CheckEcosystem(w, r)
+			})
+
+			r.Get("/root_container_pointer", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+				adapter.GetRootContainer(w, r)
+			})
+		})
 		r.Route("/templates/{templateID}", func(r chi.Router) {
 			r.Use(
 				func(h stdhttp.Handler) stdhttp.Handler {
@@ -0,0 +1,119 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gatewayv1beta1 "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	rpcv1beta1 "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
+	providerv1beta1 "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/middleware"
+	ctxpkg "github.com/opencloud-eu/reva/v2/pkg/ctx"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
+	"github.com/opencloud-eu/reva/v2/pkg/storagespace"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// ErrLockMismatch is returned if the file is locked with a different lock
+	// than the one known in the session
+	ErrLockMismatch = errors.New("the file is locked by someone else")
+	// ErrUnlockFailed is returned if the session was closed, but the lock of
+	// the file couldn't be released
+	ErrUnlockFailed = errors.New("the session was closed, but the lock of the file couldn't be released")
+)
+
+// Admin inspects and force-closes the sessions of the registry
+type Admin struct {
+	gws      pool.Selectable[gatewayv1beta1.GatewayAPIClient]
+	cfg      *config.Config
+	registry *Registry
+}
+
+// NewAdmin creates a new Admin for the sessions of the registry
+func NewAdmin(gws pool.Selectable[gatewayv1beta1.GatewayAPIClient], cfg *config.Config, registry *Registry) *Admin {
+	return &Admin{
+		gws:      gws,
+		cfg:      cfg,
+		registry: registry,
+	}
+}
+
+// List returns all the active sessions
+func (a *Admin) List() ([]Session, error) {
+	return a.registry.List()
+}
+
+// Get returns the active session with the given id
+func (a *Admin) Get(id string) (Session, error) {
+	return a.registry.Get(id)
+}
+
+// Close force-closes the session with the given id. The lock of the file is
+// released and the WOPI access tokens used in the session are revoked, so the
+// WOPI app can't save the file anymore. Users have to open the file again.
+// The session is also closed if the lock can't be released, ErrUnlockFailed
+// is returned in that case.
+func (a *Admin) Close(ctx context.Context, id string) error {
+	rec, err := a.registry.read(id)
+	if err != nil {
+		return err
+	}
+
+	var unlockErr error
+	if rec.LockID != "" && rec.LockToken != "" {
+		unlockErr = a.unlock(ctx, rec)
+	}
+
+	if err := a.registry.remove(rec); err != nil {
+		return err
+	}
+	if unlockErr != nil {
+		return fmt.Errorf("%w: %w", ErrUnlockFailed, unlockErr)
+	}
+	return nil
+}
+
+// unlock releases the lock of the file on behalf of the lock holder, because
+// only the lock holder is allowed to release it
+func (a *Admin) unlock(ctx context.Context, rec *record) error {
+	resourceID, err := storagespace.ParseID(rec.ResourceID)
+	if err != nil {
+		return err
+	}
+
+	accessToken, err := middleware.DecryptAES([]byte(a.cfg.Wopi.Secret), rec.LockToken)
+	if err != nil {
+		return fmt.Errorf("cannot decrypt the access token of the lock holder: %w", err)
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, ctxpkg.TokenHeader, accessToken)
+
+	gwc, err := a.gws.Next()
+	if err != nil {
+		return err
+	}
+	resp, err := gwc.Unlock(ctx, &providerv1beta1.UnlockRequest{
+		Ref: &providerv1beta1.Reference{
+			ResourceId: &resourceID,
+		},
+		Lock: &providerv1beta1.Lock{
+			LockId:  rec.LockID,
+			AppName: rec.App,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	switch resp.GetStatus().GetCode() {
+	case rpcv1beta1.Code_CODE_OK, rpcv1beta1.Code_CODE_ABORTED:
+		// the file isn't locked anymore if the unlock was aborted
+		return nil
+	case rpcv1beta1.Code_CODE_LOCKED:
+		return ErrLockMismatch
+	default:
+		return fmt.Errorf("cannot release the lock: %s", resp.GetStatus().GetMessage())
+	}
+}
@@ -0,0 +1,139 @@
+package sessions_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	userv1beta1 "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	providerv1beta1 "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/sessions"
+	"github.com/opencloud-eu/opencloud/services/graph/mocks"
+	ctxpkg "github.com/opencloud-eu/reva/v2/pkg/ctx"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/status"
+	cs3mocks "github.com/opencloud-eu/reva/v2/tests/cs3mocks/mocks"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/metadata"
+)
+
+var _ = Describe("Admin", func() {
+	var (
+		cfg             *config.Config
+		registry        *sessions.Registry
+		admin           *sessions.Admin
+		gatewayClient   *cs3mocks.GatewayAPIClient
+		gatewaySelector *mocks.Selectable[gateway.GatewayAPIClient]
+		resourceID      *providerv1beta1.ResourceId
+		alice           *userv1beta1.User
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			Wopi: config.Wopi{
+				Secret: "wopiSecret",
+			},
+		}
+		registry = sessions.NewRegistry(kv.NewMemoryStore(30*time.Minute), kv.NewMemoryStore(0), "Collabora", 30*time.Minute)
+
+		gatewayClient = cs3mocks.NewGatewayAPIClient(GinkgoT())
+		gatewaySelector = mocks.NewSelectable[gateway.GatewayAPIClient](GinkgoT())
+		admin = sessions.NewAdmin(gatewaySelector, cfg, registry)
+
+		resourceID = &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "fileid"}
+		alice = &userv1beta1.User{Id: &userv1beta1.UserId{OpaqueId: "alice"}, DisplayName: "Alice"}
+	})
+
+	It("returns ErrNotFound for unknown sessions", func() {
+		Expect(admin.Close(context.Background(), "unknown")).To(MatchError(sessions.ErrNotFound))
+	})
+
+	It("closes sessions without lock", func() {
+		Expect(registry.Record(sessions.Activity{FileID: "abc", ResourceID: resourceID, User: alice, TokenHash: "hash", Time: time.Now()})).To(Succeed())
+
+		Expect(admin.Close(context.Background(), "abc")).To(Succeed())
+		_, err := admin.Get("abc")
+		Expect(err).To(MatchError(sessions.ErrNotFound))
+		Expect(registry.IsRevoked("hash")).To(BeTrue())
+		Expect(registry.IsRevoked("otherhash")).To(BeFalse())
+	})
+
+	Describe("with lock", func() {
+		BeforeEach(func() {
+			lockToken, err := middleware.EncryptAES([]byte(cfg.Wopi.Secret), "revaToken")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(registry.Record(sessions.Activity{
+				FileID:     "abc",
+				ResourceID: resourceID,
+				User:       alice,
+				Lock:       sessions.LockAcquired,
+				LockID:     "lock-1",
+				LockToken:  lockToken,
+				TokenHash:  "hash",
+				Time:       time.Now(),
+			})).To(Succeed())
+			gatewaySelector.On("Next").Return(gatewayClient, nil)
+		})
+
+		It("releases the lock as lock holder", func() {
+			gatewayClient.On("Unlock", mock.MatchedBy(func(ctx context.Context) bool {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				return len(md.Get(ctxpkg.TokenHeader)) == 1 && md.Get(ctxpkg.TokenHeader)[0] == "revaToken"
+			}), mock.MatchedBy(func(req *providerv1beta1.UnlockRequest) bool {
+				return req.GetRef().GetResourceId().GetOpaqueId() == "fileid" &&
+					req.GetLock().GetLockId() == "lock-1" &&
+					req.GetLock().GetAppName() == "Collabora"
+			})).Times(1).Return(&providerv1beta1.UnlockResponse{
+				Status: status.NewOK(context.Background()),
+			}, nil)
+
+			Expect(admin.Close(context.Background(), "abc")).To(Succeed())
+			_, err := admin.Get("abc")
+			Expect(err).To(MatchError(sessions.ErrNotFound))
+		})
+
+		It("closes the session and reports that the lock changed", func() {
+			gatewayClient.On("Unlock", mock.Anything, mock.Anything).Times(1).Return(&providerv1beta1.UnlockResponse{
+				Status: status.NewLocked(context.Background(), "locked"),
+			}, nil)
+
+			err := admin.Close(context.Background(), "abc")
+			Expect(err).To(MatchError(sessions.ErrUnlockFailed))
+			Expect(err).To(MatchError(sessions.ErrLockMismatch))
+			_, err = admin.Get("abc")
+			Expect(err).To(MatchError(sessions.ErrNotFound))
+			Expect(registry.IsRevoked("hash")).To(BeTrue())
+		})
+	})
+
+	It("rejects the requests of closed sessions", func() {
+		handler := sessions.Middleware(cfg, registry, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		serve := func() int {
+			req := httptest.NewRequest(http.MethodGet, "/wopi/files/abc?access_token=wopiToken", nil)
+			ctx := middleware.WopiContextToCtx(context.Background(), middleware.WopiContext{
+				AccessToken:   "revaToken",
+				FileReference: &providerv1beta1.Reference{ResourceId: resourceID},
+			})
+			ctx = ctxpkg.ContextSetUser(ctx, alice)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req.WithContext(ctx))
+			return resp.Code
+		}
+
+		Expect(serve()).To(Equal(http.StatusOK))
+		list, err := admin.List()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(HaveLen(1))
+
+		Expect(admin.Close(context.Background(), list[0].ID)).To(Succeed())
+		Expect(serve()).To(Equal(http.StatusUnauthorized))
+	})
+})
@@ -0,0 +1,96 @@
+package sessions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/helpers"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/middleware"
+	ctxpkg "github.com/opencloud-eu/reva/v2/pkg/ctx"
+	"github.com/rs/zerolog"
+)
+
+// Middleware will prepare an HTTP handler to be used as middleware. The
+// handler records the WOPI requests in the sessions of the registry and
+// rejects the requests of closed sessions.
+//
+// The middleware must run after the WopiContextAuthMiddleware, because it
+// needs the WopiContext and the user in the request's context.
+func Middleware(cfg *config.Config, registry *Registry, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		logger := zerolog.Ctx(ctx)
+
+		wopiContext, err := middleware.WopiContextFromCtx(ctx)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tokenHash := hashToken(r.URL.Query().Get("access_token"))
+		if registry.IsRevoked(tokenHash) {
+			logger.Error().Msg("the session of the access token has been closed")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		wrap := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(wrap, r)
+
+		user, _ := ctxpkg.ContextGetUser(ctx)
+
+		activity := Activity{
+			FileID:      helpers.HashResourceId(wopiContext.FileReference.GetResourceId()),
+			ResourceID:  wopiContext.FileReference.GetResourceId(),
+			User:        user,
+			ViewMode:    wopiContext.ViewMode,
+			TokenHash:   tokenHash,
+			TokenExpiry: tokenExpiry(wopiContext.AccessToken),
+			Time:        time.Now(),
+		}
+
+		// the status is 0 if the handler didn't write anything
+		if status := wrap.Status(); status == 0 || status == http.StatusOK {
+			switch r.Header.Get("X-WOPI-Override") {
+			case "LOCK", "REFRESH_LOCK":
+				lockToken, err := middleware.EncryptAES([]byte(cfg.Wopi.Secret), wopiContext.AccessToken)
+				if err != nil {
+					logger.Error().Err(err).Msg("failed to encrypt the access token of the lock holder")
+					break
+				}
+				activity.Lock = LockAcquired
+				activity.LockID = r.Header.Get("X-WOPI-Lock")
+				activity.LockToken = lockToken
+			case "UNLOCK":
+				activity.Lock = LockReleased
+			}
+		}
+
+		if err := registry.Record(activity); err != nil {
+			logger.Error().Err(err).Msg("failed to record the session activity")
+		}
+	})
+}
+
+// hashToken hashes the WOPI access token, so the token itself doesn't need
+// to be kept in the store
+func hashToken(token string) string {
+	c := sha256.New()
+	c.Write([]byte(token))
+	return hex.EncodeToString(c.Sum(nil))
+}
+
+// tokenExpiry returns the expiration time of the reva access token, which is
+// also the expiration time of the WOPI access token
+func tokenExpiry(accessToken string) time.Time {
+	claims := &jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(accessToken, claims); err != nil || claims.ExpiresAt == nil {
+		return time.Time{}
+	}
+	return claims.ExpiresAt.Time
+}
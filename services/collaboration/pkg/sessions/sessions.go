@@ -0,0 +1,271 @@
+// Package sessions keeps track of the active WOPI sessions.
+//
+// A session covers all the users working on the same file with the same app,
+// so a co-authoring session is a single session with several users. The
+// sessions are kept in a key value store and expire after the TTL of the
+// store without any activity. The sessions are updated with a revision check,
+// so all instances of the collaboration service can share the store.
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	appproviderv1beta1 "github.com/cs3org/go-cs3apis/cs3/app/provider/v1beta1"
+	userv1beta1 "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	providerv1beta1 "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+	"github.com/opencloud-eu/reva/v2/pkg/storagespace"
+)
+
+const (
+	sessionPrefix = "session:"
+	revokedPrefix = "revoked:"
+)
+
+// ErrNotFound is returned if there is no active session with the requested id
+var ErrNotFound = errors.New("session not found")
+
+// User is a user taking part in a session
+type User struct {
+	ID           string    `json:"id"`
+	DisplayName  string    `json:"display_name"`
+	ViewMode     string    `json:"view_mode"`
+	LastActivity time.Time `json:"last_activity"`
+}
+
+// Session is an active WOPI session of a file
+type Session struct {
+	// ID is the WOPI file id of the file
+	ID           string    `json:"id"`
+	ResourceID   string    `json:"resource_id"`
+	App          string    `json:"app"`
+	LockID       string    `json:"lock_id,omitempty"`
+	LockHolder   string    `json:"lock_holder,omitempty"`
+	Users        []User    `json:"users"`
+	StartTime    time.Time `json:"start_time"`
+	LastActivity time.Time `json:"last_activity"`
+}
+
+// LockAction describes how a request changed the lock of the file
+type LockAction int
+
+const (
+	// LockUnchanged is used for requests which didn't change the lock
+	LockUnchanged LockAction = iota
+	// LockAcquired is used for requests which locked the file or refreshed the lock
+	LockAcquired
+	// LockReleased is used for requests which unlocked the file
+	LockReleased
+)
+
+// Activity is a WOPI request done in a session
+type Activity struct {
+	FileID     string
+	ResourceID *providerv1beta1.ResourceId
+	User       *userv1beta1.User
+	ViewMode   appproviderv1beta1.ViewMode
+	// TokenHash is the hash of the WOPI access token used for the request
+	TokenHash string
+	// TokenExpiry is the time the WOPI access token expires
+	TokenExpiry time.Time
+	Lock        LockAction
+	LockID      string
+	// LockToken is the encrypted reva access token of the user who acquired
+	// the lock. It is needed to release the lock when closing the session.
+	LockToken string
+	Time      time.Time
+}
+
+// record is a session as it is kept in the store
+type record struct {
+	Session
+	LockToken string               `json:"lock_token,omitempty"`
+	Tokens    map[string]time.Time `json:"tokens,omitempty"`
+}
+
+// Registry keeps the sessions of an app in a store
+type Registry struct {
+	sessions kv.Store
+	revoked  kv.Store
+	app      string
+	ttl      time.Duration
+}
+
+// NewRegistry creates a new registry for the sessions of the app. Sessions
+// without any activity are removed after the ttl. The revoked WOPI access
+// tokens are kept in a separate store, its TTL has to be longer than the
+// lifetime of the tokens.
+func NewRegistry(sessions, revoked kv.Store, app string, ttl time.Duration) *Registry {
+	return &Registry{
+		sessions: sessions,
+		revoked:  revoked,
+		app:      app,
+		ttl:      ttl,
+	}
+}
+
+// Record adds the activity to the session of the file. The session is created
+// if there isn't one yet.
+func (r *Registry) Record(a Activity) error {
+	return kv.Modify(r.sessions, r.sessionKey(a.FileID), func(value []byte) ([]byte, error) {
+		rec := &record{
+			Session: Session{
+				ID:         a.FileID,
+				ResourceID: storagespace.FormatResourceID(a.ResourceID),
+				App:        r.app,
+				StartTime:  a.Time,
+			},
+		}
+		if value != nil {
+			rec = &record{}
+			if err := json.Unmarshal(value, rec); err != nil {
+				return nil, err
+			}
+		}
+
+		r.apply(rec, a)
+		return json.Marshal(rec)
+	})
+}
+
+// apply changes the session according to the activity
+func (r *Registry) apply(rec *record, a Activity) {
+	rec.LastActivity = a.Time
+	userID := a.User.GetId().GetOpaqueId()
+
+	// drop the users which left the session
+	rec.Users = slices.DeleteFunc(rec.Users, func(u User) bool {
+		return u.ID == userID || (r.ttl > 0 && a.Time.Sub(u.LastActivity) > r.ttl)
+	})
+	rec.Users = append(rec.Users, User{
+		ID:           userID,
+		DisplayName:  a.User.GetDisplayName(),
+		ViewMode:     a.ViewMode.String(),
+		LastActivity: a.Time,
+	})
+
+	if a.TokenHash != "" {
+		if rec.Tokens == nil {
+			rec.Tokens = make(map[string]time.Time)
+		}
+		rec.Tokens[a.TokenHash] = a.TokenExpiry
+		for hash, expiry := range rec.Tokens {
+			if !expiry.IsZero() && expiry.Before(a.Time) {
+				delete(rec.Tokens, hash)
+			}
+		}
+	}
+
+	switch a.Lock {
+	case LockAcquired:
+		rec.LockID = a.LockID
+		rec.LockHolder = userID
+		rec.LockToken = a.LockToken
+	case LockReleased:
+		rec.LockID = ""
+		rec.LockHolder = ""
+		rec.LockToken = ""
+	}
+}
+
+// List returns all the active sessions of the app, the most recently active first
+func (r *Registry) List() ([]Session, error) {
+	keys, err := r.sessions.Keys(r.sessionKey(""))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Session, 0, len(keys))
+	for _, k := range keys {
+		rec, err := r.read(strings.TrimPrefix(k, r.sessionKey("")))
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				// expired in the meantime
+				continue
+			}
+			return nil, err
+		}
+		list = append(list, rec.Session)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].LastActivity.After(list[j].LastActivity)
+	})
+	return list, nil
+}
+
+// Get returns the active session with the given id
+func (r *Registry) Get(id string) (Session, error) {
+	rec, err := r.read(id)
+	if err != nil {
+		return Session{}, err
+	}
+	return rec.Session, nil
+}
+
+// IsRevoked checks if the WOPI access token with the given hash belongs to a
+// closed session.
+func (r *Registry) IsRevoked(tokenHash string) bool {
+	value, _, err := r.revoked.Get(r.revokedKey(tokenHash))
+	if err != nil {
+		return false
+	}
+
+	var expiry time.Time
+	if err := expiry.UnmarshalText(value); err != nil {
+		return false
+	}
+	// expired tokens are rejected anyway
+	return expiry.IsZero() || time.Now().Before(expiry)
+}
+
+// remove deletes the session from the store and revokes all the WOPI access
+// tokens used in it
+func (r *Registry) remove(rec *record) error {
+	for hash, expiry := range rec.Tokens {
+		if err := r.revoke(hash, expiry); err != nil {
+			return err
+		}
+	}
+	return r.sessions.Delete(r.sessionKey(rec.ID))
+}
+
+// revoke adds the token to the revoked tokens. The revocation is removed after
+// the TTL of the store.
+func (r *Registry) revoke(tokenHash string, expiry time.Time) error {
+	value, err := expiry.MarshalText()
+	if err != nil {
+		return err
+	}
+	_, err = r.revoked.Put(r.revokedKey(tokenHash), value)
+	return err
+}
+
+func (r *Registry) read(id string) (*record, error) {
+	value, _, err := r.sessions.Get(r.sessionKey(id))
+	switch {
+	case errors.Is(err, kv.ErrNotFound):
+		return nil, ErrNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	rec := &record{}
+	if err := json.Unmarshal(value, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (r *Registry) sessionKey(id string) string {
+	return sessionPrefix + r.app + ":" + id
+}
+
+func (r *Registry) revokedKey(tokenHash string) string {
+	return revokedPrefix + r.app + ":" + tokenHash
+}
@@ -0,0 +1,229 @@
+package sessions_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	appproviderv1beta1 "github.com/cs3org/go-cs3apis/cs3/app/provider/v1beta1"
+	userv1beta1 "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	providerv1beta1 "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/helpers"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/collaboration/pkg/sessions"
+	ctxpkg "github.com/opencloud-eu/reva/v2/pkg/ctx"
+)
+
+var _ = Describe("Registry", func() {
+	var (
+		st         kv.Store
+		registry   *sessions.Registry
+		resourceID *providerv1beta1.ResourceId
+		alice      *userv1beta1.User
+		bob        *userv1beta1.User
+		start      time.Time
+	)
+
+	BeforeEach(func() {
+		st = kv.NewMemoryStore(30 * time.Minute)
+		registry = sessions.NewRegistry(st, kv.NewMemoryStore(0), "Collabora", 30*time.Minute)
+		resourceID = &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "fileid"}
+		alice = &userv1beta1.User{Id: &userv1beta1.UserId{OpaqueId: "alice"}, DisplayName: "Alice"}
+		bob = &userv1beta1.User{Id: &userv1beta1.UserId{OpaqueId: "bob"}, DisplayName: "Bob"}
+		start = time.Now()
+	})
+
+	It("returns ErrNotFound for unknown sessions", func() {
+		_, err := registry.Get("unknown")
+		Expect(err).To(MatchError(sessions.ErrNotFound))
+	})
+
+	It("records the users of a session", func() {
+		Expect(registry.Record(sessions.Activity{
+			FileID:     "abc",
+			ResourceID: resourceID,
+			User:       alice,
+			ViewMode:   appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE,
+			Time:       start,
+		})).To(Succeed())
+		Expect(registry.Record(sessions.Activity{
+			FileID:     "abc",
+			ResourceID: resourceID,
+			User:       bob,
+			ViewMode:   appproviderv1beta1.ViewMode_VIEW_MODE_READ_ONLY,
+			Time:       start.Add(time.Minute),
+		})).To(Succeed())
+		Expect(registry.Record(sessions.Activity{
+			FileID:     "abc",
+			ResourceID: resourceID,
+			User:       alice,
+			ViewMode:   appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE,
+			Time:       start.Add(2 * time.Minute),
+		})).To(Succeed())
+
+		session, err := registry.Get("abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.ResourceID).To(Equal("storageid$spaceid!fileid"))
+		Expect(session.App).To(Equal("Collabora"))
+		Expect(session.StartTime).To(BeTemporally("==", start))
+		Expect(session.LastActivity).To(BeTemporally("==", start.Add(2*time.Minute)))
+		Expect(session.Users).To(HaveLen(2))
+		Expect(session.Users[0].ID).To(Equal("bob"))
+		Expect(session.Users[0].ViewMode).To(Equal("VIEW_MODE_READ_ONLY"))
+		Expect(session.Users[1].ID).To(Equal("alice"))
+	})
+
+	It("drops inactive users", func() {
+		Expect(registry.Record(sessions.Activity{FileID: "abc", ResourceID: resourceID, User: bob, Time: start})).To(Succeed())
+		Expect(registry.Record(sessions.Activity{FileID: "abc", ResourceID: resourceID, User: alice, Time: start.Add(time.Hour)})).To(Succeed())
+
+		session, err := registry.Get("abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.Users).To(HaveLen(1))
+		Expect(session.Users[0].ID).To(Equal("alice"))
+	})
+
+	It("keeps track of the lock", func() {
+		Expect(registry.Record(sessions.Activity{
+			FileID:     "abc",
+			ResourceID: resourceID,
+			User:       alice,
+			Lock:       sessions.LockAcquired,
+			LockID:     "lock-1",
+			Time:       start,
+		})).To(Succeed())
+
+		session, err := registry.Get("abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.LockID).To(Equal("lock-1"))
+		Expect(session.LockHolder).To(Equal("alice"))
+
+		Expect(registry.Record(sessions.Activity{FileID: "abc", ResourceID: resourceID, User: bob, Time: start})).To(Succeed())
+		session, err = registry.Get("abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.LockID).To(Equal("lock-1"))
+
+		Expect(registry.Record(sessions.Activity{
+			FileID:     "abc",
+			ResourceID: resourceID,
+			User:       alice,
+			Lock:       sessions.LockReleased,
+			Time:       start,
+		})).To(Succeed())
+		session, err = registry.Get("abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.LockID).To(BeEmpty())
+		Expect(session.LockHolder).To(BeEmpty())
+	})
+
+	It("doesn't lose the users recorded by other instances", func() {
+		// two instances of the service sharing the store
+		other := sessions.NewRegistry(st, kv.NewMemoryStore(0), "Collabora", 30*time.Minute)
+
+		var wg sync.WaitGroup
+		for i, r := range []*sessions.Registry{registry, other, registry, other} {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				u := &userv1beta1.User{Id: &userv1beta1.UserId{OpaqueId: strconv.Itoa(i)}}
+				Expect(r.Record(sessions.Activity{FileID: "abc", ResourceID: resourceID, User: u, Time: start})).To(Succeed())
+			}()
+		}
+		wg.Wait()
+
+		session, err := registry.Get("abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.Users).To(HaveLen(4))
+	})
+
+	It("lists the sessions of the app, the most recently active first", func() {
+		other := sessions.NewRegistry(st, kv.NewMemoryStore(0), "OnlyOffice", 30*time.Minute)
+		Expect(other.Record(sessions.Activity{FileID: "ghi", ResourceID: resourceID, User: alice, Time: start})).To(Succeed())
+
+		Expect(registry.Record(sessions.Activity{FileID: "abc", ResourceID: resourceID, User: alice, Time: start})).To(Succeed())
+		Expect(registry.Record(sessions.Activity{FileID: "def", ResourceID: resourceID, User: bob, Time: start.Add(time.Minute)})).To(Succeed())
+
+		list, err := registry.List()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(HaveLen(2))
+		Expect(list[0].ID).To(Equal("def"))
+		Expect(list[1].ID).To(Equal("abc"))
+	})
+})
+
+var _ = Describe("Middleware", func() {
+	var (
+		cfg        *config.Config
+		registry   *sessions.Registry
+		wopiCtx    middleware.WopiContext
+		user       *userv1beta1.User
+		fileID     string
+		statusCode int
+		handler    http.Handler
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			Wopi: config.Wopi{
+				Secret: "wopiSecret",
+			},
+		}
+		registry = sessions.NewRegistry(kv.NewMemoryStore(30*time.Minute), kv.NewMemoryStore(0), "Collabora", 30*time.Minute)
+		resourceID := &providerv1beta1.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "fileid"}
+		fileID = helpers.HashResourceId(resourceID)
+		wopiCtx = middleware.WopiContext{
+			AccessToken: "revaToken",
+			FileReference: &providerv1beta1.Reference{
+				ResourceId: resourceID,
+			},
+			ViewMode: appproviderv1beta1.ViewMode_VIEW_MODE_READ_WRITE,
+		}
+		user = &userv1beta1.User{Id: &userv1beta1.UserId{OpaqueId: "alice"}, DisplayName: "Alice"}
+		statusCode = http.StatusOK
+
+		handler = sessions.Middleware(cfg, registry, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+		}))
+	})
+
+	serve := func(override, lockID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/wopi/files/"+fileID+"?access_token=wopiToken", nil)
+		req.Header.Set("X-WOPI-Override", override)
+		req.Header.Set("X-WOPI-Lock", lockID)
+		ctx := middleware.WopiContextToCtx(context.Background(), wopiCtx)
+		ctx = ctxpkg.ContextSetUser(ctx, user)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req.WithContext(ctx))
+		return resp
+	}
+
+	It("records the lock of successful requests", func() {
+		Expect(serve("LOCK", "lock-1").Code).To(Equal(http.StatusOK))
+
+		session, err := registry.Get(fileID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.LockID).To(Equal("lock-1"))
+		Expect(session.LockHolder).To(Equal("alice"))
+		Expect(session.Users).To(HaveLen(1))
+
+		statusCode = http.StatusConflict
+		Expect(serve("UNLOCK", "lock-2").Code).To(Equal(http.StatusConflict))
+		session, err = registry.Get(fileID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.LockID).To(Equal("lock-1"))
+
+		statusCode = http.StatusOK
+		Expect(serve("UNLOCK", "lock-1").Code).To(Equal(http.StatusOK))
+		session, err = registry.Get(fileID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.LockID).To(BeEmpty())
+	})
+})
@@ -36,6 +36,39 @@ func GenerateWopiSrc(fileRef string, cfg *config.Config) (*url.URL, error) {
 	return generateDirectSrc(fileRef, wopiSrcURL)
 }
 
+// GenerateContainerSrc generates the WOPI URL for the given container reference.
+// Containers are always accessed directly because the proxy only supports files.
+// Example:
+// https://cloud.example.test/wopi/containers/12312678470610632091729803710923
+func GenerateContainerSrc(containerRef string, cfg *config.Config) (*url.URL, error) {
+	wopiSrcURL, err := url.Parse(cfg.Wopi.WopiSrc)
+	if err != nil {
+		return nil, err
+	}
+	if wopiSrcURL.Host == "" {
+		return nil, errors.New("invalid WopiSrc URL")
+	}
+
+	wopiSrcURL.Path = path.Join("wopi", "containers", containerRef)
+	return wopiSrcURL, nil
+}
+
+// GenerateEcosystemSrc generates the WOPI URL of the ecosystem.
+// Example:
+// https://cloud.example.test/wopi/ecosystem
+func GenerateEcosystemSrc(cfg *config.Config) (*url.URL, error) {
+	wopiSrcURL, err := url.Parse(cfg.Wopi.WopiSrc)
+	if err != nil {
+		return nil, err
+	}
+	if wopiSrcURL.Host == "" {
+		return nil, errors.New("invalid WopiSrc URL")
+	}
+
+	wopiSrcURL.Path = path.Join("wopi", "ecosystem")
+	return wopiSrcURL, nil
+}
+
 func generateDirectSrc(fileRef string, wopiSrcURL *url.URL) (*url.URL, error) {
 	wopiSrcURL.Path = path.Join("wopi", "files", fileRef)
 	return wopiSrcURL, nil
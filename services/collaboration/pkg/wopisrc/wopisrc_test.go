@@ -61,4 +61,40 @@ var _ = Describe("Wopisrc Test", func() {
 			})
 		})
 	})
+
+	Context("GenerateContainerSrc", func() {
+		BeforeEach(func() {
+			c = &config.Config{
+				Wopi: config.Wopi{
+					WopiSrc:     "https://cloud.example.test/wopi/files",
+					ProxyURL:    "https://cloud.proxy.com",
+					ProxySecret: "secret",
+				},
+			}
+		})
+		It("should generate a direct URL even if a proxy is configured", func() {
+			url, err := wopisrc.GenerateContainerSrc("123456", c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(url.String()).To(Equal("https://cloud.example.test/wopi/containers/123456"))
+		})
+		It("should fail for an invalid WopiSrc URL", func() {
+			c.Wopi.WopiSrc = "cloud"
+			url, err := wopisrc.GenerateContainerSrc("123456", c)
+			Expect(err).To(HaveOccurred())
+			Expect(url).To(BeNil())
+		})
+	})
+
+	Context("GenerateEcosystemSrc", func() {
+		It("should generate the ecosystem URL", func() {
+			c = &config.Config{
+				Wopi: config.Wopi{
+					WopiSrc: "https://cloud.example.test",
+				},
+			}
+			url, err := wopisrc.GenerateEcosystemSrc(c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(url.String()).To(Equal("https://cloud.example.test/wopi/ecosystem"))
+		})
+	})
 })
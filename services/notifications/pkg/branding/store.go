@@ -0,0 +1,161 @@
+// Package branding persists the email templates and the logo managed at runtime.
+package branding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/opencloud-eu/reva/v2/pkg/errtypes"
+	"github.com/opencloud-eu/reva/v2/pkg/storage/utils/metadata"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/email"
+)
+
+const (
+	// SpaceID is the id of the metadata space holding the branding
+	SpaceID = "3b1cbd6e-9e4e-4bd6-a6e2-5d0f7c9a4e21"
+
+	_brandingFile = "branding.json"
+
+	// _updateRetries is the number of times Update tries to store the branding
+	_updateRetries = 5
+)
+
+// ErrConflict is returned by Update if the branding kept being changed concurrently
+var ErrConflict = errors.New("the branding was changed concurrently")
+
+// Store keeps the branding in the metadata storage. Reads are cached for the given ttl,
+// so changes made by other instances show up after the ttl at the latest.
+type Store struct {
+	storage metadata.Storage
+	ttl     time.Duration
+	logger  log.Logger
+
+	mu       sync.Mutex
+	branding *email.Branding
+	loadedAt time.Time
+}
+
+// NewStore returns a new Store backed by the given metadata storage, the storage has to be initialized.
+func NewStore(storage metadata.Storage, ttl time.Duration, logger log.Logger) *Store {
+	return &Store{
+		storage: storage,
+		ttl:     ttl,
+		logger:  logger,
+	}
+}
+
+// Get returns the current branding, the result must not be modified.
+func (s *Store) Get(ctx context.Context) (*email.Branding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.branding != nil && time.Since(s.loadedAt) < s.ttl {
+		return s.branding, nil
+	}
+
+	b, _, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.branding, s.loadedAt = b, time.Now()
+	return b, nil
+}
+
+// Branding returns the current branding for rendering emails. Errors are logged and nil is returned,
+// which renders the emails without customizations. It is safe to call on a nil Store.
+func (s *Store) Branding(ctx context.Context) *email.Branding {
+	if s == nil {
+		return nil
+	}
+
+	b, err := s.Get(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("could not load the email branding")
+		return nil
+	}
+	return b
+}
+
+// Update loads the current branding, applies the changes and stores it. The branding is only stored
+// if it wasn't changed in the meantime, e.g. by another instance, otherwise the update is retried
+// with the new branding, so f may be called several times.
+func (s *Store) Update(ctx context.Context, f func(b *email.Branding) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < _updateRetries; i++ {
+		b, err := s.update(ctx, f)
+		switch {
+		case isConflict(err):
+			s.logger.Debug().Err(err).Msg("the email branding was changed concurrently, retrying")
+			continue
+		case err != nil:
+			return err
+		}
+
+		s.branding, s.loadedAt = b, time.Now()
+		return nil
+	}
+
+	return ErrConflict
+}
+
+func (s *Store) update(ctx context.Context, f func(b *email.Branding) error) (*email.Branding, error) {
+	b, etag, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f(b); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	req := metadata.UploadRequest{
+		Path:        _brandingFile,
+		Content:     data,
+		IfMatchEtag: etag,
+	}
+	// make sure the file wasn't created in the meantime if it didn't exist
+	if etag == "" {
+		req.IfNoneMatch = []string{"*"}
+	}
+	if _, err := s.storage.Upload(ctx, req); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// load reads the branding and its etag from the storage, a missing file results in an empty branding
+func (s *Store) load(ctx context.Context) (*email.Branding, string, error) {
+	res, err := s.storage.Download(ctx, metadata.DownloadRequest{Path: _brandingFile})
+	switch {
+	case errors.As(err, new(errtypes.NotFound)):
+		return &email.Branding{}, "", nil
+	case err != nil:
+		return nil, "", err
+	}
+
+	b := &email.Branding{}
+	if err := json.Unmarshal(res.Content, b); err != nil {
+		return nil, "", err
+	}
+	return b, res.Etag, nil
+}
+
+// isConflict returns true if the upload failed because the branding was changed concurrently.
+// The storages report it differently, see the jsoncs3 share manager.
+func isConflict(err error) bool {
+	return errors.As(err, new(errtypes.Aborted)) ||
+		errors.As(err, new(errtypes.PreconditionFailed)) ||
+		errors.As(err, new(errtypes.AlreadyExists))
+}
@@ -0,0 +1,123 @@
+package branding_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/opencloud-eu/reva/v2/pkg/errtypes"
+	"github.com/opencloud-eu/reva/v2/pkg/storage/utils/metadata"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/branding"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/email"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	storage, err := metadata.NewDiskStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := branding.NewStore(storage, time.Minute, log.NopLogger())
+	b, err := s.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if b.HTMLLayout != "" || b.Logo != nil || len(b.Texts) != 0 {
+		t.Fatalf("Get() = %v, want an empty branding", b)
+	}
+
+	err = s.Update(ctx, func(b *email.Branding) error {
+		b.TextLayout = "{{ .MessageBody }}"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// a new store has to read the branding from the storage
+	b, err = branding.NewStore(storage, time.Minute, log.NopLogger()).Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if b.TextLayout != "{{ .MessageBody }}" {
+		t.Fatalf("TextLayout = %q", b.TextLayout)
+	}
+}
+
+func TestStoreConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	storage, err := metadata.NewDiskStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := branding.NewStore(storage, time.Minute, log.NopLogger())
+	other := branding.NewStore(storage, time.Minute, log.NopLogger())
+	if err := s.Update(ctx, func(b *email.Branding) error { return nil }); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	calls := 0
+	err = s.Update(ctx, func(b *email.Branding) error {
+		calls++
+		if calls == 1 {
+			// another instance changes the branding in the meantime
+			err := other.Update(ctx, func(b *email.Branding) error {
+				b.HTMLLayout = "<p>{{ .MessageBody }}</p>"
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+		}
+		b.TextLayout = "{{ .MessageBody }}"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("the update was applied %d times, want 2", calls)
+	}
+
+	b, err := branding.NewStore(storage, time.Minute, log.NopLogger()).Get(ctx)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if b.TextLayout != "{{ .MessageBody }}" || b.HTMLLayout != "<p>{{ .MessageBody }}</p>" {
+		t.Fatalf("Get() = %v, want both changes", b)
+	}
+}
+
+// conflictingStorage fails every upload as if the file was changed concurrently
+type conflictingStorage struct {
+	metadata.Storage
+}
+
+func (conflictingStorage) Upload(context.Context, metadata.UploadRequest) (*metadata.UploadResponse, error) {
+	return nil, errtypes.Aborted("etag mismatch")
+}
+
+func TestStoreUpdateConflict(t *testing.T) {
+	storage, err := metadata.NewDiskStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := branding.NewStore(conflictingStorage{storage}, time.Minute, log.NopLogger())
+	err = s.Update(context.Background(), func(b *email.Branding) error { return nil })
+	if !errors.Is(err, branding.ErrConflict) {
+		t.Fatalf("Update() error = %v, want %v", err, branding.ErrConflict)
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var s *branding.Store
+	if b := s.Branding(context.Background()); b != nil {
+		t.Fatalf("Branding() = %v, want nil", b)
+	}
+}
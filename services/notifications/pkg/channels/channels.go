@@ -14,6 +14,13 @@ import (
 	"github.com/opencloud-eu/opencloud/services/notifications/pkg/config"
 )
 
+// The names of the additional channels, they match the option keys of the notification settings of the users.
+const (
+	NameWebhook = "webhook"
+	NameMatrix  = "matrix"
+	NamePush    = "push"
+)
+
 // Channel defines the methods of a communication channel.
 type Channel interface {
 	// SendMessage sends a message to users.
@@ -22,8 +29,10 @@ type Channel interface {
 
 // Message represent the already rendered message including the user id opaqueID
 type Message struct {
-	Sender       string
-	Recipient    []string
+	Sender    string
+	Recipient []string
+	// Username is the name of the user the message is sent to. It is only set for the additional channels.
+	Username     string
 	Subject      string
 	TextBody     string
 	HTMLBody     string
@@ -0,0 +1,274 @@
+package channels
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/config"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func newRecordingServer(t *testing.T, status int) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{method: r.Method, path: r.URL.EscapedPath(), header: r.Header.Clone(), body: body})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+var testMessage = &Message{
+	Sender:   "Dr. S. Harer",
+	Subject:  "Dr. S. Harer shared 'secrets' with you",
+	TextBody: "Hello Eric",
+}
+
+func TestWebhook_SendMessage(t *testing.T) {
+	srv, requests := newRecordingServer(t, http.StatusNoContent)
+
+	cfg := config.Config{}
+	cfg.Notifications.Webhook.Secret = "secret"
+	cfg.Notifications.Webhook.AllowPrivateNetworks = true
+	ch, err := NewWebhookChannel(cfg, log.NopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := *testMessage
+	msg.Recipient = []string{srv.URL + "/hook"}
+	if err := ch.SendMessage(context.Background(), &msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(*requests))
+	}
+	req := (*requests)[0]
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Subject != testMessage.Subject || payload.Message != testMessage.TextBody || payload.Sender != testMessage.Sender {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(req.body)
+	if got, want := req.header.Get("X-OpenCloud-Signature"), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("signature = %v, want %v", got, want)
+	}
+}
+
+func TestWebhook_SendMessageAllowedHosts(t *testing.T) {
+	srv, requests := newRecordingServer(t, http.StatusOK)
+
+	cfg := config.Config{}
+	cfg.Notifications.Webhook.AllowedHosts = []string{"hooks.example.com"}
+	ch, err := NewWebhookChannel(cfg, log.NopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := *testMessage
+	msg.Recipient = []string{srv.URL}
+	if err := ch.SendMessage(context.Background(), &msg); err == nil {
+		t.Error("expected an error for a host which is not allowed")
+	}
+	if len(*requests) != 0 {
+		t.Errorf("expected no request, got %d", len(*requests))
+	}
+}
+
+func TestWebhook_SendMessagePrivateNetworks(t *testing.T) {
+	srv, requests := newRecordingServer(t, http.StatusOK)
+
+	ch, err := NewWebhookChannel(config.Config{}, log.NopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := *testMessage
+	msg.Recipient = []string{srv.URL}
+	if err := ch.SendMessage(context.Background(), &msg); !errors.Is(err, errForbiddenAddress) {
+		t.Errorf("expected the loopback address to be refused, got %v", err)
+	}
+	if len(*requests) != 0 {
+		t.Errorf("expected no request, got %d", len(*requests))
+	}
+}
+
+func TestWebhook_SendMessageRedirect(t *testing.T) {
+	target, requests := newRecordingServer(t, http.StatusOK)
+	redirect := httptest.NewServer(http.RedirectHandler(strings.Replace(target.URL, "127.0.0.1", "localhost", 1), http.StatusTemporaryRedirect))
+	t.Cleanup(redirect.Close)
+
+	cfg := config.Config{}
+	cfg.Notifications.Webhook.AllowedHosts = []string{"127.0.0.1"}
+	cfg.Notifications.Webhook.AllowPrivateNetworks = true
+	ch, err := NewWebhookChannel(cfg, log.NopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := *testMessage
+	msg.Recipient = []string{redirect.URL}
+	if err := ch.SendMessage(context.Background(), &msg); err == nil {
+		t.Error("expected the redirect to a host which is not allowed to fail")
+	}
+	if len(*requests) != 0 {
+		t.Errorf("expected no request, got %d", len(*requests))
+	}
+}
+
+func TestMatrix_SendMessage(t *testing.T) {
+	srv, requests := newRecordingServer(t, http.StatusOK)
+
+	cfg := config.Config{}
+	cfg.Notifications.Matrix.HomeserverURL = srv.URL
+	cfg.Notifications.Matrix.AccessToken = "token"
+	ch, err := NewMatrixChannel(cfg, log.NopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := *testMessage
+	msg.Recipient = []string{"!room:example.com"}
+	msg.Username = "einstein"
+	if err := ch.SendMessage(context.Background(), &msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(*requests))
+	}
+	if got, want := (*requests)[0].path, "/_matrix/client/v3/rooms/%21room:example.com/state/eu.opencloud.notifications/einstein"; got != want {
+		t.Errorf("verification path = %v, want %v", got, want)
+	}
+
+	req := (*requests)[1]
+	if req.method != http.MethodPut {
+		t.Errorf("method = %v, want PUT", req.method)
+	}
+	if !strings.HasPrefix(req.path, "/_matrix/client/v3/rooms/%21room:example.com/send/m.room.message/") {
+		t.Errorf("unexpected path %v", req.path)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %v", got)
+	}
+	if !strings.Contains(string(req.body), `"msgtype":"m.text"`) {
+		t.Errorf("unexpected body %s", req.body)
+	}
+}
+
+func TestMatrix_SendMessageUnverifiedRoom(t *testing.T) {
+	srv, requests := newRecordingServer(t, http.StatusNotFound)
+
+	cfg := config.Config{}
+	cfg.Notifications.Matrix.HomeserverURL = srv.URL
+	cfg.Notifications.Matrix.AccessToken = "token"
+	ch, err := NewMatrixChannel(cfg, log.NopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := *testMessage
+	msg.Recipient = []string{"!room:example.com"}
+	msg.Username = "einstein"
+	if err := ch.SendMessage(context.Background(), &msg); !errors.Is(err, errRoomNotVerified) {
+		t.Fatalf("expected the room to be rejected, got %v", err)
+	}
+	for _, req := range *requests {
+		if req.method != http.MethodGet {
+			t.Errorf("expected no message to be sent, got %s %s", req.method, req.path)
+		}
+	}
+}
+
+func TestPush_SendMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		pushType   string
+		wantPath   string
+		wantHeader string
+		wantValue  string
+	}{
+		{
+			name:       "ntfy",
+			pushType:   "ntfy",
+			wantPath:   "/alerts",
+			wantHeader: "Title",
+			wantValue:  testMessage.Subject,
+		},
+		{
+			name:       "gotify",
+			pushType:   "gotify",
+			wantPath:   "/message",
+			wantHeader: "X-Gotify-Key",
+			wantValue:  "alerts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := newRecordingServer(t, http.StatusOK)
+
+			cfg := config.Config{}
+			cfg.Notifications.Push.Type = tt.pushType
+			cfg.Notifications.Push.URL = srv.URL
+			ch, err := NewPushChannel(cfg, log.NopLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg := *testMessage
+			msg.Recipient = []string{"alerts"}
+			if err := ch.SendMessage(context.Background(), &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			req := (*requests)[0]
+			if req.path != tt.wantPath {
+				t.Errorf("path = %v, want %v", req.path, tt.wantPath)
+			}
+			if got := req.header.Get(tt.wantHeader); got != tt.wantValue {
+				t.Errorf("%s = %v, want %v", tt.wantHeader, got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestPush_SendMessageFails(t *testing.T) {
+	srv, _ := newRecordingServer(t, http.StatusForbidden)
+
+	cfg := config.Config{}
+	cfg.Notifications.Push.Type = "ntfy"
+	cfg.Notifications.Push.URL = srv.URL
+	ch, err := NewPushChannel(cfg, log.NopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := *testMessage
+	msg.Recipient = []string{"alerts"}
+	if err := ch.SendMessage(context.Background(), &msg); err == nil {
+		t.Error("expected an error for an unsuccessful status code")
+	}
+}
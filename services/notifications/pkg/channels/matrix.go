@@ -0,0 +1,122 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/config"
+)
+
+// NewMatrixChannel instantiates a new matrix communication channel.
+func NewMatrixChannel(cfg config.Config, logger log.Logger) (Channel, error) {
+	if _, err := url.Parse(cfg.Notifications.Matrix.HomeserverURL); err != nil {
+		logger.Err(err).Msg("parsing error, the 'homeserver_url' must be a valid URL.")
+		return nil, err
+	}
+	return Matrix{
+		conf:   cfg.Notifications.Matrix,
+		client: newHTTPClient(cfg.Notifications.Matrix.Insecure),
+		logger: logger,
+	}, nil
+}
+
+// Matrix is the communication channel which sends the messages to the recipient rooms
+// using the Matrix client-server API.
+type Matrix struct {
+	conf   config.Matrix
+	client *http.Client
+	logger log.Logger
+}
+
+// MatrixVerificationEvent is the type of the state event which links a room to an OpenCloud user. The state key
+// is the username. Only members allowed to change the state of a room can add it, so messages of a user can't be
+// sent to rooms of others.
+const MatrixVerificationEvent = "eu.opencloud.notifications"
+
+// errRoomNotVerified is returned when a room isn't linked to the user
+var errRoomNotVerified = errors.New("the matrix room is not linked to the user")
+
+// matrixMessage is the content of a m.room.message event
+type matrixMessage struct {
+	MsgType string `json:"msgtype"`
+	Body    string `json:"body"`
+}
+
+// SendMessage sends the message to all recipient rooms.
+func (m Matrix) SendMessage(ctx context.Context, message *Message) error {
+	body, err := json.Marshal(matrixMessage{
+		MsgType: "m.text",
+		Body:    message.Subject + "\n\n" + message.TextBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, room := range message.Recipient {
+		if err := m.verify(ctx, room, message.Username); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// the transaction id makes the request idempotent
+		endpoint, err := url.JoinPath(m.conf.HomeserverURL, "_matrix/client/v3/rooms", url.PathEscape(room), "send/m.room.message", uuid.NewString())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+m.conf.AccessToken)
+
+		if err := do(m.client, req); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// verify checks that the room contains the verification state event of the user
+func (m Matrix) verify(ctx context.Context, room, username string) error {
+	if username == "" {
+		return errRoomNotVerified
+	}
+	endpoint, err := url.JoinPath(m.conf.HomeserverURL, "_matrix/client/v3/rooms", url.PathEscape(room), "state", MatrixVerificationEvent, url.PathEscape(username))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.conf.AccessToken)
+
+	res, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errRoomNotVerified, room)
+	default:
+		return fmt.Errorf("unexpected status code %d from %s", res.StatusCode, req.URL.Host)
+	}
+}
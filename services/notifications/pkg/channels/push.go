@@ -0,0 +1,107 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/config"
+)
+
+// NewPushChannel instantiates a new push communication channel.
+func NewPushChannel(cfg config.Config, logger log.Logger) (Channel, error) {
+	if _, err := url.Parse(cfg.Notifications.Push.URL); err != nil {
+		logger.Err(err).Msg("parsing error, the push 'url' must be a valid URL.")
+		return nil, err
+	}
+	return Push{
+		conf:   cfg.Notifications.Push,
+		client: newHTTPClient(cfg.Notifications.Push.Insecure),
+		logger: logger,
+	}, nil
+}
+
+// Push is the communication channel for ntfy and gotify push servers.
+// The recipients are ntfy topics or gotify application tokens.
+type Push struct {
+	conf   config.Push
+	client *http.Client
+	logger log.Logger
+}
+
+// gotifyMessage is the message document of the gotify API
+type gotifyMessage struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// SendMessage publishes the message to all recipient topics.
+func (p Push) SendMessage(ctx context.Context, message *Message) error {
+	var errs []error
+	for _, topic := range message.Recipient {
+		var (
+			req *http.Request
+			err error
+		)
+		switch p.conf.Type {
+		case "gotify":
+			req, err = p.gotifyRequest(ctx, topic, message)
+		default:
+			req, err = p.ntfyRequest(ctx, topic, message)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := do(p.client, req); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p Push) ntfyRequest(ctx context.Context, topic string, message *Message) (*http.Request, error) {
+	endpoint, err := url.JoinPath(p.conf.URL, url.PathEscape(topic))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(message.TextBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Title", message.Subject)
+	if p.conf.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.conf.Token)
+	}
+	return req, nil
+}
+
+func (p Push) gotifyRequest(ctx context.Context, token string, message *Message) (*http.Request, error) {
+	endpoint, err := url.JoinPath(p.conf.URL, "message")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(gotifyMessage{
+		Title:   message.Subject,
+		Message: message.TextBody,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", token)
+	return req, nil
+}
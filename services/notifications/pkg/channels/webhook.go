@@ -0,0 +1,189 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/config"
+)
+
+// _httpTimeout is the timeout for requests of the http based channels
+const _httpTimeout = 10 * time.Second
+
+// WebhookPayload is the JSON document posted to the webhook URLs.
+type WebhookPayload struct {
+	Sender  string `json:"sender,omitempty"`
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+// _maxRedirects is the number of redirects followed for webhooks
+const _maxRedirects = 5
+
+// errForbiddenAddress is returned when a webhook URL resolves to an address in a private network
+var errForbiddenAddress = errors.New("webhook address is not allowed")
+
+// NewWebhookChannel instantiates a new webhook communication channel.
+func NewWebhookChannel(cfg config.Config, logger log.Logger) (Channel, error) {
+	w := Webhook{
+		conf:   cfg.Notifications.Webhook,
+		logger: logger,
+	}
+	w.client = newWebhookClient(w.conf)
+	return w, nil
+}
+
+// Webhook is the communication channel which posts the messages as JSON to the recipient URLs.
+type Webhook struct {
+	conf   config.Webhook
+	client *http.Client
+	logger log.Logger
+}
+
+// SendMessage posts the message to all recipient URLs.
+func (w Webhook) SendMessage(ctx context.Context, message *Message) error {
+	body, err := json.Marshal(WebhookPayload{
+		Sender:  message.Sender,
+		Subject: message.Subject,
+		Message: message.TextBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, recipient := range message.Recipient {
+		if err := w.post(ctx, recipient, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w Webhook) post(ctx context.Context, target string, body []byte) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	if err := validateWebhookURL(w.conf, u); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.conf.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.conf.Secret))
+		mac.Write(body)
+		req.Header.Set("X-OpenCloud-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	return do(w.client, req)
+}
+
+// validateWebhookURL checks the scheme and the host of a webhook url. The addresses are checked when connecting.
+func validateWebhookURL(conf config.Webhook, u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported webhook url scheme '%s'", u.Scheme)
+	}
+	if len(conf.AllowedHosts) > 0 && !slices.Contains(conf.AllowedHosts, u.Hostname()) {
+		return fmt.Errorf("webhook host '%s' is not allowed", u.Hostname())
+	}
+	return nil
+}
+
+// newWebhookClient returns the http client used to post to the webhook urls. The webhook urls are chosen by the
+// users, so unless private networks are allowed, the client refuses to connect to loopback, private and link-local
+// addresses. The addresses are checked after resolving the host names, so a host name can't be used to point to
+// an internal service. Redirects are validated like the webhook urls. Proxies are not used, because the client
+// couldn't check the addresses the proxy connects to.
+func newWebhookClient(conf config.Webhook) *http.Client {
+	dialer := &net.Dialer{Timeout: _httpTimeout}
+	if !conf.AllowPrivateNetworks {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if isInternalAddr(addr.Unmap()) {
+				return fmt.Errorf("%w: %s", errForbiddenAddress, addr)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: _httpTimeout,
+		Transport: &http.Transport{
+			DialContext: dialer.DialContext,
+			TLSClientConfig: &tls.Config{
+				MinVersion:         tls.VersionTLS12,
+				InsecureSkipVerify: conf.Insecure, //nolint:gosec
+			},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= _maxRedirects {
+				return errors.New("too many redirects")
+			}
+			return validateWebhookURL(conf, req.URL)
+		},
+	}
+}
+
+// isInternalAddr returns true for addresses which aren't reachable from the internet
+func isInternalAddr(addr netip.Addr) bool {
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() || _sharedAddressSpace.Contains(addr)
+}
+
+// _sharedAddressSpace is the carrier-grade NAT range (RFC 6598)
+var _sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// newHTTPClient returns the http client used by the http based channels
+func newHTTPClient(insecure bool) *http.Client {
+	return &http.Client{
+		Timeout: _httpTimeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				MinVersion:         tls.VersionTLS12,
+				InsecureSkipVerify: insecure, //nolint:gosec
+			},
+		},
+	}
+}
+
+// do sends the request and fails on unsuccessful status codes
+func do(client *http.Client, req *http.Request) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d from %s", res.StatusCode, req.URL.Host)
+	}
+	return nil
+}
@@ -15,17 +15,21 @@ import (
 	"github.com/opencloud-eu/reva/v2/pkg/events"
 	"github.com/opencloud-eu/reva/v2/pkg/events/stream"
 	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
+	revaMetadata "github.com/opencloud-eu/reva/v2/pkg/storage/utils/metadata"
 
 	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
 	"github.com/opencloud-eu/opencloud/pkg/registry"
 	"github.com/opencloud-eu/opencloud/pkg/service/grpc"
+	"github.com/opencloud-eu/opencloud/pkg/storage/metadata"
 	"github.com/opencloud-eu/opencloud/pkg/tracing"
 	settingssvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/settings/v0"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/branding"
 	"github.com/opencloud-eu/opencloud/services/notifications/pkg/channels"
 	"github.com/opencloud-eu/opencloud/services/notifications/pkg/config"
 	"github.com/opencloud-eu/opencloud/services/notifications/pkg/config/parser"
 	"github.com/opencloud-eu/opencloud/services/notifications/pkg/logging"
 	"github.com/opencloud-eu/opencloud/services/notifications/pkg/server/debug"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/server/http"
 	"github.com/opencloud-eu/opencloud/services/notifications/pkg/service"
 )
 
@@ -78,6 +82,44 @@ func Server(cfg *config.Config) *cli.Command {
 				})
 			}
 
+			brandingStorage, err := revaMetadata.NewCS3Storage(
+				cfg.Metadata.GatewayAddress,
+				cfg.Metadata.StorageAddress,
+				cfg.Metadata.SystemUserID,
+				cfg.Metadata.SystemUserIDP,
+				cfg.Metadata.SystemUserAPIKey,
+			)
+			if err != nil {
+				return fmt.Errorf("could not initialize reva metadata storage: %w", err)
+			}
+			brandingStorage, err = metadata.NewLazyStorage(brandingStorage)
+			if err != nil {
+				return fmt.Errorf("could not initialize lazy metadata storage: %w", err)
+			}
+			if err := brandingStorage.Init(context.Background(), branding.SpaceID); err != nil {
+				return fmt.Errorf("could not initialize metadata storage: %w", err)
+			}
+			brandingStore := branding.NewStore(brandingStorage, cfg.Notifications.BrandingCacheTTL, logger)
+
+			{
+				server, err := http.Server(
+					http.Logger(logger),
+					http.Context(ctx),
+					http.Config(cfg),
+					http.BrandingStore(brandingStore),
+					http.Role(settingssvc.NewRoleService("eu.opencloud.api.settings", grpcClient)),
+					http.TracerProvider(traceProvider),
+				)
+				if err != nil {
+					logger.Info().Err(err).Str("transport", "http").Msg("Failed to initialize server")
+					return err
+				}
+
+				gr.Add(server.Run, func(_ error) {
+					cancel()
+				})
+			}
+
 			// evs defines a list of events to subscribe to
 			evs := []events.Unmarshaller{
 				events.ShareCreated{},
@@ -106,6 +148,22 @@ func Server(cfg *config.Config) *cli.Command {
 			if err != nil {
 				return err
 			}
+			extraChannels := map[string]channels.Channel{}
+			if cfg.Notifications.Webhook.Enabled {
+				if extraChannels[channels.NameWebhook], err = channels.NewWebhookChannel(*cfg, logger); err != nil {
+					return err
+				}
+			}
+			if cfg.Notifications.Matrix.HomeserverURL != "" {
+				if extraChannels[channels.NameMatrix], err = channels.NewMatrixChannel(*cfg, logger); err != nil {
+					return err
+				}
+			}
+			if cfg.Notifications.Push.URL != "" {
+				if extraChannels[channels.NamePush], err = channels.NewPushChannel(*cfg, logger); err != nil {
+					return err
+				}
+			}
 			tm, err := pool.StringToTLSMode(cfg.Notifications.GRPCClientTLS.Mode)
 			if err != nil {
 				return err
@@ -132,10 +190,10 @@ func Server(cfg *config.Config) *cli.Command {
 				store.Authentication(cfg.Store.AuthUsername, cfg.Store.AuthPassword),
 			)
 
-			svc := service.NewEventsNotifier(evts, channel, logger, gatewaySelector, valueService,
+			svc := service.NewEventsNotifier(evts, channel, extraChannels, logger, gatewaySelector, valueService,
 				cfg.ServiceAccount.ServiceAccountID, cfg.ServiceAccount.ServiceAccountSecret,
 				cfg.Notifications.EmailTemplatePath, cfg.Notifications.DefaultLanguage, cfg.WebUIURL,
-				cfg.Notifications.TranslationPath, cfg.Notifications.SMTP.Sender, notificationStore, historyClient, registeredEvents)
+				cfg.Notifications.TranslationPath, cfg.Notifications.SMTP.Sender, notificationStore, historyClient, registeredEvents, brandingStore)
 
 			gr.Add(svc.Run, func(error) {
 				cancel()
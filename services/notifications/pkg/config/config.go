@@ -17,6 +17,10 @@ type Config struct {
 	Tracing *Tracing `yaml:"tracing"`
 	Log     *Log     `yaml:"log"`
 	Debug   Debug    `yaml:"debug"`
+	HTTP    HTTP     `yaml:"http"`
+
+	TokenManager *TokenManager `yaml:"token_manager"`
+	Metadata     Metadata      `yaml:"metadata_config"`
 
 	WebUIURL string `yaml:"opencloud_url" env:"OC_URL;NOTIFICATIONS_WEB_UI_URL" desc:"The public facing URL of the OpenCloud Web UI, used e.g. when sending notification eMails" introductionVersion:"1.0.0"`
 
@@ -32,12 +36,16 @@ type Config struct {
 // Notifications defines the config options for the notifications service.
 type Notifications struct {
 	SMTP              SMTP                  `yaml:"SMTP"`
+	Webhook           Webhook               `yaml:"webhook"`
+	Matrix            Matrix                `yaml:"matrix"`
+	Push              Push                  `yaml:"push"`
 	Events            Events                `yaml:"events"`
 	EmailTemplatePath string                `yaml:"email_template_path" env:"OC_EMAIL_TEMPLATE_PATH;NOTIFICATIONS_EMAIL_TEMPLATE_PATH" desc:"Path to Email notification templates overriding embedded ones." introductionVersion:"1.0.0"`
 	TranslationPath   string                `yaml:"translation_path" env:"OC_TRANSLATION_PATH;NOTIFICATIONS_TRANSLATION_PATH" desc:"(optional) Set this to a path with custom translations to overwrite the builtin translations. Note that file and folder naming rules apply, see the documentation for more details." introductionVersion:"1.0.0"`
 	DefaultLanguage   string                `yaml:"default_language" env:"OC_DEFAULT_LANGUAGE" desc:"The default language used by services and the WebUI. If not defined, English will be used as default. See the documentation for more details." introductionVersion:"1.0.0"`
 	RevaGateway       string                `yaml:"reva_gateway" env:"OC_REVA_GATEWAY" desc:"CS3 gateway used to look up user metadata" introductionVersion:"1.0.0"`
 	GRPCClientTLS     *shared.GRPCClientTLS `yaml:"grpc_client_tls"`
+	BrandingCacheTTL  time.Duration         `yaml:"branding_cache_ttl" env:"NOTIFICATIONS_BRANDING_CACHE_TTL" desc:"Time to cache the email templates and the logo managed via the admin API. Changes made on other instances of the service take effect after this time at the latest. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
 }
 
 // SMTP combines the smtp configuration options.
@@ -52,6 +60,31 @@ type SMTP struct {
 	Encryption     string `yaml:"smtp_encryption" env:"NOTIFICATIONS_SMTP_ENCRYPTION" desc:"Encryption method for the SMTP communication. Possible values are 'starttls', 'ssltls' and 'none'." introductionVersion:"1.0.0"`
 }
 
+// Webhook combines the configuration options for the webhook channel.
+type Webhook struct {
+	Enabled      bool     `yaml:"enabled" env:"NOTIFICATIONS_WEBHOOK_ENABLED" desc:"Allow users to receive notifications as JSON posted to a webhook URL of their choice." introductionVersion:"%%NEXT%%"`
+	AllowedHosts []string `yaml:"allowed_hosts" env:"NOTIFICATIONS_WEBHOOK_ALLOWED_HOSTS" desc:"A list of hosts webhook URLs may point to. If empty, all hosts are allowed. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Secret       string   `yaml:"secret" env:"NOTIFICATIONS_WEBHOOK_SECRET" desc:"A secret used to sign the webhook payload. The HMAC-SHA256 signature is sent in the 'X-OpenCloud-Signature' header. No signature is sent if empty." introductionVersion:"%%NEXT%%"`
+	Insecure     bool     `yaml:"insecure" env:"NOTIFICATIONS_WEBHOOK_INSECURE" desc:"Allow insecure connections to the webhook URLs." introductionVersion:"%%NEXT%%"`
+
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" env:"NOTIFICATIONS_WEBHOOK_ALLOW_PRIVATE_NETWORKS" desc:"Allow webhook URLs pointing to loopback, private and link-local addresses. By default, such URLs are refused, so users can't make the service send requests to internal services." introductionVersion:"%%NEXT%%"`
+}
+
+// Matrix combines the configuration options for the matrix channel.
+type Matrix struct {
+	HomeserverURL string `yaml:"homeserver_url" env:"NOTIFICATIONS_MATRIX_HOMESERVER_URL" desc:"URL of the Matrix homeserver used to send notifications to the rooms chosen by the users. The Matrix channel is disabled if empty." introductionVersion:"%%NEXT%%"`
+	AccessToken   string `yaml:"access_token" env:"NOTIFICATIONS_MATRIX_ACCESS_TOKEN" desc:"Access token of the Matrix account sending the notifications. The account must be a member of the rooms." introductionVersion:"%%NEXT%%"`
+	Insecure      bool   `yaml:"insecure" env:"NOTIFICATIONS_MATRIX_INSECURE" desc:"Allow insecure connections to the Matrix homeserver." introductionVersion:"%%NEXT%%"`
+}
+
+// Push combines the configuration options for the push channel.
+type Push struct {
+	Type     string `yaml:"type" env:"NOTIFICATIONS_PUSH_TYPE" desc:"The type of the push server. Supported values are 'ntfy' and 'gotify'." introductionVersion:"%%NEXT%%"`
+	URL      string `yaml:"url" env:"NOTIFICATIONS_PUSH_URL" desc:"URL of the push server. The push channel is disabled if empty." introductionVersion:"%%NEXT%%"`
+	Token    string `yaml:"token" env:"NOTIFICATIONS_PUSH_TOKEN" desc:"Access token used to publish to an ntfy server. Not used for 'gotify', where the users provide an application token as topic." introductionVersion:"%%NEXT%%"`
+	Insecure bool   `yaml:"insecure" env:"NOTIFICATIONS_PUSH_INSECURE" desc:"Allow insecure connections to the push server." introductionVersion:"%%NEXT%%"`
+}
+
 // Events combines the configuration options for the event bus.
 type Events struct {
 	Endpoint             string `yaml:"endpoint" env:"OC_EVENTS_ENDPOINT;NOTIFICATIONS_EVENTS_ENDPOINT" desc:"The address of the event system. The event system is the message queuing service. It is used as message broker for the microservice architecture." introductionVersion:"1.0.0"`
@@ -79,3 +112,35 @@ type Store struct {
 	AuthUsername string        `yaml:"username" env:"OC_PERSISTENT_STORE_AUTH_USERNAME;NOTIFICATIONS_STORE_AUTH_USERNAME" desc:"The username to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"1.0.0"`
 	AuthPassword string        `yaml:"password" env:"OC_PERSISTENT_STORE_AUTH_PASSWORD;NOTIFICATIONS_STORE_AUTH_PASSWORD" desc:"The password to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"1.0.0"`
 }
+
+// CORS defines the available cors configuration.
+type CORS struct {
+	AllowedOrigins   []string `yaml:"allow_origins" env:"OC_CORS_ALLOW_ORIGINS;NOTIFICATIONS_CORS_ALLOW_ORIGINS" desc:"A list of allowed CORS origins. See following chapter for more details: *Access-Control-Allow-Origin* at https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Access-Control-Allow-Origin. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	AllowedMethods   []string `yaml:"allow_methods" env:"OC_CORS_ALLOW_METHODS;NOTIFICATIONS_CORS_ALLOW_METHODS" desc:"A list of allowed CORS methods. See following chapter for more details: *Access-Control-Request-Method* at https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Access-Control-Request-Method. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	AllowedHeaders   []string `yaml:"allow_headers" env:"OC_CORS_ALLOW_HEADERS;NOTIFICATIONS_CORS_ALLOW_HEADERS" desc:"A list of allowed CORS headers. See following chapter for more details: *Access-Control-Request-Headers* at https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Access-Control-Request-Headers. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	AllowCredentials bool     `yaml:"allow_credentials" env:"OC_CORS_ALLOW_CREDENTIALS;NOTIFICATIONS_CORS_ALLOW_CREDENTIALS" desc:"Allow credentials for CORS.See following chapter for more details: *Access-Control-Allow-Credentials* at https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Access-Control-Allow-Credentials." introductionVersion:"%%NEXT%%"`
+}
+
+// HTTP defines the available http configuration.
+type HTTP struct {
+	Addr      string                `yaml:"addr" env:"NOTIFICATIONS_HTTP_ADDR" desc:"The bind address of the HTTP service." introductionVersion:"%%NEXT%%"`
+	Namespace string                `yaml:"-"`
+	Root      string                `yaml:"root" env:"NOTIFICATIONS_HTTP_ROOT" desc:"Subdirectory that serves as the root for this HTTP service." introductionVersion:"%%NEXT%%"`
+	CORS      CORS                  `yaml:"cors"`
+	TLS       shared.HTTPServiceTLS `yaml:"tls"`
+}
+
+// TokenManager is the config for using the reva token manager
+type TokenManager struct {
+	JWTSecret string `yaml:"jwt_secret" env:"OC_JWT_SECRET;NOTIFICATIONS_JWT_SECRET" desc:"The secret to mint and validate jwt tokens." introductionVersion:"%%NEXT%%"`
+}
+
+// Metadata configures the metadata storage holding the email templates and the logo
+type Metadata struct {
+	GatewayAddress string `yaml:"gateway_addr" env:"NOTIFICATIONS_STORAGE_GATEWAY_GRPC_ADDR;STORAGE_GATEWAY_GRPC_ADDR" desc:"GRPC address of the STORAGE-SYSTEM service." introductionVersion:"%%NEXT%%"`
+	StorageAddress string `yaml:"storage_addr" env:"NOTIFICATIONS_STORAGE_GRPC_ADDR;STORAGE_GRPC_ADDR" desc:"GRPC address of the STORAGE-SYSTEM service." introductionVersion:"%%NEXT%%"`
+
+	SystemUserID     string `yaml:"system_user_id" env:"OC_SYSTEM_USER_ID;NOTIFICATIONS_SYSTEM_USER_ID" desc:"ID of the OpenCloud STORAGE-SYSTEM system user. Admins need to set the ID for the STORAGE-SYSTEM system user in this config option which is then used to reference the user. Any reasonable long string is possible, preferably this would be an UUIDv4 format." introductionVersion:"%%NEXT%%"`
+	SystemUserIDP    string `yaml:"system_user_idp" env:"OC_SYSTEM_USER_IDP;NOTIFICATIONS_SYSTEM_USER_IDP" desc:"IDP of the OpenCloud STORAGE-SYSTEM system user." introductionVersion:"%%NEXT%%"`
+	SystemUserAPIKey string `yaml:"system_user_api_key" env:"OC_SYSTEM_USER_API_KEY" desc:"API key for the STORAGE-SYSTEM system user." introductionVersion:"%%NEXT%%"`
+}
@@ -1,6 +1,7 @@
 package defaults
 
 import (
+	"strings"
 	"time"
 
 	"github.com/opencloud-eu/opencloud/pkg/shared"
@@ -27,6 +28,17 @@ func DefaultConfig() *config.Config {
 			Zpages: false,
 			Pprof:  false,
 		},
+		HTTP: config.HTTP{
+			Addr:      "127.0.0.1:9170",
+			Root:      "/",
+			Namespace: "eu.opencloud.web",
+			CORS: config.CORS{
+				AllowedOrigins:   []string{"*"},
+				AllowedMethods:   []string{"GET", "PUT", "DELETE"},
+				AllowedHeaders:   []string{"Authorization", "Origin", "Content-Type", "Accept", "X-Requested-With", "X-Request-Id"},
+				AllowCredentials: true,
+			},
+		},
 		Service: config.Service{
 			Name: "notifications",
 		},
@@ -35,12 +47,21 @@ func DefaultConfig() *config.Config {
 			SMTP: config.SMTP{
 				Encryption: "none",
 			},
+			Push: config.Push{
+				Type: "ntfy",
+			},
 			Events: config.Events{
 				Endpoint:  "127.0.0.1:9233",
 				Cluster:   "opencloud-cluster",
 				EnableTLS: false,
 			},
-			RevaGateway: shared.DefaultRevaConfig().Address,
+			RevaGateway:      shared.DefaultRevaConfig().Address,
+			BrandingCacheTTL: time.Minute,
+		},
+		Metadata: config.Metadata{
+			GatewayAddress: "eu.opencloud.api.storage-system",
+			StorageAddress: "eu.opencloud.api.storage-system",
+			SystemUserIDP:  "internal",
 		},
 		Store: config.Store{
 			Store:    "nats-js-kv",
@@ -81,9 +102,31 @@ func EnsureDefaults(cfg *config.Config) {
 	if cfg.Notifications.GRPCClientTLS == nil && cfg.Commons != nil {
 		cfg.Notifications.GRPCClientTLS = structs.CopyOrZeroValue(cfg.Commons.GRPCClientTLS)
 	}
+
+	if cfg.TokenManager == nil && cfg.Commons != nil && cfg.Commons.TokenManager != nil {
+		cfg.TokenManager = &config.TokenManager{
+			JWTSecret: cfg.Commons.TokenManager.JWTSecret,
+		}
+	} else if cfg.TokenManager == nil {
+		cfg.TokenManager = &config.TokenManager{}
+	}
+
+	if cfg.Commons != nil {
+		cfg.HTTP.TLS = cfg.Commons.HTTPServiceTLS
+	}
+
+	if cfg.Metadata.SystemUserAPIKey == "" && cfg.Commons != nil && cfg.Commons.SystemUserAPIKey != "" {
+		cfg.Metadata.SystemUserAPIKey = cfg.Commons.SystemUserAPIKey
+	}
+
+	if cfg.Metadata.SystemUserID == "" && cfg.Commons != nil && cfg.Commons.SystemUserID != "" {
+		cfg.Metadata.SystemUserID = cfg.Commons.SystemUserID
+	}
 }
 
 // Sanitize sanitizes the configuration
 func Sanitize(cfg *config.Config) {
-	// nothing to sanitize here atm
+	if cfg.HTTP.Root != "/" {
+		cfg.HTTP.Root = strings.TrimSuffix(cfg.HTTP.Root, "/")
+	}
 }
@@ -67,6 +67,21 @@ func Validate(cfg *config.Config) error {
 		}
 	}
 
+	if cfg.Notifications.Push.URL != "" {
+		switch cfg.Notifications.Push.Type {
+		case "ntfy", "gotify":
+		default:
+			return fmt.Errorf(
+				"unknown value '%s' for 'push type' in service %s. Allowed values are 'ntfy' or 'gotify'",
+				cfg.Notifications.Push.Type, cfg.Service.Name,
+			)
+		}
+	}
+
+	if cfg.TokenManager.JWTSecret == "" {
+		return shared.MissingJWTTokenError(cfg.Service.Name)
+	}
+
 	if cfg.ServiceAccount.ServiceAccountID == "" {
 		return shared.MissingServiceAccountID(cfg.Service.Name)
 	}
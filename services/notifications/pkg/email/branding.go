@@ -0,0 +1,178 @@
+package email
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/channels"
+)
+
+// ErrInvalidTemplate is returned if a customized template can't be rendered
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// _variableRegexp matches the variables of the translatable templates, e.g. {ShareSharer}
+var _variableRegexp = regexp.MustCompile(`\{(\w+)\}`)
+
+// Texts holds the customized texts of a message template, empty fields keep the default text.
+type Texts struct {
+	Subject      string `json:"subject,omitempty"`
+	Greeting     string `json:"greeting,omitempty"`
+	MessageBody  string `json:"messageBody,omitempty"`
+	CallToAction string `json:"callToAction,omitempty"`
+}
+
+// Logo is the image attached inline to the html emails
+type Logo struct {
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"data"`
+}
+
+// Branding holds the email templates and the logo managed at runtime.
+// They take precedence over the embedded templates and the ones in the email template path.
+type Branding struct {
+	// Texts holds the customized texts by template name and locale
+	Texts      map[string]map[string]Texts `json:"texts,omitempty"`
+	TextLayout string                      `json:"textLayout,omitempty"`
+	HTMLLayout string                      `json:"htmlLayout,omitempty"`
+	Logo       *Logo                       `json:"logo,omitempty"`
+}
+
+// Apply returns the message template with the customized texts, layouts and logo.
+// The texts are looked up by the locale, falling back to the default locale and to english.
+func (b *Branding) Apply(mt MessageTemplate, locale, defaultLocale string) MessageTemplate {
+	if b == nil {
+		return mt
+	}
+
+	if t, ok := b.texts(mt.name, locale, defaultLocale, "en"); ok {
+		mt.Subject = cmp.Or(t.Subject, mt.Subject)
+		mt.Greeting = cmp.Or(t.Greeting, mt.Greeting)
+		mt.MessageBody = cmp.Or(t.MessageBody, mt.MessageBody)
+		mt.CallToAction = cmp.Or(t.CallToAction, mt.CallToAction)
+	}
+	mt.textLayout = b.TextLayout
+	mt.htmlLayout = b.HTMLLayout
+	mt.logo = b.Logo
+	return mt
+}
+
+// texts returns the customized texts of the message template for the first of the locales which has them
+func (b *Branding) texts(name string, locales ...string) (Texts, bool) {
+	for _, l := range locales {
+		if t, ok := b.Texts[name][l]; ok && l != "" {
+			return t, true
+		}
+	}
+	return Texts{}, false
+}
+
+// ApplyGrouped returns the grouped message template with the customized layouts and logo.
+func (b *Branding) ApplyGrouped(gmt GroupedMessageTemplate) GroupedMessageTemplate {
+	if b == nil {
+		return gmt
+	}
+
+	gmt.textLayout = b.TextLayout
+	gmt.htmlLayout = b.HTMLLayout
+	gmt.logo = b.Logo
+	return gmt
+}
+
+// Name returns the name which identifies the message template
+func (mt MessageTemplate) Name() string {
+	return mt.name
+}
+
+// Variables returns the variables which can be used in the texts of the message template
+func (mt MessageTemplate) Variables() []string {
+	var vars []string
+	for _, s := range []string{mt.Subject, mt.Greeting, mt.MessageBody, mt.CallToAction} {
+		for _, m := range _variableRegexp.FindAllStringSubmatch(s, -1) {
+			if !slices.Contains(vars, m[1]) {
+				vars = append(vars, m[1])
+			}
+		}
+	}
+	slices.Sort(vars)
+	return vars
+}
+
+// TemplateByName returns the message template with the given name
+func TemplateByName(name string) (MessageTemplate, bool) {
+	for _, mt := range Templates {
+		if mt.name == name {
+			return mt, true
+		}
+	}
+	return MessageTemplate{}, false
+}
+
+// ValidateTexts checks that all variables used in the texts resolve for the message template.
+func ValidateTexts(mt MessageTemplate, t Texts) error {
+	vars := mt.Variables()
+	for field, s := range map[string]string{
+		"subject":      t.Subject,
+		"greeting":     t.Greeting,
+		"messageBody":  t.MessageBody,
+		"callToAction": t.CallToAction,
+	} {
+		if strings.Contains(s, "{{") {
+			return fmt.Errorf("%w: %s must not contain template actions", ErrInvalidTemplate, field)
+		}
+		for _, m := range _variableRegexp.FindAllStringSubmatch(s, -1) {
+			if !slices.Contains(vars, m[1]) {
+				return fmt.Errorf("%w: unknown variable {%s} in %s, available variables are %s", ErrInvalidTemplate, m[1], field, formatVariables(vars))
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateLayout checks that the layout parses and only uses the fields provided when rendering an email.
+func ValidateLayout(layout string) error {
+	tpl, err := template.New("").Option("missingkey=error").Parse(layout)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidTemplate, err.Error())
+	}
+	if _, err := executeTemplate(tpl, map[string]interface{}{
+		"Greeting":     template.HTML(""),
+		"MessageBody":  template.HTML(""),
+		"CallToAction": template.HTML(""),
+		"Logo":         template.URL(""),
+	}); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidTemplate, err.Error())
+	}
+	return nil
+}
+
+// ValidateLogo checks that the logo is an image which can be attached to an email and returns its content type.
+func ValidateLogo(data []byte) (string, error) {
+	if !validateMime(data) {
+		return "", fmt.Errorf("%w: the logo must be a png, jpeg or gif image", ErrInvalidTemplate)
+	}
+	return http.DetectContentType(data), nil
+}
+
+// Preview renders the message template with the variable names as values.
+func Preview(mt MessageTemplate, locale, defaultLocale string, emailTemplatePath string, translationPath string) (*channels.Message, error) {
+	vars := make(map[string]string)
+	for _, v := range mt.Variables() {
+		vars[v] = "[" + v + "]"
+	}
+	return RenderEmailTemplate(mt, locale, defaultLocale, emailTemplatePath, translationPath, vars)
+}
+
+func formatVariables(vars []string) string {
+	formatted := make([]string, 0, len(vars))
+	for _, v := range vars {
+		formatted = append(formatted, "{"+v+"}")
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,111 @@
+package email
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var _png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+
+func TestValidateTexts(t *testing.T) {
+	tests := []struct {
+		name    string
+		texts   Texts
+		wantErr bool
+	}{
+		{name: "known variables", texts: Texts{Subject: "{ShareSharer} shared {ShareFolder}", CallToAction: "Open {ShareLink}"}},
+		{name: "no variables", texts: Texts{Greeting: "Hi,"}},
+		{name: "unknown variable", texts: Texts{MessageBody: "{SpaceName} was shared"}, wantErr: true},
+		{name: "template action", texts: Texts{MessageBody: "{{ .ShareSharer }}"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTexts(ShareCreated, tt.texts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateTexts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTemplate) {
+				t.Fatalf("ValidateTexts() error = %v, want ErrInvalidTemplate", err)
+			}
+		})
+	}
+}
+
+func TestValidateLayout(t *testing.T) {
+	if err := ValidateLayout("{{ .Greeting }}\n{{ .MessageBody }}{{ if .Logo }}<img src=\"{{ .Logo }}\">{{ end }}"); err != nil {
+		t.Fatalf("ValidateLayout() error = %v", err)
+	}
+	if err := ValidateLayout("{{ .Greeting }"); !errors.Is(err, ErrInvalidTemplate) {
+		t.Fatalf("ValidateLayout() error = %v, want ErrInvalidTemplate", err)
+	}
+	if err := ValidateLayout("{{ .Unknown }}"); !errors.Is(err, ErrInvalidTemplate) {
+		t.Fatalf("ValidateLayout() error = %v, want ErrInvalidTemplate", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	b := &Branding{
+		Texts: map[string]map[string]Texts{
+			"ShareCreated": {"de": {Subject: "{ShareSharer} hat {ShareFolder} geteilt"}},
+		},
+		HTMLLayout: `<p>{{ .Greeting }}</p><img src="{{ .Logo }}">`,
+		Logo:       &Logo{Name: "logo.png", ContentType: "image/png", Data: _png},
+	}
+
+	msg, err := RenderEmailTemplate(b.Apply(ShareCreated, "de", "en"), "de", "en", "", "", map[string]string{
+		"ShareSharer":  "Alice",
+		"ShareFolder":  "Docs",
+		"ShareGrantee": "Bob",
+		"ShareLink":    "https://localhost/s/1",
+	})
+	if err != nil {
+		t.Fatalf("RenderEmailTemplate() error = %v", err)
+	}
+	if msg.Subject != "Alice hat Docs geteilt" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	if !strings.HasPrefix(msg.HTMLBody, "<p>") || !strings.Contains(msg.HTMLBody, "Bob</p>") {
+		t.Errorf("HTMLBody = %q", msg.HTMLBody)
+	}
+	if !strings.Contains(msg.HTMLBody, `src="cid:logo.png"`) {
+		t.Errorf("HTMLBody = %q, want the logo", msg.HTMLBody)
+	}
+	if string(msg.AttachInline["logo.png"]) != string(_png) {
+		t.Errorf("AttachInline = %v, want the logo", msg.AttachInline)
+	}
+
+	msg, err = RenderEmailTemplate(b.Apply(ShareCreated, "fr", "en"), "fr", "en", "", "", map[string]string{
+		"ShareSharer": "Alice",
+		"ShareFolder": "Docs",
+	})
+	if err != nil {
+		t.Fatalf("RenderEmailTemplate() error = %v", err)
+	}
+	if strings.Contains(msg.Subject, "geteilt") {
+		t.Errorf("Subject = %q, want the default", msg.Subject)
+	}
+
+	// texts missing for the locale fall back to the default locale
+	b.Texts["ShareCreated"]["en"] = Texts{Subject: "{ShareSharer} shared {ShareFolder}"}
+	msg, err = RenderEmailTemplate(b.Apply(ShareCreated, "fr", "en"), "fr", "en", "", "", map[string]string{
+		"ShareSharer": "Alice",
+		"ShareFolder": "Docs",
+	})
+	if err != nil {
+		t.Fatalf("RenderEmailTemplate() error = %v", err)
+	}
+	if msg.Subject != "Alice shared Docs" {
+		t.Errorf("Subject = %q, want the text of the default locale", msg.Subject)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	msg, err := Preview(SharedSpace, "en", "en", "", "")
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if msg.Subject != "[SpaceSharer] invited you to join [SpaceName]" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+}
@@ -31,7 +31,7 @@ func RenderEmailTemplate(mt MessageTemplate, locale, defaultLocale string, email
 	if err != nil {
 		return nil, err
 	}
-	tpl, err := parseTemplate(emailTemplatePath, mt.textTemplate)
+	tpl, err := parseTemplate(emailTemplatePath, mt.textTemplate, mt.textLayout)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func RenderEmailTemplate(mt MessageTemplate, locale, defaultLocale string, email
 	if err != nil {
 		return nil, err
 	}
-	htmlTpl, err := parseTemplate(emailTemplatePath, mt.htmlTemplate)
+	htmlTpl, err := parseTemplate(emailTemplatePath, mt.htmlTemplate, mt.htmlLayout)
 	if err != nil {
 		return nil, err
 	}
@@ -51,12 +51,9 @@ func RenderEmailTemplate(mt MessageTemplate, locale, defaultLocale string, email
 	if err != nil {
 		return nil, err
 	}
-	var data map[string][]byte
-	if emailTemplatePath != "" {
-		data, err = readImages(emailTemplatePath)
-		if err != nil {
-			return nil, err
-		}
+	data, err := inlineImages(emailTemplatePath, mt.logo)
+	if err != nil {
+		return nil, err
 	}
 
 	return &channels.Message{
@@ -73,7 +70,7 @@ func RenderGroupedEmailTemplate(gmt GroupedMessageTemplate, vars map[string]stri
 	if err != nil {
 		return nil, err
 	}
-	tpl, err := parseTemplate(emailTemplatePath, gmt.textTemplate)
+	tpl, err := parseTemplate(emailTemplatePath, gmt.textTemplate, gmt.textLayout)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +87,7 @@ func RenderGroupedEmailTemplate(gmt GroupedMessageTemplate, vars map[string]stri
 	if err != nil {
 		return nil, err
 	}
-	htmlTpl, err := parseTemplate(emailTemplatePath, gmt.htmlTemplate)
+	htmlTpl, err := parseTemplate(emailTemplatePath, gmt.htmlTemplate, gmt.htmlLayout)
 	if err != nil {
 		return nil, err
 	}
@@ -98,12 +95,9 @@ func RenderGroupedEmailTemplate(gmt GroupedMessageTemplate, vars map[string]stri
 	if err != nil {
 		return nil, err
 	}
-	var data map[string][]byte
-	if emailTemplatePath != "" {
-		data, err = readImages(emailTemplatePath)
-		if err != nil {
-			return nil, err
-		}
+	data, err := inlineImages(emailTemplatePath, gmt.logo)
+	if err != nil {
+		return nil, err
 	}
 
 	return &channels.Message{
@@ -120,6 +114,7 @@ func emailTemplate(tpl *template.Template, mt MessageTemplate) (string, error) {
 		"Greeting":     template.HTML(strings.TrimSpace(mt.Greeting)),     // #nosec G203
 		"MessageBody":  template.HTML(strings.TrimSpace(mt.MessageBody)),  // #nosec G203
 		"CallToAction": template.HTML(strings.TrimSpace(mt.CallToAction)), // #nosec G203
+		"Logo":         logoURL(mt.logo),
 	})
 	if err != nil {
 		return "", err
@@ -132,6 +127,7 @@ func groupedEmailTemplate(tpl *template.Template, gmt GroupedMessageTemplate) (s
 	str, err := executeTemplate(tpl, map[string]interface{}{
 		"Greeting":    template.HTML(strings.TrimSpace(gmt.Greeting)),    // #nosec G203
 		"MessageBody": template.HTML(strings.TrimSpace(gmt.MessageBody)), // #nosec G203
+		"Logo":        logoURL(gmt.logo),
 	})
 	if err != nil {
 		return "", err
@@ -139,7 +135,10 @@ func groupedEmailTemplate(tpl *template.Template, gmt GroupedMessageTemplate) (s
 	return str, err
 }
 
-func parseTemplate(emailTemplatePath string, file string) (*template.Template, error) {
+func parseTemplate(emailTemplatePath string, file string, layout string) (*template.Template, error) {
+	if layout != "" {
+		return template.New(filepath.Base(file)).Parse(layout)
+	}
 	if emailTemplatePath != "" {
 		return template.ParseFiles(filepath.Join(emailTemplatePath, file))
 	}
@@ -154,6 +153,33 @@ func executeTemplate(tpl *template.Template, vars any) (string, error) {
 	return writer.String(), nil
 }
 
+// inlineImages returns the images from the email template path and the logo
+func inlineImages(emailTemplatePath string, logo *Logo) (map[string][]byte, error) {
+	var data map[string][]byte
+	if emailTemplatePath != "" {
+		var err error
+		data, err = readImages(emailTemplatePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if logo != nil {
+		if data == nil {
+			data = make(map[string][]byte)
+		}
+		data[logo.Name] = logo.Data
+	}
+	return data, nil
+}
+
+// logoURL returns the url to reference the inline logo in the html email
+func logoURL(logo *Logo) template.URL {
+	if logo == nil {
+		return ""
+	}
+	return template.URL("cid:" + logo.Name) // #nosec G203
+}
+
 func readImages(emailTemplatePath string) (map[string][]byte, error) {
 	dir := filepath.Join(emailTemplatePath, imgDir)
 	entries, err := os.ReadDir(dir)
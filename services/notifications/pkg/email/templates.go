@@ -11,6 +11,7 @@ const (
 var (
 	// Shares
 	ShareCreated = MessageTemplate{
+		name:         "ShareCreated",
 		textTemplate: _textTemplate,
 		htmlTemplate: _htmlTemplate,
 		// ShareCreated email template, Subject field (resolves directly)
@@ -24,6 +25,7 @@ var (
 	}
 
 	ShareExpired = MessageTemplate{
+		name:         "ShareExpired",
 		textTemplate: _textTemplate,
 		htmlTemplate: _htmlTemplate,
 		// ShareExpired email template, Subject field (resolves directly)
@@ -38,6 +40,7 @@ Even though this share has been revoked you still might have access through othe
 
 	// Spaces templates
 	SharedSpace = MessageTemplate{
+		name:         "SharedSpace",
 		textTemplate: _textTemplate,
 		htmlTemplate: _htmlTemplate,
 		// SharedSpace email template, Subject field (resolves directly)
@@ -51,6 +54,7 @@ Even though this share has been revoked you still might have access through othe
 	}
 
 	UnsharedSpace = MessageTemplate{
+		name:         "UnsharedSpace",
 		textTemplate: _textTemplate,
 		htmlTemplate: _htmlTemplate,
 		// UnsharedSpace email template, Subject field (resolves directly)
@@ -66,6 +70,7 @@ You might still have access through your other groups or direct membership.`),
 	}
 
 	MembershipExpired = MessageTemplate{
+		name:         "MembershipExpired",
 		textTemplate: _textTemplate,
 		htmlTemplate: _htmlTemplate,
 		// MembershipExpired email template, Subject field (resolves directly)
@@ -79,6 +84,7 @@ Even though this membership has expired you still might have access through othe
 	}
 
 	ScienceMeshInviteTokenGenerated = MessageTemplate{
+		name:         "ScienceMeshInviteTokenGenerated",
 		textTemplate: _textTemplate,
 		htmlTemplate: _htmlTemplate,
 		// ScienceMeshInviteTokenGenerated email template, Subject field (resolves directly)
@@ -96,6 +102,7 @@ Alternatively, you can visit your federation settings and use the following deta
 	}
 
 	ScienceMeshInviteTokenGeneratedWithoutShareLink = MessageTemplate{
+		name:         "ScienceMeshInviteTokenGeneratedWithoutShareLink",
 		textTemplate: _textTemplate,
 		htmlTemplate: _htmlTemplate,
 		// ScienceMeshInviteTokenGeneratedWithoutShareLink email template, Subject field (resolves directly)
@@ -136,12 +143,30 @@ var _placeholders = map[string]string{
 	"{DisplayName}":     "{{ .DisplayName }}",
 }
 
+// Templates holds all message templates which can be customized at runtime
+var Templates = []MessageTemplate{
+	ShareCreated,
+	ShareExpired,
+	SharedSpace,
+	UnsharedSpace,
+	MembershipExpired,
+	ScienceMeshInviteTokenGenerated,
+	ScienceMeshInviteTokenGeneratedWithoutShareLink,
+}
+
 // MessageTemplate is the data structure for the email
 type MessageTemplate struct {
+	// name identifies the template when it is customized
+	name string
 	// textTemplate represent the path to text plain .tmpl file
 	textTemplate string
 	// htmlTemplate represent the path to html .tmpl file
 	htmlTemplate string
+	// textLayout and htmlLayout replace the .tmpl files if set
+	textLayout string
+	htmlLayout string
+	// logo is attached inline to the html email if set
+	logo *Logo
 	// The fields below represent the placeholders for the translatable templates
 	Subject      string
 	Greeting     string
@@ -155,6 +180,11 @@ type GroupedMessageTemplate struct {
 	textTemplate string
 	// htmlTemplate represent the path to html .tmpl file
 	htmlTemplate string
+	// textLayout and htmlLayout replace the .tmpl files if set
+	textLayout string
+	htmlLayout string
+	// logo is attached inline to the html email if set
+	logo *Logo
 	// The fields below represent the placeholders for the translatable templates
 	Subject     string
 	Greeting    string
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/pkg/roles"
+	"github.com/opencloud-eu/opencloud/services/graph/pkg/errorcode"
+	graphmiddleware "github.com/opencloud-eu/opencloud/services/graph/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/branding"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/email"
+)
+
+const (
+	// _maxLogoSize is the maximum size of an uploaded logo in bytes
+	_maxLogoSize = 1 << 20
+	// _maxLayoutSize is the maximum size of an uploaded layout in bytes
+	_maxLayoutSize = 1 << 20
+)
+
+// _localeRegexp matches the locales templates can be customized for, e.g. "de" or "pt_BR"
+var _localeRegexp = regexp.MustCompile(`^[a-z]{2,3}([_-][A-Za-z]{2,4})?$`)
+
+// TemplateInfo describes a message template which can be customized
+type TemplateInfo struct {
+	Name      string   `json:"name"`
+	Variables []string `json:"variables"`
+	Locales   []string `json:"locales"`
+}
+
+// PreviewResponse is the rendered preview of a message template
+type PreviewResponse struct {
+	Subject  string `json:"subject"`
+	TextBody string `json:"textBody"`
+	HTMLBody string `json:"htmlBody"`
+}
+
+// BrandingService serves the admin api to manage the email templates and the logo
+type BrandingService struct {
+	mux               *chi.Mux
+	store             *branding.Store
+	logger            log.Logger
+	defaultLanguage   string
+	emailTemplatePath string
+	translationPath   string
+}
+
+// NewBrandingService registers the routes of the branding api on the mux, all of them require an admin.
+func NewBrandingService(mux *chi.Mux, store *branding.Store, rm *roles.Manager, logger log.Logger, defaultLanguage, emailTemplatePath, translationPath string) *BrandingService {
+	s := &BrandingService{
+		mux:               mux,
+		store:             store,
+		logger:            logger,
+		defaultLanguage:   defaultLanguage,
+		emailTemplatePath: emailTemplatePath,
+		translationPath:   translationPath,
+	}
+
+	s.mux.Route("/api/v0/notifications", func(r chi.Router) {
+		r.Use(graphmiddleware.RequireAdmin(rm, logger))
+
+		r.Get("/templates", s.HandleListTemplates)
+		r.Route("/templates/{name}/{locale}", func(r chi.Router) {
+			r.Get("/", s.HandleGetTemplate)
+			r.Put("/", s.HandlePutTemplate)
+			r.Delete("/", s.HandleDeleteTemplate)
+			r.Get("/preview", s.HandlePreviewTemplate)
+		})
+		r.Get("/layouts/{type}", s.HandleGetLayout)
+		r.Put("/layouts/{type}", s.HandlePutLayout)
+		r.Delete("/layouts/{type}", s.HandleDeleteLayout)
+		r.Get("/logo", s.HandleGetLogo)
+		r.Put("/logo", s.HandlePutLogo)
+		r.Delete("/logo", s.HandleDeleteLogo)
+	})
+
+	return s
+}
+
+// ServeHTTP fulfills Handler interface
+func (s *BrandingService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// HandleListTemplates lists the message templates with their variables and customized locales
+func (s *BrandingService) HandleListTemplates(w http.ResponseWriter, r *http.Request) {
+	b, err := s.store.Get(r.Context())
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	infos := make([]TemplateInfo, 0, len(email.Templates))
+	for _, mt := range email.Templates {
+		info := TemplateInfo{Name: mt.Name(), Variables: mt.Variables(), Locales: []string{}}
+		for locale := range b.Texts[mt.Name()] {
+			info.Locales = append(info.Locales, locale)
+		}
+		slices.Sort(info.Locales)
+		infos = append(infos, info)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, infos)
+}
+
+// HandleGetTemplate returns the customized texts of a message template
+func (s *BrandingService) HandleGetTemplate(w http.ResponseWriter, r *http.Request) {
+	mt, locale, ok := s.templateFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	b, err := s.store.Get(r.Context())
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	t, ok := b.Texts[mt.Name()][locale]
+	if !ok {
+		errorcode.ItemNotFound.Render(w, r, http.StatusNotFound, "the template is not customized for this locale")
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, t)
+}
+
+// HandlePutTemplate customizes the texts of a message template
+func (s *BrandingService) HandlePutTemplate(w http.ResponseWriter, r *http.Request) {
+	mt, locale, ok := s.templateFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var t email.Texts
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		errorcode.InvalidRequest.Render(w, r, http.StatusBadRequest, "invalid body: "+err.Error())
+		return
+	}
+	if err := email.ValidateTexts(mt, t); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	err := s.store.Update(r.Context(), func(b *email.Branding) error {
+		if b.Texts == nil {
+			b.Texts = make(map[string]map[string]email.Texts)
+		}
+		if b.Texts[mt.Name()] == nil {
+			b.Texts[mt.Name()] = make(map[string]email.Texts)
+		}
+		b.Texts[mt.Name()][locale] = t
+		return nil
+	})
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, t)
+}
+
+// HandleDeleteTemplate resets the texts of a message template to the defaults
+func (s *BrandingService) HandleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	mt, locale, ok := s.templateFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	err := s.store.Update(r.Context(), func(b *email.Branding) error {
+		delete(b.Texts[mt.Name()], locale)
+		if len(b.Texts[mt.Name()]) == 0 {
+			delete(b.Texts, mt.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// HandlePreviewTemplate renders a message template with the current branding
+func (s *BrandingService) HandlePreviewTemplate(w http.ResponseWriter, r *http.Request) {
+	mt, locale, ok := s.templateFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	b, err := s.store.Get(r.Context())
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	msg, err := email.Preview(b.Apply(mt, locale, s.defaultLanguage), locale, s.defaultLanguage, s.emailTemplatePath, s.translationPath)
+	if err != nil {
+		s.renderError(w, r, fmt.Errorf("%w: %s", email.ErrInvalidTemplate, err.Error()))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, PreviewResponse{
+		Subject:  msg.Subject,
+		TextBody: msg.TextBody,
+		HTMLBody: msg.HTMLBody,
+	})
+}
+
+// HandleGetLayout returns the customized text or html layout
+func (s *BrandingService) HandleGetLayout(w http.ResponseWriter, r *http.Request) {
+	typ := chi.URLParam(r, "type")
+	if !validLayoutType(w, r, typ) {
+		return
+	}
+
+	b, err := s.store.Get(r.Context())
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	layout := *layoutField(b, typ)
+	if layout == "" {
+		errorcode.ItemNotFound.Render(w, r, http.StatusNotFound, "the layout is not customized")
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.PlainText(w, r, layout)
+}
+
+// HandlePutLayout replaces the text or html layout, the layout is a go template
+func (s *BrandingService) HandlePutLayout(w http.ResponseWriter, r *http.Request) {
+	typ := chi.URLParam(r, "type")
+	if !validLayoutType(w, r, typ) {
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, _maxLayoutSize))
+	if err != nil {
+		errorcode.InvalidRequest.Render(w, r, http.StatusBadRequest, "invalid body: "+err.Error())
+		return
+	}
+	layout := string(data)
+	if layout == "" {
+		errorcode.InvalidRequest.Render(w, r, http.StatusBadRequest, "the layout must not be empty")
+		return
+	}
+	if err := email.ValidateLayout(layout); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	err = s.store.Update(r.Context(), func(b *email.Branding) error {
+		*layoutField(b, typ) = layout
+		return nil
+	})
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// HandleDeleteLayout resets the text or html layout to the default
+func (s *BrandingService) HandleDeleteLayout(w http.ResponseWriter, r *http.Request) {
+	typ := chi.URLParam(r, "type")
+	if !validLayoutType(w, r, typ) {
+		return
+	}
+
+	err := s.store.Update(r.Context(), func(b *email.Branding) error {
+		*layoutField(b, typ) = ""
+		return nil
+	})
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// HandleGetLogo returns the logo
+func (s *BrandingService) HandleGetLogo(w http.ResponseWriter, r *http.Request) {
+	b, err := s.store.Get(r.Context())
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	if b.Logo == nil {
+		errorcode.ItemNotFound.Render(w, r, http.StatusNotFound, "no logo uploaded")
+		return
+	}
+
+	w.Header().Set("Content-Type", b.Logo.ContentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b.Logo.Data)
+}
+
+// HandlePutLogo uploads the logo which is attached to all html emails
+func (s *BrandingService) HandlePutLogo(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, _maxLogoSize))
+	if err != nil {
+		errorcode.InvalidRequest.Render(w, r, http.StatusBadRequest, "invalid body: "+err.Error())
+		return
+	}
+	contentType, err := email.ValidateLogo(data)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	err = s.store.Update(r.Context(), func(b *email.Branding) error {
+		b.Logo = &email.Logo{
+			Name:        logoName(contentType),
+			ContentType: contentType,
+			Data:        data,
+		}
+		return nil
+	})
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// HandleDeleteLogo removes the logo
+func (s *BrandingService) HandleDeleteLogo(w http.ResponseWriter, r *http.Request) {
+	err := s.store.Update(r.Context(), func(b *email.Branding) error {
+		b.Logo = nil
+		return nil
+	})
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// templateFromRequest returns the message template and the locale addressed by the request
+func (s *BrandingService) templateFromRequest(w http.ResponseWriter, r *http.Request) (email.MessageTemplate, string, bool) {
+	mt, ok := email.TemplateByName(chi.URLParam(r, "name"))
+	if !ok {
+		errorcode.ItemNotFound.Render(w, r, http.StatusNotFound, "unknown template")
+		return mt, "", false
+	}
+
+	locale := chi.URLParam(r, "locale")
+	if !_localeRegexp.MatchString(locale) {
+		errorcode.InvalidRequest.Render(w, r, http.StatusBadRequest, "invalid locale")
+		return mt, "", false
+	}
+	return mt, locale, true
+}
+
+func (s *BrandingService) renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, email.ErrInvalidTemplate):
+		errorcode.InvalidRequest.Render(w, r, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, branding.ErrConflict):
+		errorcode.GeneralException.Render(w, r, http.StatusConflict, err.Error())
+		return
+	}
+
+	s.logger.Error().Err(err).Msg("email branding request failed")
+	errorcode.GeneralException.Render(w, r, http.StatusInternalServerError, "")
+}
+
+func validLayoutType(w http.ResponseWriter, r *http.Request, typ string) bool {
+	switch typ {
+	case "text", "html":
+		return true
+	default:
+		errorcode.ItemNotFound.Render(w, r, http.StatusNotFound, "unknown layout, must be 'text' or 'html'")
+		return false
+	}
+}
+
+func layoutField(b *email.Branding, typ string) *string {
+	if typ == "html" {
+		return &b.HTMLLayout
+	}
+	return &b.TextLayout
+}
+
+func logoName(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return "logo.jpg"
+	case "image/gif":
+		return "logo.gif"
+	default:
+		return "logo.png"
+	}
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	group "github.com/cs3org/go-cs3apis/cs3/identity/group/v1beta1"
+	user "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	rpc "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
+	micrometadata "go-micro.dev/v4/metadata"
+
+	"github.com/opencloud-eu/opencloud/pkg/middleware"
+	settingssvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/settings/v0"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/channels"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/email"
+	"github.com/opencloud-eu/opencloud/services/settings/pkg/store/defaults"
+)
+
+// _channelRecipientSettings maps the additional channels to the settings holding the recipient of a user
+var _channelRecipientSettings = map[string]string{
+	channels.NameWebhook: defaults.SettingUUIDProfileNotificationWebhookURL,
+	channels.NameMatrix:  defaults.SettingUUIDProfileNotificationMatrixRoom,
+	channels.NamePush:    defaults.SettingUUIDProfileNotificationPushTopic,
+}
+
+// _channelTimeout limits the time spent on delivering a notification to the additional channels
+const _channelTimeout = 30 * time.Second
+
+// sendToChannels delivers the notification to the additional channels the users enabled for the event.
+// Other than emails, these notifications are always sent instantly. They are delivered in the background,
+// so slow webhooks or servers don't delay the emails and the processing of the following events.
+func (s eventsNotifier) sendToChannels(ctx context.Context, settingID string, template email.MessageTemplate,
+	granteeFieldName string, fields map[string]string, executant, u *user.UserId, g *group.GroupId, sender string) {
+	if len(s.extraChannels) == 0 {
+		return
+	}
+
+	// rendering the messages changes the fields
+	fields = maps.Clone(fields)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _channelTimeout)
+		defer cancel()
+		s.deliverToChannels(ctx, settingID, template, granteeFieldName, fields, executant, u, g, sender)
+	}()
+}
+
+// deliverToChannels sends the notification to the additional channels of the users
+func (s eventsNotifier) deliverToChannels(ctx context.Context, settingID string, template email.MessageTemplate,
+	granteeFieldName string, fields map[string]string, executant, u *user.UserId, g *group.GroupId, sender string) {
+	users, err := s.getUsers(ctx, executant, u, g)
+	if err != nil {
+		s.logger.Error().Err(err).Str("event", "sendToChannels").Msg("could not get users")
+		return
+	}
+
+	for _, usr := range users {
+		userID := usr.GetId().GetOpaqueId()
+		enabled, err := getEnabledChannels(ctx, s.valueService, userID, settingID)
+		if err != nil {
+			s.logger.Debug().Err(err).Str("userId", userID).Str("settingId", settingID).Msg("cannot get user event setting")
+			continue
+		}
+
+		var rendered *channels.Message
+		for name, ch := range s.extraChannels {
+			if !enabled[name] {
+				continue
+			}
+
+			recipient, err := getStringSetting(ctx, s.valueService, userID, _channelRecipientSettings[name])
+			if err != nil || recipient == "" {
+				s.logger.Debug().Err(err).Str("userId", userID).Str("channel", name).Msg("no recipient set for channel")
+				continue
+			}
+
+			if rendered == nil {
+				messages, err := s.render(ctx, template, granteeFieldName, fields, []*user.User{usr}, sender)
+				if err != nil {
+					s.logger.Error().Err(err).Str("userId", userID).Msg("could not render the message")
+					break
+				}
+				rendered = messages[0]
+			}
+
+			if err := ch.SendMessage(ctx, &channels.Message{
+				Sender:    rendered.Sender,
+				Recipient: []string{recipient},
+				Username:  usr.GetUsername(),
+				Subject:   rendered.Subject,
+				TextBody:  rendered.TextBody,
+			}); err != nil {
+				s.logger.Error().Err(err).Str("userId", userID).Str("channel", name).Msg("failed to send a message")
+			}
+		}
+	}
+}
+
+// getUsers returns the user or the members of the group, the executant is skipped.
+// Other than getGranteeList it doesn't care about the email settings of the users.
+func (s eventsNotifier) getUsers(ctx context.Context, executant, u *user.UserId, g *group.GroupId) ([]*user.User, error) {
+	switch {
+	case u != nil:
+		usr, err := s.getUser(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		return []*user.User{usr}, nil
+	case g != nil:
+		gatewayClient, err := s.gatewaySelector.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := gatewayClient.GetGroup(ctx, &group.GetGroupRequest{GroupId: g})
+		if err != nil {
+			return nil, err
+		}
+		if res.GetStatus().GetCode() != rpc.Code_CODE_OK {
+			return nil, errors.New("could not get group")
+		}
+
+		users := make([]*user.User, 0, len(res.GetGroup().GetMembers()))
+		for _, userID := range res.GetGroup().GetMembers() {
+			if userID.GetOpaqueId() == executant.GetOpaqueId() {
+				continue
+			}
+			usr, err := s.getUser(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			users = append(users, usr)
+		}
+		return users, nil
+	default:
+		return nil, errors.New("need at least one non-nil grantee")
+	}
+}
+
+// getEnabledChannels returns the options of the event setting which are enabled
+func getEnabledChannels(ctx context.Context, vc settingssvc.ValueService, userID string, settingID string) (map[string]bool, error) {
+	resp, err := vc.GetValueByUniqueIdentifiers(
+		micrometadata.Set(ctx, middleware.AccountID, userID),
+		&settingssvc.GetValueByUniqueIdentifiersRequest{
+			AccountUuid: userID,
+			SettingId:   settingID,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[string]bool)
+	for _, option := range resp.GetValue().GetValue().GetCollectionValue().GetValues() {
+		enabled[option.GetKey()] = option.GetBoolValue()
+	}
+	return enabled, nil
+}
+
+// getStringSetting returns the value of a string setting of the user
+func getStringSetting(ctx context.Context, vc settingssvc.ValueService, userID string, settingID string) (string, error) {
+	resp, err := vc.GetValueByUniqueIdentifiers(
+		micrometadata.Set(ctx, middleware.AccountID, userID),
+		&settingssvc.GetValueByUniqueIdentifiersRequest{
+			AccountUuid: userID,
+			SettingId:   settingID,
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("could not get setting %s: %w", settingID, err)
+	}
+	return resp.GetValue().GetValue().GetStringValue(), nil
+}
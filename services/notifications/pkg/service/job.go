@@ -133,7 +133,12 @@ func (s eventsNotifier) createGroupedMail(ctx context.Context, logger zerolog.Lo
 		}
 	}
 
-	rendered, err := email.RenderGroupedEmailTemplate(email.Grouped, map[string]string{
+	b := s.brandingStore.Branding(ctx)
+	for i := range mts {
+		mts[i] = b.Apply(mts[i], locale, s.defaultLanguage)
+	}
+
+	rendered, err := email.RenderGroupedEmailTemplate(b.ApplyGrouped(email.Grouped), map[string]string{
 		"DisplayName": userEvents.User.GetDisplayName(),
 	}, locale, s.defaultLanguage, s.emailTemplatePath, s.translationPath, mts, mtsVars)
 	if err != nil {
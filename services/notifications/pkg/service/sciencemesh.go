@@ -63,7 +63,7 @@ func (s eventsNotifier) handleScienceMeshInviteTokenGenerated(e events.ScienceMe
 	}
 
 	msg, err := email.RenderEmailTemplate(
-		emailTpl,
+		s.brandingStore.Branding(ctx).Apply(emailTpl, s.defaultLanguage, s.defaultLanguage),
 		s.defaultLanguage, // fixMe: the recipient is unknown, should it be the defaultLocale?,
 		s.defaultLanguage, // fixMe: the defaultLocale is not set by default, shouldn't it be?,
 		s.emailTemplatePath,
@@ -27,6 +27,7 @@ import (
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	"github.com/opencloud-eu/opencloud/pkg/middleware"
 	settingssvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/settings/v0"
+	"github.com/opencloud-eu/opencloud/services/notifications/pkg/branding"
 	"github.com/opencloud-eu/opencloud/services/notifications/pkg/channels"
 	"github.com/opencloud-eu/opencloud/services/notifications/pkg/email"
 	"github.com/opencloud-eu/opencloud/services/settings/pkg/store/defaults"
@@ -50,17 +51,20 @@ type Service interface {
 func NewEventsNotifier(
 	events <-chan events.Event,
 	channel channels.Channel,
+	extraChannels map[string]channels.Channel,
 	logger log.Logger,
 	gatewaySelector pool.Selectable[gateway.GatewayAPIClient],
 	valueService settingssvc.ValueService,
 	serviceAccountID, serviceAccountSecret, emailTemplatePath, defaultLanguage, openCloudURL, translationPath, emailSender string,
 	store store.Store,
 	historyClient ehsvc.EventHistoryService,
-	registeredEvents map[string]events.Unmarshaller) Service {
+	registeredEvents map[string]events.Unmarshaller,
+	brandingStore *branding.Store) Service {
 
 	return eventsNotifier{
 		logger:               logger,
 		channel:              channel,
+		extraChannels:        extraChannels,
 		events:               events,
 		signals:              make(chan os.Signal, 1),
 		gatewaySelector:      gatewaySelector,
@@ -76,12 +80,14 @@ func NewEventsNotifier(
 		splitter:             newIntervalSplitter(logger, valueService),
 		userEventStore:       newUserEventStore(logger, store, historyClient),
 		registeredEvents:     registeredEvents,
+		brandingStore:        brandingStore,
 	}
 }
 
 type eventsNotifier struct {
 	logger               log.Logger
 	channel              channels.Channel
+	extraChannels        map[string]channels.Channel
 	events               <-chan events.Event
 	signals              chan os.Signal
 	gatewaySelector      pool.Selectable[gateway.GatewayAPIClient]
@@ -97,6 +103,7 @@ type eventsNotifier struct {
 	splitter             *intervalSplitter
 	userEventStore       *userEventStore
 	registeredEvents     map[string]events.Unmarshaller
+	brandingStore        *branding.Store
 }
 
 func (s eventsNotifier) Run() error {
@@ -135,12 +142,13 @@ func (s eventsNotifier) Run() error {
 func (s eventsNotifier) render(ctx context.Context, template email.MessageTemplate,
 	granteeFieldName string, fields map[string]string, granteeList []*user.User, sender string) ([]*channels.Message, error) {
 	// Render the Email Template for each user
+	b := s.brandingStore.Branding(ctx)
 	messageList := make([]*channels.Message, len(granteeList))
 	for i, usr := range granteeList {
 		locale := l10n.MustGetUserLocale(ctx, usr.GetId().GetOpaqueId(), "", s.valueService)
 		fields[granteeFieldName] = usr.GetDisplayName()
 
-		rendered, err := email.RenderEmailTemplate(template, locale, s.defaultLanguage, s.emailTemplatePath, s.translationPath, fields)
+		rendered, err := email.RenderEmailTemplate(b.Apply(template, locale, s.defaultLanguage), locale, s.defaultLanguage, s.emailTemplatePath, s.translationPath, fields)
 		if err != nil {
 			return nil, err
 		}
@@ -93,9 +93,9 @@ var _ = Describe("Notifications", func() {
 			cfg := defaults.FullDefaultConfig()
 			cfg.GRPCClientTLS = &shared.GRPCClientTLS{}
 			ch := make(chan events.Event)
-			evts := service.NewEventsNotifier(ch, tc, log.NewLogger(), gatewaySelector, vs, "",
+			evts := service.NewEventsNotifier(ch, tc, nil, log.NewLogger(), gatewaySelector, vs, "",
 				"", "", "", "", "", "",
-				store.Create(), nil, nil)
+				store.Create(), nil, nil, nil)
 			go evts.Run()
 
 			ch <- ev
@@ -306,9 +306,9 @@ var _ = Describe("Notifications X-Site Scripting", func() {
 			cfg := defaults.FullDefaultConfig()
 			cfg.GRPCClientTLS = &shared.GRPCClientTLS{}
 			ch := make(chan events.Event)
-			evts := service.NewEventsNotifier(ch, tc, log.NewLogger(), gatewaySelector, vs, "",
+			evts := service.NewEventsNotifier(ch, tc, nil, log.NewLogger(), gatewaySelector, vs, "",
 				"", "", "", "", "", "",
-				store.Create(), nil, nil)
+				store.Create(), nil, nil, nil)
 			go evts.Run()
 
 			ch <- ev
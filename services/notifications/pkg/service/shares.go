@@ -24,6 +24,15 @@ func (s eventsNotifier) handleShareCreated(e events.ShareCreated, eventId string
 		return
 	}
 
+	sharerDisplayName := owner.GetDisplayName()
+	fields := map[string]string{
+		"ShareSharer": sharerDisplayName,
+		"ShareFolder": shareFolder,
+		"ShareLink":   shareLink,
+	}
+	s.sendToChannels(ctx, defaults.SettingUUIDProfileEventShareCreated, email.ShareCreated, "ShareGrantee", fields,
+		owner.GetId(), e.GranteeUserID, e.GranteeGroupID, sharerDisplayName)
+
 	granteeList := s.ensureGranteeList(ctx, owner.GetId(), e.GranteeUserID, e.GranteeGroupID)
 	filteredGrantees := s.filter.execute(ctx, granteeList, defaults.SettingUUIDProfileEventShareCreated)
 
@@ -34,14 +43,7 @@ func (s eventsNotifier) handleShareCreated(e events.ShareCreated, eventId string
 		return
 	}
 
-	sharerDisplayName := owner.GetDisplayName()
-	emails, err := s.render(ctx, email.ShareCreated,
-		"ShareGrantee",
-		map[string]string{
-			"ShareSharer": sharerDisplayName,
-			"ShareFolder": shareFolder,
-			"ShareLink":   shareLink,
-		}, recipientsInstant, sharerDisplayName)
+	emails, err := s.render(ctx, email.ShareCreated, "ShareGrantee", fields, recipientsInstant, sharerDisplayName)
 	if err != nil {
 		logger.Error().Err(err).Msg("could not get render the email")
 		return
@@ -114,6 +116,13 @@ func (s eventsNotifier) handleShareExpired(e events.ShareExpired, eventId string
 		return
 	}
 
+	fields := map[string]string{
+		"ShareFolder": shareFolder,
+		"ExpiredAt":   e.ExpiredAt.Format("2006-01-02 15:04:05"),
+	}
+	s.sendToChannels(ctx, defaults.SettingUUIDProfileEventShareExpired, email.ShareExpired, "ShareGrantee", fields,
+		owner.GetId(), e.GranteeUserID, e.GranteeGroupID, owner.GetDisplayName())
+
 	granteeList := s.ensureGranteeList(ctx, owner.GetId(), e.GranteeUserID, e.GranteeGroupID)
 	filteredGrantees := s.filter.execute(ctx, granteeList, defaults.SettingUUIDProfileEventShareExpired)
 
@@ -124,12 +133,7 @@ func (s eventsNotifier) handleShareExpired(e events.ShareExpired, eventId string
 		return
 	}
 
-	emails, err := s.render(ctx, email.ShareExpired,
-		"ShareGrantee",
-		map[string]string{
-			"ShareFolder": shareFolder,
-			"ExpiredAt":   e.ExpiredAt.Format("2006-01-02 15:04:05"),
-		}, recipientsInstant, owner.GetDisplayName())
+	emails, err := s.render(ctx, email.ShareExpired, "ShareGrantee", fields, recipientsInstant, owner.GetDisplayName())
 	if err != nil {
 		logger.Error().Err(err).Msg("could not get render the email")
 		return
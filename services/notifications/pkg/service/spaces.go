@@ -23,6 +23,15 @@ func (s eventsNotifier) handleSpaceShared(e events.SpaceShared, eventId string)
 		return
 	}
 
+	sharerDisplayName := executant.GetDisplayName()
+	fields := map[string]string{
+		"SpaceSharer": sharerDisplayName,
+		"SpaceName":   spaceName,
+		"ShareLink":   shareLink,
+	}
+	s.sendToChannels(ctx, defaults.SettingUUIDProfileEventSpaceShared, email.SharedSpace, "SpaceGrantee", fields,
+		executant.GetId(), e.GranteeUserID, e.GranteeGroupID, sharerDisplayName)
+
 	granteeList := s.ensureGranteeList(ctx, executant.GetId(), e.GranteeUserID, e.GranteeGroupID)
 	filteredGrantees := s.filter.execute(ctx, granteeList, defaults.SettingUUIDProfileEventSpaceShared)
 
@@ -33,14 +42,7 @@ func (s eventsNotifier) handleSpaceShared(e events.SpaceShared, eventId string)
 		return
 	}
 
-	sharerDisplayName := executant.GetDisplayName()
-	emails, err := s.render(ctx, email.SharedSpace,
-		"SpaceGrantee",
-		map[string]string{
-			"SpaceSharer": sharerDisplayName,
-			"SpaceName":   spaceName,
-			"ShareLink":   shareLink,
-		}, recipientsInstant, sharerDisplayName)
+	emails, err := s.render(ctx, email.SharedSpace, "SpaceGrantee", fields, recipientsInstant, sharerDisplayName)
 	if err != nil {
 		logger.Error().Err(err).Msg("could not get render the email")
 		return
@@ -108,6 +110,15 @@ func (s eventsNotifier) handleSpaceUnshared(e events.SpaceUnshared, eventId stri
 		return
 	}
 
+	sharerDisplayName := executant.GetDisplayName()
+	fields := map[string]string{
+		"SpaceSharer": sharerDisplayName,
+		"SpaceName":   spaceName,
+		"ShareLink":   shareLink,
+	}
+	s.sendToChannels(ctx, defaults.SettingUUIDProfileEventSpaceUnshared, email.UnsharedSpace, "SpaceGrantee", fields,
+		executant.GetId(), e.GranteeUserID, e.GranteeGroupID, sharerDisplayName)
+
 	granteeList := s.ensureGranteeList(ctx, executant.GetId(), e.GranteeUserID, e.GranteeGroupID)
 	filteredGrantees := s.filter.execute(ctx, granteeList, defaults.SettingUUIDProfileEventSpaceUnshared)
 
@@ -118,14 +129,7 @@ func (s eventsNotifier) handleSpaceUnshared(e events.SpaceUnshared, eventId stri
 		return
 	}
 
-	sharerDisplayName := executant.GetDisplayName()
-	emails, err := s.render(ctx, email.UnsharedSpace,
-		"SpaceGrantee",
-		map[string]string{
-			"SpaceSharer": sharerDisplayName,
-			"SpaceName":   spaceName,
-			"ShareLink":   shareLink,
-		}, recipientsInstant, sharerDisplayName)
+	emails, err := s.render(ctx, email.UnsharedSpace, "SpaceGrantee", fields, recipientsInstant, sharerDisplayName)
 	if err != nil {
 		logger.Error().Err(err).Msg("Could not get render the email")
 		return
@@ -207,6 +211,13 @@ func (s eventsNotifier) handleSpaceMembershipExpired(e events.SpaceMembershipExp
 		return
 	}
 
+	fields := map[string]string{
+		"SpaceName": e.SpaceName,
+		"ExpiredAt": e.ExpiredAt.Format("2006-01-02 15:04:05"),
+	}
+	s.sendToChannels(ctx, defaults.SettingUUIDProfileEventSpaceMembershipExpired, email.MembershipExpired, "SpaceGrantee", fields,
+		owner.GetId(), e.GranteeUserID, e.GranteeGroupID, owner.GetDisplayName())
+
 	granteeList := s.ensureGranteeList(ctx, owner.GetId(), e.GranteeUserID, e.GranteeGroupID)
 	if granteeList == nil {
 		return
@@ -220,12 +231,7 @@ func (s eventsNotifier) handleSpaceMembershipExpired(e events.SpaceMembershipExp
 		return
 	}
 
-	emails, err := s.render(ctx, email.MembershipExpired,
-		"SpaceGrantee",
-		map[string]string{
-			"SpaceName": e.SpaceName,
-			"ExpiredAt": e.ExpiredAt.Format("2006-01-02 15:04:05"),
-		}, recipientsInstant, owner.GetDisplayName())
+	emails, err := s.render(ctx, email.MembershipExpired, "SpaceGrantee", fields, recipientsInstant, owner.GetDisplayName())
 	if err != nil {
 		logger.Error().Err(err).Msg("could not get render the email")
 		return
@@ -16,6 +16,10 @@ func GetCommands(cfg *config.Config) cli.Commands {
 
 		// interaction with this service
 		RestartPostprocessing(cfg),
+		ListUploads(cfg),
+		RetryUpload(cfg),
+		SkipStep(cfg),
+		AbortUpload(cfg),
 
 		// infos about this service
 		Health(cfg),
@@ -10,11 +10,13 @@ import (
 	"github.com/urfave/cli/v2"
 	microstore "go-micro.dev/v4/store"
 
+	"github.com/opencloud-eu/opencloud/pkg/kv"
 	"github.com/opencloud-eu/opencloud/pkg/tracing"
 	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/config"
 	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/config/parser"
 	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/logging"
 	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/server/debug"
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/server/http"
 	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/service"
 )
 
@@ -45,20 +47,34 @@ func Server(cfg *config.Config) *cli.Command {
 				return err
 			}
 
-			{
-				st := store.Create(
-					store.Store(cfg.Store.Store),
-					store.TTL(cfg.Store.TTL),
-					microstore.Nodes(cfg.Store.Nodes...),
-					microstore.Database(cfg.Store.Database),
-					microstore.Table(cfg.Store.Table),
-					store.Authentication(cfg.Store.AuthUsername, cfg.Store.AuthPassword),
-				)
+			st := store.Create(
+				store.Store(cfg.Store.Store),
+				store.TTL(cfg.Store.TTL),
+				microstore.Nodes(cfg.Store.Nodes...),
+				microstore.Database(cfg.Store.Database),
+				microstore.Table(cfg.Store.Table),
+				store.Authentication(cfg.Store.AuthUsername, cfg.Store.AuthPassword),
+			)
 
-				svc, err := service.NewPostprocessingService(ctx, logger, st, traceProvider, cfg)
-				if err != nil {
-					return err
-				}
+			locks, err := kv.New(kv.Options{
+				Store:        cfg.LockStore.Store,
+				Nodes:        cfg.LockStore.Nodes,
+				Database:     cfg.LockStore.Database,
+				TTL:          cfg.LockStore.TTL,
+				AuthUsername: cfg.LockStore.AuthUsername,
+				AuthPassword: cfg.LockStore.AuthPassword,
+			})
+			if err != nil {
+				logger.Error().Err(err).Msg("could not create the lock store")
+				return err
+			}
+
+			svc, err := service.NewPostprocessingService(ctx, logger, st, locks, traceProvider, cfg)
+			if err != nil {
+				return err
+			}
+
+			{
 				gr.Add(func() error {
 					err := make(chan error, 1)
 					select {
@@ -85,6 +101,26 @@ func Server(cfg *config.Config) *cli.Command {
 				})
 			}
 
+			if cfg.HTTP.Token == "" {
+				logger.Warn().Str("transport", "http").Msg("POSTPROCESSING_HTTP_TOKEN is not set, the admin API is not started")
+			} else {
+				httpServer, err := http.Server(
+					http.Logger(logger),
+					http.Context(ctx),
+					http.Config(cfg),
+					http.WithAdmin(svc),
+				)
+				if err != nil {
+					logger.Info().Err(err).Str("transport", "http").Msg("Failed to initialize server")
+					return err
+				}
+
+				gr.Add(httpServer.ListenAndServe, func(_ error) {
+					_ = httpServer.Shutdown(ctx)
+					cancel()
+				})
+			}
+
 			{
 				debugServer, err := debug.Server(
 					debug.Logger(logger),
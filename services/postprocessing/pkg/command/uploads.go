@@ -0,0 +1,204 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/opencloud-eu/reva/v2/pkg/events"
+	"github.com/urfave/cli/v2"
+
+	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/config/parser"
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/service"
+)
+
+var _uploadIDFlag = cli.StringFlag{
+	Name:     "upload-id",
+	Aliases:  []string{"u"},
+	Usage:    "the id of the upload",
+	Required: true,
+}
+
+// ListUploads cli command to list the uploads in postprocessing
+func ListUploads(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "list the uploads in postprocessing with their current step, retries and age",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "include uploads which already finished postprocessing",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output as json",
+			},
+		},
+		Before: func(c *cli.Context) error {
+			return configlog.ReturnFatal(parser.ParseConfig(cfg))
+		},
+		Action: func(c *cli.Context) error {
+			var items []service.Item
+			if err := adminRequest(c.Context, cfg, http.MethodGet, "/api/v0/uploads", &items); err != nil {
+				return err
+			}
+
+			if !c.Bool("all") {
+				inFlight := items[:0]
+				for _, item := range items {
+					if !item.Finished && item.CurrentStep != events.PPStepFinished {
+						inFlight = append(inFlight, item)
+					}
+				}
+				items = inFlight
+			}
+
+			if c.Bool("json") {
+				b, err := json.Marshal(items)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(b))
+				return nil
+			}
+
+			table := tablewriter.NewTable(os.Stdout, tablewriter.WithHeaderAutoFormat(tw.Off))
+			table.Header([]string{"Upload ID", "Filename", "Space", "Current step", "Pending steps", "Retries", "Outcome", "Age"})
+			for _, item := range items {
+				_ = table.Append([]string{
+					item.UploadID,
+					item.Filename,
+					item.SpaceID,
+					string(item.CurrentStep),
+					joinSteps(item.PendingSteps),
+					strconv.Itoa(retries(item)),
+					string(item.Outcome),
+					age(item.StartTime),
+				})
+			}
+			return table.Render()
+		},
+	}
+}
+
+// RetryUpload cli command to retry the pending postprocessing steps of an upload
+func RetryUpload(cfg *config.Config) *cli.Command {
+	uploadIDFlag := _uploadIDFlag
+	return &cli.Command{
+		Name:  "retry",
+		Usage: "retry the pending postprocessing steps of an upload and reset their retry counters. Unknown or failed uploads are restarted.",
+		Flags: []cli.Flag{
+			&uploadIDFlag,
+		},
+		Before: func(c *cli.Context) error {
+			return configlog.ReturnFatal(parser.ParseConfig(cfg))
+		},
+		Action: func(c *cli.Context) error {
+			return adminRequest(c.Context, cfg, http.MethodPost, "/api/v0/uploads/"+url.PathEscape(c.String("upload-id"))+"/retry", nil)
+		},
+	}
+}
+
+// SkipStep cli command to skip a pending postprocessing step of an upload
+func SkipStep(cfg *config.Config) *cli.Command {
+	uploadIDFlag := _uploadIDFlag
+	return &cli.Command{
+		Name:  "skip",
+		Usage: "finish a pending postprocessing step of an upload without waiting for its result",
+		Flags: []cli.Flag{
+			&uploadIDFlag,
+			&cli.StringFlag{
+				Name:     "step",
+				Aliases:  []string{"s"},
+				Usage:    "the step to skip",
+				Required: true,
+			},
+		},
+		Before: func(c *cli.Context) error {
+			return configlog.ReturnFatal(parser.ParseConfig(cfg))
+		},
+		Action: func(c *cli.Context) error {
+			p := "/api/v0/uploads/" + url.PathEscape(c.String("upload-id")) + "/skip/" + url.PathEscape(c.String("step"))
+			return adminRequest(c.Context, cfg, http.MethodPost, p, nil)
+		},
+	}
+}
+
+// AbortUpload cli command to abort the postprocessing of an upload
+func AbortUpload(cfg *config.Config) *cli.Command {
+	uploadIDFlag := _uploadIDFlag
+	return &cli.Command{
+		Name:  "abort",
+		Usage: "abort the postprocessing of an upload, the file is kept",
+		Flags: []cli.Flag{
+			&uploadIDFlag,
+		},
+		Before: func(c *cli.Context) error {
+			return configlog.ReturnFatal(parser.ParseConfig(cfg))
+		},
+		Action: func(c *cli.Context) error {
+			return adminRequest(c.Context, cfg, http.MethodPost, "/api/v0/uploads/"+url.PathEscape(c.String("upload-id"))+"/abort", nil)
+		},
+	}
+}
+
+// adminRequest sends a request to the admin API of the running postprocessing service and decodes the response into v
+func adminRequest(ctx context.Context, cfg *config.Config, method, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, "http://"+cfg.HTTP.Addr+path, nil)
+	if err != nil {
+		return err
+	}
+	if cfg.HTTP.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.HTTP.Token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach the postprocessing service: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("postprocessing service responded with %d: %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func joinSteps(steps []events.Postprocessingstep) string {
+	s := make([]string, 0, len(steps))
+	for _, step := range steps {
+		s = append(s, string(step))
+	}
+	return strings.Join(s, ",")
+}
+
+func retries(item service.Item) int {
+	var n int
+	for _, s := range item.PendingSteps {
+		n = max(n, item.Retries[s])
+	}
+	return n
+}
+
+func age(start time.Time) string {
+	if start.IsZero() {
+		return "-"
+	}
+	return time.Since(start).Round(time.Second).String()
+}
@@ -16,8 +16,10 @@ type Config struct {
 	Tracing *Tracing `yaml:"tracing"`
 	Log     *Log     `yaml:"log"`
 	Debug   Debug    `yaml:"debug"`
+	HTTP    HTTP     `yaml:"http"`
 
 	Store          Store          `yaml:"store"`
+	LockStore      LockStore      `yaml:"lock_store"`
 	Postprocessing Postprocessing `yaml:"postprocessing"`
 
 	Context context.Context `yaml:"-"`
@@ -28,13 +30,37 @@ type Postprocessing struct {
 	Events  Events `yaml:"events"`
 	Workers int    `yaml:"workers" env:"POSTPROCESSING_WORKERS" desc:"The number of concurrent go routines that fetch events from the event queue." introductionVersion:"1.0.0"`
 
-	Steps           []string      `yaml:"steps" env:"POSTPROCESSING_STEPS" desc:"A list of postprocessing steps processed in order of their appearance. Currently supported values by the system are: 'virusscan', 'policies' and 'delay'. Custom steps are allowed. See the documentation for instructions. See the Environment Variable Types description for more details." introductionVersion:"1.0.0"`
+	Steps           []string      `yaml:"steps" env:"POSTPROCESSING_STEPS" desc:"A list of postprocessing steps processed in order of their appearance. Currently supported values by the system are: 'virusscan', 'policies' and 'delay'. Custom steps are allowed. Steps joined with a '+' like 'virusscan+customstep' are processed in parallel. See the documentation for instructions. See the Environment Variable Types description for more details." introductionVersion:"1.0.0"`
 	Delayprocessing time.Duration `yaml:"delayprocessing" env:"POSTPROCESSING_DELAY" desc:"After uploading a file but before making it available for download, a delay step can be added. Intended for developing purposes only. If a duration is set but the keyword 'delay' is not explicitely added to 'POSTPROCESSING_STEPS', the delay step will be processed as last step. In such a case, a log entry will be written on service startup to remind the admin about that situation. See the Environment Variable Types description for more details." introductionVersion:"1.0.0"`
 
+	// Pipeline defines the postprocessing steps with conditions, takes precedence over Steps if set.
+	Pipeline []PipelineStage `yaml:"pipeline"`
+
 	RetryBackoffDuration time.Duration `yaml:"retry_backoff_duration" env:"POSTPROCESSING_RETRY_BACKOFF_DURATION" desc:"The base for the exponential backoff duration before retrying a failed postprocessing step. See the Environment Variable Types description for more details." introductionVersion:"1.0.0"`
 	MaxRetries           int           `yaml:"max_retries" env:"POSTPROCESSING_MAX_RETRIES" desc:"The maximum number of retries for a failed postprocessing step." introductionVersion:"1.0.0"`
 }
 
+// PipelineStage is a set of postprocessing steps which are processed in parallel.
+// The stages of a pipeline are processed in order of their appearance.
+type PipelineStage struct {
+	Steps []PipelineStep `yaml:"steps"`
+}
+
+// PipelineStep is a postprocessing step which is only processed for the uploads matching all of its conditions.
+// Empty conditions match all uploads.
+type PipelineStep struct {
+	Name string `yaml:"name"`
+	// MimeTypes limits the step to the given mime types, wildcards like 'video/*' are supported
+	MimeTypes        []string `yaml:"mime_types,omitempty"`
+	ExcludeMimeTypes []string `yaml:"exclude_mime_types,omitempty"`
+	// MinSize and MaxSize limit the step to files of the given size, e.g. '10MB'
+	MinSize string `yaml:"min_size,omitempty"`
+	MaxSize string `yaml:"max_size,omitempty"`
+	// Spaces limits the step to the given space ids
+	Spaces        []string `yaml:"spaces,omitempty"`
+	ExcludeSpaces []string `yaml:"exclude_spaces,omitempty"`
+}
+
 // Events combines the configuration options for the event bus.
 type Events struct {
 	Endpoint string `yaml:"endpoint" env:"OC_EVENTS_ENDPOINT;POSTPROCESSING_EVENTS_ENDPOINT" desc:"The address of the event system. The event system is the message queuing service. It is used as message broker for the microservice architecture." introductionVersion:"1.0.0"`
@@ -68,3 +94,14 @@ type Store struct {
 	AuthUsername string        `yaml:"username" env:"OC_PERSISTENT_STORE_AUTH_USERNAME;POSTPROCESSING_STORE_AUTH_USERNAME" desc:"The username to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"1.0.0"`
 	AuthPassword string        `yaml:"password" env:"OC_PERSISTENT_STORE_AUTH_PASSWORD;POSTPROCESSING_STORE_AUTH_PASSWORD" desc:"The password to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"1.0.0"`
 }
+
+// LockStore configures the store for the locks of the uploads, which serialize the events of an upload
+// across all instances of the service.
+type LockStore struct {
+	Store        string        `yaml:"store" env:"POSTPROCESSING_LOCK_STORE" desc:"The type of the store for the locks of the uploads. Supported values are: 'memory' and 'nats-js-kv'. Use 'nats-js-kv' if several instances of the service are running." introductionVersion:"%%NEXT%%"`
+	Nodes        []string      `yaml:"nodes" env:"OC_PERSISTENT_STORE_NODES;POSTPROCESSING_LOCK_STORE_NODES" desc:"A list of nodes to access the configured store. This has no effect when 'memory' store is configured. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Database     string        `yaml:"database" env:"POSTPROCESSING_LOCK_STORE_DATABASE" desc:"The name of the bucket the locks are stored in." introductionVersion:"%%NEXT%%"`
+	TTL          time.Duration `yaml:"ttl" env:"POSTPROCESSING_LOCK_STORE_TTL" desc:"Time after which the lock of an upload is released if the instance holding it crashed. It is also the max time to wait for a lock. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	AuthUsername string        `yaml:"username" env:"OC_PERSISTENT_STORE_AUTH_USERNAME;POSTPROCESSING_LOCK_STORE_AUTH_USERNAME" desc:"The username to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+	AuthPassword string        `yaml:"password" env:"OC_PERSISTENT_STORE_AUTH_PASSWORD;POSTPROCESSING_LOCK_STORE_AUTH_PASSWORD" desc:"The password to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+}
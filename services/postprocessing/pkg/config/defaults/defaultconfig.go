@@ -23,6 +23,9 @@ func DefaultConfig() *config.Config {
 			Pprof:  false,
 			Zpages: false,
 		},
+		HTTP: config.HTTP{
+			Addr: "127.0.0.1:9256",
+		},
 		Service: config.Service{
 			Name: "postprocessing",
 		},
@@ -43,6 +46,12 @@ func DefaultConfig() *config.Config {
 			Database: "postprocessing",
 			Table:    "",
 		},
+		LockStore: config.LockStore{
+			Store:    "nats-js-kv",
+			Nodes:    []string{"127.0.0.1:9233"},
+			Database: "postprocessing-locks",
+			TTL:      time.Minute,
+		},
 	}
 }
 
@@ -0,0 +1,7 @@
+package config
+
+// HTTP defines the available http configuration of the admin API.
+type HTTP struct {
+	Addr  string `yaml:"addr" env:"POSTPROCESSING_HTTP_ADDR" desc:"The bind address of the HTTP admin API which is used to inspect and control the postprocessing of uploads." introductionVersion:"%%NEXT%%"`
+	Token string `yaml:"token" env:"POSTPROCESSING_HTTP_TOKEN" desc:"Token to secure the HTTP admin API. Requests must send it as bearer token in the Authorization header. The admin API is not started if no token is set. The postprocessing CLI commands use the same token." introductionVersion:"%%NEXT%%"`
+}
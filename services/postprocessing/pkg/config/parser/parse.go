@@ -38,7 +38,14 @@ func ParseConfig(cfg *config.Config) error {
 // Validate validates the config
 func Validate(cfg *config.Config) error {
 	if cfg.Postprocessing.Delayprocessing != 0 {
-		if !contains(cfg.Postprocessing.Steps, events.PPStepDelay) {
+		if len(cfg.Postprocessing.Pipeline) > 0 {
+			if !pipelineContains(cfg.Postprocessing.Pipeline, events.PPStepDelay) {
+				fmt.Println("Added delay step as last stage of the postprocessing pipeline. NOTE: Add the step `delay` to the pipeline to suppress this message and choose the order of postprocessing steps.")
+				cfg.Postprocessing.Pipeline = append(cfg.Postprocessing.Pipeline, config.PipelineStage{
+					Steps: []config.PipelineStep{{Name: string(events.PPStepDelay)}},
+				})
+			}
+		} else if !contains(cfg.Postprocessing.Steps, events.PPStepDelay) {
 			if len(cfg.Postprocessing.Steps) > 0 {
 				s := strings.Join(append(cfg.Postprocessing.Steps, string(events.PPStepDelay)), ",")
 				fmt.Printf("Added delay step to the list of postprocessing steps. NOTE: Use envvar `POSTPROCESSING_STEPS=%s` to suppress this message and choose the order of postprocessing steps.\n", s)
@@ -52,8 +59,21 @@ func Validate(cfg *config.Config) error {
 
 func contains(all []string, candidate events.Postprocessingstep) bool {
 	for _, s := range all {
-		if s == string(candidate) {
-			return true
+		for _, step := range strings.Split(s, "+") {
+			if step == string(candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func pipelineContains(pipeline []config.PipelineStage, candidate events.Postprocessingstep) bool {
+	for _, stage := range pipeline {
+		for _, s := range stage.Steps {
+			if s.Name == string(candidate) {
+				return true
+			}
 		}
 	}
 	return false
@@ -0,0 +1,196 @@
+package postprocessing
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	provider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/config"
+	"github.com/opencloud-eu/reva/v2/pkg/bytesize"
+	"github.com/opencloud-eu/reva/v2/pkg/events"
+	"github.com/opencloud-eu/reva/v2/pkg/mime"
+	"github.com/opencloud-eu/reva/v2/pkg/storagespace"
+)
+
+// Pipeline is the ordered list of postprocessing stages
+type Pipeline []Stage
+
+// Stage is a set of postprocessing steps which are processed in parallel
+type Stage []Step
+
+// Step is a postprocessing step which is only processed for uploads matching its condition
+type Step struct {
+	Name      events.Postprocessingstep
+	Condition Condition
+}
+
+// Condition selects the uploads a step is processed for, empty fields match all uploads
+type Condition struct {
+	MimeTypes        []string
+	ExcludeMimeTypes []string
+	MinSize          uint64
+	MaxSize          uint64
+	Spaces           []string
+	ExcludeSpaces    []string
+}
+
+// Upload holds the information about an upload the conditions are evaluated against
+type Upload struct {
+	Filename   string
+	Filesize   uint64
+	ResourceID *provider.ResourceId
+}
+
+// NewPipeline builds the pipeline from the configuration. If no pipeline is configured,
+// every entry of the configured steps becomes a stage, steps joined with a '+' are processed in parallel.
+func NewPipeline(c config.Postprocessing) (Pipeline, error) {
+	if len(c.Pipeline) == 0 {
+		pipeline := make(Pipeline, 0, len(c.Steps))
+		for _, s := range c.Steps {
+			var stage Stage
+			for _, name := range strings.Split(s, "+") {
+				if name = strings.TrimSpace(name); name != "" {
+					stage = append(stage, Step{Name: events.Postprocessingstep(name)})
+				}
+			}
+			if len(stage) > 0 {
+				pipeline = append(pipeline, stage)
+			}
+		}
+		return pipeline, nil
+	}
+
+	pipeline := make(Pipeline, 0, len(c.Pipeline))
+	for i, s := range c.Pipeline {
+		stage := make(Stage, 0, len(s.Steps))
+		for _, step := range s.Steps {
+			if step.Name == "" {
+				return nil, fmt.Errorf("postprocessing pipeline stage %d: step without name", i)
+			}
+
+			condition, err := newCondition(step)
+			if err != nil {
+				return nil, fmt.Errorf("postprocessing pipeline step '%s': %w", step.Name, err)
+			}
+
+			stage = append(stage, Step{Name: events.Postprocessingstep(step.Name), Condition: condition})
+		}
+		if len(stage) > 0 {
+			pipeline = append(pipeline, stage)
+		}
+	}
+	return pipeline, nil
+}
+
+// Steps returns all steps of the pipeline in order of their appearance
+func (p Pipeline) Steps() []events.Postprocessingstep {
+	var steps []events.Postprocessingstep
+	for _, stage := range p {
+		for _, s := range stage {
+			steps = append(steps, s.Name)
+		}
+	}
+	return steps
+}
+
+// Resolve returns the stages of steps which need to be processed for the given upload.
+// Stages without any matching step are left out.
+func (p Pipeline) Resolve(u Upload) [][]events.Postprocessingstep {
+	mimeType := mime.Detect(false, u.Filename)
+
+	stages := make([][]events.Postprocessingstep, 0, len(p))
+	for _, stage := range p {
+		var steps []events.Postprocessingstep
+		for _, s := range stage {
+			if s.Condition.Matches(u, mimeType) {
+				steps = append(steps, s.Name)
+			}
+		}
+		if len(steps) > 0 {
+			stages = append(stages, steps)
+		}
+	}
+	return stages
+}
+
+// Matches checks if the given upload matches the condition
+func (c Condition) Matches(u Upload, mimeType string) bool {
+	if len(c.MimeTypes) > 0 && !matchMimeType(c.MimeTypes, mimeType) {
+		return false
+	}
+	if matchMimeType(c.ExcludeMimeTypes, mimeType) {
+		return false
+	}
+	if c.MinSize > 0 && u.Filesize < c.MinSize {
+		return false
+	}
+	if c.MaxSize > 0 && u.Filesize > c.MaxSize {
+		return false
+	}
+	if len(c.Spaces) > 0 && !matchSpace(c.Spaces, u.ResourceID) {
+		return false
+	}
+	if matchSpace(c.ExcludeSpaces, u.ResourceID) {
+		return false
+	}
+	return true
+}
+
+func newCondition(step config.PipelineStep) (Condition, error) {
+	c := Condition{
+		MimeTypes:        step.MimeTypes,
+		ExcludeMimeTypes: step.ExcludeMimeTypes,
+		Spaces:           step.Spaces,
+		ExcludeSpaces:    step.ExcludeSpaces,
+	}
+
+	for _, p := range append(append([]string{}, step.MimeTypes...), step.ExcludeMimeTypes...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return c, fmt.Errorf("invalid mime type pattern '%s': %w", p, err)
+		}
+	}
+
+	if step.MinSize != "" {
+		s, err := bytesize.Parse(step.MinSize)
+		if err != nil {
+			return c, fmt.Errorf("invalid min size: %w", err)
+		}
+		c.MinSize = s.Bytes()
+	}
+	if step.MaxSize != "" {
+		s, err := bytesize.Parse(step.MaxSize)
+		if err != nil {
+			return c, fmt.Errorf("invalid max size: %w", err)
+		}
+		c.MaxSize = s.Bytes()
+	}
+	return c, nil
+}
+
+// matchMimeType checks the mime type against the patterns, e.g. 'video/*' matches all videos
+func matchMimeType(patterns []string, mimeType string) bool {
+	mimeType = strings.ToLower(mimeType)
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), mimeType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSpace checks if the resource is located in one of the given spaces,
+// the spaces can either be given as space id or as '<storageid>$<spaceid>'
+func matchSpace(spaces []string, id *provider.ResourceId) bool {
+	if id == nil {
+		return false
+	}
+
+	storageSpaceID := storagespace.FormatStorageID(id.GetStorageId(), id.GetSpaceId())
+	for _, s := range spaces {
+		if s == id.GetSpaceId() || s == storageSpaceID {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package postprocessing
 
 import (
 	"math"
+	"slices"
 	"time"
 
 	user "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
@@ -10,7 +11,8 @@ import (
 	"github.com/opencloud-eu/reva/v2/pkg/events"
 )
 
-// Postprocessing handles postprocessing of a file
+// Postprocessing handles postprocessing of a file.
+// Stages contains the steps which apply to the file, the steps of a stage are processed in parallel.
 type Postprocessing struct {
 	ID                string
 	URL               string
@@ -20,18 +22,24 @@ type Postprocessing struct {
 	Filesize          uint64
 	ResourceID        *provider.ResourceId
 	Steps             []events.Postprocessingstep
+	Stages            [][]events.Postprocessingstep
 	Status            Status
 	Failures          int
+	StepFailures      map[events.Postprocessingstep]int
 	InitiatorID       string
 	Finished          bool
+	StartTime         time.Time
 
 	config config.Postprocessing
 }
 
-// Status is helper struct to show current postprocessing status
+// Status is helper struct to show current postprocessing status.
+// PendingSteps are the steps of the current stage which did not finish yet.
 type Status struct {
-	CurrentStep events.Postprocessingstep
-	Outcome     events.PostprocessingOutcome
+	CurrentStep  events.Postprocessingstep
+	Stage        int
+	PendingSteps []events.Postprocessingstep
+	Outcome      events.PostprocessingOutcome
 }
 
 // New returns a new postprocessing instance
@@ -41,42 +49,87 @@ func New(config config.Postprocessing) *Postprocessing {
 	}
 }
 
-// Init is the first step of the postprocessing
-func (pp *Postprocessing) Init(_ events.BytesReceived) interface{} {
-	if len(pp.Steps) == 0 {
-		return pp.finished(events.PPOutcomeContinue)
+// Init is the first step of the postprocessing, it returns the events to start the first stage
+func (pp *Postprocessing) Init(_ events.BytesReceived) []interface{} {
+	pp.migrate()
+	if len(pp.Stages) == 0 {
+		return []interface{}{pp.finished(events.PPOutcomeContinue)}
 	}
 
-	return pp.step(pp.Steps[0])
+	return pp.startStage(0)
 }
 
-// NextStep returns the next postprocessing step
-func (pp *Postprocessing) NextStep(ev events.PostprocessingStepFinished) interface{} {
+// NextStep returns the events following the finished step. No events are returned
+// while other steps of the current stage are still running.
+func (pp *Postprocessing) NextStep(ev events.PostprocessingStepFinished) []interface{} {
+	pp.migrate()
+	if pp.Status.CurrentStep == events.PPStepFinished || !slices.Contains(pp.Status.PendingSteps, ev.FinishedStep) {
+		// the postprocessing was already finished by another step or the event was delivered twice
+		return nil
+	}
+
 	switch ev.Outcome {
 	case events.PPOutcomeContinue:
 		return pp.next(ev.FinishedStep)
 	case events.PPOutcomeRetry:
-		pp.Failures++
+		if pp.StepFailures == nil {
+			pp.StepFailures = make(map[events.Postprocessingstep]int)
+		}
+		pp.StepFailures[ev.FinishedStep]++
+		pp.Failures = pp.StepFailures[ev.FinishedStep]
 		if pp.Failures > pp.config.MaxRetries {
-			return pp.finished(events.PPOutcomeAbort)
+			return []interface{}{pp.finished(events.PPOutcomeAbort)}
 		}
-		return pp.retry()
+		return []interface{}{pp.retry()}
 	default:
-		return pp.finished(ev.Outcome)
+		return []interface{}{pp.finished(ev.Outcome)}
 	}
 }
 
-// CurrentStep returns the current postprocessing step
-func (pp *Postprocessing) CurrentStep() interface{} {
+// CurrentStep returns the events to (re)start the pending steps of the current stage
+func (pp *Postprocessing) CurrentStep() []interface{} {
+	pp.migrate()
 	if pp.Status.CurrentStep == events.PPStepFinished {
-		return pp.finished(pp.Status.Outcome)
+		return []interface{}{pp.finished(pp.Status.Outcome)}
+	}
+
+	evs := make([]interface{}, 0, len(pp.Status.PendingSteps))
+	for _, s := range pp.Status.PendingSteps {
+		evs = append(evs, pp.step(s))
+	}
+	return evs
+}
+
+// Skip finishes the given pending step without processing it and returns the following events
+func (pp *Postprocessing) Skip(step events.Postprocessingstep) []interface{} {
+	return pp.NextStep(events.PostprocessingStepFinished{
+		FinishedStep: step,
+		Outcome:      events.PPOutcomeContinue,
+	})
+}
+
+// Abort stops the postprocessing, the file is kept
+func (pp *Postprocessing) Abort() []interface{} {
+	return []interface{}{pp.finished(events.PPOutcomeAbort)}
+}
+
+// ResetFailures resets the retry counters of the pending steps
+func (pp *Postprocessing) ResetFailures() {
+	pp.Failures = 0
+	for _, s := range pp.Status.PendingSteps {
+		delete(pp.StepFailures, s)
+	}
+	if pp.Status.Outcome == events.PPOutcomeRetry {
+		pp.Status.Outcome = ""
 	}
-	return pp.step(pp.Status.CurrentStep)
 }
 
 // Delay will sleep the configured time then continue
-func (pp *Postprocessing) Delay(f func(next interface{})) {
-	next := pp.next(events.PPStepDelay)
+func (pp *Postprocessing) Delay(f func(next []interface{})) {
+	next := pp.NextStep(events.PostprocessingStepFinished{
+		FinishedStep: events.PPStepDelay,
+		Outcome:      events.PPOutcomeContinue,
+	})
 	go func() {
 		time.Sleep(pp.config.Delayprocessing)
 		f(next)
@@ -88,18 +141,54 @@ func (pp *Postprocessing) BackoffDuration() time.Duration {
 	return pp.config.RetryBackoffDuration * time.Duration(math.Pow(2, float64(pp.Failures-1)))
 }
 
-func (pp *Postprocessing) next(current events.Postprocessingstep) interface{} {
-	l := len(pp.Steps)
-	for i, s := range pp.Steps {
-		if s == current && i+1 < l {
-			return pp.step(pp.Steps[i+1])
+// migrate converts postprocessings which were stored before stages were introduced
+func (pp *Postprocessing) migrate() {
+	if len(pp.Stages) == 0 {
+		for _, s := range pp.Steps {
+			pp.Stages = append(pp.Stages, []events.Postprocessingstep{s})
 		}
 	}
-	return pp.finished(events.PPOutcomeContinue)
+
+	current := pp.Status.CurrentStep
+	if len(pp.Status.PendingSteps) > 0 || current == "" || current == events.PPStepFinished {
+		return
+	}
+	for i, stage := range pp.Stages {
+		if slices.Contains(stage, current) {
+			pp.Status.Stage = i
+			pp.Status.PendingSteps = []events.Postprocessingstep{current}
+			return
+		}
+	}
+}
+
+func (pp *Postprocessing) startStage(i int) []interface{} {
+	pp.Status.Stage = i
+	pp.Status.PendingSteps = slices.Clone(pp.Stages[i])
+	return pp.CurrentStep()
+}
+
+func (pp *Postprocessing) next(finished events.Postprocessingstep) []interface{} {
+	pp.Status.Outcome = ""
+	pp.Status.PendingSteps = slices.DeleteFunc(pp.Status.PendingSteps, func(s events.Postprocessingstep) bool {
+		return s == finished
+	})
+	if len(pp.Status.PendingSteps) > 0 {
+		// wait for the other steps of the stage
+		pp.Status.CurrentStep = pp.Status.PendingSteps[0]
+		return nil
+	}
+
+	if pp.Status.Stage+1 < len(pp.Stages) {
+		return pp.startStage(pp.Status.Stage + 1)
+	}
+	return []interface{}{pp.finished(events.PPOutcomeContinue)}
 }
 
 func (pp *Postprocessing) step(next events.Postprocessingstep) events.StartPostprocessingStep {
-	pp.Status.CurrentStep = next
+	if pp.Status.CurrentStep == "" || !slices.Contains(pp.Status.PendingSteps, pp.Status.CurrentStep) {
+		pp.Status.CurrentStep = next
+	}
 	return events.StartPostprocessingStep{
 		UploadID:          pp.ID,
 		URL:               pp.URL,
@@ -114,6 +203,7 @@ func (pp *Postprocessing) step(next events.Postprocessingstep) events.StartPostp
 
 func (pp *Postprocessing) finished(outcome events.PostprocessingOutcome) events.PostprocessingFinished {
 	pp.Status.CurrentStep = events.PPStepFinished
+	pp.Status.PendingSteps = nil
 	pp.Status.Outcome = outcome
 	return events.PostprocessingFinished{
 		UploadID:          pp.ID,
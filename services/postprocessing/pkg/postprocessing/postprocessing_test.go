@@ -0,0 +1,167 @@
+package postprocessing_test
+
+import (
+	"testing"
+
+	provider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/opencloud-eu/reva/v2/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/postprocessing"
+)
+
+func startedSteps(evs []interface{}) []events.Postprocessingstep {
+	var steps []events.Postprocessingstep
+	for _, ev := range evs {
+		if s, ok := ev.(events.StartPostprocessingStep); ok {
+			steps = append(steps, s.StepToStart)
+		}
+	}
+	return steps
+}
+
+func TestNewPipeline(t *testing.T) {
+	t.Run("parallel steps from the steps list", func(t *testing.T) {
+		p, err := postprocessing.NewPipeline(config.Postprocessing{Steps: []string{"virusscan+classifier", "policies"}})
+		require.NoError(t, err)
+		assert.Equal(t, [][]events.Postprocessingstep{{"virusscan", "classifier"}, {"policies"}}, p.Resolve(postprocessing.Upload{Filename: "a.txt"}))
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := postprocessing.NewPipeline(config.Postprocessing{Pipeline: []config.PipelineStage{
+			{Steps: []config.PipelineStep{{Name: "virusscan", MaxSize: "lots"}}},
+		}})
+		assert.Error(t, err)
+	})
+
+	t.Run("step without name", func(t *testing.T) {
+		_, err := postprocessing.NewPipeline(config.Postprocessing{Pipeline: []config.PipelineStage{
+			{Steps: []config.PipelineStep{{MimeTypes: []string{"video/*"}}}},
+		}})
+		assert.Error(t, err)
+	})
+}
+
+func TestPipeline_Resolve(t *testing.T) {
+	p, err := postprocessing.NewPipeline(config.Postprocessing{Pipeline: []config.PipelineStage{
+		{Steps: []config.PipelineStep{
+			{Name: "virusscan", MaxSize: "1GB"},
+			{Name: "classifier", MimeTypes: []string{"image/*"}, ExcludeSpaces: []string{"storage$archive"}},
+		}},
+		{Steps: []config.PipelineStep{
+			{Name: "transcode", MimeTypes: []string{"video/*"}, Spaces: []string{"media"}},
+			{Name: "policies", ExcludeMimeTypes: []string{"video/*"}},
+		}},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		upload postprocessing.Upload
+		want   [][]events.Postprocessingstep
+	}{
+		{
+			name:   "image",
+			upload: postprocessing.Upload{Filename: "photo.JPG", Filesize: 1024, ResourceID: &provider.ResourceId{StorageId: "storage", SpaceId: "personal"}},
+			want:   [][]events.Postprocessingstep{{"virusscan", "classifier"}, {"policies"}},
+		},
+		{
+			name:   "image in excluded space",
+			upload: postprocessing.Upload{Filename: "photo.jpg", Filesize: 1024, ResourceID: &provider.ResourceId{StorageId: "storage", SpaceId: "archive"}},
+			want:   [][]events.Postprocessingstep{{"virusscan"}, {"policies"}},
+		},
+		{
+			name:   "large video in media space",
+			upload: postprocessing.Upload{Filename: "movie.mp4", Filesize: 4 << 30, ResourceID: &provider.ResourceId{StorageId: "storage", SpaceId: "media"}},
+			want:   [][]events.Postprocessingstep{{"transcode"}},
+		},
+		{
+			name:   "large video in other space",
+			upload: postprocessing.Upload{Filename: "movie.mp4", Filesize: 4 << 30, ResourceID: &provider.ResourceId{StorageId: "storage", SpaceId: "personal"}},
+			want:   [][]events.Postprocessingstep{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.Resolve(tt.upload))
+		})
+	}
+}
+
+func TestPostprocessing_ParallelStage(t *testing.T) {
+	newPP := func() *postprocessing.Postprocessing {
+		pp := postprocessing.New(config.Postprocessing{MaxRetries: 1})
+		pp.ID = "upload"
+		pp.Stages = [][]events.Postprocessingstep{{"virusscan", "classifier"}, {"policies"}}
+		return pp
+	}
+
+	t.Run("waits for all steps of a stage", func(t *testing.T) {
+		pp := newPP()
+		assert.Equal(t, []events.Postprocessingstep{"virusscan", "classifier"}, startedSteps(pp.Init(events.BytesReceived{})))
+
+		next := pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "classifier", Outcome: events.PPOutcomeContinue})
+		assert.Empty(t, next)
+		assert.Equal(t, []events.Postprocessingstep{"virusscan"}, pp.Status.PendingSteps)
+
+		// duplicate events are ignored
+		assert.Empty(t, pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "classifier", Outcome: events.PPOutcomeContinue}))
+
+		next = pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "virusscan", Outcome: events.PPOutcomeContinue})
+		assert.Equal(t, []events.Postprocessingstep{"policies"}, startedSteps(next))
+
+		next = pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "policies", Outcome: events.PPOutcomeContinue})
+		require.Len(t, next, 1)
+		assert.Equal(t, events.PPOutcomeContinue, next[0].(events.PostprocessingFinished).Outcome)
+		assert.Equal(t, events.PPStepFinished, pp.Status.CurrentStep)
+	})
+
+	t.Run("retries steps independently", func(t *testing.T) {
+		pp := newPP()
+		pp.Init(events.BytesReceived{})
+
+		next := pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "virusscan", Outcome: events.PPOutcomeRetry})
+		require.Len(t, next, 1)
+		assert.IsType(t, events.PostprocessingRetry{}, next[0])
+
+		next = pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "classifier", Outcome: events.PPOutcomeRetry})
+		require.Len(t, next, 1)
+		assert.IsType(t, events.PostprocessingRetry{}, next[0], "failures are counted per step")
+
+		next = pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "virusscan", Outcome: events.PPOutcomeRetry})
+		require.Len(t, next, 1)
+		assert.Equal(t, events.PPOutcomeAbort, next[0].(events.PostprocessingFinished).Outcome)
+	})
+
+	t.Run("aborts the stage", func(t *testing.T) {
+		pp := newPP()
+		pp.Init(events.BytesReceived{})
+
+		next := pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "virusscan", Outcome: events.PPOutcomeDelete})
+		require.Len(t, next, 1)
+		assert.Equal(t, events.PPOutcomeDelete, next[0].(events.PostprocessingFinished).Outcome)
+
+		// late events of other steps are ignored
+		assert.Empty(t, pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "classifier", Outcome: events.PPOutcomeContinue}))
+	})
+
+	t.Run("resumes the pending steps", func(t *testing.T) {
+		pp := newPP()
+		pp.Init(events.BytesReceived{})
+		pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "virusscan", Outcome: events.PPOutcomeContinue})
+
+		assert.Equal(t, []events.Postprocessingstep{"classifier"}, startedSteps(pp.CurrentStep()))
+	})
+
+	t.Run("continues sequential postprocessings stored without stages", func(t *testing.T) {
+		pp := postprocessing.New(config.Postprocessing{})
+		pp.Steps = []events.Postprocessingstep{"virusscan", "policies"}
+		pp.Status.CurrentStep = "virusscan"
+
+		next := pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "virusscan", Outcome: events.PPOutcomeContinue})
+		assert.Equal(t, []events.Postprocessingstep{"policies"}, startedSteps(next))
+	})
+}
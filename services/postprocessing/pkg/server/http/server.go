@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/opencloud-eu/reva/v2/pkg/events"
+
+	"github.com/opencloud-eu/opencloud/pkg/middleware"
+	graphMiddleware "github.com/opencloud-eu/opencloud/services/graph/pkg/middleware"
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/service"
+)
+
+// Admin inspects and controls the postprocessing of uploads
+type Admin interface {
+	List() ([]service.Item, error)
+	Get(uploadID string) (service.Item, error)
+	Retry(ctx context.Context, uploadID string) error
+	Skip(ctx context.Context, uploadID string, step events.Postprocessingstep) error
+	Abort(ctx context.Context, uploadID string) error
+}
+
+// Server initializes the http server of the admin API.
+func Server(opts ...Option) (*http.Server, error) {
+	options := newOptions(opts...)
+
+	if options.Config.HTTP.Token == "" {
+		return nil, errors.New("the admin API requires a token")
+	}
+
+	baseCtx := options.Context
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+
+	return &http.Server{
+		Addr: options.Config.HTTP.Addr,
+		BaseContext: func(_ net.Listener) context.Context {
+			return baseCtx
+		},
+		Handler: NewHandler(options.Admin, options.Config.HTTP.Token),
+	}, nil
+}
+
+// NewHandler returns the handler of the admin API
+//
+//	GET  /api/v0/uploads
+//	GET  /api/v0/uploads/{uploadID}
+//	POST /api/v0/uploads/{uploadID}/retry
+//	POST /api/v0/uploads/{uploadID}/skip/{step}
+//	POST /api/v0/uploads/{uploadID}/abort
+func NewHandler(admin Admin, token string) http.Handler {
+	mux := chi.NewMux()
+	mux.Use(chimiddleware.RealIP)
+	mux.Use(chimiddleware.RequestID)
+	mux.Use(middleware.NoCache)
+	mux.Use(graphMiddleware.Token(token))
+
+	mux.Route("/api/v0/uploads", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			items, err := admin.List()
+			if err != nil {
+				renderError(w, r, err)
+				return
+			}
+			render.JSON(w, r, items)
+		})
+		r.Route("/{uploadID}", func(r chi.Router) {
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				item, err := admin.Get(chi.URLParam(r, "uploadID"))
+				if err != nil {
+					renderError(w, r, err)
+					return
+				}
+				render.JSON(w, r, item)
+			})
+			r.Post("/retry", func(w http.ResponseWriter, r *http.Request) {
+				renderResult(w, r, admin.Retry(r.Context(), chi.URLParam(r, "uploadID")))
+			})
+			r.Post("/skip/{step}", func(w http.ResponseWriter, r *http.Request) {
+				step := events.Postprocessingstep(chi.URLParam(r, "step"))
+				renderResult(w, r, admin.Skip(r.Context(), chi.URLParam(r, "uploadID"), step))
+			})
+			r.Post("/abort", func(w http.ResponseWriter, r *http.Request) {
+				renderResult(w, r, admin.Abort(r.Context(), chi.URLParam(r, "uploadID")))
+			})
+		})
+	})
+
+	return mux
+}
+
+func renderResult(w http.ResponseWriter, r *http.Request, err error) {
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		render.Status(r, http.StatusNotFound)
+	case errors.Is(err, service.ErrFinished), errors.Is(err, service.ErrStepNotPending), errors.Is(err, service.ErrLocked):
+		render.Status(r, http.StatusConflict)
+	default:
+		render.Status(r, http.StatusInternalServerError)
+	}
+	render.PlainText(w, r, err.Error())
+}
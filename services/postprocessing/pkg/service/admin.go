@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/postprocessing"
+	"github.com/opencloud-eu/reva/v2/pkg/events"
+	"github.com/opencloud-eu/reva/v2/pkg/storagespace"
+	"github.com/opencloud-eu/reva/v2/pkg/utils"
+)
+
+var (
+	// ErrFinished is returned when the postprocessing of an upload has already finished.
+	ErrFinished = errors.New("postprocessing already finished")
+	// ErrStepNotPending is returned when a step is not pending for an upload.
+	ErrStepNotPending = errors.New("step is not pending")
+)
+
+// Item describes the postprocessing state of an upload
+type Item struct {
+	UploadID     string                            `json:"upload_id"`
+	Filename     string                            `json:"filename"`
+	Filesize     uint64                            `json:"filesize"`
+	SpaceID      string                            `json:"space_id,omitempty"`
+	CurrentStep  events.Postprocessingstep         `json:"current_step"`
+	PendingSteps []events.Postprocessingstep       `json:"pending_steps,omitempty"`
+	Stages       [][]events.Postprocessingstep     `json:"stages,omitempty"`
+	Retries      map[events.Postprocessingstep]int `json:"retries,omitempty"`
+	Outcome      events.PostprocessingOutcome      `json:"outcome,omitempty"`
+	Finished     bool                              `json:"finished"`
+	StartTime    time.Time                         `json:"start_time"`
+}
+
+// NewItem converts the postprocessing of an upload to an Item
+func NewItem(pp *postprocessing.Postprocessing) Item {
+	item := Item{
+		UploadID:     pp.ID,
+		Filename:     pp.Filename,
+		Filesize:     pp.Filesize,
+		CurrentStep:  pp.Status.CurrentStep,
+		PendingSteps: pp.Status.PendingSteps,
+		Stages:       pp.Stages,
+		Retries:      pp.StepFailures,
+		Outcome:      pp.Status.Outcome,
+		Finished:     pp.Finished,
+		StartTime:    pp.StartTime,
+	}
+	if id := pp.ResourceID; id != nil {
+		item.SpaceID = storagespace.FormatStorageID(id.GetStorageId(), id.GetSpaceId())
+	}
+	return item
+}
+
+// List returns the postprocessing state of all known uploads, the oldest first
+func (pps *PostprocessingService) List() ([]Item, error) {
+	keys, err := pps.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list uploads: %w", err)
+	}
+
+	items := make([]Item, 0, len(keys))
+	for _, k := range keys {
+		pp, err := pps.getPP(pps.store, k)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				// finished in the meantime
+				continue
+			}
+			pps.log.Error().Str("uploadID", k).Err(err).Msg("cannot read upload")
+			continue
+		}
+		items = append(items, NewItem(pp))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].StartTime.Before(items[j].StartTime)
+	})
+	return items, nil
+}
+
+// Get returns the postprocessing state of an upload
+func (pps *PostprocessingService) Get(uploadID string) (Item, error) {
+	pp, err := pps.getPP(pps.store, uploadID)
+	if err != nil {
+		return Item{}, err
+	}
+	return NewItem(pp), nil
+}
+
+// Retry starts the pending steps of an upload again and resets their retry counters.
+// Uploads which are unknown or failed already are restarted from the beginning.
+func (pps *PostprocessingService) Retry(ctx context.Context, uploadID string) error {
+	unlock, err := pps.lock(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	pp, err := pps.getPP(pps.store, uploadID)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return pps.restart(ctx, uploadID)
+	case err != nil:
+		return err
+	case pp.Finished:
+		return pps.restart(ctx, uploadID)
+	}
+
+	pp.ResetFailures()
+	return pps.storeAndPublish(ctx, pp, pp.CurrentStep())
+}
+
+// Skip finishes a pending step of an upload without waiting for its result
+func (pps *PostprocessingService) Skip(ctx context.Context, uploadID string, step events.Postprocessingstep) error {
+	unlock, err := pps.lock(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	pp, err := pps.getPP(pps.store, uploadID)
+	if err != nil {
+		return err
+	}
+	if pp.Status.CurrentStep == events.PPStepFinished {
+		return ErrFinished
+	}
+	if !slices.Contains(pp.Status.PendingSteps, step) && pp.Status.CurrentStep != step {
+		return fmt.Errorf("%w: %s", ErrStepNotPending, step)
+	}
+
+	return pps.storeAndPublish(ctx, pp, pp.Skip(step))
+}
+
+// Abort stops the postprocessing of an upload, the file is kept
+func (pps *PostprocessingService) Abort(ctx context.Context, uploadID string) error {
+	unlock, err := pps.lock(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	pp, err := pps.getPP(pps.store, uploadID)
+	if err != nil {
+		return err
+	}
+	if pp.Status.CurrentStep == events.PPStepFinished {
+		return ErrFinished
+	}
+
+	return pps.storeAndPublish(ctx, pp, pp.Abort())
+}
+
+func (pps *PostprocessingService) restart(ctx context.Context, uploadID string) error {
+	return events.Publish(ctx, pps.pub, events.RestartPostprocessing{
+		UploadID:  uploadID,
+		Timestamp: utils.TSNow(),
+	})
+}
+
+func (pps *PostprocessingService) storeAndPublish(ctx context.Context, pp *postprocessing.Postprocessing, next []interface{}) error {
+	if err := storePP(pps.store, pp); err != nil {
+		return fmt.Errorf("cannot store upload: %w", err)
+	}
+
+	for _, ev := range next {
+		if err := events.Publish(ctx, pps.pub, ev); err != nil {
+			return fmt.Errorf("cannot publish event: %w", err)
+		}
+	}
+	return nil
+}
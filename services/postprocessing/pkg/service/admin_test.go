@@ -0,0 +1,119 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opencloud-eu/reva/v2/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mevents "go-micro.dev/v4/events"
+	"go-micro.dev/v4/store"
+
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/postprocessing"
+)
+
+type recordingPublisher struct {
+	published []interface{}
+}
+
+func (p *recordingPublisher) Publish(_ string, msg interface{}, _ ...mevents.PublishOption) error {
+	p.published = append(p.published, msg)
+	return nil
+}
+
+func newAdminTestService(t *testing.T) (*PostprocessingService, *recordingPublisher) {
+	pub := &recordingPublisher{}
+	pps := &PostprocessingService{
+		log:   log.NopLogger(),
+		pub:   pub,
+		store: store.NewMemoryStore(),
+		c:     config.Postprocessing{MaxRetries: 3},
+
+		locks:       kv.NewMemoryStore(time.Minute),
+		lockTimeout: time.Second,
+	}
+
+	pp := postprocessing.New(pps.c)
+	pp.ID = "upload"
+	pp.Filename = "file.txt"
+	pp.Stages = [][]events.Postprocessingstep{{"virusscan", "custom"}, {"policies"}}
+	pp.Init(events.BytesReceived{})
+	pp.NextStep(events.PostprocessingStepFinished{FinishedStep: "custom", Outcome: events.PPOutcomeRetry})
+	require.NoError(t, storePP(pps.store, pp))
+
+	return pps, pub
+}
+
+func TestAdmin(t *testing.T) {
+	t.Run("lists the uploads", func(t *testing.T) {
+		pps, _ := newAdminTestService(t)
+
+		items, err := pps.List()
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "upload", items[0].UploadID)
+		assert.Equal(t, []events.Postprocessingstep{"virusscan", "custom"}, items[0].PendingSteps)
+		assert.Equal(t, 1, items[0].Retries["custom"])
+	})
+
+	t.Run("retries the pending steps", func(t *testing.T) {
+		pps, pub := newAdminTestService(t)
+
+		require.NoError(t, pps.Retry(t.Context(), "upload"))
+		require.Len(t, pub.published, 2)
+
+		item, err := pps.Get("upload")
+		require.NoError(t, err)
+		assert.Empty(t, item.Retries)
+	})
+
+	t.Run("restarts unknown uploads", func(t *testing.T) {
+		pps, pub := newAdminTestService(t)
+
+		require.NoError(t, pps.Retry(t.Context(), "unknown"))
+		require.Len(t, pub.published, 1)
+		assert.IsType(t, events.RestartPostprocessing{}, pub.published[0])
+	})
+
+	t.Run("skips a pending step", func(t *testing.T) {
+		pps, pub := newAdminTestService(t)
+
+		require.NoError(t, pps.Skip(t.Context(), "upload", "custom"))
+		assert.Empty(t, pub.published)
+		require.NoError(t, pps.Skip(t.Context(), "upload", "virusscan"))
+		require.Len(t, pub.published, 1)
+		assert.Equal(t, events.Postprocessingstep("policies"), pub.published[0].(events.StartPostprocessingStep).StepToStart)
+
+		assert.ErrorIs(t, pps.Skip(t.Context(), "upload", "virusscan"), ErrStepNotPending)
+	})
+
+	t.Run("aborts the upload", func(t *testing.T) {
+		pps, pub := newAdminTestService(t)
+
+		require.NoError(t, pps.Abort(t.Context(), "upload"))
+		require.Len(t, pub.published, 1)
+		assert.Equal(t, events.PPOutcomeAbort, pub.published[0].(events.PostprocessingFinished).Outcome)
+
+		assert.ErrorIs(t, pps.Abort(t.Context(), "upload"), ErrFinished)
+		assert.ErrorIs(t, pps.Abort(t.Context(), "unknown"), ErrNotFound)
+	})
+
+	t.Run("waits for the lock held by another instance", func(t *testing.T) {
+		pps, pub := newAdminTestService(t)
+		pps.lockTimeout = 100 * time.Millisecond
+
+		// another instance sharing the lock store
+		unlock, err := (&PostprocessingService{log: pps.log, locks: pps.locks, lockTimeout: time.Second}).lock(t.Context(), "upload")
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, pps.Abort(t.Context(), "upload"), ErrLocked)
+		assert.Empty(t, pub.published)
+
+		unlock()
+		require.NoError(t, pps.Abort(t.Context(), "upload"))
+	})
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+)
+
+// _lockRetryInterval is the time to wait before trying to acquire a taken lock again
+const _lockRetryInterval = 50 * time.Millisecond
+
+// ErrLocked is returned if the lock of an upload couldn't be acquired in time
+var ErrLocked = errors.New("upload is locked by another request")
+
+// lock locks the upload for all instances of the service and returns the unlock function. The
+// parallel steps of a stage may finish at the same time on different instances, the lock makes
+// sure their results are applied one after another. Locks of crashed instances are released
+// after the TTL of the lock store, so lock waits at most that long.
+func (pps *PostprocessingService) lock(ctx context.Context, uploadID string) (func(), error) {
+	ctx, cancel := context.WithTimeout(ctx, pps.lockTimeout)
+	defer cancel()
+
+	for {
+		_, err := pps.locks.Create(uploadID, []byte(time.Now().Format(time.RFC3339Nano)))
+		switch {
+		case err == nil:
+			return func() {
+				if err := pps.locks.Delete(uploadID); err != nil {
+					pps.log.Error().Str("uploadID", uploadID).Err(err).Msg("cannot release the lock of the upload")
+				}
+			}, nil
+		case !errors.Is(err, kv.ErrConflict):
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ErrLocked
+		case <-time.After(_lockRetryInterval):
+		}
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/opencloud-eu/opencloud/pkg/kv"
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/config"
 	"github.com/opencloud-eu/opencloud/services/postprocessing/pkg/postprocessing"
@@ -22,14 +24,18 @@ import (
 
 // PostprocessingService is an instance of the service handling postprocessing of files
 type PostprocessingService struct {
-	ctx    context.Context
-	log    log.Logger
-	events <-chan raw.Event
-	pub    events.Publisher
-	steps  []events.Postprocessingstep
-	store  store.Store
-	c      config.Postprocessing
-	tp     trace.TracerProvider
+	ctx      context.Context
+	log      log.Logger
+	events   <-chan raw.Event
+	pub      events.Publisher
+	pipeline postprocessing.Pipeline
+	store    store.Store
+	c        config.Postprocessing
+	tp       trace.TracerProvider
+
+	// locks serialize the events of an upload across all instances
+	locks       kv.Store
+	lockTimeout time.Duration
 }
 
 var (
@@ -42,7 +48,7 @@ var (
 )
 
 // NewPostprocessingService returns a new instance of a postprocessing service
-func NewPostprocessingService(ctx context.Context, logger log.Logger, sto store.Store, tp trace.TracerProvider, cfg *config.Config) (*PostprocessingService, error) {
+func NewPostprocessingService(ctx context.Context, logger log.Logger, sto store.Store, locks kv.Store, tp trace.TracerProvider, cfg *config.Config) (*PostprocessingService, error) {
 	pub, err := stream.NatsFromConfig(cfg.Service.Name, false, stream.NatsConfig{
 		Endpoint:             cfg.Postprocessing.Events.Endpoint,
 		Cluster:              cfg.Postprocessing.Events.Cluster,
@@ -68,6 +74,11 @@ func NewPostprocessingService(ctx context.Context, logger log.Logger, sto store.
 		AckWait:              cfg.Postprocessing.Events.AckWait,
 	})
 
+	pipeline, err := postprocessing.NewPipeline(cfg.Postprocessing)
+	if err != nil {
+		return nil, err
+	}
+
 	evs, err := raw.Consume("postprocessing-pull",
 		events.BytesReceived{},
 		events.StartPostprocessingStep{},
@@ -79,14 +90,17 @@ func NewPostprocessingService(ctx context.Context, logger log.Logger, sto store.
 	}
 
 	return &PostprocessingService{
-		ctx:    ctx,
-		log:    logger,
-		events: evs,
-		pub:    pub,
-		steps:  getSteps(cfg.Postprocessing),
-		store:  sto,
-		c:      cfg.Postprocessing,
-		tp:     tp,
+		ctx:      ctx,
+		log:      logger,
+		events:   evs,
+		pub:      pub,
+		pipeline: pipeline,
+		store:    sto,
+		c:        cfg.Postprocessing,
+		tp:       tp,
+
+		locks:       locks,
+		lockTimeout: cfg.LockStore.TTL,
 	}, nil
 }
 
@@ -119,9 +133,10 @@ func (pps *PostprocessingService) Run() error {
 
 func (pps *PostprocessingService) processEvent(e raw.Event) error {
 	var (
-		next interface{}
-		pp   *postprocessing.Postprocessing
-		err  error
+		next   []interface{}
+		pp     *postprocessing.Postprocessing
+		unlock func()
+		err    error
 	)
 
 	ctx := e.GetTraceContext(pps.ctx)
@@ -139,6 +154,11 @@ func (pps *PostprocessingService) processEvent(e raw.Event) error {
 
 	switch ev := e.Event.Event.(type) {
 	case events.BytesReceived:
+		stages := pps.pipeline.Resolve(postprocessing.Upload{
+			Filename:   ev.Filename,
+			Filesize:   ev.Filesize,
+			ResourceID: ev.ResourceID,
+		})
 		pp = &postprocessing.Postprocessing{
 			ID:                ev.UploadID,
 			URL:               ev.URL,
@@ -146,9 +166,11 @@ func (pps *PostprocessingService) processEvent(e raw.Event) error {
 			Filename:          ev.Filename,
 			Filesize:          ev.Filesize,
 			ResourceID:        ev.ResourceID,
-			Steps:             pps.steps,
+			Steps:             slices.Concat(stages...),
+			Stages:            stages,
 			InitiatorID:       e.InitiatorID,
 			ImpersonatingUser: ev.ImpersonatingUser,
+			StartTime:         time.Now(),
 		}
 		next = pp.Init(ev)
 	case events.PostprocessingStepFinished:
@@ -156,6 +178,13 @@ func (pps *PostprocessingService) processEvent(e raw.Event) error {
 			// no current upload - this was an on demand scan
 			return nil
 		}
+		unlock, err = pps.lock(ctx, ev.UploadID)
+		if err != nil {
+			ackEvent = false
+			pps.log.Error().Str("uploadID", ev.UploadID).Err(err).Msg("cannot lock upload")
+			return fmt.Errorf("%w: cannot lock upload", ErrEvent)
+		}
+		defer unlock()
 		pp, err = pps.getPP(pps.store, ev.UploadID)
 		if err != nil {
 			pps.log.Error().Str("uploadID", ev.UploadID).Err(err).Msg("cannot get upload")
@@ -163,9 +192,8 @@ func (pps *PostprocessingService) processEvent(e raw.Event) error {
 		}
 		next = pp.NextStep(ev)
 
-		switch pp.Status.Outcome {
-		case events.PPOutcomeRetry:
-			// schedule retry
+		if slices.ContainsFunc(next, isRetry) {
+			// schedule retry of the failed step, the other steps of the stage are not affected
 			backoff := pp.BackoffDuration()
 			go func() {
 				time.Sleep(backoff)
@@ -176,7 +204,7 @@ func (pps *PostprocessingService) processEvent(e raw.Event) error {
 					Filename:          pp.Filename,
 					Filesize:          pp.Filesize,
 					ResourceID:        pp.ResourceID,
-					StepToStart:       pp.Status.CurrentStep,
+					StepToStart:       ev.FinishedStep,
 					ImpersonatingUser: pp.ImpersonatingUser,
 				}
 				err := events.Publish(ctx, pps.pub, retryEvent)
@@ -189,17 +217,33 @@ func (pps *PostprocessingService) processEvent(e raw.Event) error {
 		if ev.StepToStart != events.PPStepDelay {
 			return nil
 		}
+		unlock, err = pps.lock(ctx, ev.UploadID)
+		if err != nil {
+			ackEvent = false
+			pps.log.Error().Str("uploadID", ev.UploadID).Err(err).Msg("cannot lock upload")
+			return fmt.Errorf("%w: cannot lock upload", ErrEvent)
+		}
+		defer unlock()
 		pp, err = pps.getPP(pps.store, ev.UploadID)
 		if err != nil {
 			pps.log.Error().Str("uploadID", ev.UploadID).Err(err).Msg("cannot get upload")
 			return fmt.Errorf("%w: cannot get upload", ErrEvent)
 		}
-		pp.Delay(func(next interface{}) {
-			if err := events.Publish(ctx, pps.pub, next); err != nil {
-				pps.log.Error().Err(err).Msg("cannot publish event")
+		pp.Delay(func(next []interface{}) {
+			for _, ev := range next {
+				if err := events.Publish(ctx, pps.pub, ev); err != nil {
+					pps.log.Error().Err(err).Msg("cannot publish event")
+				}
 			}
 		})
 	case events.UploadReady:
+		unlock, err = pps.lock(ctx, ev.UploadID)
+		if err != nil {
+			ackEvent = false
+			pps.log.Error().Str("uploadID", ev.UploadID).Err(err).Msg("cannot lock upload")
+			return fmt.Errorf("%w: cannot lock upload", ErrEvent)
+		}
+		defer unlock()
 		if ev.Failed {
 			// the upload failed - let's keep it around for a while - but mark it as finished
 			pp, err = pps.getPP(pps.store, ev.UploadID)
@@ -230,8 +274,8 @@ func (pps *PostprocessingService) processEvent(e raw.Event) error {
 		}
 	}
 
-	if next != nil {
-		if err := events.Publish(ctx, pps.pub, next); err != nil {
+	for _, ev := range next {
+		if err := events.Publish(ctx, pps.pub, ev); err != nil {
 			pps.log.Error().Err(err).Msg("unable to publish event")
 			return fmt.Errorf("%w: unable to publish event", ErrFatal) // we can't publish -> we are screwed
 		}
@@ -239,6 +283,11 @@ func (pps *PostprocessingService) processEvent(e raw.Event) error {
 	return nil
 }
 
+func isRetry(ev interface{}) bool {
+	_, ok := ev.(events.PostprocessingRetry)
+	return ok
+}
+
 func (pps *PostprocessingService) getPP(sto store.Store, uploadID string) (*postprocessing.Postprocessing, error) {
 	recs, err := sto.Read(uploadID)
 	if err != nil {
@@ -265,18 +314,6 @@ func (pps *PostprocessingService) getPP(sto store.Store, uploadID string) (*post
 	return pp, nil
 }
 
-func getSteps(c config.Postprocessing) []events.Postprocessingstep {
-	// NOTE: improved version only allows configuring order of postprocessing steps
-	// But we aim for a system where postprocessing steps can be configured per space, ideally by the spaceadmin itself
-	// We need to iterate over configuring PP service when we see fit
-	steps := make([]events.Postprocessingstep, 0, len(c.Steps))
-	for _, s := range c.Steps {
-		steps = append(steps, events.Postprocessingstep(s))
-	}
-
-	return steps
-}
-
 func storePP(sto store.Store, pp *postprocessing.Postprocessing) error {
 	b, err := json.Marshal(pp)
 	if err != nil {
@@ -323,7 +360,12 @@ func (pps *PostprocessingService) resumePP(ctx context.Context, uploadID string)
 		return nil
 	}
 
-	return events.Publish(ctx, pps.pub, pp.CurrentStep())
+	for _, ev := range pp.CurrentStep() {
+		if err := events.Publish(ctx, pps.pub, ev); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (pps *PostprocessingService) findUploadsByStep(step events.Postprocessingstep) []string {
@@ -353,7 +395,7 @@ func (pps *PostprocessingService) findUploadsByStep(step events.Postprocessingst
 			continue
 		}
 
-		if pp.Status.CurrentStep == step {
+		if pp.Status.CurrentStep == step || slices.Contains(pp.Status.PendingSteps, step) {
 			ids = append(ids, pp.ID)
 		}
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/justinas/alice"
 	"github.com/oklog/run"
 	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
+	"github.com/opencloud-eu/opencloud/pkg/kv"
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	pkgmiddleware "github.com/opencloud-eu/opencloud/pkg/middleware"
 	"github.com/opencloud-eu/opencloud/pkg/oidc"
@@ -27,6 +28,7 @@ import (
 	"github.com/opencloud-eu/opencloud/services/proxy/pkg/metrics"
 	"github.com/opencloud-eu/opencloud/services/proxy/pkg/middleware"
 	"github.com/opencloud-eu/opencloud/services/proxy/pkg/proxy"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/ratelimit"
 	"github.com/opencloud-eu/opencloud/services/proxy/pkg/router"
 	"github.com/opencloud-eu/opencloud/services/proxy/pkg/server/debug"
 	proxyHTTP "github.com/opencloud-eu/opencloud/services/proxy/pkg/server/http"
@@ -74,6 +76,18 @@ func Server(cfg *config.Config) *cli.Command {
 				store.Authentication(cfg.PreSignedURL.SigningKeys.AuthUsername, cfg.PreSignedURL.SigningKeys.AuthPassword),
 			)
 
+			rateLimitStore, err := kv.New(kv.Options{
+				Store:        cfg.RateLimit.Store.Store,
+				Nodes:        cfg.RateLimit.Store.Nodes,
+				Database:     "proxy-rate-limit",
+				TTL:          cfg.RateLimit.Store.TTL,
+				AuthUsername: cfg.RateLimit.Store.AuthUsername,
+				AuthPassword: cfg.RateLimit.Store.AuthPassword,
+			})
+			if err != nil {
+				return err
+			}
+
 			logger := logging.Configure(cfg.Service.Name, cfg.Log)
 			traceProvider, err := tracing.GetServiceTraceProvider(cfg.Tracing, cfg.Service.Name)
 			if err != nil {
@@ -182,7 +196,7 @@ func Server(cfg *config.Config) *cli.Command {
 			}
 
 			{
-				middlewares := loadMiddlewares(logger, cfg, userInfoCache, signingKeyStore, traceProvider, *m, userProvider, publisher, gatewaySelector, serviceSelector)
+				middlewares := loadMiddlewares(logger, cfg, userInfoCache, signingKeyStore, rateLimitStore, traceProvider, *m, userProvider, publisher, gatewaySelector, serviceSelector)
 
 				server, err := proxyHTTP.Server(
 					proxyHTTP.Handler(lh.Handler()),
@@ -243,7 +257,7 @@ func Server(cfg *config.Config) *cli.Command {
 }
 
 func loadMiddlewares(logger log.Logger, cfg *config.Config,
-	userInfoCache, signingKeyStore microstore.Store,
+	userInfoCache, signingKeyStore microstore.Store, rateLimitStore kv.Store,
 	traceProvider trace.TracerProvider, metrics metrics.Metrics,
 	userProvider backend.UserBackend, publisher events.Publisher,
 	gatewaySelector pool.Selectable[gateway.GatewayAPIClient], serviceSelector selector.Selector) alice.Chain {
@@ -342,6 +356,12 @@ func loadMiddlewares(logger log.Logger, cfg *config.Config,
 		logger.Fatal().Err(err).Msg("Failed to load CSP configuration.")
 	}
 
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to parse the trusted proxies.")
+	}
+	rateLimiter := ratelimit.NewLimiter(rateLimitStore)
+
 	return alice.New(
 		// first make sure we log all requests and redirect to https if necessary
 		otelhttp.NewMiddleware("proxy",
@@ -353,6 +373,7 @@ func loadMiddlewares(logger log.Logger, cfg *config.Config,
 		middleware.Tracer(traceProvider),
 		pkgmiddleware.TraceContext,
 		middleware.Instrumenter(metrics),
+		middleware.PeerAddr,
 		chimiddleware.RealIP,
 		chimiddleware.RequestID,
 		middleware.AccessLog(logger),
@@ -360,6 +381,7 @@ func loadMiddlewares(logger log.Logger, cfg *config.Config,
 		middleware.HTTPSRedirect,
 		middleware.Security(cspConfig),
 		router.Middleware(serviceSelector, cfg.PolicySelector, cfg.Policies, logger),
+		middleware.FailedAuthRateLimit(rateLimiter, cfg.RateLimit, trustedProxies, metrics, logger),
 		middleware.Authentication(
 			authenticators,
 			middleware.CredentialsByUserAgent(cfg.AuthMiddleware.CredentialsByUserAgent),
@@ -368,6 +390,7 @@ func loadMiddlewares(logger log.Logger, cfg *config.Config,
 			middleware.EnableBasicAuth(cfg.EnableBasicAuth || cfg.AuthMiddleware.AllowAppAuth),
 			middleware.TraceProvider(traceProvider),
 		),
+		middleware.AppTokenRestrictions(logger, trustedProxies),
 		middleware.AccountResolver(
 			middleware.Logger(logger),
 			middleware.UserProvider(userProvider),
@@ -378,6 +401,7 @@ func loadMiddlewares(logger log.Logger, cfg *config.Config,
 			middleware.AutoprovisionAccounts(cfg.AutoprovisionAccounts),
 			middleware.EventsPublisher(publisher),
 		),
+		middleware.RateLimit(rateLimiter, cfg.RateLimit, trustedProxies, metrics, logger),
 		middleware.SelectorCookie(
 			middleware.Logger(logger),
 			middleware.PolicySelectorConfig(*cfg.PolicySelector),
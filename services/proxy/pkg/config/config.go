@@ -32,6 +32,8 @@ type Config struct {
 	RoleAssignment        RoleAssignment      `yaml:"role_assignment"`
 	PolicySelector        *PolicySelector     `yaml:"policy_selector"`
 	PreSignedURL          PreSignedURL        `yaml:"pre_signed_url"`
+	RateLimit             RateLimit           `yaml:"rate_limit"`
+	TrustedProxies        []string            `yaml:"trusted_proxies" env:"PROXY_TRUSTED_PROXIES" desc:"A list of IP addresses or CIDR networks of reverse proxies in front of the proxy. The client IP address is only taken from the X-Forwarded-For and X-Real-IP headers of requests sent by these proxies. It is used to enforce the IP restrictions of app tokens and for rate limiting. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
 	AccountBackend        string              `yaml:"account_backend" env:"PROXY_ACCOUNT_BACKEND_TYPE" desc:"Account backend the PROXY service should use. Currently only 'cs3' is possible here." introductionVersion:"1.0.0"`
 	UserOIDCClaim         string              `yaml:"user_oidc_claim" env:"PROXY_USER_OIDC_CLAIM" desc:"The name of an OpenID Connect claim that is used for resolving users with the account backend. The value of the claim must hold a per user unique, stable and non re-assignable identifier. The availability of claims depends on your Identity Provider. There are common claims available for most Identity providers like 'email' or 'preferred_username' but you can also add your own claim." introductionVersion:"1.0.0"`
 	UserCS3Claim          string              `yaml:"user_cs3_claim" env:"PROXY_USER_CS3_CLAIM" desc:"The name of a CS3 user attribute (claim) that should be mapped to the 'user_oidc_claim'. Supported values are 'username', 'mail' and 'userid'." introductionVersion:"1.0.0"`
@@ -70,6 +72,18 @@ type Route struct {
 	AdditionalHeaders map[string]string `yaml:"additional_headers,omitempty"`
 	RemoteUserHeader  string            `yaml:"remote_user_header,omitempty"`
 	SkipXAccessToken  bool              `yaml:"skip_x_access_token"`
+	// RateLimit optionally overrides the default rate limit for requests to this route
+	RateLimit *RateLimitRule `yaml:"rate_limit,omitempty"`
+}
+
+// RateLimitRule defines how many requests a client can make in a period of time
+type RateLimitRule struct {
+	// Requests is the number of requests per period, 0 disables the limit
+	Requests int `yaml:"requests"`
+	// Period is the time in which the requests can be made
+	Period time.Duration `yaml:"period"`
+	// Burst is the number of requests which can be made at once, it defaults to Requests
+	Burst int `yaml:"burst,omitempty"`
 }
 
 // RouteType defines the type of route
@@ -186,6 +200,28 @@ type PreSignedURL struct {
 	JWTSigningSharedSecret string       `yaml:"url_signing_shared_secret" env:"OC_URL_SIGNING_SHARED_SECRET" desc:"The shared secret used to sign URLs." introductionVersion:"4.0.0"`
 }
 
+// RateLimit configures the rate limiting of requests per client
+type RateLimit struct {
+	Enabled  bool          `yaml:"enabled" env:"PROXY_RATE_LIMIT_ENABLED" desc:"Enable rate limiting of requests. Clients are identified by their public link token, app token, user or IP address. See the text description for details." introductionVersion:"%%NEXT%%"`
+	Requests int           `yaml:"requests" env:"PROXY_RATE_LIMIT_REQUESTS" desc:"The number of requests a client can make per period. Routes can override the limit in the proxy policies." introductionVersion:"%%NEXT%%"`
+	Period   time.Duration `yaml:"period" env:"PROXY_RATE_LIMIT_PERIOD" desc:"The period the number of requests applies to. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Burst    int           `yaml:"burst" env:"PROXY_RATE_LIMIT_BURST" desc:"The number of requests a client can make at once. Defaults to the number of requests per period if set to 0." introductionVersion:"%%NEXT%%"`
+
+	FailedAuthAttempts int           `yaml:"failed_auth_attempts" env:"PROXY_RATE_LIMIT_FAILED_AUTH_ATTEMPTS" desc:"The number of failed authentication attempts per period after which all requests of an IP address are rejected. Set to 0 to disable the limit." introductionVersion:"%%NEXT%%"`
+	FailedAuthPeriod   time.Duration `yaml:"failed_auth_period" env:"PROXY_RATE_LIMIT_FAILED_AUTH_PERIOD" desc:"The period the number of failed authentication attempts applies to. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+
+	Store RateLimitStore `yaml:"store"`
+}
+
+// RateLimitStore configures the store holding the rate limit counters
+type RateLimitStore struct {
+	Store        string        `yaml:"store" env:"PROXY_RATE_LIMIT_STORE" desc:"The type of the store holding the counters. Supported values are: 'memory' and 'nats-js-kv'. When running multiple instances of the proxy, use 'nats-js-kv' to share the counters. See the text description for details." introductionVersion:"%%NEXT%%"`
+	Nodes        []string      `yaml:"addresses" env:"OC_CACHE_STORE_NODES;PROXY_RATE_LIMIT_STORE_NODES" desc:"A list of nodes to access the configured store. This has no effect when 'memory' store is configured. Note that the behaviour how nodes are used is dependent on the library of the configured store. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	TTL          time.Duration `yaml:"ttl" env:"PROXY_RATE_LIMIT_STORE_TTL" desc:"Time to live for the counters in the store. It must be longer than the longest period of the rate limits, otherwise clients get new requests early. Only applies when creating the 'nats-js-kv' bucket. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	AuthUsername string        `yaml:"username" env:"OC_CACHE_AUTH_USERNAME;PROXY_RATE_LIMIT_STORE_AUTH_USERNAME" desc:"The username to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+	AuthPassword string        `yaml:"password" env:"OC_CACHE_AUTH_PASSWORD;PROXY_RATE_LIMIT_STORE_AUTH_PASSWORD" desc:"The password to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+}
+
 // SigningKeys is a store configuration.
 type SigningKeys struct {
 	Store              string        `yaml:"store" env:"OC_CACHE_STORE;PROXY_PRESIGNEDURL_SIGNING_KEYS_STORE" desc:"The type of the signing key store. Supported values are: 'redis-sentinel', 'nats-js-kv' and 'opencloudstoreservice' (deprecated). See the text description for details." introductionVersion:"1.0.0"`
@@ -82,6 +82,18 @@ func DefaultConfig() *config.Config {
 				DisablePersistence: true,
 			},
 		},
+		RateLimit: config.RateLimit{
+			Enabled:  false,
+			Requests: 600,
+			Period:   time.Minute,
+			Store: config.RateLimitStore{
+				Store: "memory",
+				Nodes: []string{"127.0.0.1:9233"},
+				TTL:   time.Hour,
+			},
+			FailedAuthAttempts: 20,
+			FailedAuthPeriod:   time.Minute,
+		},
 		AccountBackend:        "cs3",
 		UserOIDCClaim:         "preferred_username",
 		UserCS3Claim:          "username",
@@ -155,6 +167,10 @@ func DefaultPolicies() []config.Policy {
 					Endpoint: "/ocs/v2.php/apps/notifications/api/v1/notifications/sse",
 					Service:  "eu.opencloud.sse.sse",
 				},
+				{
+					Endpoint: "/ocs/v2.php/apps/notifications/api/v1/notifications/ws",
+					Service:  "eu.opencloud.sse.sse",
+				},
 				{
 					// reroute oc10 notifications endpoint to userlog service
 					Endpoint: "/ocs/v2.php/apps/notifications/api/v1/notifications",
@@ -268,6 +284,10 @@ func DefaultPolicies() []config.Policy {
 					Endpoint: "/api/v0/settings",
 					Service:  "eu.opencloud.web.settings",
 				},
+				{
+					Endpoint: "/api/v0/notifications",
+					Service:  "eu.opencloud.web.notifications",
+				},
 				{
 					Endpoint: "/auth-app/tokens",
 					Service:  "eu.opencloud.web.auth-app",
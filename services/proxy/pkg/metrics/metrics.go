@@ -14,10 +14,11 @@ var (
 
 // Metrics defines the available metrics of this service.
 type Metrics struct {
-	Requests  *prometheus.CounterVec
-	Errors    *prometheus.CounterVec
-	Duration  *prometheus.HistogramVec
-	BuildInfo *prometheus.GaugeVec
+	Requests    *prometheus.CounterVec
+	Errors      *prometheus.CounterVec
+	Duration    *prometheus.HistogramVec
+	BuildInfo   *prometheus.GaugeVec
+	RateLimited *prometheus.CounterVec
 }
 
 // New initializes the available metrics.
@@ -47,6 +48,12 @@ func New() *Metrics {
 			Name:      "build_info",
 			Help:      "Build Information",
 		}, []string{"version"}),
+		RateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "rate_limited_total",
+			Help:      "How many requests were rejected by the rate limit, by kind of client",
+		}, []string{"client"}),
 	}
 
 	// Initialize the metrics with 0
@@ -57,5 +64,6 @@ func New() *Metrics {
 	_ = prometheus.Register(m.Errors)
 	_ = prometheus.Register(m.Duration)
 	_ = prometheus.Register(m.BuildInfo)
+	_ = prometheus.Register(m.RateLimited)
 	return m
 }
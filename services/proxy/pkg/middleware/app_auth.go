@@ -1,12 +1,16 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
+	"net/netip"
 
 	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
 	cs3rpc "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
+	"github.com/opencloud-eu/opencloud/pkg/apptoken"
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	"github.com/opencloud-eu/opencloud/services/proxy/pkg/userroles"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/webdav"
 	revactx "github.com/opencloud-eu/reva/v2/pkg/ctx"
 	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
 )
@@ -49,6 +53,12 @@ func (m AppAuthAuthenticator) Authenticate(r *http.Request) (*http.Request, bool
 		return nil, false
 	}
 
+	restrictions, err := apptoken.FromToken(authenticateResponse.GetToken())
+	if err != nil {
+		m.Logger.Error().Err(err).Str("clientid", username).Msg("app auth: failed to read the token restrictions")
+		return nil, false
+	}
+
 	user := authenticateResponse.GetUser()
 	if user, err = m.UserRoleAssigner.ApplyUserRole(r.Context(), user); err != nil {
 		m.Logger.Error().Err(err).Str("clientid", username).Msg("app auth: failed to load user roles")
@@ -57,8 +67,42 @@ func (m AppAuthAuthenticator) Authenticate(r *http.Request) (*http.Request, bool
 
 	ctx := revactx.ContextSetUser(r.Context(), user)
 	ctx = revactx.ContextSetToken(ctx, authenticateResponse.GetToken())
+	if !restrictions.IsZero() {
+		ctx = context.WithValue(ctx, appTokenRestrictionsKey{}, restrictions)
+	}
 
 	r = r.WithContext(ctx)
 
 	return r, true
 }
+
+// appTokenRestrictionsKey is the context key of the restrictions of the app token used to authenticate
+type appTokenRestrictionsKey struct{}
+
+// AppTokenRestrictions rejects requests which are not allowed by the restrictions of the app token they were authenticated with.
+// The forwarding headers are only used to determine the client ip if the request was sent by one of the trusted proxies.
+func AppTokenRestrictions(logger log.Logger, trustedProxies []netip.Prefix) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			restrictions, ok := r.Context().Value(appTokenRestrictionsKey{}).(apptoken.Restrictions)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := restrictions.Check(r, ClientIP(r, trustedProxies)); err != nil {
+				logger.Debug().Err(err).Str("method", r.Method).Str("path", r.URL.Path).Msg("app token restrictions denied request")
+				w.WriteHeader(http.StatusForbidden)
+				if webdav.IsWebdavRequest(r) {
+					b, err := webdav.Marshal(webdav.Exception{
+						Code:    webdav.SabredavPermissionDenied,
+						Message: "Forbidden by app token restrictions",
+					})
+					webdav.HandleWebdavError(w, b, err)
+				}
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
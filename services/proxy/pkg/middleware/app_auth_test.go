@@ -7,8 +7,10 @@ import (
 	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
 	userv1beta1 "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
 	rpcv1beta1 "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
+	"github.com/golang-jwt/jwt/v5"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/opencloud-eu/opencloud/pkg/apptoken"
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	userRoleMocks "github.com/opencloud-eu/opencloud/services/proxy/pkg/userroles/mocks"
 	revactx "github.com/opencloud-eu/reva/v2/pkg/ctx"
@@ -17,9 +19,24 @@ import (
 	"google.golang.org/grpc"
 )
 
+// mintAppToken returns a reva token with the given restrictions in its scope
+func mintAppToken(r apptoken.Restrictions) string {
+	scopes, err := apptoken.AddScope(r, nil)
+	Expect(err).ToNot(HaveOccurred())
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"scope": scopes}).SignedString([]byte("secret"))
+	Expect(err).ToNot(HaveOccurred())
+	return token
+}
+
 var _ = Describe("Authenticating requests", Label("AppAuthAuthenticator"), func() {
-	var authenticator Authenticator
+	var (
+		authenticator Authenticator
+		revaToken     string
+		readOnlyToken string
+	)
 	BeforeEach(func() {
+		revaToken = mintAppToken(apptoken.Restrictions{})
+		readOnlyToken = mintAppToken(apptoken.Restrictions{ReadOnly: true})
 		pool.RemoveSelector("GatewaySelector" + "eu.opencloud.api.gateway")
 		ra := &userRoleMocks.UserRoleAssigner{}
 		ra.On("ApplyUserRole", mock.Anything, mock.Anything, mock.Anything).Return(&userv1beta1.User{}, nil)
@@ -36,7 +53,11 @@ var _ = Describe("Authenticating requests", Label("AppAuthAuthenticator"), func(
 							}
 
 							if clientID == "test-user" && clientSecret == "AppPassword" {
-								return "reva-token", rpcv1beta1.Code_CODE_OK
+								return revaToken, rpcv1beta1.Code_CODE_OK
+							}
+
+							if clientID == "test-user" && clientSecret == "ReadOnlyAppPassword" {
+								return readOnlyToken, rpcv1beta1.Code_CODE_OK
 							}
 
 							return "", rpcv1beta1.Code_CODE_NOT_FOUND
@@ -62,7 +83,50 @@ var _ = Describe("Authenticating requests", Label("AppAuthAuthenticator"), func(
 			Expect(user).ToNot(BeNil())
 			token, ok := revactx.ContextGetToken(req2.Context())
 			Expect(ok).To(BeTrue())
-			Expect(token).To(Equal("reva-token"))
+			Expect(token).To(Equal(revaToken))
+		})
+	})
+
+	When("the app token is restricted", func() {
+		var handler http.Handler
+		BeforeEach(func() {
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r, ok := authenticator.Authenticate(r)
+				Expect(ok).To(BeTrue())
+				AppTokenRestrictions(log.NewLogger(), nil)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				})).ServeHTTP(w, r)
+			})
+		})
+
+		It("allows requests within the restrictions", func() {
+			req := httptest.NewRequest("PROPFIND", "http://example.com/dav/spaces/some-space", http.NoBody)
+			req.SetBasicAuth("test-user", "ReadOnlyAppPassword")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		})
+
+		It("rejects requests outside of the restrictions", func() {
+			req := httptest.NewRequest(http.MethodPut, "http://example.com/dav/spaces/some-space/file.txt", http.NoBody)
+			req.SetBasicAuth("test-user", "ReadOnlyAppPassword")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			Expect(rec.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("doesn't restrict unrestricted tokens", func() {
+			req := httptest.NewRequest(http.MethodPut, "http://example.com/dav/spaces/some-space/file.txt", http.NoBody)
+			req.SetBasicAuth("test-user", "AppPassword")
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
 		})
 	})
 
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// peerAddrKey is the context key of the address of the peer connected to the proxy
+type peerAddrKey struct{}
+
+// PeerAddr remembers the address of the peer connected to the proxy. It has to run before
+// chimiddleware.RealIP, which replaces the remote address with the one from the forwarding headers.
+func PeerAddr(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), peerAddrKey{}, r.RemoteAddr)))
+	})
+}
+
+// ParseTrustedProxies parses the ip addresses and networks of the trusted proxies
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if strings.Contains(p, "/") {
+			prefix, err := netip.ParsePrefix(p)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(p)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// ClientIP returns the ip address of the client. The X-Forwarded-For and X-Real-IP headers can be set
+// by anyone, so they are only used if the request was sent by a trusted proxy. The X-Forwarded-For
+// header is read from the right, the first address which is not a trusted proxy is the client.
+func ClientIP(r *http.Request, trusted []netip.Prefix) netip.Addr {
+	peer, ok := r.Context().Value(peerAddrKey{}).(string)
+	if !ok {
+		peer = r.RemoteAddr
+	}
+	addr := parseAddr(peer)
+	if !addr.IsValid() || !isTrusted(addr, trusted) {
+		return addr
+	}
+
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) != 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := parseAddr(strings.TrimSpace(hops[i]))
+			if !hop.IsValid() {
+				// the header was not written by a trusted proxy from here on
+				return addr
+			}
+			addr = hop
+			if !isTrusted(hop, trusted) {
+				return hop
+			}
+		}
+		return addr
+	}
+
+	if realIP := parseAddr(r.Header.Get("X-Real-IP")); realIP.IsValid() {
+		return realIP
+	}
+	return addr
+}
+
+// parseAddr parses an ip address with or without a port
+func parseAddr(s string) netip.Addr {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
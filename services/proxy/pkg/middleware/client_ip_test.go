@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Determining the client ip", Label("ClientIP"), func() {
+	var trusted []netip.Prefix
+
+	BeforeEach(func() {
+		var err error
+		trusted, err = ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	// clientIP runs the request through the middlewares of the proxy and returns the client ip
+	clientIP := func(remote string, headers map[string]string) netip.Addr {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remote
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		var ip netip.Addr
+		PeerAddr(chimiddleware.RealIP(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			ip = ClientIP(r, trusted)
+		}))).ServeHTTP(httptest.NewRecorder(), r)
+		return ip
+	}
+
+	It("ignores the forwarding headers of untrusted peers", func() {
+		Expect(clientIP("203.0.113.7:1234", map[string]string{"X-Forwarded-For": "10.1.1.1"})).To(Equal(netip.MustParseAddr("203.0.113.7")))
+		Expect(clientIP("203.0.113.7:1234", map[string]string{"X-Real-IP": "10.1.1.1"})).To(Equal(netip.MustParseAddr("203.0.113.7")))
+	})
+
+	It("uses the forwarding headers of trusted proxies", func() {
+		Expect(clientIP("192.168.1.1:1234", map[string]string{"X-Forwarded-For": "203.0.113.7"})).To(Equal(netip.MustParseAddr("203.0.113.7")))
+		Expect(clientIP("10.0.0.1:1234", map[string]string{"X-Real-IP": "203.0.113.7"})).To(Equal(netip.MustParseAddr("203.0.113.7")))
+	})
+
+	It("skips trusted proxies but not addresses prepended by the client", func() {
+		Expect(clientIP("10.0.0.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 10.0.0.2"})).To(Equal(netip.MustParseAddr("203.0.113.7")))
+	})
+
+	It("rejects invalid trusted proxies", func() {
+		_, err := ParseTrustedProxies([]string{"not-an-ip"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	rpc "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	revactx "github.com/opencloud-eu/reva/v2/pkg/ctx"
 	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
@@ -120,6 +121,9 @@ func (a PublicShareAuthenticator) Authenticate(r *http.Request) (*http.Request,
 	}
 
 	r.Header.Add(headerRevaAccessToken, authResp.Token)
+	if authResp.GetStatus().GetCode() == rpc.Code_CODE_OK {
+		r = r.WithContext(contextWithPublicShareToken(r.Context(), shareToken))
+	}
 
 	a.Logger.Debug().
 		Str("authenticator", "public_share").
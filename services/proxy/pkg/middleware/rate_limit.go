@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/metrics"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/ratelimit"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/router"
+	revactx "github.com/opencloud-eu/reva/v2/pkg/ctx"
+)
+
+// The kinds of clients requests are limited for
+const (
+	rateLimitPublicLink = "public_link"
+	rateLimitAppToken   = "app_token"
+	rateLimitUser       = "user"
+	rateLimitIP         = "ip"
+	rateLimitFailedAuth = "failed_auth"
+)
+
+// publicShareTokenKey is the context key of the token of an authenticated public link
+type publicShareTokenKey struct{}
+
+// contextWithPublicShareToken marks the request as authenticated with the public link token
+func contextWithPublicShareToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, publicShareTokenKey{}, token)
+}
+
+// RateLimit limits the rate of requests per client. Clients are identified by their public link token,
+// app token, user or IP address. Routes can override the default limit. It has to run after the
+// authentication, so only tokens which have been verified are used to identify clients.
+func RateLimit(limiter *ratelimit.Limiter, cfg config.RateLimit, trustedProxies []netip.Prefix, m metrics.Metrics, logger log.Logger) func(next http.Handler) http.Handler {
+	defaultRule := config.RateLimitRule{
+		Requests: cfg.Requests,
+		Period:   cfg.Period,
+		Burst:    cfg.Burst,
+	}
+
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			kind, client := rateLimitClient(r, trustedProxies)
+
+			bucket, rule := "default", defaultRule
+			if name, routeRule := router.ContextRoutingInfo(r.Context()).RateLimit(); routeRule != nil {
+				bucket, rule = name, *routeRule
+			}
+
+			ok, retryAfter, err := limiter.Allow(bucket+"|"+client, rule)
+			if err != nil {
+				// don't lock out everyone when the store is not available
+				logger.Error().Err(err).Msg("rate limit: could not check the limit")
+				next.ServeHTTP(w, r)
+				return
+			}
+			if ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			m.RateLimited.WithLabelValues(kind).Inc()
+			logger.Debug().Str("client", kind).Str("bucket", bucket).Dur("retryAfter", retryAfter).Msg("rate limit exceeded")
+			tooManyRequests(w, retryAfter)
+		})
+	}
+}
+
+// FailedAuthRateLimit limits the number of failed authentication attempts per IP address. Once the limit
+// is reached, all requests of the IP address are rejected until the period has passed. It has to run
+// before the authentication.
+func FailedAuthRateLimit(limiter *ratelimit.Limiter, cfg config.RateLimit, trustedProxies []netip.Prefix, m metrics.Metrics, logger log.Logger) func(next http.Handler) http.Handler {
+	rule := config.RateLimitRule{
+		Requests: cfg.FailedAuthAttempts,
+		Period:   cfg.FailedAuthPeriod,
+	}
+
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled || rule.Requests <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := "failed-auth|ip:" + ClientIP(r, trustedProxies).String()
+
+			ok, retryAfter, err := limiter.Check(key, rule)
+			if err != nil {
+				logger.Error().Err(err).Msg("rate limit: could not check the failed authentication attempts")
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !ok {
+				m.RateLimited.WithLabelValues(rateLimitFailedAuth).Inc()
+				logger.Debug().Str("client", rateLimitFailedAuth).Dur("retryAfter", retryAfter).Msg("too many failed authentication attempts")
+				tooManyRequests(w, retryAfter)
+				return
+			}
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+			if ww.Status() != http.StatusUnauthorized {
+				return
+			}
+			if _, _, err := limiter.Allow(key, rule); err != nil {
+				logger.Error().Err(err).Msg("rate limit: could not count the failed authentication attempt")
+			}
+		})
+	}
+}
+
+// rateLimitClient identifies the client of the request. It returns the kind of the client and its key.
+func rateLimitClient(r *http.Request, trustedProxies []netip.Prefix) (string, string) {
+	// public link requests may have the share owner in the context, so the token has to be checked first
+	if token, ok := r.Context().Value(publicShareTokenKey{}).(string); ok && token != "" {
+		return rateLimitPublicLink, "public:" + token
+	}
+
+	if u, ok := revactx.ContextGetUser(r.Context()); ok && u.GetId().GetOpaqueId() != "" {
+		// basic auth of authenticated users is used by app tokens
+		if username, password, ok := r.BasicAuth(); ok {
+			return rateLimitAppToken, "apptoken:" + username + ":" + password
+		}
+		return rateLimitUser, "user:" + u.GetId().GetOpaqueId()
+	}
+
+	return rateLimitIP, "ip:" + ClientIP(r, trustedProxies).String()
+}
+
+func tooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds())))))
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
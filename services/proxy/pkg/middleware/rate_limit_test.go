@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	userv1beta1 "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/metrics"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/ratelimit"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/router"
+	revactx "github.com/opencloud-eu/reva/v2/pkg/ctx"
+)
+
+var _ = Describe("Rate limiting requests", Label("RateLimit"), func() {
+	var handler http.Handler
+
+	withUser := func(r *http.Request, id string) *http.Request {
+		return r.WithContext(revactx.ContextSetUser(r.Context(), &userv1beta1.User{Id: &userv1beta1.UserId{OpaqueId: id}}))
+	}
+
+	request := func(user string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/graph/v1.0/me", nil)
+		r = r.WithContext(router.SetRoutingInfo(r.Context(), router.RoutingInfo{}))
+		return withUser(r, user)
+	}
+
+	BeforeEach(func() {
+		handler = RateLimit(
+			ratelimit.NewLimiter(kv.NewMemoryStore(0)),
+			config.RateLimit{Enabled: true, Requests: 1, Period: time.Minute},
+			nil,
+			*metrics.New(),
+			log.NopLogger(),
+		)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	It("rejects requests over the limit with Retry-After", func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, request("einstein"))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, request("einstein"))
+		Expect(rec.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(rec.Header().Get("Retry-After")).To(Equal("60"))
+	})
+
+	It("limits clients separately", func() {
+		for _, user := range []string{"einstein", "marie"} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, request(user))
+			Expect(rec.Code).To(Equal(http.StatusOK))
+		}
+	})
+
+	DescribeTable("identifies the client",
+		func(r *http.Request, kind, key string) {
+			k, c := rateLimitClient(r, nil)
+			Expect(k).To(Equal(kind))
+			Expect(c).To(Equal(key))
+		},
+		Entry("by the authenticated public link token",
+			func() *http.Request {
+				r := withUser(httptest.NewRequest("PROPFIND", "/dav/public-files/abc/file.txt", nil), "owner")
+				return r.WithContext(contextWithPublicShareToken(r.Context(), "abc"))
+			}(), rateLimitPublicLink, "public:abc"),
+		Entry("not by an unauthenticated public link token",
+			func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/archiver", nil)
+				r.RemoteAddr = "10.0.0.1:4711"
+				r.Header.Set(headerShareToken, "abc")
+				return r
+			}(), rateLimitIP, "ip:10.0.0.1"),
+		Entry("by the app token",
+			func() *http.Request {
+				r := httptest.NewRequest("PROPFIND", "/remote.php/webdav", nil)
+				r.SetBasicAuth("einstein", "secret")
+				return withUser(r, "einstein")
+			}(), rateLimitAppToken, "apptoken:einstein:secret"),
+		Entry("by the user",
+			withUser(httptest.NewRequest(http.MethodGet, "/graph/v1.0/me", nil), "einstein"), rateLimitUser, "user:einstein"),
+		Entry("by the ip address",
+			func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.RemoteAddr = "10.0.0.1:4711"
+				return r
+			}(), rateLimitIP, "ip:10.0.0.1"),
+		Entry("not by a spoofed ip address",
+			func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.RemoteAddr = "10.0.0.1:4711"
+				r.Header.Set("X-Forwarded-For", "10.0.0.2")
+				return r
+			}(), rateLimitIP, "ip:10.0.0.1"),
+	)
+
+	Describe("failed authentication attempts", func() {
+		var status int
+
+		BeforeEach(func() {
+			status = http.StatusUnauthorized
+			handler = FailedAuthRateLimit(
+				ratelimit.NewLimiter(kv.NewMemoryStore(0)),
+				config.RateLimit{Enabled: true, FailedAuthAttempts: 2, FailedAuthPeriod: time.Minute},
+				nil,
+				*metrics.New(),
+				log.NopLogger(),
+			)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(status)
+			}))
+		})
+
+		request := func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/remote.php/webdav", nil)
+			r.RemoteAddr = "10.0.0.1:4711"
+			return r
+		}
+
+		It("rejects all requests of an ip address after too many failed attempts", func() {
+			for range 2 {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, request())
+				Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			}
+
+			status = http.StatusOK
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, request())
+			Expect(rec.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(rec.Header().Get("Retry-After")).ToNot(BeEmpty())
+		})
+
+		It("doesn't count successful requests", func() {
+			status = http.StatusOK
+			for range 5 {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, request())
+				Expect(rec.Code).To(Equal(http.StatusOK))
+			}
+		})
+	})
+})
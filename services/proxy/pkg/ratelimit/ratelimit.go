@@ -0,0 +1,93 @@
+// Package ratelimit implements token buckets which are kept in a store, so they can be shared by multiple proxy instances.
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/config"
+)
+
+// bucket is the stored state of a token bucket
+type bucket struct {
+	Tokens  float64 `json:"tokens"`
+	Updated int64   `json:"updated"`
+}
+
+// Limiter limits the rate of requests with a token bucket per key. The buckets are updated with
+// a revision check, so the limits also hold when several instances share the store. Buckets are
+// removed after the TTL of the store, which has to be longer than the time a bucket takes to refill.
+type Limiter struct {
+	store kv.Store
+}
+
+// NewLimiter returns a new Limiter keeping the buckets in the given store.
+func NewLimiter(store kv.Store) *Limiter {
+	return &Limiter{store: store}
+}
+
+// Allow takes a token from the bucket of the key. If there is none left, it returns false and the
+// time until the next token is available. A rule without requests allows everything.
+func (l *Limiter) Allow(key string, rule config.RateLimitRule) (bool, time.Duration, error) {
+	return l.take(key, rule, 1)
+}
+
+// Check returns whether the bucket of the key has a token left without taking it. If there is none
+// left, it returns the time until the next token is available.
+func (l *Limiter) Check(key string, rule config.RateLimitRule) (bool, time.Duration, error) {
+	return l.take(key, rule, 0)
+}
+
+func (l *Limiter) take(key string, rule config.RateLimitRule, n float64) (bool, time.Duration, error) {
+	if rule.Requests <= 0 || rule.Period <= 0 {
+		return true, 0, nil
+	}
+
+	capacity := float64(rule.Burst)
+	if rule.Burst <= 0 {
+		capacity = float64(rule.Requests)
+	}
+	// tokens per nanosecond
+	rate := float64(rule.Requests) / float64(rule.Period)
+
+	// keys may contain secrets like app tokens
+	sum := sha256.Sum256([]byte(key))
+	id := hex.EncodeToString(sum[:])
+
+	var (
+		b       *bucket
+		allowed bool
+	)
+	err := kv.Modify(l.store, id, func(value []byte) ([]byte, error) {
+		now := time.Now()
+		b = &bucket{Tokens: capacity}
+		if value != nil {
+			if err := json.Unmarshal(value, b); err != nil {
+				return nil, err
+			}
+			elapsed := float64(now.UnixNano() - b.Updated)
+			b.Tokens = math.Min(capacity, b.Tokens+math.Max(0, elapsed)*rate)
+		}
+		b.Updated = now.UnixNano()
+
+		allowed = b.Tokens >= 1
+		if !allowed || n == 0 {
+			// nothing is taken, the refill is calculated from the last update
+			return nil, nil
+		}
+		b.Tokens -= n
+		return json.Marshal(b)
+	})
+	if err != nil {
+		return false, 0, err
+	}
+
+	if allowed {
+		return true, 0, nil
+	}
+	return false, time.Duration(math.Ceil((1 - b.Tokens) / rate)), nil
+}
@@ -0,0 +1,112 @@
+package ratelimit_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/proxy/pkg/ratelimit"
+)
+
+func TestAllow(t *testing.T) {
+	l := ratelimit.NewLimiter(kv.NewMemoryStore(0))
+	rule := config.RateLimitRule{Requests: 2, Period: time.Hour}
+
+	for i := range 2 {
+		ok, _, err := l.Allow("user:einstein", rule)
+		if err != nil || !ok {
+			t.Fatalf("request %d: expected to be allowed, got %v, %v", i, ok, err)
+		}
+	}
+
+	ok, retryAfter, err := l.Allow("user:einstein", rule)
+	if err != nil || ok {
+		t.Fatalf("expected to be limited, got %v, %v", ok, err)
+	}
+	if retryAfter <= 29*time.Minute || retryAfter > 30*time.Minute {
+		t.Errorf("expected to retry after about 30 minutes, got %s", retryAfter)
+	}
+
+	if ok, _, err := l.Allow("user:marie", rule); err != nil || !ok {
+		t.Errorf("expected other keys to be allowed, got %v, %v", ok, err)
+	}
+}
+
+func TestAllowBurst(t *testing.T) {
+	l := ratelimit.NewLimiter(kv.NewMemoryStore(0))
+	rule := config.RateLimitRule{Requests: 10, Period: time.Hour, Burst: 1}
+
+	if ok, _, err := l.Allow("ip:127.0.0.1", rule); err != nil || !ok {
+		t.Fatalf("expected to be allowed, got %v, %v", ok, err)
+	}
+	if ok, _, err := l.Allow("ip:127.0.0.1", rule); err != nil || ok {
+		t.Fatalf("expected to be limited by the burst, got %v, %v", ok, err)
+	}
+}
+
+func TestAllowRefill(t *testing.T) {
+	l := ratelimit.NewLimiter(kv.NewMemoryStore(0))
+	rule := config.RateLimitRule{Requests: 1, Period: 50 * time.Millisecond}
+
+	if ok, _, err := l.Allow("ip:127.0.0.1", rule); err != nil || !ok {
+		t.Fatalf("expected to be allowed, got %v, %v", ok, err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if ok, _, err := l.Allow("ip:127.0.0.1", rule); err != nil || !ok {
+		t.Fatalf("expected the bucket to be refilled, got %v, %v", ok, err)
+	}
+}
+
+func TestAllowWithoutLimit(t *testing.T) {
+	l := ratelimit.NewLimiter(kv.NewMemoryStore(0))
+
+	for range 100 {
+		if ok, _, err := l.Allow("ip:127.0.0.1", config.RateLimitRule{}); err != nil || !ok {
+			t.Fatalf("expected to be allowed, got %v, %v", ok, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	l := ratelimit.NewLimiter(kv.NewMemoryStore(0))
+	rule := config.RateLimitRule{Requests: 1, Period: time.Hour}
+
+	for range 3 {
+		if ok, _, err := l.Check("ip:127.0.0.1", rule); err != nil || !ok {
+			t.Fatalf("expected checking not to take a token, got %v, %v", ok, err)
+		}
+	}
+	if ok, _, err := l.Allow("ip:127.0.0.1", rule); err != nil || !ok {
+		t.Fatalf("expected to be allowed, got %v, %v", ok, err)
+	}
+	if ok, _, err := l.Check("ip:127.0.0.1", rule); err != nil || ok {
+		t.Fatalf("expected the bucket to be empty, got %v, %v", ok, err)
+	}
+}
+
+func TestAllowSharedStore(t *testing.T) {
+	// two proxy instances sharing a store
+	s := kv.NewMemoryStore(0)
+	a, b := ratelimit.NewLimiter(s), ratelimit.NewLimiter(s)
+	rule := config.RateLimitRule{Requests: 10, Period: time.Hour}
+
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := range 20 {
+		wg.Add(1)
+		go func(l *ratelimit.Limiter) {
+			defer wg.Done()
+			if ok, _, err := l.Allow("user:einstein", rule); err == nil && ok {
+				allowed.Add(1)
+			}
+		}([]*ratelimit.Limiter{a, b}[i%2])
+	}
+	wg.Wait()
+
+	if allowed.Load() != 10 {
+		t.Errorf("expected 10 requests to be allowed, got %d", allowed.Load())
+	}
+}
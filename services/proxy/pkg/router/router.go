@@ -91,6 +91,8 @@ type RoutingInfo struct {
 	unprotected      bool
 	remoteUserHeader string
 	skipXAccessToken bool
+	rateLimit        *config.RateLimitRule
+	rateLimitName    string
 }
 
 // Rewrite returns the proxy rewrite hook.
@@ -114,6 +116,12 @@ func (r RoutingInfo) SkipXAccessToken() bool {
 	return r.skipXAccessToken
 }
 
+// RateLimit returns the rate limit of the route and a name identifying the route. If the route
+// has no rate limit of its own, nil is returned and the default limit applies.
+func (r RoutingInfo) RateLimit() (string, *config.RateLimitRule) {
+	return r.rateLimitName, r.rateLimit
+}
+
 // Router handles the routing of HTTP requests according to the given policies.
 type Router struct {
 	logger          log.Logger
@@ -143,6 +151,8 @@ func (rt Router) addHost(policy string, target *url.URL, route config.Route) {
 		unprotected:      route.Unprotected,
 		remoteUserHeader: route.RemoteUserHeader,
 		skipXAccessToken: route.SkipXAccessToken,
+		rateLimit:        route.RateLimit,
+		rateLimitName:    policy + ":" + route.Method + ":" + route.Endpoint,
 		rewrite: func(req *httputil.ProxyRequest) {
 			if route.Service != "" {
 				// select next node
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
+	"github.com/opencloud-eu/opencloud/pkg/service/grpc"
+	"github.com/opencloud-eu/opencloud/pkg/tracing"
+	searchmsg "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/search/v0"
+	searchsvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/search/v0"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/config/parser"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/search"
+)
+
+// Reindex is the entrypoint for the reindex command.
+func Reindex(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:     "reindex",
+		Usage:    "rebuild the whole index in the background and swap it in once it is complete",
+		Category: "index management",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "status",
+				Usage: "show the status of the current or last reindex instead of starting a new one",
+			},
+			&cli.BoolFlag{
+				Name:  "abort",
+				Usage: "abort the running reindex, the current index stays in use",
+			},
+			&cli.BoolFlag{
+				Name:  "wait",
+				Usage: "wait until the reindex is done",
+			},
+		},
+		Before: func(_ *cli.Context) error {
+			return configlog.ReturnFatal(parser.ParseConfig(cfg))
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.Bool("status") && ctx.Bool("abort") {
+				return errors.New("--status and --abort can't be combined")
+			}
+
+			traceProvider, err := tracing.GetServiceTraceProvider(cfg.Tracing, cfg.Service.Name)
+			if err != nil {
+				return err
+			}
+			grpcClient, err := grpc.NewClient(
+				append(grpc.GetClientOptions(cfg.GRPCClientTLS),
+					grpc.WithTraceProvider(traceProvider),
+				)...,
+			)
+			if err != nil {
+				return err
+			}
+
+			c := searchsvc.NewSearchProviderService("eu.opencloud.api.search", grpcClient)
+
+			var status *searchmsg.ReindexStatus
+			if ctx.Bool("status") {
+				res, err := c.GetReindexStatus(context.Background(), &searchsvc.GetReindexStatusRequest{})
+				if err != nil {
+					fmt.Println("failed to get the reindex status: " + err.Error())
+					return err
+				}
+				status = res.GetStatus()
+			} else {
+				res, err := c.Reindex(context.Background(), &searchsvc.ReindexRequest{Abort: ctx.Bool("abort")})
+				if err != nil {
+					fmt.Println("failed to reindex: " + err.Error())
+					return err
+				}
+				status = res.GetStatus()
+			}
+
+			for ctx.Bool("wait") && status.GetState() == search.ReindexStateRunning {
+				printReindexStatus(status)
+				time.Sleep(5 * time.Second)
+
+				res, err := c.GetReindexStatus(context.Background(), &searchsvc.GetReindexStatusRequest{})
+				if err != nil {
+					fmt.Println("failed to get the reindex status: " + err.Error())
+					return err
+				}
+				status = res.GetStatus()
+			}
+
+			printReindexStatus(status)
+			if status.GetState() == search.ReindexStateFailed {
+				return errors.New(status.GetError())
+			}
+			return nil
+		},
+	}
+}
+
+func printReindexStatus(status *searchmsg.ReindexStatus) {
+	fmt.Printf("state: %s, spaces: %d/%d, resources: %d", status.GetState(), status.GetSpacesDone(), status.GetSpacesTotal(), status.GetResources())
+	if status.GetStartedAt() != nil {
+		fmt.Printf(", started: %s", status.GetStartedAt().AsTime().Format(time.RFC3339))
+	}
+	if status.GetFinishedAt() != nil {
+		fmt.Printf(", finished: %s", status.GetFinishedAt().AsTime().Format(time.RFC3339))
+	}
+	if status.GetError() != "" {
+		fmt.Printf(", error: %s", status.GetError())
+	}
+	fmt.Println()
+}
@@ -1,10 +1,13 @@
 package config
 
+import "time"
+
 // Extractor defines which extractor to use
 type Extractor struct {
-	Type             string        `yaml:"type" env:"SEARCH_EXTRACTOR_TYPE" desc:"Defines the content extraction engine. Defaults to 'basic'. Supported values are: 'basic' and 'tika'." introductionVersion:"1.0.0"`
-	CS3AllowInsecure bool          `yaml:"cs3_allow_insecure" env:"OC_INSECURE;SEARCH_EXTRACTOR_CS3SOURCE_INSECURE" desc:"Ignore untrusted SSL certificates when connecting to the CS3 source." introductionVersion:"1.0.0"`
-	Tika             ExtractorTika `yaml:"tika"`
+	Type             string          `yaml:"type" env:"SEARCH_EXTRACTOR_TYPE" desc:"Defines the content extraction engine. Defaults to 'basic'. Supported values are: 'basic', 'tika' and 'native'. See the text description for more details." introductionVersion:"1.0.0"`
+	CS3AllowInsecure bool            `yaml:"cs3_allow_insecure" env:"OC_INSECURE;SEARCH_EXTRACTOR_CS3SOURCE_INSECURE" desc:"Ignore untrusted SSL certificates when connecting to the CS3 source." introductionVersion:"1.0.0"`
+	Tika             ExtractorTika   `yaml:"tika"`
+	Native           ExtractorNative `yaml:"native"`
 }
 
 // ExtractorTika configures the Tika extractor
@@ -12,3 +15,9 @@ type ExtractorTika struct {
 	TikaURL        string `yaml:"tika_url" env:"SEARCH_EXTRACTOR_TIKA_TIKA_URL" desc:"URL of the tika server." introductionVersion:"1.0.0"`
 	CleanStopWords bool   `yaml:"clean_stop_words" env:"SEARCH_EXTRACTOR_TIKA_CLEAN_STOP_WORDS" desc:"Defines if stop words should be cleaned or not. See the documentation for more details." introductionVersion:"1.0.0"`
 }
+
+// ExtractorNative configures the native extractor
+type ExtractorNative struct {
+	Timeout          time.Duration `yaml:"timeout" env:"SEARCH_EXTRACTOR_NATIVE_TIMEOUT" desc:"The maximum time spent extracting the content of a single file. Only the metadata is indexed if it is exceeded. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	MaxContentLength uint64        `yaml:"max_content_length" env:"SEARCH_EXTRACTOR_NATIVE_MAX_CONTENT_LENGTH" desc:"The maximum number of bytes of text extracted from a single file, the rest is not indexed. This also limits the uncompressed size read from office documents." introductionVersion:"%%NEXT%%"`
+}
@@ -39,6 +39,11 @@ func DefaultConfig() *config.Config {
 			Bleve: config.EngineBleve{
 				Datapath: filepath.Join(defaults.BaseDataPath(), "search"),
 			},
+			OpenSearch: config.EngineOpenSearch{
+				URL:            "http://127.0.0.1:9200",
+				Index:          "opencloud-search",
+				RequestTimeout: 30 * time.Second,
+			},
 		},
 		Extractor: config.Extractor{
 			Type:             "basic",
@@ -47,6 +52,10 @@ func DefaultConfig() *config.Config {
 				TikaURL:        "http://127.0.0.1:9998",
 				CleanStopWords: true,
 			},
+			Native: config.ExtractorNative{
+				Timeout:          30 * time.Second,
+				MaxContentLength: 1024 * 1024,
+			},
 		},
 		Events: config.Events{
 			Endpoint:         "127.0.0.1:9233",
@@ -1,12 +1,25 @@
 package config
 
+import "time"
+
 // Engine defines which search engine to use
 type Engine struct {
-	Type  string      `yaml:"type" env:"SEARCH_ENGINE_TYPE" desc:"Defines which search engine to use. Defaults to 'bleve'. Supported values are: 'bleve'." introductionVersion:"1.0.0"`
-	Bleve EngineBleve `yaml:"bleve"`
+	Type       string           `yaml:"type" env:"SEARCH_ENGINE_TYPE" desc:"Defines which search engine to use. Defaults to 'bleve'. Supported values are: 'bleve' and 'opensearch'." introductionVersion:"1.0.0"`
+	Bleve      EngineBleve      `yaml:"bleve"`
+	OpenSearch EngineOpenSearch `yaml:"opensearch"`
 }
 
 // EngineBleve configures the bleve engine
 type EngineBleve struct {
 	Datapath string `yaml:"data_path" env:"SEARCH_ENGINE_BLEVE_DATA_PATH" desc:"The directory where the filesystem will store search data. If not defined, the root directory derives from $OC_BASE_DATA_PATH/search." introductionVersion:"1.0.0"`
 }
+
+// EngineOpenSearch configures the OpenSearch engine
+type EngineOpenSearch struct {
+	URL            string        `yaml:"url" env:"SEARCH_ENGINE_OPENSEARCH_URL" desc:"URL of the OpenSearch or Elasticsearch cluster." introductionVersion:"%%NEXT%%"`
+	Index          string        `yaml:"index" env:"SEARCH_ENGINE_OPENSEARCH_INDEX" desc:"Name of the index which is used to store the search data. The index is created with the required mapping if it does not exist." introductionVersion:"%%NEXT%%"`
+	Username       string        `yaml:"username" env:"SEARCH_ENGINE_OPENSEARCH_USERNAME" desc:"Username for the basic authentication against the cluster. Leave empty to disable authentication." introductionVersion:"%%NEXT%%"`
+	Password       string        `yaml:"password" env:"SEARCH_ENGINE_OPENSEARCH_PASSWORD" desc:"Password for the basic authentication against the cluster." introductionVersion:"%%NEXT%%"`
+	Insecure       bool          `yaml:"insecure" env:"OC_INSECURE;SEARCH_ENGINE_OPENSEARCH_INSECURE" desc:"Ignore untrusted SSL certificates when connecting to the cluster." introductionVersion:"%%NEXT%%"`
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"SEARCH_ENGINE_OPENSEARCH_REQUEST_TIMEOUT" desc:"Timeout for requests to the cluster. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+}
@@ -0,0 +1,228 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	provider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/config"
+)
+
+// errUnsupported is returned by the native parsers for content they can't handle
+var errUnsupported = errors.New("unsupported content")
+
+// parsed is the result of a native parser
+type parsed struct {
+	title   string
+	content string
+}
+
+// Native is used to extract content from a resource in-process without an external service.
+// It supports plain text, markdown, pdf, office open xml and open document files as well as
+// the exif metadata and dimensions of images.
+type Native struct {
+	*Basic
+	Retriever
+	ContentExtractionSizeLimit uint64
+	MaxContentLength           uint64
+	Timeout                    time.Duration
+}
+
+// NewNativeExtractor creates a new Native instance.
+func NewNativeExtractor(gatewaySelector pool.Selectable[gateway.GatewayAPIClient], logger log.Logger, cfg *config.Config) (*Native, error) {
+	basic, err := NewBasicExtractor(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Native{
+		Basic:                      basic,
+		Retriever:                  newCS3Retriever(gatewaySelector, logger, cfg.Extractor.CS3AllowInsecure),
+		ContentExtractionSizeLimit: cfg.ContentExtractionSizeLimit,
+		MaxContentLength:           cfg.Extractor.Native.MaxContentLength,
+		Timeout:                    cfg.Extractor.Native.Timeout,
+	}, nil
+}
+
+// Extract loads a resource from its underlying storage, parses it and processes the result into a Document.
+// Only the metadata is returned if the file can't be parsed in time.
+func (n Native) Extract(ctx context.Context, ri *provider.ResourceInfo) (Document, error) {
+	doc, err := n.Basic.Extract(ctx, ri)
+	if err != nil {
+		return doc, err
+	}
+
+	if ri.Size == 0 || ri.Type != provider.ResourceType_RESOURCE_TYPE_FILE {
+		return doc, nil
+	}
+
+	if ri.Size > n.ContentExtractionSizeLimit {
+		n.logger.Info().Interface("ResourceID", ri.Id).Str("Name", ri.Name).Msg("file exceeds content extraction size limit. skipping.")
+		return doc, nil
+	}
+
+	kind := nativeKind(ri.MimeType, ri.Name)
+	if kind == "" {
+		return doc, nil
+	}
+
+	if n.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.Timeout)
+		defer cancel()
+	}
+
+	data, err := n.Retrieve(ctx, ri.Id)
+	if err != nil {
+		return doc, err
+	}
+	defer data.Close()
+
+	b, err := io.ReadAll(io.LimitReader(data, int64(n.ContentExtractionSizeLimit)))
+	if err != nil {
+		return doc, err
+	}
+
+	// the parsers run in the background and stop when the context is done
+	result := make(chan Document, 1)
+	go func() {
+		d := doc
+		if err := n.parse(ctx, kind, b, &d); err != nil {
+			n.logger.Debug().Err(err).Interface("ResourceID", ri.Id).Str("Name", ri.Name).Msg("could not extract content")
+		}
+		result <- d
+	}()
+
+	select {
+	case d := <-result:
+		return d, nil
+	case <-ctx.Done():
+		n.logger.Info().Interface("ResourceID", ri.Id).Str("Name", ri.Name).Msg("content extraction timed out. skipping.")
+		return doc, nil
+	}
+}
+
+func (n Native) parse(ctx context.Context, kind string, b []byte, doc *Document) error {
+	var (
+		p   parsed
+		err error
+	)
+	switch kind {
+	case "text":
+		p.content = string(b)
+	case "markdown":
+		p.content = stripMarkdown(string(b))
+	case "pdf":
+		p, err = parsePDF(ctx, b, n.MaxContentLength)
+	case "ooxml":
+		p, err = parseOOXML(ctx, b, n.MaxContentLength)
+	case "odf":
+		p, err = parseODF(ctx, b, n.MaxContentLength)
+	case "image":
+		doc.Image, doc.Photo, doc.Location = parseImage(b)
+		return nil
+	}
+
+	doc.Title = strings.TrimSpace(p.title)
+	doc.Content = truncateUTF8(strings.TrimSpace(strings.ToValidUTF8(p.content, "")), n.MaxContentLength)
+	return err
+}
+
+// nativeKind returns the parser for the mime type or file extension, it is empty for unsupported files
+func nativeKind(mimeType, name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	switch {
+	case mimeType == "text/markdown" || ext == ".md" || ext == ".markdown":
+		return "markdown"
+	case strings.HasPrefix(mimeType, "text/"), mimeType == "application/json", mimeType == "application/xml":
+		return "text"
+	case mimeType == "application/pdf":
+		return "pdf"
+	case strings.HasPrefix(mimeType, "application/vnd.openxmlformats-officedocument."):
+		return "ooxml"
+	case strings.HasPrefix(mimeType, "application/vnd.oasis.opendocument."):
+		return "odf"
+	case strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	}
+	return ""
+}
+
+var (
+	_mdFence    = regexp.MustCompile("(?m)^[ \\t]*(```|~~~).*$")
+	_mdHeading  = regexp.MustCompile(`(?m)^[ \t]{0,3}#{1,6}[ \t]+`)
+	_mdQuote    = regexp.MustCompile(`(?m)^[ \t]*>[ \t]?`)
+	_mdList     = regexp.MustCompile(`(?m)^[ \t]*([*+-]|\d+[.)])[ \t]+(\[[ xX]\][ \t]+)?`)
+	_mdRule     = regexp.MustCompile(`(?m)^[ \t]*([-*_][ \t]*){3,}$`)
+	_mdImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	_mdLink     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	_mdEmphasis = regexp.MustCompile("(\\*\\*|__|\\*|_|~~|`)([^*_~`\n]+)(\\*\\*|__|\\*|_|~~|`)")
+	_mdHTML     = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+)
+
+// stripMarkdown removes the markdown syntax and keeps the text
+func stripMarkdown(s string) string {
+	s = _mdFence.ReplaceAllString(s, "")
+	s = _mdHeading.ReplaceAllString(s, "")
+	s = _mdQuote.ReplaceAllString(s, "")
+	s = _mdRule.ReplaceAllString(s, "")
+	s = _mdList.ReplaceAllString(s, "")
+	s = _mdImage.ReplaceAllString(s, "$1")
+	s = _mdLink.ReplaceAllString(s, "$1")
+	s = _mdEmphasis.ReplaceAllString(s, "$2")
+	return _mdHTML.ReplaceAllString(s, "")
+}
+
+// truncateUTF8 cuts s to at most max bytes without splitting a character
+func truncateUTF8(s string, max uint64) string {
+	if max == 0 || uint64(len(s)) <= max {
+		return s
+	}
+	s = s[:max]
+	for len(s) > 0 {
+		// s is valid utf8, so an invalid last rune was cut
+		if r, size := utf8.DecodeLastRuneInString(s); r != utf8.RuneError || size != 1 {
+			break
+		}
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// textBuffer collects text up to a limit
+type textBuffer struct {
+	bytes.Buffer
+	ctx   context.Context
+	limit uint64
+}
+
+// full reports if the limit is reached or the context is done, the parsers stop then
+func (t *textBuffer) full() bool {
+	if t.ctx != nil && t.ctx.Err() != nil {
+		return true
+	}
+	return t.limit > 0 && uint64(t.Len()) >= t.limit
+}
+
+// space separates words without doubling whitespace
+func (t *textBuffer) space(sep byte) {
+	if t.Len() == 0 {
+		return
+	}
+	switch t.Bytes()[t.Len()-1] {
+	case ' ', '\n':
+		return
+	}
+	t.WriteByte(sep)
+}
@@ -0,0 +1,306 @@
+package content
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/gif"  // register the gif decoder for the image dimensions
+	_ "image/jpeg" // register the jpeg decoder for the image dimensions
+	_ "image/png"  // register the png decoder for the image dimensions
+	"math"
+	"strings"
+	"time"
+
+	libregraph "github.com/opencloud-eu/libre-graph-api-go"
+	_ "golang.org/x/image/bmp"  // register the bmp decoder for the image dimensions
+	_ "golang.org/x/image/tiff" // register the tiff decoder for the image dimensions
+)
+
+// exif tags
+const (
+	_exifMake             = 0x010f
+	_exifModel            = 0x0110
+	_exifOrientation      = 0x0112
+	_exifIFD              = 0x8769
+	_exifGPSIFD           = 0x8825
+	_exifExposureTime     = 0x829a
+	_exifFNumber          = 0x829d
+	_exifISO              = 0x8827
+	_exifDateTimeOriginal = 0x9003
+	_exifFocalLength      = 0x920a
+
+	_gpsLatitudeRef  = 0x0001
+	_gpsLatitude     = 0x0002
+	_gpsLongitudeRef = 0x0003
+	_gpsLongitude    = 0x0004
+	_gpsAltitudeRef  = 0x0005
+	_gpsAltitude     = 0x0006
+)
+
+// parseImage returns the dimensions of an image and the photo and location data of its exif metadata
+func parseImage(b []byte) (*libregraph.Image, *libregraph.Photo, *libregraph.GeoCoordinates) {
+	var img *libregraph.Image
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(b)); err == nil {
+		img = libregraph.NewImage()
+		img.SetWidth(int32(cfg.Width))
+		img.SetHeight(int32(cfg.Height))
+	}
+
+	tiff := exifData(b)
+	if tiff == nil {
+		return img, nil, nil
+	}
+	e, ok := newExifReader(tiff)
+	if !ok {
+		return img, nil, nil
+	}
+
+	ifd0 := e.ifd(e.order.Uint32(tiff[4:]))
+	return img, e.photo(ifd0), e.location(ifd0)
+}
+
+// exifData returns the tiff structure holding the exif metadata of jpeg and tiff files
+func exifData(b []byte) []byte {
+	if bytes.HasPrefix(b, []byte("II*\x00")) || bytes.HasPrefix(b, []byte("MM\x00*")) {
+		return b
+	}
+	if !bytes.HasPrefix(b, []byte{0xff, 0xd8}) {
+		return nil
+	}
+
+	// walk the jpeg segments up to the start of the scan
+	for pos := 2; pos+4 <= len(b); {
+		if b[pos] != 0xff {
+			return nil
+		}
+		marker := b[pos+1]
+		if marker == 0xda || marker == 0xd9 {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(b[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(b) {
+			return nil
+		}
+		if data := b[pos+4 : end]; marker == 0xe1 && bytes.HasPrefix(data, []byte("Exif\x00\x00")) {
+			return data[6:]
+		}
+		pos = end
+	}
+	return nil
+}
+
+type exifEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+type exifReader struct {
+	b     []byte
+	order binary.ByteOrder
+}
+
+func newExifReader(b []byte) (*exifReader, bool) {
+	if len(b) < 8 {
+		return nil, false
+	}
+	switch string(b[:2]) {
+	case "II":
+		return &exifReader{b: b, order: binary.LittleEndian}, true
+	case "MM":
+		return &exifReader{b: b, order: binary.BigEndian}, true
+	}
+	return nil, false
+}
+
+// ifd reads the entries of the image file directory at offset
+func (e *exifReader) ifd(offset uint32) map[uint16]exifEntry {
+	entries := make(map[uint16]exifEntry)
+	if uint64(offset)+2 > uint64(len(e.b)) {
+		return entries
+	}
+
+	n := int(e.order.Uint16(e.b[offset:]))
+	for i := 0; i < n; i++ {
+		p := int(offset) + 2 + i*12
+		if p+12 > len(e.b) {
+			break
+		}
+		typ := e.order.Uint16(e.b[p+2:])
+		count := e.order.Uint32(e.b[p+4:])
+
+		size := uint64(exifTypeSize(typ)) * uint64(count)
+		if size == 0 {
+			continue
+		}
+		value := e.b[p+8 : p+12]
+		if size > 4 {
+			o := uint64(e.order.Uint32(e.b[p+8:]))
+			if o+size > uint64(len(e.b)) {
+				continue
+			}
+			value = e.b[o : o+size]
+		}
+		entries[e.order.Uint16(e.b[p:])] = exifEntry{typ: typ, count: count, value: value[:size]}
+	}
+	return entries
+}
+
+func exifTypeSize(typ uint16) int {
+	switch typ {
+	case 1, 2, 7: // byte, ascii, undefined
+		return 1
+	case 3: // short
+		return 2
+	case 4, 9: // long, slong
+		return 4
+	case 5, 10: // rational, srational
+		return 8
+	}
+	return 0
+}
+
+func (e *exifReader) string(entries map[uint16]exifEntry, tag uint16) (string, bool) {
+	v, ok := entries[tag]
+	if !ok || v.typ != 2 {
+		return "", false
+	}
+	s := strings.TrimSpace(strings.TrimRight(string(v.value), "\x00"))
+	return s, s != ""
+}
+
+func (e *exifReader) uint(entries map[uint16]exifEntry, tag uint16) (uint32, bool) {
+	v, ok := entries[tag]
+	if !ok {
+		return 0, false
+	}
+	switch v.typ {
+	case 3:
+		return uint32(e.order.Uint16(v.value)), true
+	case 4:
+		return e.order.Uint32(v.value), true
+	}
+	return 0, false
+}
+
+// rationals returns the values of rational entries
+func (e *exifReader) rationals(entries map[uint16]exifEntry, tag uint16) ([]float64, bool) {
+	v, ok := entries[tag]
+	if !ok || (v.typ != 5 && v.typ != 10) {
+		return nil, false
+	}
+	r := make([]float64, 0, v.count)
+	for i := 0; i+8 <= len(v.value); i += 8 {
+		num, den := float64(e.order.Uint32(v.value[i:])), float64(e.order.Uint32(v.value[i+4:]))
+		if v.typ == 10 {
+			num, den = float64(int32(e.order.Uint32(v.value[i:]))), float64(int32(e.order.Uint32(v.value[i+4:])))
+		}
+		if den == 0 {
+			return nil, false
+		}
+		r = append(r, num/den)
+	}
+	return r, len(r) > 0
+}
+
+func (e *exifReader) photo(ifd0 map[uint16]exifEntry) *libregraph.Photo {
+	var photo *libregraph.Photo
+	initPhoto := func() {
+		if photo == nil {
+			photo = libregraph.NewPhoto()
+		}
+	}
+
+	if v, ok := e.string(ifd0, _exifMake); ok {
+		initPhoto()
+		photo.SetCameraMake(v)
+	}
+	if v, ok := e.string(ifd0, _exifModel); ok {
+		initPhoto()
+		photo.SetCameraModel(v)
+	}
+	if v, ok := e.uint(ifd0, _exifOrientation); ok {
+		initPhoto()
+		photo.SetOrientation(int32(v))
+	}
+
+	offset, ok := e.uint(ifd0, _exifIFD)
+	if !ok {
+		return photo
+	}
+	exif := e.ifd(offset)
+
+	if v, ok := e.rationals(exif, _exifFNumber); ok {
+		initPhoto()
+		photo.SetFNumber(v[0])
+	}
+	if v, ok := e.rationals(exif, _exifFocalLength); ok {
+		initPhoto()
+		photo.SetFocalLength(v[0])
+	}
+	if v, ok := e.uint(exif, _exifISO); ok {
+		initPhoto()
+		photo.SetIso(int32(v))
+	}
+	if v, ok := e.string(exif, _exifDateTimeOriginal); ok {
+		if t, err := time.Parse("2006:01:02 15:04:05", v); err == nil {
+			initPhoto()
+			photo.SetTakenDateTime(t)
+		}
+	}
+	if v, ok := e.rationals(exif, _exifExposureTime); ok && v[0] > 0 {
+		initPhoto()
+		photo.SetExposureNumerator(1)
+		photo.SetExposureDenominator(math.Round(1 / v[0]))
+	}
+
+	return photo
+}
+
+func (e *exifReader) location(ifd0 map[uint16]exifEntry) *libregraph.GeoCoordinates {
+	offset, ok := e.uint(ifd0, _exifGPSIFD)
+	if !ok {
+		return nil
+	}
+	gps := e.ifd(offset)
+
+	var location *libregraph.GeoCoordinates
+	initLocation := func() {
+		if location == nil {
+			location = libregraph.NewGeoCoordinates()
+		}
+	}
+
+	degrees := func(tag, refTag uint16, negative string) (float64, bool) {
+		v, ok := e.rationals(gps, tag)
+		if !ok || len(v) != 3 {
+			return 0, false
+		}
+		d := v[0] + v[1]/60 + v[2]/3600
+		if ref, _ := e.string(gps, refTag); ref == negative {
+			d = -d
+		}
+		return d, true
+	}
+
+	if v, ok := degrees(_gpsLatitude, _gpsLatitudeRef, "S"); ok {
+		initLocation()
+		location.SetLatitude(v)
+	}
+	if v, ok := degrees(_gpsLongitude, _gpsLongitudeRef, "W"); ok {
+		initLocation()
+		location.SetLongitude(v)
+	}
+	if v, ok := e.rationals(gps, _gpsAltitude); ok {
+		// the altitude is stored in metres, a reference of 1 means below sea level
+		alt := v[0]
+		if ref, ok := gps[_gpsAltitudeRef]; ok && len(ref.value) > 0 && ref.value[0] == 1 {
+			alt = -alt
+		}
+		initLocation()
+		location.SetAltitude(alt / 0.3048)
+	}
+
+	return location
+}
@@ -0,0 +1,185 @@
+package content
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// _xmlMaxPartSize limits the uncompressed size of a single xml part, also without a content limit
+const _xmlMaxPartSize = 64 << 20
+
+// parseOOXML extracts the text of office open xml documents (docx, xlsx, pptx)
+func parseOOXML(ctx context.Context, b []byte, limit uint64) (parsed, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return parsed{}, fmt.Errorf("%w: %s", errUnsupported, err)
+	}
+
+	var p parsed
+	if f := zipFile(zr, "docProps/core.xml"); f != nil {
+		p.title, _ = xmlElementText(f, "title", limit)
+	}
+
+	var parts []*zip.File
+	for _, f := range zr.File {
+		switch {
+		case f.Name == "word/document.xml",
+			f.Name == "xl/sharedStrings.xml",
+			strings.HasPrefix(f.Name, "xl/worksheets/sheet"),
+			strings.HasPrefix(f.Name, "ppt/slides/slide") && path.Ext(f.Name) == ".xml":
+			parts = append(parts, f)
+		}
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		return partIndex(parts[i].Name) < partIndex(parts[j].Name)
+	})
+
+	buf := &textBuffer{ctx: ctx, limit: limit}
+	for _, f := range parts {
+		// text is stored in <w:t>, <t> or <a:t> elements, inline strings of worksheets in <is><t>
+		if err := xmlText(f, buf, func(name string) bool { return name == "t" }); err != nil {
+			return p, err
+		}
+		if buf.full() {
+			break
+		}
+	}
+	p.content = buf.String()
+	return p, nil
+}
+
+// parseODF extracts the text of open document files (odt, ods, odp)
+func parseODF(ctx context.Context, b []byte, limit uint64) (parsed, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return parsed{}, fmt.Errorf("%w: %s", errUnsupported, err)
+	}
+
+	var p parsed
+	if f := zipFile(zr, "meta.xml"); f != nil {
+		p.title, _ = xmlElementText(f, "title", limit)
+	}
+
+	f := zipFile(zr, "content.xml")
+	if f == nil {
+		return p, fmt.Errorf("%w: content.xml missing", errUnsupported)
+	}
+
+	buf := &textBuffer{ctx: ctx, limit: limit}
+	// all text of the body is content, paragraphs and headings are separated by newlines
+	err = xmlText(f, buf, func(string) bool { return true })
+	p.content = buf.String()
+	return p, err
+}
+
+func zipFile(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// partIndex returns the number of parts like slide12.xml to sort them naturally
+func partIndex(name string) int {
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	i := strings.LastIndexFunc(base, func(r rune) bool { return r < '0' || r > '9' })
+	n, err := strconv.Atoi(base[i+1:])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// xmlText writes the character data of the elements matched by isText to buf. The uncompressed size
+// read is limited to protect against zip bombs. Paragraphs, headings, rows and cells are separated.
+func xmlText(f *zip.File, buf *textBuffer, isText func(local string) bool) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// markup is usually a multiple of the text
+	size := int64(_xmlMaxPartSize)
+	if buf.limit > 0 && int64(buf.limit)*16 < size {
+		size = int64(buf.limit) * 16
+	}
+	r := io.LimitReader(rc, size)
+
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	depth := 0
+	for !buf.full() {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			// keep what was extracted from truncated or malformed parts
+			return nil
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if isText(t.Name.Local) {
+				depth++
+			}
+			switch t.Name.Local {
+			case "tab", "s":
+				buf.space(' ')
+			case "br", "line-break":
+				buf.space('\n')
+			}
+		case xml.EndElement:
+			if isText(t.Name.Local) && depth > 0 {
+				depth--
+			}
+			switch t.Name.Local {
+			case "p", "h", "row", "si", "sp", "table-row":
+				buf.space('\n')
+			case "c", "table-cell":
+				buf.space(' ')
+			}
+		case xml.CharData:
+			if depth > 0 {
+				buf.Write(t)
+			}
+		}
+	}
+	return nil
+}
+
+// xmlElementText returns the text of the first element with the local name
+func xmlElementText(f *zip.File, local string, limit uint64) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	dec := xml.NewDecoder(io.LimitReader(rc, int64(max(limit, 64*1024))))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		if s, ok := tok.(xml.StartElement); ok && s.Name.Local == local {
+			var text string
+			if err := dec.DecodeElement(&text, &s); err != nil {
+				return "", err
+			}
+			return text, nil
+		}
+	}
+}
@@ -0,0 +1,489 @@
+package content
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf16"
+)
+
+const (
+	// _pdfMaxStreamSize limits the decompressed size of a single pdf stream
+	_pdfMaxStreamSize = 32 << 20
+	// _pdfMaxNesting limits the depth of nested arrays, deeper arrays are flattened
+	_pdfMaxNesting = 32
+)
+
+// parsePDF extracts the text of the content streams of a pdf. Only text shown with standard
+// encodings can be extracted, text using embedded fonts with custom encodings is skipped.
+func parsePDF(ctx context.Context, b []byte, limit uint64) (parsed, error) {
+	if !bytes.HasPrefix(b, []byte("%PDF-")) {
+		return parsed{}, fmt.Errorf("%w: not a pdf", errUnsupported)
+	}
+	if bytes.Contains(b, []byte("/Encrypt")) {
+		return parsed{}, fmt.Errorf("%w: encrypted pdf", errUnsupported)
+	}
+
+	var p parsed
+	if i := bytes.Index(b, []byte("/Title")); i >= 0 {
+		l := &pdfLexer{b: b, pos: i + len("/Title")}
+		if t := l.next(); t.kind == pdfString {
+			p.title = pdfText(t.val)
+		}
+	}
+
+	buf := &textBuffer{ctx: ctx, limit: limit}
+	for pos := 0; !buf.full(); {
+		dict, data, next, ok := nextPDFStream(b, pos)
+		if !ok {
+			break
+		}
+		pos = next
+
+		if content := pdfStreamContent(dict, data); content != nil {
+			extractPDFText(content, buf)
+			buf.space('\n')
+		}
+	}
+	p.content = buf.String()
+	return p, nil
+}
+
+// nextPDFStream finds the next stream after pos and returns its dictionary, its raw data and the position after it
+func nextPDFStream(b []byte, pos int) ([]byte, []byte, int, bool) {
+	for {
+		i := bytes.Index(b[pos:], []byte("stream"))
+		if i < 0 {
+			return nil, nil, 0, false
+		}
+		start := pos + i
+		pos = start + len("stream")
+
+		// must be the keyword following a dictionary and not 'endstream'
+		dictEnd := bytes.LastIndex(bytes.TrimRight(b[:start], " \t\r\n"), []byte(">>"))
+		if dictEnd < 0 || dictEnd+2 != len(bytes.TrimRight(b[:start], " \t\r\n")) {
+			continue
+		}
+		dictStart := pdfDictStart(b, dictEnd+1)
+		if dictStart < 0 {
+			continue
+		}
+
+		dataStart := pos
+		if dataStart < len(b) && b[dataStart] == '\r' {
+			dataStart++
+		}
+		if dataStart < len(b) && b[dataStart] == '\n' {
+			dataStart++
+		}
+		end := bytes.Index(b[dataStart:], []byte("endstream"))
+		if end < 0 {
+			return nil, nil, 0, false
+		}
+		data := bytes.TrimRight(b[dataStart:dataStart+end], "\r\n")
+		return b[dictStart : dictEnd+2], data, dataStart + end + len("endstream"), true
+	}
+}
+
+// pdfDictStart returns the start of the dictionary ending at end by matching nested dictionaries
+func pdfDictStart(b []byte, end int) int {
+	depth := 0
+	for i := end; i > 0; i-- {
+		switch {
+		case b[i] == '>' && b[i-1] == '>':
+			depth++
+			i--
+		case b[i] == '<' && b[i-1] == '<':
+			depth--
+			i--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// pdfStreamContent returns the decoded data of content streams, other streams like images or fonts return nil
+func pdfStreamContent(dict, data []byte) []byte {
+	for _, skip := range []string{"/Image", "/XRef", "/ObjStm", "/Metadata", "/FontFile", "/Length1", "/Length2", "/Type1C", "/CIDFontType0C", "/OpenType", "/EmbeddedFile"} {
+		if bytes.Contains(dict, []byte(skip)) {
+			return nil
+		}
+	}
+
+	if !bytes.Contains(dict, []byte("/Filter")) {
+		return data
+	}
+	for _, f := range []string{"/ASCII85Decode", "/LZWDecode", "/DCTDecode", "/JPXDecode", "/CCITTFaxDecode", "/JBIG2Decode", "/RunLengthDecode", "/ASCIIHexDecode"} {
+		if bytes.Contains(dict, []byte(f)) {
+			return nil
+		}
+	}
+	if !bytes.Contains(dict, []byte("/FlateDecode")) {
+		return nil
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	defer zr.Close()
+	content, _ := io.ReadAll(io.LimitReader(zr, _pdfMaxStreamSize))
+	return content
+}
+
+// extractPDFText interprets the text operators of a content stream
+func extractPDFText(content []byte, buf *textBuffer) {
+	if !bytes.Contains(content, []byte("BT")) {
+		return
+	}
+
+	l := &pdfLexer{b: content}
+	var operands []pdfToken
+	for !buf.full() {
+		t := l.next()
+		switch t.kind {
+		case pdfEOF:
+			return
+		case pdfOperator:
+		default:
+			operands = append(operands, t)
+			continue
+		}
+
+		switch string(t.val) {
+		case "Tj":
+			writePDFStrings(buf, operands)
+		case "'", "\"":
+			buf.space('\n')
+			writePDFStrings(buf, operands)
+		case "TJ":
+			for _, o := range operands {
+				if o.kind != pdfArray {
+					continue
+				}
+				for _, e := range o.elems {
+					switch e.kind {
+					case pdfString:
+						buf.WriteString(pdfText(e.val))
+					case pdfNumber:
+						// larger negative offsets are used as word spacing
+						if pdfNumberValue(e) < -200 {
+							buf.space(' ')
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) == 2 && pdfNumberValue(operands[1]) != 0 {
+				buf.space('\n')
+			} else {
+				buf.space(' ')
+			}
+		case "T*", "Tm", "ET":
+			buf.space('\n')
+		case "ID":
+			l.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+func pdfNumberValue(t pdfToken) float64 {
+	n, _ := strconv.ParseFloat(string(t.val), 64)
+	return n
+}
+
+func writePDFStrings(buf *textBuffer, operands []pdfToken) {
+	for _, o := range operands {
+		if o.kind == pdfString {
+			buf.WriteString(pdfText(o.val))
+		}
+	}
+}
+
+// pdfText decodes a pdf text string. Strings which are mostly control characters, like glyph ids
+// of fonts with custom encodings, are dropped.
+func pdfText(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		u := make([]uint16, 0, len(b)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return string(utf16.Decode(u))
+	}
+
+	control := 0
+	r := make([]rune, 0, len(b))
+	for _, c := range b {
+		switch {
+		case c == '\t' || c == '\n' || c == '\r':
+			r = append(r, ' ')
+		case c < 0x20:
+			control++
+		default:
+			// PDFDocEncoding matches latin-1 for the printable characters
+			r = append(r, rune(c))
+		}
+	}
+	if control*2 > len(b) {
+		return ""
+	}
+	return string(r)
+}
+
+type pdfTokenKind int
+
+const (
+	pdfEOF pdfTokenKind = iota
+	pdfString
+	pdfNumber
+	pdfName
+	pdfOperator
+	pdfArray
+	pdfDict
+)
+
+type pdfToken struct {
+	kind  pdfTokenKind
+	val   []byte
+	elems []pdfToken
+}
+
+// pdfLexer tokenizes pdf objects and content streams
+type pdfLexer struct {
+	b   []byte
+	pos int
+}
+
+// next returns the next token. Arrays are collected on a stack instead of recursively,
+// so deeply nested or unbalanced input can't exhaust the stack.
+func (l *pdfLexer) next() pdfToken {
+	var (
+		arrays  []pdfToken
+		ignored int // arrays deeper than _pdfMaxNesting
+	)
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.b) {
+			if len(arrays) == 0 {
+				return pdfToken{kind: pdfEOF}
+			}
+			// close the unterminated arrays
+			for i := len(arrays) - 1; i > 0; i-- {
+				arrays[i-1].elems = append(arrays[i-1].elems, arrays[i])
+			}
+			return arrays[0]
+		}
+
+		var t pdfToken
+		c := l.b[l.pos]
+		switch {
+		case c == '(':
+			t = pdfToken{kind: pdfString, val: l.literalString()}
+		case c == '<' && l.pos+1 < len(l.b) && l.b[l.pos+1] == '<':
+			l.pos += 2
+			l.skipUntil(">>")
+			t = pdfToken{kind: pdfDict}
+		case c == '<':
+			t = pdfToken{kind: pdfString, val: l.hexString()}
+		case c == '[':
+			l.pos++
+			if len(arrays) >= _pdfMaxNesting {
+				ignored++
+			} else {
+				arrays = append(arrays, pdfToken{kind: pdfArray})
+			}
+			continue
+		case c == ']':
+			l.pos++
+			switch {
+			case ignored > 0:
+				ignored--
+				continue
+			case len(arrays) == 0:
+				// a stray delimiter
+				continue
+			}
+			t = arrays[len(arrays)-1]
+			arrays = arrays[:len(arrays)-1]
+		case c == '/':
+			l.pos++
+			t = pdfToken{kind: pdfName, val: l.regular()}
+		case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+			t = pdfToken{kind: pdfNumber, val: l.regular()}
+		case c == ')' || c == '>' || c == '{' || c == '}':
+			// a stray delimiter
+			l.pos++
+			continue
+		default:
+			t = pdfToken{kind: pdfOperator, val: l.regular()}
+		}
+
+		if len(arrays) == 0 {
+			return t
+		}
+		arrays[len(arrays)-1].elems = append(arrays[len(arrays)-1].elems, t)
+	}
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.b) {
+		switch l.b[l.pos] {
+		case ' ', '\t', '\r', '\n', '\f', 0:
+			l.pos++
+		case '%':
+			for l.pos < len(l.b) && l.b[l.pos] != '\n' && l.b[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *pdfLexer) skipUntil(s string) {
+	if i := bytes.Index(l.b[l.pos:], []byte(s)); i >= 0 {
+		l.pos += i + len(s)
+		return
+	}
+	l.pos = len(l.b)
+}
+
+// skipInlineImage skips the binary data of an inline image up to the 'EI' operator
+func (l *pdfLexer) skipInlineImage() {
+	for l.pos < len(l.b) {
+		i := bytes.Index(l.b[l.pos:], []byte("EI"))
+		if i < 0 {
+			l.pos = len(l.b)
+			return
+		}
+		l.pos += i + 2
+		if l.pos >= len(l.b) || isPDFSpace(l.b[l.pos]) {
+			return
+		}
+	}
+}
+
+func (l *pdfLexer) regular() []byte {
+	start := l.pos
+	for l.pos < len(l.b) && !isPDFSpace(l.b[l.pos]) && !isPDFDelimiter(l.b[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		// a delimiter which is not a token on its own
+		l.pos++
+	}
+	return l.b[start:l.pos]
+}
+
+func (l *pdfLexer) literalString() []byte {
+	l.pos++ // (
+	var out []byte
+	depth := 1
+	for l.pos < len(l.b) {
+		c := l.b[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.b) {
+				return out
+			}
+			e := l.b[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.b) && l.b[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					n := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.b) && l.b[l.pos] >= '0' && l.b[l.pos] <= '7'; i++ {
+						n = n*8 + int(l.b[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(n)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) hexString() []byte {
+	l.pos++ // <
+	var out []byte
+	var hi byte
+	odd := false
+	for l.pos < len(l.b) {
+		c := l.b[l.pos]
+		l.pos++
+		var v byte
+		switch {
+		case c == '>':
+			if odd {
+				out = append(out, hi<<4)
+			}
+			return out
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		default:
+			continue
+		}
+		if odd {
+			out = append(out, hi<<4|v)
+		} else {
+			hi = v
+		}
+		odd = !odd
+	}
+	return out
+}
+
+func isPDFSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0:
+		return true
+	}
+	return false
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+package content_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"strings"
+	"time"
+
+	provider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	libregraph "github.com/opencloud-eu/libre-graph-api-go"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	conf "github.com/opencloud-eu/opencloud/services/search/pkg/config/defaults"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/content"
+	contentMocks "github.com/opencloud-eu/opencloud/services/search/pkg/content/mocks"
+)
+
+var _ = Describe("Native", func() {
+	var (
+		native *content.Native
+		body   []byte
+	)
+
+	extract := func(name, mimeType string) content.Document {
+		doc, err := native.Extract(context.TODO(), &provider.ResourceInfo{
+			Type:     provider.ResourceType_RESOURCE_TYPE_FILE,
+			Name:     name,
+			MimeType: mimeType,
+			Size:     uint64(len(body)),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return doc
+	}
+
+	BeforeEach(func() {
+		body = nil
+
+		cfg := conf.DefaultConfig()
+		var err error
+		native, err = content.NewNativeExtractor(nil, log.NewLogger(), cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		retriever := &contentMocks.Retriever{}
+		retriever.On("Retrieve", mock.Anything, mock.Anything, mock.Anything).Return(func(context.Context, *provider.ResourceId) io.ReadCloser {
+			return io.NopCloser(bytes.NewReader(body))
+		}, nil)
+		native.Retriever = retriever
+	})
+
+	It("skips non file resources", func() {
+		doc, err := native.Extract(context.TODO(), &provider.ResourceInfo{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(doc.Content).To(Equal(""))
+	})
+
+	It("skips unsupported files", func() {
+		body = []byte("binary")
+		doc := extract("file.bin", "application/octet-stream")
+		Expect(doc.Content).To(Equal(""))
+	})
+
+	It("adds text content", func() {
+		body = []byte("any body\n")
+		doc := extract("file.txt", "text/plain")
+		Expect(doc.Content).To(Equal("any body"))
+		Expect(doc.Name).To(Equal("file.txt"))
+	})
+
+	It("limits the content length", func() {
+		native.MaxContentLength = 6
+		body = []byte("héllo world")
+		doc := extract("file.txt", "text/plain")
+		Expect(doc.Content).To(Equal("héllo"))
+	})
+
+	It("strips markdown", func() {
+		body = []byte("# Title\n\nSome **bold** and _italic_ text with a [link](https://example.com).\n\n- item\n\n```go\ncode()\n```\n")
+		doc := extract("README.md", "text/markdown")
+		Expect(doc.Content).To(Equal("Title\n\nSome bold and italic text with a link.\n\nitem\n\n\ncode()"))
+	})
+
+	It("adds pdf content", func() {
+		stream := "BT /F1 12 Tf 72 712 Td (Hello PDF) Tj 0 -14 Td [(Wor) -20 (ld) -300 (again)] TJ ET"
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		_, _ = zw.Write([]byte(stream))
+		_ = zw.Close()
+
+		body = []byte(fmt.Sprintf("%%PDF-1.4\n1 0 obj\n<< /Title (Test \\(PDF\\)) >>\nendobj\n"+
+			"2 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream\nendobj\n"+
+			"3 0 obj\n<< /Type /XObject /Subtype /Image /Length 4 >>\nstream\nBT (image) Tj ET\nendstream\nendobj\n%%%%EOF\n",
+			compressed.Len(), compressed.String()))
+
+		doc := extract("file.pdf", "application/pdf")
+		Expect(doc.Title).To(Equal("Test (PDF)"))
+		Expect(doc.Content).To(Equal("Hello PDF\nWorld again"))
+	})
+
+	It("survives deeply nested and unbalanced arrays", func() {
+		stream := "BT " + strings.Repeat("[", 1<<20) + " (deep) " + strings.Repeat("]", 10) + strings.Repeat(")>}", 1<<18) + " [(Hello)] TJ ET"
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		_, _ = zw.Write([]byte(stream))
+		_ = zw.Close()
+
+		body = []byte(fmt.Sprintf("%%PDF-1.4\n1 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream\nendobj\n%%%%EOF\n",
+			compressed.Len(), compressed.String()))
+
+		doc := extract("file.pdf", "application/pdf")
+		Expect(doc.Content).To(BeEmpty())
+	})
+
+	It("adds office open xml content", func() {
+		body = zipFile(map[string]string{
+			"docProps/core.xml":   `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Docx Title</dc:title></cp:coreProperties>`,
+			"word/document.xml":   `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Hello</w:t></w:r><w:r><w:tab/><w:t>docx</w:t></w:r></w:p><w:p><w:r><w:t xml:space="preserve">Second paragraph</w:t></w:r></w:p></w:body></w:document>`,
+			"word/styles.xml":     `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:t>style</w:t></w:styles>`,
+			"[Content_Types].xml": `<Types/>`,
+		})
+		doc := extract("file.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document")
+		Expect(doc.Title).To(Equal("Docx Title"))
+		Expect(doc.Content).To(Equal("Hello docx\nSecond paragraph"))
+	})
+
+	It("adds the slides in order", func() {
+		slide := `<p:sld xmlns:p="p" xmlns:a="a"><p:sp><a:p><a:r><a:t>%s</a:t></a:r></a:p></p:sp></p:sld>`
+		body = zipFile(map[string]string{
+			"ppt/slides/slide10.xml": fmt.Sprintf(slide, "ten"),
+			"ppt/slides/slide2.xml":  fmt.Sprintf(slide, "two"),
+		})
+		doc := extract("file.pptx", "application/vnd.openxmlformats-officedocument.presentationml.presentation")
+		Expect(doc.Content).To(Equal("two\nten"))
+	})
+
+	It("adds open document content", func() {
+		body = zipFile(map[string]string{
+			"meta.xml":    `<office:document-meta xmlns:office="o" xmlns:dc="http://purl.org/dc/elements/1.1/"><office:meta><dc:title>ODT Title</dc:title></office:meta></office:document-meta>`,
+			"content.xml": `<office:document-content xmlns:office="o" xmlns:text="t"><office:body><office:text><text:h>Heading</text:h><text:p>Some<text:s/>text</text:p></office:text></office:body></office:document-content>`,
+		})
+		doc := extract("file.odt", "application/vnd.oasis.opendocument.text")
+		Expect(doc.Title).To(Equal("ODT Title"))
+		Expect(doc.Content).To(Equal("Heading\nSome text"))
+	})
+
+	It("adds image and exif content", func() {
+		body = jpegWithExif()
+		doc := extract("photo.jpg", "image/jpeg")
+
+		Expect(doc.Image).ToNot(BeNil())
+		Expect(doc.Image.Width).To(Equal(libregraph.PtrInt32(4)))
+		Expect(doc.Image.Height).To(Equal(libregraph.PtrInt32(2)))
+
+		Expect(doc.Photo).ToNot(BeNil())
+		Expect(doc.Photo.CameraMake).To(Equal(libregraph.PtrString("Canon")))
+		Expect(doc.Photo.CameraModel).To(Equal(libregraph.PtrString("Canon EOS 5D")))
+		Expect(doc.Photo.Orientation).To(Equal(libregraph.PtrInt32(6)))
+		Expect(doc.Photo.FNumber).To(Equal(libregraph.PtrFloat64(1.8)))
+		Expect(doc.Photo.FocalLength).To(Equal(libregraph.PtrFloat64(50)))
+		Expect(doc.Photo.Iso).To(Equal(libregraph.PtrInt32(100)))
+		Expect(doc.Photo.ExposureNumerator).To(Equal(libregraph.PtrFloat64(1)))
+		Expect(doc.Photo.ExposureDenominator).To(Equal(libregraph.PtrFloat64(1000)))
+		Expect(doc.Photo.TakenDateTime).To(Equal(libregraph.PtrTime(time.Date(2018, 1, 1, 12, 34, 56, 0, time.UTC))))
+
+		Expect(doc.Location).ToNot(BeNil())
+		Expect(*doc.Location.Latitude).To(BeNumerically("~", 49.5, 0.0001))
+		Expect(*doc.Location.Longitude).To(BeNumerically("~", -11.25, 0.0001))
+	})
+})
+
+func zipFile(files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = w.Write([]byte(data))
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(zw.Close()).To(Succeed())
+	return buf.Bytes()
+}
+
+type exifTag struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+func exifASCII(tag uint16, s string) exifTag {
+	return exifTag{tag: tag, typ: 2, count: uint32(len(s) + 1), data: append([]byte(s), 0)}
+}
+
+func exifShort(tag uint16, v uint16) exifTag {
+	return exifTag{tag: tag, typ: 3, count: 1, data: binary.BigEndian.AppendUint16(nil, v)}
+}
+
+func exifLong(tag uint16, v uint32) exifTag {
+	return exifTag{tag: tag, typ: 4, count: 1, data: binary.BigEndian.AppendUint32(nil, v)}
+}
+
+func exifRational(tag uint16, v ...uint32) exifTag {
+	var data []byte
+	for _, n := range v {
+		data = binary.BigEndian.AppendUint32(data, n)
+	}
+	return exifTag{tag: tag, typ: 5, count: uint32(len(v) / 2), data: data}
+}
+
+func ifdSize(tags []exifTag) uint32 {
+	size := uint32(2 + 12*len(tags) + 4)
+	for _, t := range tags {
+		if len(t.data) > 4 {
+			size += uint32(len(t.data))
+		}
+	}
+	return size
+}
+
+func writeIFD(buf *bytes.Buffer, offset uint32, tags []exifTag) {
+	data := offset + uint32(2+12*len(tags)+4)
+	var extra []byte
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(tags)))
+	for _, t := range tags {
+		_ = binary.Write(buf, binary.BigEndian, t.tag)
+		_ = binary.Write(buf, binary.BigEndian, t.typ)
+		_ = binary.Write(buf, binary.BigEndian, t.count)
+		if len(t.data) > 4 {
+			_ = binary.Write(buf, binary.BigEndian, data+uint32(len(extra)))
+			extra = append(extra, t.data...)
+			continue
+		}
+		buf.Write(append(t.data, make([]byte, 4-len(t.data))...))
+	}
+	_ = binary.Write(buf, binary.BigEndian, uint32(0))
+	buf.Write(extra)
+}
+
+func jpegWithExif() []byte {
+	exif := []exifTag{
+		exifRational(0x829a, 1, 1000),
+		exifRational(0x829d, 18, 10),
+		exifShort(0x8827, 100),
+		exifASCII(0x9003, "2018:01:01 12:34:56"),
+		exifRational(0x920a, 50, 1),
+	}
+	gps := []exifTag{
+		exifASCII(0x0001, "N"),
+		exifRational(0x0002, 49, 1, 30, 1, 0, 1),
+		exifASCII(0x0003, "W"),
+		exifRational(0x0004, 11, 1, 15, 1, 0, 1),
+	}
+	ifd0 := []exifTag{
+		exifASCII(0x010f, "Canon"),
+		exifASCII(0x0110, "Canon EOS 5D"),
+		exifShort(0x0112, 6),
+		exifLong(0x8769, 0),
+		exifLong(0x8825, 0),
+	}
+	exifOffset := 8 + ifdSize(ifd0)
+	gpsOffset := exifOffset + ifdSize(exif)
+	ifd0[3] = exifLong(0x8769, exifOffset)
+	ifd0[4] = exifLong(0x8825, gpsOffset)
+
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00\x2a")
+	_ = binary.Write(&tiff, binary.BigEndian, uint32(8))
+	writeIFD(&tiff, 8, ifd0)
+	writeIFD(&tiff, exifOffset, exif)
+	writeIFD(&tiff, gpsOffset, gps)
+
+	var img bytes.Buffer
+	Expect(jpeg.Encode(&img, image.NewGray(image.Rect(0, 0, 4, 2)), nil)).To(Succeed())
+
+	app1 := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	out := append([]byte{}, img.Bytes()[:2]...)
+	out = append(out, 0xff, 0xe1)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(app1)+2))
+	out = append(out, app1...)
+	return append(out, img.Bytes()[2:]...)
+}
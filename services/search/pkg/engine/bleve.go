@@ -18,6 +18,7 @@ import (
 	"github.com/blevesearch/bleve/v2/analysis/tokenizer/single"
 	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
 	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
 	"github.com/blevesearch/bleve/v2/search/query"
 	storageProvider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
 	"github.com/opencloud-eu/reva/v2/pkg/errtypes"
@@ -41,7 +42,16 @@ type Bleve struct {
 // NewBleveIndex returns a new bleve index
 // given path must exist.
 func NewBleveIndex(root string) (bleve.Index, error) {
-	destination := filepath.Join(root, "bleve")
+	name, err := activeBleveIndexName(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return openBleveIndex(filepath.Join(root, name))
+}
+
+// openBleveIndex opens the bleve index at the given destination, the index is created if it does not exist.
+func openBleveIndex(destination string) (bleve.Index, error) {
 	index, err := bleve.Open(destination)
 	if errors.Is(bleve.ErrorIndexPathDoesNotExist, err) {
 		m, err := BuildBleveMapping()
@@ -121,6 +131,10 @@ func BuildBleveMapping() (mapping.IndexMapping, error) {
 // Search executes a search request operation within the index.
 // Returns a SearchIndexResponse object or an error.
 func (b *Bleve) Search(ctx context.Context, sir *searchService.SearchIndexRequest) (*searchService.SearchIndexResponse, error) {
+	if err := validateFacets(sir.Facets); err != nil {
+		return nil, err
+	}
+
 	createdQuery, err := b.queryCreator.Create(sir.Query)
 	if err != nil {
 		if searchQuery.IsValidationError(err) {
@@ -152,11 +166,27 @@ func (b *Bleve) Search(ctx context.Context, sir *searchService.SearchIndexReques
 				),
 			},
 		)
+
+		// restrict the matches to the requested path, so the facets only count those
+		if requestedPath := utils.MakeRelativePath(sir.Ref.Path); requestedPath != "." {
+			q.Conjuncts = append(
+				q.Conjuncts,
+				bleve.NewDisjunctionQuery(
+					&query.TermQuery{FieldVal: "Path", Term: requestedPath},
+					&query.PrefixQuery{FieldVal: "Path", Prefix: requestedPath + "/"},
+				),
+			)
+		}
 	}
 
 	bleveReq := bleve.NewSearchRequest(q)
 	bleveReq.Highlight = bleve.NewHighlight()
 
+	facetTime := time.Now()
+	for _, f := range sir.Facets {
+		bleveReq.AddFacet(f, bleveFacetRequest(f, facetTime))
+	}
+
 	switch {
 	case sir.PageSize == -1:
 		bleveReq.Size = math.MaxInt
@@ -227,12 +257,64 @@ func (b *Bleve) Search(ctx context.Context, sir *searchService.SearchIndexReques
 		matches = append(matches, match)
 	}
 
+	facets := make([]*searchMessage.Facet, 0, len(sir.Facets))
+	for _, f := range sir.Facets {
+		facets = append(facets, buildFacet(f, bleveFacetCounts(res.Facets[f]), facetTime))
+	}
+
 	return &searchService.SearchIndexResponse{
 		Matches:      matches,
 		TotalMatches: int32(totalMatches),
+		Facets:       facets,
 	}, nil
 }
 
+// bleveFacetRequest builds the bleve facet for the facet name
+func bleveFacetRequest(name string, t time.Time) *bleve.FacetRequest {
+	switch name {
+	case FacetMediaType:
+		return bleve.NewFacetRequest("MimeType", _facetMimeTypesSize)
+	case FacetMtime:
+		ranges := mtimeRanges(t)
+		fr := bleve.NewFacetRequest("Mtime", len(ranges))
+		for _, r := range ranges {
+			fr.AddDateTimeRange(r.name, *r.from, *r.to)
+		}
+		return fr
+	case FacetSize:
+		fr := bleve.NewFacetRequest("Size", len(_sizeRanges))
+		for _, r := range _sizeRanges {
+			fr.AddNumericRange(r.name, r.from, r.to)
+		}
+		return fr
+	case FacetSpace:
+		return bleve.NewFacetRequest("RootID", _facetTermsSize)
+	default:
+		return bleve.NewFacetRequest("Tags", _facetTermsSize)
+	}
+}
+
+// bleveFacetCounts returns the counts of a bleve facet result
+func bleveFacetCounts(fr *search.FacetResult) facetCounts {
+	counts := facetCounts{terms: map[string]int64{}}
+	if fr == nil {
+		return counts
+	}
+
+	counts.missing = int64(fr.Missing)
+	counts.other = int64(fr.Other)
+	for _, t := range fr.Terms.Terms() {
+		counts.terms[t.Term] = int64(t.Count)
+	}
+	for _, r := range fr.NumericRanges {
+		counts.terms[r.Name] = int64(r.Count)
+	}
+	for _, r := range fr.DateRanges {
+		counts.terms[r.Name] = int64(r.Count)
+	}
+	return counts
+}
+
 // Upsert indexes or stores Resource data fields.
 func (b *Bleve) Upsert(id string, r Resource) error {
 	return b.index.Index(id, r)
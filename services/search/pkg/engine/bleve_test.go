@@ -3,6 +3,7 @@ package engine_test
 import (
 	"context"
 	"fmt"
+	"time"
 
 	bleveSearch "github.com/blevesearch/bleve/v2"
 	sprovider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
@@ -354,6 +355,97 @@ var _ = Describe("Bleve", func() {
 			})
 		})
 
+		Context("with facets", func() {
+			BeforeEach(func() {
+				recent := time.Now().UTC().Format(time.RFC3339Nano)
+				old := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(time.RFC3339Nano)
+
+				for _, r := range []engine.Resource{
+					parentResource,
+					childResource,
+					{
+						ID: "1$2!5", ParentID: parentResource.ID, RootID: rootResource.ID, Path: "./parent d!r/notes.txt",
+						Type:     uint64(sprovider.ResourceType_RESOURCE_TYPE_FILE),
+						Document: content.Document{Name: "notes.txt", MimeType: "text/plain", Size: 2 << 20, Tags: []string{"foo", "bar"}, Mtime: recent},
+					},
+					{
+						ID: "1$2!6", ParentID: rootResource.ID, RootID: rootResource.ID, Path: "./photo.jpg",
+						Type:     uint64(sprovider.ResourceType_RESOURCE_TYPE_FILE),
+						Document: content.Document{Name: "photo.jpg", MimeType: "image/jpeg", Size: 10, Tags: []string{"foo"}, Mtime: old},
+					},
+				} {
+					if r.ID == childResource.ID {
+						r.MimeType = "application/pdf"
+						r.Size = 42
+						r.Mtime = recent
+					}
+					if r.ID == parentResource.ID {
+						r.MimeType = "httpd/unix-directory"
+					}
+					Expect(eng.Upsert(r.ID, r)).To(Succeed())
+				}
+			})
+
+			search := func(path string, facets ...string) *searchsvc.SearchIndexResponse {
+				res, err := eng.Search(context.Background(), &searchsvc.SearchIndexRequest{
+					Query: "*",
+					Ref: &searchmsg.Reference{
+						ResourceId: &searchmsg.ResourceID{StorageId: "1", SpaceId: "2", OpaqueId: "2"},
+						Path:       path,
+					},
+					Facets: facets,
+				})
+				ExpectWithOffset(1, err).ToNot(HaveOccurred())
+				return res
+			}
+
+			It("returns no facets if none are requested", func() {
+				Expect(search("").Facets).To(BeEmpty())
+			})
+
+			It("rejects unknown facets", func() {
+				_, err := eng.Search(context.Background(), &searchsvc.SearchIndexRequest{Query: "*", Facets: []string{"color"}})
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("groups the mime types", func() {
+				facets := search("", engine.FacetMediaType).Facets
+				Expect(facets).To(HaveLen(1))
+				Expect(facets[0].Name).To(Equal(engine.FacetMediaType))
+				Expect(facets[0].Buckets[0].Name).To(Equal("folder"))
+				Expect(facetCounts(facets[0])).To(Equal(map[string]int64{"folder": 1, "document": 1, "pdf": 1, "image": 1}))
+			})
+
+			It("counts the tags", func() {
+				facets := search("", engine.FacetTags).Facets
+				Expect(facets[0].Buckets[0].Name).To(Equal("foo"))
+				Expect(facetCounts(facets[0])).To(Equal(map[string]int64{"foo": 2, "bar": 1}))
+				Expect(facets[0].Missing).To(Equal(int64(2)))
+			})
+
+			It("counts the size and mtime ranges", func() {
+				facets := search("", engine.FacetSize, engine.FacetMtime).Facets
+				Expect(facets).To(HaveLen(2))
+				Expect(facetCounts(facets[0])).To(Equal(map[string]int64{"small": 3, "medium": 1, "large": 0, "huge": 0}))
+				Expect(facets[1].Buckets).To(HaveLen(6))
+				Expect(facets[1].Buckets[0].Name).To(Equal("today"))
+				Expect(facets[1].Buckets[0].Count).To(Equal(int64(2)))
+				Expect(facets[1].Buckets[0].From).ToNot(BeEmpty())
+				Expect(facetCounts(facets[1])["last 30 days"]).To(Equal(int64(2)))
+			})
+
+			It("counts the spaces", func() {
+				facets := search("", engine.FacetSpace).Facets
+				Expect(facetCounts(facets[0])).To(Equal(map[string]int64{rootResource.ID: 4}))
+			})
+
+			It("only counts the matches below the requested path", func() {
+				res := search("./parent d!r", engine.FacetMediaType)
+				Expect(res.TotalMatches).To(Equal(int32(3)))
+				Expect(facetCounts(res.Facets[0])).To(Equal(map[string]int64{"folder": 1, "document": 1, "pdf": 1}))
+			})
+		})
+
 	})
 
 	Describe("Upsert", func() {
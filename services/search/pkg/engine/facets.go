@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/now"
+	"github.com/opencloud-eu/reva/v2/pkg/errtypes"
+
+	searchMessage "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/search/v0"
+)
+
+// The facets which can be requested with a search request.
+// The names match the keys of the query language, so a bucket can be used to narrow down a search.
+const (
+	FacetMediaType = "mediatype"
+	FacetMtime     = "mtime"
+	FacetTags      = "tags"
+	FacetSize      = "size"
+	FacetSpace     = "space"
+)
+
+const (
+	// _facetTermsSize limits the number of buckets of the tags and space facets
+	_facetTermsSize = 20
+	// _facetMimeTypesSize limits the number of distinct mime types which are grouped into media types,
+	// the matches of all other mime types are counted as 'other'
+	_facetMimeTypesSize = 1000
+	// _mediaTypeOther is the media type of all mime types which are not part of a media type group
+	_mediaTypeOther = "other"
+)
+
+// _mediaTypes lists the media types in the order of the buckets, they match the values of the mediatype query
+var _mediaTypes = []string{"folder", "document", "spreadsheet", "presentation", "pdf", "image", "video", "audio", "archive", _mediaTypeOther}
+
+var _mediaTypeMimeTypes = map[string]string{
+	"httpd/unix-directory": "folder",
+
+	"application/msword": "document",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": "document",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.form":     "document",
+	"application/vnd.oasis.opendocument.text":                                 "document",
+	"text/plain":                  "document",
+	"text/markdown":               "document",
+	"application/rtf":             "document",
+	"application/vnd.apple.pages": "document",
+
+	"application/vnd.ms-excel":                       "spreadsheet",
+	"application/vnd.oasis.opendocument.spreadsheet": "spreadsheet",
+	"text/csv": "spreadsheet",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": "spreadsheet",
+	"application/vnd.apple.numbers":                                     "spreadsheet",
+
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": "presentation",
+	"application/vnd.oasis.opendocument.presentation":                           "presentation",
+	"application/vnd.ms-powerpoint":                                             "presentation",
+	"application/vnd.apple.keynote":                                             "presentation",
+
+	"application/pdf": "pdf",
+
+	"application/zip":              "archive",
+	"application/gzip":             "archive",
+	"application/x-gzip":           "archive",
+	"application/x-7z-compressed":  "archive",
+	"application/x-rar-compressed": "archive",
+	"application/x-tar":            "archive",
+	"application/x-bzip2":          "archive",
+	"application/x-bzip":           "archive",
+	"application/x-tgz":            "archive",
+}
+
+// MediaType returns the media type group of a mime type as used by the mediatype query
+func MediaType(mimeType string) string {
+	mimeType = strings.ToLower(mimeType)
+	if mt, ok := _mediaTypeMimeTypes[mimeType]; ok {
+		return mt
+	}
+
+	for _, prefix := range []string{"image", "video", "audio"} {
+		if strings.HasPrefix(mimeType, prefix+"/") {
+			return prefix
+		}
+	}
+
+	return _mediaTypeOther
+}
+
+// facetRange is a bucket of a range facet, open ends are nil
+type facetRange[T any] struct {
+	name     string
+	from, to *T
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// _sizeRanges are the buckets of the size facet in bytes
+var _sizeRanges = []facetRange[float64]{
+	{name: "small", to: ptr[float64](1 << 20)},
+	{name: "medium", from: ptr[float64](1 << 20), to: ptr[float64](100 << 20)},
+	{name: "large", from: ptr[float64](100 << 20), to: ptr[float64](1 << 30)},
+	{name: "huge", from: ptr[float64](1 << 30)},
+}
+
+// mtimeRanges returns the buckets of the mtime facet relative to t, the names match the date ranges of the query language
+func mtimeRanges(t time.Time) []facetRange[time.Time] {
+	n := (&now.Config{WeekStartDay: time.Monday}).With(t)
+	yesterday := n.With(n.AddDate(0, 0, -1))
+	lastYear := n.With(n.AddDate(-1, 0, 0))
+
+	return []facetRange[time.Time]{
+		{name: "today", from: ptr(n.BeginningOfDay()), to: ptr(n.EndOfDay())},
+		{name: "yesterday", from: ptr(yesterday.BeginningOfDay()), to: ptr(yesterday.EndOfDay())},
+		{name: "last 7 days", from: ptr(n.With(n.AddDate(0, 0, -6)).BeginningOfDay()), to: ptr(n.EndOfDay())},
+		{name: "last 30 days", from: ptr(n.With(n.AddDate(0, 0, -29)).BeginningOfDay()), to: ptr(n.EndOfDay())},
+		{name: "this year", from: ptr(n.BeginningOfYear()), to: ptr(n.EndOfYear())},
+		{name: "last year", from: ptr(lastYear.BeginningOfYear()), to: ptr(lastYear.EndOfYear())},
+	}
+}
+
+// validateFacets checks that only known facets are requested
+func validateFacets(facets []string) error {
+	for _, f := range facets {
+		switch f {
+		case FacetMediaType, FacetMtime, FacetTags, FacetSize, FacetSpace:
+		default:
+			return errtypes.BadRequest(fmt.Sprintf("unknown facet '%s'", f))
+		}
+	}
+	return nil
+}
+
+// facetCounts holds the counts of a facet as returned by an engine
+type facetCounts struct {
+	// terms holds the number of matches per term of the terms facets or per range name of the range facets
+	terms map[string]int64
+	// other is the number of matches with terms which are not part of terms
+	other   int64
+	missing int64
+}
+
+// buildFacet converts the counts of an engine into a facet, t must be the time used for the mtime ranges of the request
+func buildFacet(name string, counts facetCounts, t time.Time) *searchMessage.Facet {
+	facet := &searchMessage.Facet{Name: name, Missing: counts.missing}
+
+	switch name {
+	case FacetMediaType:
+		groups := make(map[string]int64, len(_mediaTypes))
+		for term, count := range counts.terms {
+			groups[MediaType(term)] += count
+		}
+		groups[_mediaTypeOther] += counts.other
+
+		for _, mt := range _mediaTypes {
+			if groups[mt] > 0 {
+				facet.Buckets = append(facet.Buckets, &searchMessage.FacetBucket{Name: mt, Count: groups[mt]})
+			}
+		}
+	case FacetMtime:
+		for _, r := range mtimeRanges(t) {
+			facet.Buckets = append(facet.Buckets, &searchMessage.FacetBucket{
+				Name:  r.name,
+				Count: counts.terms[r.name],
+				From:  r.from.Format(time.RFC3339),
+				To:    r.to.Format(time.RFC3339),
+			})
+		}
+	case FacetSize:
+		for _, r := range _sizeRanges {
+			b := &searchMessage.FacetBucket{Name: r.name, Count: counts.terms[r.name]}
+			if r.from != nil {
+				b.From = strconv.FormatFloat(*r.from, 'f', -1, 64)
+			}
+			if r.to != nil {
+				b.To = strconv.FormatFloat(*r.to, 'f', -1, 64)
+			}
+			facet.Buckets = append(facet.Buckets, b)
+		}
+	default:
+		for term, count := range counts.terms {
+			facet.Buckets = append(facet.Buckets, &searchMessage.FacetBucket{Name: term, Count: count})
+		}
+		facet.Buckets = sortBuckets(facet.Buckets)
+	}
+
+	return facet
+}
+
+// sortBuckets orders the buckets of terms facets by count and name and applies the size limit
+func sortBuckets(buckets []*searchMessage.FacetBucket) []*searchMessage.FacetBucket {
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count == buckets[j].Count {
+			return buckets[i].Name < buckets[j].Name
+		}
+		return buckets[i].Count > buckets[j].Count
+	})
+	if len(buckets) > _facetTermsSize {
+		buckets = buckets[:_facetTermsSize]
+	}
+	return buckets
+}
+
+// MergeFacets sums up the facets of several search responses, e.g. of the searches in different spaces.
+// The bucket order of the range facets is kept, the buckets of the terms facets are sorted by count.
+func MergeFacets(responses ...[]*searchMessage.Facet) []*searchMessage.Facet {
+	var merged []*searchMessage.Facet
+	for _, facets := range responses {
+		for _, f := range facets {
+			i := slices.IndexFunc(merged, func(m *searchMessage.Facet) bool { return m.Name == f.Name })
+			if i < 0 {
+				merged = append(merged, &searchMessage.Facet{Name: f.Name})
+				i = len(merged) - 1
+			}
+			m := merged[i]
+			m.Missing += f.Missing
+
+			for _, b := range f.Buckets {
+				j := slices.IndexFunc(m.Buckets, func(mb *searchMessage.FacetBucket) bool { return mb.Name == b.Name })
+				if j < 0 {
+					m.Buckets = append(m.Buckets, &searchMessage.FacetBucket{Name: b.Name, Label: b.Label, From: b.From, To: b.To})
+					j = len(m.Buckets) - 1
+				}
+				m.Buckets[j].Count += b.Count
+			}
+		}
+	}
+
+	for _, m := range merged {
+		switch m.Name {
+		case FacetTags, FacetSpace:
+			m.Buckets = sortBuckets(m.Buckets)
+		case FacetMediaType:
+			sort.SliceStable(m.Buckets, func(i, j int) bool {
+				return slices.Index(_mediaTypes, m.Buckets[i].Name) < slices.Index(_mediaTypes, m.Buckets[j].Name)
+			})
+		}
+	}
+
+	return merged
+}
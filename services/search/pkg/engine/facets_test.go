@@ -0,0 +1,75 @@
+package engine_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	searchmsg "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/search/v0"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/engine"
+)
+
+func facetCounts(f *searchmsg.Facet) map[string]int64 {
+	counts := map[string]int64{}
+	for _, b := range f.Buckets {
+		counts[b.Name] = b.Count
+	}
+	return counts
+}
+
+var _ = Describe("Facets", func() {
+	Describe("MediaType", func() {
+		DescribeTable("groups the mime types like the mediatype query",
+			func(mimeType, mediaType string) {
+				Expect(engine.MediaType(mimeType)).To(Equal(mediaType))
+			},
+			Entry("folder", "httpd/unix-directory", "folder"),
+			Entry("document", "application/vnd.oasis.opendocument.text", "document"),
+			Entry("spreadsheet", "text/csv", "spreadsheet"),
+			Entry("presentation", "application/vnd.ms-powerpoint", "presentation"),
+			Entry("pdf", "application/pdf", "pdf"),
+			Entry("image", "image/png", "image"),
+			Entry("video", "video/mp4", "video"),
+			Entry("audio", "Audio/Mpeg", "audio"),
+			Entry("archive", "application/zip", "archive"),
+			Entry("other", "application/octet-stream", "other"),
+		)
+	})
+
+	Describe("MergeFacets", func() {
+		It("sums up the buckets of the responses", func() {
+			merged := engine.MergeFacets(
+				[]*searchmsg.Facet{
+					{Name: "size", Buckets: []*searchmsg.FacetBucket{{Name: "small", Count: 1, To: "1048576"}, {Name: "medium", Count: 0}}},
+					{Name: "tags", Missing: 1, Buckets: []*searchmsg.FacetBucket{{Name: "foo", Count: 1}}},
+				},
+				nil,
+				[]*searchmsg.Facet{
+					{Name: "size", Buckets: []*searchmsg.FacetBucket{{Name: "small", Count: 2}, {Name: "medium", Count: 3}}},
+					{Name: "tags", Missing: 2, Buckets: []*searchmsg.FacetBucket{{Name: "bar", Count: 4}, {Name: "foo", Count: 1}}},
+					{Name: "mediatype", Buckets: []*searchmsg.FacetBucket{{Name: "pdf", Count: 1}}},
+				},
+				[]*searchmsg.Facet{
+					{Name: "mediatype", Buckets: []*searchmsg.FacetBucket{{Name: "image", Count: 1}, {Name: "folder", Count: 2}}},
+				},
+			)
+
+			Expect(merged).To(HaveLen(3))
+			Expect(merged[0].Name).To(Equal("size"))
+			Expect(merged[0].Buckets[0].Name).To(Equal("small"))
+			Expect(merged[0].Buckets[0].To).To(Equal("1048576"))
+			Expect(facetCounts(merged[0])).To(Equal(map[string]int64{"small": 3, "medium": 3}))
+
+			Expect(merged[1].Name).To(Equal("tags"))
+			Expect(merged[1].Missing).To(Equal(int64(3)))
+			Expect(merged[1].Buckets[0].Name).To(Equal("bar"))
+			Expect(facetCounts(merged[1])).To(Equal(map[string]int64{"foo": 2, "bar": 4}))
+
+			Expect(merged[2].Name).To(Equal("mediatype"))
+			names := []string{}
+			for _, b := range merged[2].Buckets {
+				names = append(names, b.Name)
+			}
+			Expect(names).To(Equal([]string{"folder", "pdf", "image"}))
+		})
+	})
+})
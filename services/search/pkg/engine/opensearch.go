@@ -0,0 +1,552 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	storageProvider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/opencloud-eu/reva/v2/pkg/errtypes"
+	"github.com/opencloud-eu/reva/v2/pkg/storagespace"
+	"github.com/opencloud-eu/reva/v2/pkg/utils"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	searchMessage "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/search/v0"
+	searchService "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/search/v0"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/config"
+	searchQuery "github.com/opencloud-eu/opencloud/services/search/pkg/query"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/query/opensearch"
+)
+
+// openSearchMaxResultWindow is the default value of the index.max_result_window index setting,
+// requests for more results than that are rejected by the cluster.
+const openSearchMaxResultWindow = 10000
+
+// OpenSearch represents a search engine which utilizes an OpenSearch (or Elasticsearch) cluster to search and store resources.
+type OpenSearch struct {
+	client       *http.Client
+	url          string
+	index        string
+	username     string
+	password     string
+	queryCreator searchQuery.Creator[opensearch.Query]
+}
+
+// NewOpenSearchEngine creates a new OpenSearch instance
+func NewOpenSearchEngine(cfg config.EngineOpenSearch, queryCreator searchQuery.Creator[opensearch.Query]) *OpenSearch {
+	return &OpenSearch{
+		client: &http.Client{
+			Timeout: cfg.RequestTimeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: cfg.Insecure, //nolint:gosec
+				},
+			},
+		},
+		url:          strings.TrimSuffix(cfg.URL, "/"),
+		index:        cfg.Index,
+		username:     cfg.Username,
+		password:     cfg.Password,
+		queryCreator: queryCreator,
+	}
+}
+
+// BuildOpenSearchMapping builds the index settings and mappings which are used when creating the index.
+// The field names and analyzers are kept in line with the bleve mapping.
+func BuildOpenSearchMapping() map[string]interface{} {
+	lowercaseKeyword := map[string]interface{}{"type": "keyword", "normalizer": "lowercase"}
+	keyword := map[string]interface{}{"type": "keyword"}
+
+	return map[string]interface{}{
+		"settings": map[string]interface{}{
+			"analysis": map[string]interface{}{
+				"normalizer": map[string]interface{}{
+					"lowercase": map[string]interface{}{
+						"type":   "custom",
+						"filter": []string{"lowercase"},
+					},
+				},
+				"analyzer": map[string]interface{}{
+					"fulltext": map[string]interface{}{
+						"type":      "custom",
+						"tokenizer": "standard",
+						"filter":    []string{"lowercase", "porter_stem"},
+					},
+				},
+			},
+		},
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"ID":       keyword,
+				"RootID":   keyword,
+				"ParentID": keyword,
+				"Path":     keyword,
+				"Name":     lowercaseKeyword,
+				"Tags":     lowercaseKeyword,
+				"MimeType": lowercaseKeyword,
+				"Title":    map[string]interface{}{"type": "text", "analyzer": "fulltext"},
+				"Content":  map[string]interface{}{"type": "text", "analyzer": "fulltext"},
+				"Mtime":    map[string]interface{}{"type": "date"},
+				"Size":     map[string]interface{}{"type": "long"},
+				"Type":     map[string]interface{}{"type": "long"},
+				"Deleted":  map[string]interface{}{"type": "boolean"},
+				"Hidden":   map[string]interface{}{"type": "boolean"},
+			},
+		},
+	}
+}
+
+// EnsureIndex creates the configured index with the required mapping if it does not exist yet.
+func (o *OpenSearch) EnsureIndex(ctx context.Context) error {
+	status, err := o.do(ctx, http.MethodHead, o.index, nil, nil)
+	switch {
+	case err == nil:
+		return nil
+	case status != http.StatusNotFound:
+		return err
+	}
+
+	_, err = o.do(ctx, http.MethodPut, o.index, BuildOpenSearchMapping(), nil)
+	return err
+}
+
+// Search executes a search request operation within the index.
+// Returns a SearchIndexResponse object or an error.
+func (o *OpenSearch) Search(ctx context.Context, sir *searchService.SearchIndexRequest) (*searchService.SearchIndexResponse, error) {
+	if err := validateFacets(sir.Facets); err != nil {
+		return nil, err
+	}
+
+	createdQuery, err := o.queryCreator.Create(sir.Query)
+	if err != nil {
+		if searchQuery.IsValidationError(err) {
+			return nil, errtypes.BadRequest(err.Error())
+		}
+		return nil, err
+	}
+
+	filter := []opensearch.Query{
+		// Skip documents that have been marked as deleted
+		{"term": opensearch.Query{"Deleted": false}},
+	}
+
+	if sir.Ref != nil {
+		filter = append(filter, opensearch.Query{"term": opensearch.Query{
+			"RootID": storagespace.FormatResourceID(
+				&storageProvider.ResourceId{
+					StorageId: sir.Ref.GetResourceId().GetStorageId(),
+					SpaceId:   sir.Ref.GetResourceId().GetSpaceId(),
+					OpaqueId:  sir.Ref.GetResourceId().GetOpaqueId(),
+				},
+			),
+		}})
+
+		// restrict the matches to the requested path, so the facets only count those
+		if requestedPath := utils.MakeRelativePath(sir.Ref.Path); requestedPath != "." {
+			filter = append(filter, opensearch.Query{"bool": opensearch.Query{
+				"should": []opensearch.Query{
+					{"term": opensearch.Query{"Path": requestedPath}},
+					{"prefix": opensearch.Query{"Path": requestedPath + "/"}},
+				},
+				"minimum_should_match": 1,
+			}})
+		}
+	}
+
+	size := int(sir.PageSize)
+	switch {
+	case sir.PageSize == -1:
+		size = openSearchMaxResultWindow
+	case sir.PageSize == 0:
+		size = 200
+	}
+
+	req := map[string]interface{}{
+		"query": opensearch.Query{"bool": opensearch.Query{
+			"filter": filter,
+			"must":   []opensearch.Query{createdQuery},
+		}},
+		"size":             size,
+		"track_total_hits": true,
+		"highlight": map[string]interface{}{
+			"fields": map[string]interface{}{"Content": map[string]interface{}{}},
+		},
+	}
+
+	facetTime := time.Now()
+	if len(sir.Facets) > 0 {
+		aggs := map[string]interface{}{}
+		for _, f := range sir.Facets {
+			field, agg := openSearchAggregation(f, facetTime)
+			aggs[f] = agg
+			aggs[f+"_missing"] = map[string]interface{}{"missing": map[string]interface{}{"field": field}}
+		}
+		req["aggs"] = aggs
+	}
+
+	res := openSearchSearchResponse{}
+	if _, err := o.do(ctx, http.MethodPost, o.index+"/_search", req, &res); err != nil {
+		return nil, err
+	}
+
+	matches := make([]*searchMessage.Match, 0, len(res.Hits.Hits))
+	totalMatches := res.Hits.Total.Value
+	for _, hit := range res.Hits.Hits {
+		fields := map[string]interface{}{}
+		flattenFields(fields, "", hit.Source)
+
+		if sir.Ref != nil {
+			hitPath := strings.TrimSuffix(getFieldValue[string](fields, "Path"), "/")
+			requestedPath := utils.MakeRelativePath(sir.Ref.Path)
+			isRoot := hitPath == requestedPath
+
+			if !isRoot && requestedPath != "." && !strings.HasPrefix(hitPath, requestedPath+"/") {
+				totalMatches--
+				continue
+			}
+		}
+
+		rootID, err := storagespace.ParseID(getFieldValue[string](fields, "RootID"))
+		if err != nil {
+			return nil, err
+		}
+
+		rID, err := storagespace.ParseID(getFieldValue[string](fields, "ID"))
+		if err != nil {
+			return nil, err
+		}
+
+		var highlights string
+		if len(hit.Highlight["Content"]) > 0 {
+			highlights = hit.Highlight["Content"][0]
+		}
+
+		pID, _ := storagespace.ParseID(getFieldValue[string](fields, "ParentID"))
+		match := &searchMessage.Match{
+			Score: float32(hit.Score),
+			Entity: &searchMessage.Entity{
+				Ref: &searchMessage.Reference{
+					ResourceId: resourceIDtoSearchID(rootID),
+					Path:       getFieldValue[string](fields, "Path"),
+				},
+				Id:         resourceIDtoSearchID(rID),
+				Name:       getFieldValue[string](fields, "Name"),
+				ParentId:   resourceIDtoSearchID(pID),
+				Size:       uint64(getFieldValue[float64](fields, "Size")),
+				Type:       uint64(getFieldValue[float64](fields, "Type")),
+				MimeType:   getFieldValue[string](fields, "MimeType"),
+				Deleted:    getFieldValue[bool](fields, "Deleted"),
+				Tags:       getFieldSliceValue[string](fields, "Tags"),
+				Highlights: highlights,
+				Audio:      getAudioValue[searchMessage.Audio](fields),
+				Image:      getImageValue[searchMessage.Image](fields),
+				Location:   getLocationValue[searchMessage.GeoCoordinates](fields),
+				Photo:      getPhotoValue[searchMessage.Photo](fields),
+			},
+		}
+
+		if mtime, err := time.Parse(time.RFC3339, getFieldValue[string](fields, "Mtime")); err == nil {
+			match.Entity.LastModifiedTime = &timestamppb.Timestamp{Seconds: mtime.Unix(), Nanos: int32(mtime.Nanosecond())}
+		}
+
+		matches = append(matches, match)
+	}
+
+	facets := make([]*searchMessage.Facet, 0, len(sir.Facets))
+	for _, f := range sir.Facets {
+		counts := facetCounts{
+			terms:   map[string]int64{},
+			other:   res.Aggregations[f].SumOtherDocCount,
+			missing: res.Aggregations[f+"_missing"].DocCount,
+		}
+		for _, b := range res.Aggregations[f].Buckets {
+			counts.terms[b.Key] = b.DocCount
+		}
+		facets = append(facets, buildFacet(f, counts, facetTime))
+	}
+
+	return &searchService.SearchIndexResponse{
+		Matches:      matches,
+		TotalMatches: int32(totalMatches),
+		Facets:       facets,
+	}, nil
+}
+
+// openSearchAggregation builds the aggregation for the facet name and returns it together with the aggregated field
+func openSearchAggregation(name string, t time.Time) (string, map[string]interface{}) {
+	switch name {
+	case FacetMediaType:
+		return "MimeType", map[string]interface{}{"terms": map[string]interface{}{"field": "MimeType", "size": _facetMimeTypesSize}}
+	case FacetMtime:
+		var ranges []map[string]interface{}
+		for _, r := range mtimeRanges(t) {
+			ranges = append(ranges, map[string]interface{}{"key": r.name, "from": r.from.Format(time.RFC3339Nano), "to": r.to.Format(time.RFC3339Nano)})
+		}
+		return "Mtime", map[string]interface{}{"date_range": map[string]interface{}{"field": "Mtime", "ranges": ranges}}
+	case FacetSize:
+		var ranges []map[string]interface{}
+		for _, r := range _sizeRanges {
+			rng := map[string]interface{}{"key": r.name}
+			if r.from != nil {
+				rng["from"] = *r.from
+			}
+			if r.to != nil {
+				rng["to"] = *r.to
+			}
+			ranges = append(ranges, rng)
+		}
+		return "Size", map[string]interface{}{"range": map[string]interface{}{"field": "Size", "ranges": ranges}}
+	case FacetSpace:
+		return "RootID", map[string]interface{}{"terms": map[string]interface{}{"field": "RootID", "size": _facetTermsSize}}
+	default:
+		return "Tags", map[string]interface{}{"terms": map[string]interface{}{"field": "Tags", "size": _facetTermsSize}}
+	}
+}
+
+// Upsert indexes or stores Resource data fields.
+func (o *OpenSearch) Upsert(id string, r Resource) error {
+	doc, err := openSearchDocument(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = o.do(context.Background(), http.MethodPut, o.index+"/_doc/"+url.PathEscape(id)+"?refresh=wait_for", doc, nil)
+	return err
+}
+
+// openSearchDocument converts the resource to the indexed document,
+// an empty Mtime is left out because it can't be indexed as a date.
+func openSearchDocument(r Resource) (map[string]interface{}, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	if r.Mtime == "" {
+		delete(doc, "Mtime")
+	}
+
+	return doc, nil
+}
+
+// Move updates the resource location and all of its necessary fields.
+func (o *OpenSearch) Move(id string, parentid string, target string) error {
+	r, err := o.getResource(id)
+	if err != nil {
+		return err
+	}
+	currentPath := r.Path
+	nextPath := utils.MakeRelativePath(target)
+
+	r.Path = nextPath
+	r.Name = path.Base(nextPath)
+	r.ParentID = parentid
+	if err := o.Upsert(id, *r); err != nil {
+		return err
+	}
+
+	if r.Type == uint64(storageProvider.ResourceType_RESOURCE_TYPE_CONTAINER) {
+		return o.updateDescendants(r.RootID, currentPath, map[string]interface{}{
+			"source": "ctx._source.Path = params.next + ctx._source.Path.substring(params.current.length())",
+			"lang":   "painless",
+			"params": map[string]interface{}{"current": currentPath, "next": nextPath},
+		})
+	}
+
+	return nil
+}
+
+// Delete marks the resource as deleted.
+// The resource object will stay in the index,
+// instead of removing the resource it just marks it as deleted!
+// can be undone
+func (o *OpenSearch) Delete(id string) error {
+	return o.setDeleted(id, true)
+}
+
+// Restore is the counterpart to Delete.
+// It restores the resource which makes it available again.
+func (o *OpenSearch) Restore(id string) error {
+	return o.setDeleted(id, false)
+}
+
+// Purge removes a resource from the index, irreversible operation.
+func (o *OpenSearch) Purge(id string) error {
+	_, err := o.do(context.Background(), http.MethodDelete, o.index+"/_doc/"+url.PathEscape(id)+"?refresh=wait_for", nil, nil)
+	return err
+}
+
+// DocCount returns the number of resources in the index.
+func (o *OpenSearch) DocCount() (uint64, error) {
+	res := struct {
+		Count uint64 `json:"count"`
+	}{}
+	if _, err := o.do(context.Background(), http.MethodGet, o.index+"/_count", nil, &res); err != nil {
+		return 0, err
+	}
+
+	return res.Count, nil
+}
+
+func (o *OpenSearch) getResource(id string) (*Resource, error) {
+	res := struct {
+		Found  bool     `json:"found"`
+		Source Resource `json:"_source"`
+	}{}
+	status, err := o.do(context.Background(), http.MethodGet, o.index+"/_doc/"+url.PathEscape(id), nil, &res)
+	if status == http.StatusNotFound || (err == nil && !res.Found) {
+		return nil, errors.New("entity not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Source, nil
+}
+
+func (o *OpenSearch) setDeleted(id string, deleted bool) error {
+	r, err := o.getResource(id)
+	if err != nil {
+		return err
+	}
+
+	r.Deleted = deleted
+	if err := o.Upsert(id, *r); err != nil {
+		return err
+	}
+
+	if r.Type == uint64(storageProvider.ResourceType_RESOURCE_TYPE_CONTAINER) {
+		return o.updateDescendants(r.RootID, r.Path, map[string]interface{}{
+			"source": "ctx._source.Deleted = params.deleted",
+			"lang":   "painless",
+			"params": map[string]interface{}{"deleted": deleted},
+		})
+	}
+
+	return nil
+}
+
+// updateDescendants applies the given script to all resources below the given path.
+func (o *OpenSearch) updateDescendants(rootID, parentPath string, script map[string]interface{}) error {
+	req := map[string]interface{}{
+		"query": opensearch.Query{"bool": opensearch.Query{
+			"filter": []opensearch.Query{
+				{"term": opensearch.Query{"RootID": rootID}},
+				{"prefix": opensearch.Query{"Path": parentPath + "/"}},
+			},
+		}},
+		"script": script,
+	}
+
+	_, err := o.do(context.Background(), http.MethodPost, o.index+"/_update_by_query?refresh=true&conflicts=proceed", req, nil)
+	return err
+}
+
+// do sends a request to the cluster and decodes the response into out if given,
+// the returned status code is always set if the cluster answered.
+func (o *OpenSearch) do(ctx context.Context, method, endpoint string, body interface{}, out interface{}) (int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, o.url+"/"+endpoint, reqBody)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if o.username != "" {
+		req.SetBasicAuth(o.username, o.password)
+	}
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, newOpenSearchError(res)
+	}
+
+	if out == nil || method == http.MethodHead {
+		return res.StatusCode, nil
+	}
+
+	return res.StatusCode, json.NewDecoder(res.Body).Decode(out)
+}
+
+type openSearchSearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID        string                 `json:"_id"`
+			Score     float64                `json:"_score"`
+			Source    map[string]interface{} `json:"_source"`
+			Highlight map[string][]string    `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations map[string]struct {
+		DocCount         int64 `json:"doc_count"`
+		SumOtherDocCount int64 `json:"sum_other_doc_count"`
+		Buckets          []struct {
+			Key      string `json:"key"`
+			DocCount int64  `json:"doc_count"`
+		} `json:"buckets"`
+	} `json:"aggregations"`
+}
+
+func newOpenSearchError(res *http.Response) error {
+	e := struct {
+		Error struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	}{}
+	// HEAD responses and some proxies do not carry a json body
+	_ = json.NewDecoder(res.Body).Decode(&e)
+
+	if e.Error.Reason == "" {
+		return fmt.Errorf("opensearch: unexpected status %d", res.StatusCode)
+	}
+
+	if res.StatusCode == http.StatusBadRequest {
+		return errtypes.BadRequest(e.Error.Reason)
+	}
+
+	return fmt.Errorf("opensearch: unexpected status %d: %s: %s", res.StatusCode, e.Error.Type, e.Error.Reason)
+}
+
+// flattenFields converts the nested document source into the flat dotted representation
+// bleve uses for stored fields, this allows to share the field getters.
+func flattenFields(out map[string]interface{}, prefix string, in map[string]interface{}) {
+	for k, v := range in {
+		if nested, ok := v.(map[string]interface{}); ok {
+			flattenFields(out, prefix+k+".", nested)
+			continue
+		}
+		out[prefix+k] = v
+	}
+}
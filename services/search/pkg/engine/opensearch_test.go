@@ -0,0 +1,375 @@
+package engine_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	sprovider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	searchmsg "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/search/v0"
+	searchsvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/search/v0"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/content"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/engine"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/query/opensearch"
+)
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]interface{}
+}
+
+var _ = Describe("OpenSearch", func() {
+	var (
+		eng       *engine.OpenSearch
+		server    *httptest.Server
+		mu        sync.Mutex
+		requests  []recordedRequest
+		responses map[string]func(w http.ResponseWriter)
+
+		lastRequest = func() recordedRequest {
+			mu.Lock()
+			defer mu.Unlock()
+			return requests[len(requests)-1]
+		}
+
+		childResource engine.Resource
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		responses = map[string]func(w http.ResponseWriter){}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rr := recordedRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery}
+			if b, _ := io.ReadAll(r.Body); len(b) > 0 {
+				Expect(json.Unmarshal(b, &rr.Body)).To(Succeed())
+			}
+
+			mu.Lock()
+			requests = append(requests, rr)
+			mu.Unlock()
+
+			if respond, ok := responses[r.Method+" "+r.URL.Path]; ok {
+				respond(w)
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		}))
+
+		eng = engine.NewOpenSearchEngine(config.EngineOpenSearch{
+			URL:   server.URL,
+			Index: "opencloud",
+		}, opensearch.DefaultCreator)
+
+		childResource = engine.Resource{
+			ID:       "1$2!4",
+			ParentID: "1$2!3",
+			RootID:   "1$2!2",
+			Path:     "./parent d!r/child.pdf",
+			Type:     uint64(sprovider.ResourceType_RESOURCE_TYPE_FILE),
+			Document: content.Document{
+				Name:     "child.pdf",
+				MimeType: "application/pdf",
+				Tags:     []string{"foo", "bar"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("EnsureIndex", func() {
+		It("creates the index with the mapping if it does not exist", func() {
+			responses["HEAD /opencloud"] = func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusNotFound)
+			}
+
+			Expect(eng.EnsureIndex(context.Background())).To(Succeed())
+
+			r := lastRequest()
+			Expect(r.Method).To(Equal(http.MethodPut))
+			Expect(r.Path).To(Equal("/opencloud"))
+			Expect(r.Body).To(HaveKey("mappings"))
+		})
+
+		It("keeps an existing index", func() {
+			Expect(eng.EnsureIndex(context.Background())).To(Succeed())
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal(http.MethodHead))
+		})
+	})
+
+	Describe("Search", func() {
+		BeforeEach(func() {
+			responses["POST /opencloud/_search"] = func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte(`{
+					"hits": {
+						"total": {"value": 1},
+						"hits": [{
+							"_id": "1$2!4",
+							"_score": 1.5,
+							"_source": {
+								"ID": "1$2!4",
+								"RootID": "1$2!2",
+								"ParentID": "1$2!3",
+								"Path": "./parent d!r/child.pdf",
+								"Name": "child.pdf",
+								"MimeType": "application/pdf",
+								"Size": 42,
+								"Tags": ["foo", "bar"],
+								"Mtime": "2023-09-05T08:42:11Z",
+								"Deleted": false
+							},
+							"highlight": {"Content": ["some <em>content</em>"]}
+						}]
+					}
+				}`))
+			}
+		})
+
+		It("translates the kql query and converts the hits", func() {
+			res, err := eng.Search(context.Background(), &searchsvc.SearchIndexRequest{
+				Query: `name:"child.pdf" tag:foo`,
+				Ref: &searchmsg.Reference{
+					ResourceId: &searchmsg.ResourceID{StorageId: "1", SpaceId: "2", OpaqueId: "2"},
+					Path:       "./parent d!r",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.TotalMatches).To(Equal(int32(1)))
+			Expect(res.Matches).To(HaveLen(1))
+			Expect(res.Matches[0].Entity.Name).To(Equal("child.pdf"))
+			Expect(res.Matches[0].Entity.Size).To(Equal(uint64(42)))
+			Expect(res.Matches[0].Entity.Tags).To(Equal([]string{"foo", "bar"}))
+			Expect(res.Matches[0].Entity.Highlights).To(Equal("some <em>content</em>"))
+			Expect(res.Matches[0].Entity.LastModifiedTime.AsTime().Unix()).To(Equal(int64(1693903331)))
+
+			r := lastRequest()
+			query, err := json.Marshal(r.Body["query"])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(query)).To(MatchJSON(`{"bool": {
+				"filter": [
+					{"term": {"Deleted": false}},
+					{"term": {"RootID": "1$2!2"}},
+					{"bool": {
+						"should": [
+							{"term": {"Path": "./parent d!r"}},
+							{"prefix": {"Path": "./parent d!r/"}}
+						],
+						"minimum_should_match": 1
+					}}
+				],
+				"must": [
+					{"bool": {"must": [
+						{"term": {"Name": "child.pdf"}},
+						{"term": {"Tags": "foo"}}
+					]}}
+				]
+			}}`))
+			Expect(r.Body["size"]).To(Equal(float64(200)))
+		})
+
+		It("filters hits outside of the requested path", func() {
+			res, err := eng.Search(context.Background(), &searchsvc.SearchIndexRequest{
+				Query: `child.pdf`,
+				Ref: &searchmsg.Reference{
+					ResourceId: &searchmsg.ResourceID{StorageId: "1", SpaceId: "2", OpaqueId: "2"},
+					Path:       "./other",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.TotalMatches).To(Equal(int32(0)))
+			Expect(res.Matches).To(BeEmpty())
+		})
+
+		It("rejects invalid queries", func() {
+			_, err := eng.Search(context.Background(), &searchsvc.SearchIndexRequest{
+				Query: `AND child.pdf`,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(requests).To(BeEmpty())
+		})
+
+		It("rejects unknown facets", func() {
+			_, err := eng.Search(context.Background(), &searchsvc.SearchIndexRequest{
+				Query:  `child.pdf`,
+				Facets: []string{"color"},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(requests).To(BeEmpty())
+		})
+
+		It("aggregates the requested facets", func() {
+			responses["POST /opencloud/_search"] = func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte(`{
+					"hits": {"total": {"value": 5}, "hits": []},
+					"aggregations": {
+						"mediatype": {"sum_other_doc_count": 1, "buckets": [
+							{"key": "application/pdf", "doc_count": 2},
+							{"key": "text/plain", "doc_count": 1},
+							{"key": "application/vnd.oasis.opendocument.text", "doc_count": 1}
+						]},
+						"mediatype_missing": {"doc_count": 0},
+						"size": {"buckets": [
+							{"key": "small", "doc_count": 4},
+							{"key": "medium", "doc_count": 1},
+							{"key": "large", "doc_count": 0},
+							{"key": "huge", "doc_count": 0}
+						]},
+						"size_missing": {"doc_count": 0},
+						"tags": {"sum_other_doc_count": 0, "buckets": [
+							{"key": "foo", "doc_count": 1},
+							{"key": "bar", "doc_count": 3}
+						]},
+						"tags_missing": {"doc_count": 2}
+					}
+				}`))
+			}
+
+			res, err := eng.Search(context.Background(), &searchsvc.SearchIndexRequest{
+				Query:  `child.pdf`,
+				Facets: []string{"mediatype", "size", "tags"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			aggs, err := json.Marshal(lastRequest().Body["aggs"])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(aggs)).To(MatchJSON(`{
+				"mediatype": {"terms": {"field": "MimeType", "size": 1000}},
+				"mediatype_missing": {"missing": {"field": "MimeType"}},
+				"size": {"range": {"field": "Size", "ranges": [
+					{"key": "small", "to": 1048576},
+					{"key": "medium", "from": 1048576, "to": 104857600},
+					{"key": "large", "from": 104857600, "to": 1073741824},
+					{"key": "huge", "from": 1073741824}
+				]}},
+				"size_missing": {"missing": {"field": "Size"}},
+				"tags": {"terms": {"field": "Tags", "size": 20}},
+				"tags_missing": {"missing": {"field": "Tags"}}
+			}`))
+
+			Expect(res.Facets).To(HaveLen(3))
+			Expect(res.Facets[0].Name).To(Equal("mediatype"))
+			Expect(facetCounts(res.Facets[0])).To(Equal(map[string]int64{"document": 2, "pdf": 2, "other": 1}))
+			Expect(res.Facets[0].Buckets[0].Name).To(Equal("document"))
+			Expect(res.Facets[1].Name).To(Equal("size"))
+			Expect(facetCounts(res.Facets[1])).To(Equal(map[string]int64{"small": 4, "medium": 1, "large": 0, "huge": 0}))
+			Expect(res.Facets[1].Buckets[1].From).To(Equal("1048576"))
+			Expect(res.Facets[1].Buckets[1].To).To(Equal("104857600"))
+			Expect(res.Facets[2].Name).To(Equal("tags"))
+			Expect(res.Facets[2].Missing).To(Equal(int64(2)))
+			Expect(res.Facets[2].Buckets[0].Name).To(Equal("bar"))
+			Expect(facetCounts(res.Facets[2])).To(Equal(map[string]int64{"foo": 1, "bar": 3}))
+		})
+	})
+
+	Describe("Upsert", func() {
+		It("stores the resource document", func() {
+			Expect(eng.Upsert(childResource.ID, childResource)).To(Succeed())
+
+			r := lastRequest()
+			Expect(r.Method).To(Equal(http.MethodPut))
+			Expect(r.Path).To(Equal("/opencloud/_doc/1$2!4"))
+			Expect(r.Body["Name"]).To(Equal("child.pdf"))
+			Expect(r.Body["RootID"]).To(Equal("1$2!2"))
+			Expect(r.Body).ToNot(HaveKey("Mtime"))
+		})
+
+		It("stores the modification time if it is known", func() {
+			childResource.Mtime = "2023-09-05T08:42:11Z"
+			Expect(eng.Upsert(childResource.ID, childResource)).To(Succeed())
+
+			Expect(lastRequest().Body["Mtime"]).To(Equal("2023-09-05T08:42:11Z"))
+		})
+	})
+
+	Describe("Move", func() {
+		It("updates the resource and all of its descendants", func() {
+			parent := engine.Resource{
+				ID:       "1$2!3",
+				ParentID: "1$2!2",
+				RootID:   "1$2!2",
+				Path:     "./parent d!r",
+				Type:     uint64(sprovider.ResourceType_RESOURCE_TYPE_CONTAINER),
+				Document: content.Document{Name: "parent d!r"},
+			}
+			responses["GET /opencloud/_doc/1$2!3"] = func(w http.ResponseWriter) {
+				b, _ := json.Marshal(map[string]interface{}{"found": true, "_source": parent})
+				_, _ = w.Write(b)
+			}
+
+			Expect(eng.Move(parent.ID, "1$2!2", "./new parent")).To(Succeed())
+
+			Expect(requests).To(HaveLen(3))
+			Expect(requests[1].Method).To(Equal(http.MethodPut))
+			Expect(requests[1].Body["Path"]).To(Equal("./new parent"))
+			Expect(requests[1].Body["Name"]).To(Equal("new parent"))
+
+			Expect(requests[2].Path).To(Equal("/opencloud/_update_by_query"))
+			Expect(requests[2].Body["script"]).To(HaveKeyWithValue("params", map[string]interface{}{
+				"current": "./parent d!r",
+				"next":    "./new parent",
+			}))
+		})
+
+		It("fails for unknown resources", func() {
+			responses["GET /opencloud/_doc/1$2!3"] = func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"found": false}`))
+			}
+
+			Expect(eng.Move("1$2!3", "1$2!2", "./new parent")).ToNot(Succeed())
+		})
+	})
+
+	Describe("Delete and Restore", func() {
+		BeforeEach(func() {
+			responses["GET /opencloud/_doc/1$2!4"] = func(w http.ResponseWriter) {
+				b, _ := json.Marshal(map[string]interface{}{"found": true, "_source": childResource})
+				_, _ = w.Write(b)
+			}
+		})
+
+		It("marks the resource as deleted", func() {
+			Expect(eng.Delete(childResource.ID)).To(Succeed())
+			Expect(lastRequest().Body["Deleted"]).To(BeTrue())
+		})
+
+		It("restores the resource", func() {
+			Expect(eng.Restore(childResource.ID)).To(Succeed())
+			Expect(lastRequest().Body["Deleted"]).To(BeFalse())
+		})
+	})
+
+	Describe("Purge", func() {
+		It("removes the document", func() {
+			Expect(eng.Purge(childResource.ID)).To(Succeed())
+
+			r := lastRequest()
+			Expect(r.Method).To(Equal(http.MethodDelete))
+			Expect(r.Path).To(Equal("/opencloud/_doc/1$2!4"))
+		})
+	})
+
+	Describe("DocCount", func() {
+		It("returns the number of documents", func() {
+			responses["GET /opencloud/_count"] = func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte(`{"count": 7}`))
+			}
+
+			count, err := eng.DocCount()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(uint64(7)))
+		})
+	})
+})
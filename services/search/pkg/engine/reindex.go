@@ -0,0 +1,261 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/search/query"
+
+	searchService "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/search/v0"
+	searchQuery "github.com/opencloud-eu/opencloud/services/search/pkg/query"
+)
+
+const (
+	// _bleveIndexName is the name of the index directory used before any reindex happened
+	_bleveIndexName = "bleve"
+	// _bleveCurrentFile is the file in the data path which points to the active index directory
+	_bleveCurrentFile = "current"
+)
+
+var (
+	// ErrReindexRunning is returned if a reindex is started while another one is still running
+	ErrReindexRunning = errors.New("a reindex is already running")
+	// ErrNoReindexRunning is returned if a reindex should be finished or aborted but none is running
+	ErrNoReindexRunning = errors.New("no reindex is running")
+)
+
+// Reindexer is implemented by engines which are able to build a new index in the background
+// while the current one keeps serving requests.
+type Reindexer interface {
+	// StartReindex creates a new empty index and returns an engine to fill it,
+	// changes to the active index are applied to the new one as well from now on.
+	StartReindex() (Engine, error)
+	// FinishReindex atomically replaces the active index with the new one.
+	FinishReindex() error
+	// AbortReindex drops the new index.
+	AbortReindex() error
+}
+
+// BlueGreenBleve is a bleve engine which is able to build a new index next to the active one
+// and to swap them once the new index is complete.
+type BlueGreenBleve struct {
+	root         string
+	queryCreator searchQuery.Creator[query.Query]
+
+	mu         sync.RWMutex
+	active     *Bleve
+	activeName string
+	next       *Bleve
+	nextName   string
+}
+
+// NewBlueGreenBleveEngine opens the active bleve index in the given root and returns a BlueGreenBleve engine.
+// Leftovers of interrupted reindex runs are removed.
+func NewBlueGreenBleveEngine(root string, queryCreator searchQuery.Creator[query.Query]) (*BlueGreenBleve, error) {
+	name, err := activeBleveIndexName(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := removeStaleBleveIndexes(root, name); err != nil {
+		return nil, err
+	}
+
+	index, err := openBleveIndex(filepath.Join(root, name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlueGreenBleve{
+		root:         root,
+		queryCreator: queryCreator,
+		active:       NewBleveEngine(index, queryCreator),
+		activeName:   name,
+	}, nil
+}
+
+// Search executes a search request operation within the active index.
+func (b *BlueGreenBleve) Search(ctx context.Context, sir *searchService.SearchIndexRequest) (*searchService.SearchIndexResponse, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.active.Search(ctx, sir)
+}
+
+// Upsert indexes or stores Resource data fields.
+func (b *BlueGreenBleve) Upsert(id string, r Resource) error {
+	return b.write(func(e *Bleve) error { return e.Upsert(id, r) })
+}
+
+// Move updates the resource location and all of its necessary fields.
+func (b *BlueGreenBleve) Move(id string, parentid string, target string) error {
+	return b.write(func(e *Bleve) error { return e.Move(id, parentid, target) })
+}
+
+// Delete marks the resource as deleted.
+func (b *BlueGreenBleve) Delete(id string) error {
+	return b.write(func(e *Bleve) error { return e.Delete(id) })
+}
+
+// Restore is the counterpart to Delete.
+func (b *BlueGreenBleve) Restore(id string) error {
+	return b.write(func(e *Bleve) error { return e.Restore(id) })
+}
+
+// Purge removes a resource from the index, irreversible operation.
+func (b *BlueGreenBleve) Purge(id string) error {
+	return b.write(func(e *Bleve) error { return e.Purge(id) })
+}
+
+// DocCount returns the number of resources in the active index.
+func (b *BlueGreenBleve) DocCount() (uint64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.active.DocCount()
+}
+
+// write applies the change to the active index and to the new index if a reindex is running.
+// Errors of the new index are ignored, the resource might simply not be indexed there yet.
+func (b *BlueGreenBleve) write(f func(e *Bleve) error) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := f(b.active); err != nil {
+		return err
+	}
+	if b.next != nil {
+		_ = f(b.next)
+	}
+	return nil
+}
+
+// StartReindex implements the Reindexer interface
+func (b *BlueGreenBleve) StartReindex() (Engine, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.next != nil {
+		return nil, ErrReindexRunning
+	}
+
+	name := _bleveIndexName + "-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	index, err := openBleveIndex(filepath.Join(b.root, name))
+	if err != nil {
+		return nil, err
+	}
+
+	b.next = NewBleveEngine(index, b.queryCreator)
+	b.nextName = name
+	return b.next, nil
+}
+
+// FinishReindex implements the Reindexer interface
+func (b *BlueGreenBleve) FinishReindex() error {
+	b.mu.Lock()
+	if b.next == nil {
+		b.mu.Unlock()
+		return ErrNoReindexRunning
+	}
+
+	if err := writeActiveBleveIndexName(b.root, b.nextName); err != nil {
+		// the new index can't be activated, drop it so a new reindex can be started
+		next, nextName := b.next, b.nextName
+		b.next, b.nextName = nil, ""
+		b.mu.Unlock()
+		return errors.Join(err, dropBleveIndex(next, filepath.Join(b.root, nextName)))
+	}
+
+	old, oldName := b.active, b.activeName
+	b.active, b.activeName = b.next, b.nextName
+	b.next, b.nextName = nil, ""
+	b.mu.Unlock()
+
+	return dropBleveIndex(old, filepath.Join(b.root, oldName))
+}
+
+// AbortReindex implements the Reindexer interface
+func (b *BlueGreenBleve) AbortReindex() error {
+	b.mu.Lock()
+	if b.next == nil {
+		b.mu.Unlock()
+		return ErrNoReindexRunning
+	}
+
+	next, nextName := b.next, b.nextName
+	b.next, b.nextName = nil, ""
+	b.mu.Unlock()
+
+	return dropBleveIndex(next, filepath.Join(b.root, nextName))
+}
+
+// Close closes the active index and drops the new index of an unfinished reindex.
+func (b *BlueGreenBleve) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.next != nil {
+		_ = dropBleveIndex(b.next, filepath.Join(b.root, b.nextName))
+		b.next, b.nextName = nil, ""
+	}
+	return b.active.index.Close()
+}
+
+// activeBleveIndexName returns the name of the active index directory in the given root
+func activeBleveIndexName(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, _bleveCurrentFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return _bleveIndexName, nil
+	case err != nil:
+		return "", err
+	}
+
+	name := strings.TrimSpace(string(data))
+	if name == "" || name != filepath.Base(name) {
+		return "", errors.New("invalid bleve index name in " + filepath.Join(root, _bleveCurrentFile))
+	}
+	return name, nil
+}
+
+// writeActiveBleveIndexName atomically points the root to the given index directory
+func writeActiveBleveIndexName(root, name string) error {
+	tmp := filepath.Join(root, _bleveCurrentFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(name), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(root, _bleveCurrentFile))
+}
+
+// removeStaleBleveIndexes removes all index directories created by a reindex except the active one
+func removeStaleBleveIndexes(root, active string) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == active || !strings.HasPrefix(e.Name(), _bleveIndexName+"-") {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(root, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropBleveIndex closes the index and removes its directory
+func dropBleveIndex(b *Bleve, destination string) error {
+	if err := b.index.Close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(destination)
+}
@@ -0,0 +1,137 @@
+package engine_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	searchsvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/search/v0"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/content"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/engine"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/query/bleve"
+)
+
+var _ = Describe("BlueGreenBleve", func() {
+	var (
+		root string
+		eng  *engine.BlueGreenBleve
+
+		resource = func(id, name string) engine.Resource {
+			return engine.Resource{
+				ID:       id,
+				RootID:   "1$2!2",
+				Path:     "./" + name,
+				Document: content.Document{Name: name},
+			}
+		}
+
+		count = func(e engine.Engine, query string) int {
+			res, err := e.Search(context.Background(), &searchsvc.SearchIndexRequest{Query: query})
+			ExpectWithOffset(1, err).ToNot(HaveOccurred())
+			return len(res.Matches)
+		}
+	)
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+
+		var err error
+		eng, err = engine.NewBlueGreenBleveEngine(root, bleve.DefaultCreator)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() { _ = eng.Close() })
+
+		Expect(eng.Upsert("1$2!3", resource("1$2!3", "old.txt"))).To(Succeed())
+	})
+
+	It("keeps serving the active index while a reindex is running", func() {
+		next, err := eng.StartReindex()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(next.Upsert("1$2!4", resource("1$2!4", "new.txt"))).To(Succeed())
+		Expect(count(eng, "name:new.txt")).To(Equal(0))
+		Expect(count(eng, "name:old.txt")).To(Equal(1))
+	})
+
+	It("applies changes to both indexes while a reindex is running", func() {
+		next, err := eng.StartReindex()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(eng.Upsert("1$2!5", resource("1$2!5", "live.txt"))).To(Succeed())
+		Expect(count(eng, "name:live.txt")).To(Equal(1))
+		Expect(count(next, "name:live.txt")).To(Equal(1))
+	})
+
+	It("swaps to the new index when the reindex is finished", func() {
+		next, err := eng.StartReindex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next.Upsert("1$2!4", resource("1$2!4", "new.txt"))).To(Succeed())
+
+		Expect(eng.FinishReindex()).To(Succeed())
+		Expect(count(eng, "name:new.txt")).To(Equal(1))
+		Expect(count(eng, "name:old.txt")).To(Equal(0))
+
+		_, err = os.Stat(filepath.Join(root, "bleve"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("reopens the swapped index", func() {
+		next, err := eng.StartReindex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next.Upsert("1$2!4", resource("1$2!4", "new.txt"))).To(Succeed())
+		Expect(eng.FinishReindex()).To(Succeed())
+		Expect(eng.Close()).To(Succeed())
+
+		eng, err = engine.NewBlueGreenBleveEngine(root, bleve.DefaultCreator)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(count(eng, "name:new.txt")).To(Equal(1))
+	})
+
+	It("drops the new index when the reindex is aborted", func() {
+		next, err := eng.StartReindex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next.Upsert("1$2!4", resource("1$2!4", "new.txt"))).To(Succeed())
+
+		Expect(eng.AbortReindex()).To(Succeed())
+		Expect(count(eng, "name:old.txt")).To(Equal(1))
+
+		entries, err := os.ReadDir(root)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Name()).To(Equal("bleve"))
+	})
+
+	It("drops the new index if it can't be activated", func() {
+		next, err := eng.StartReindex()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(next.Upsert("1$2!4", resource("1$2!4", "new.txt"))).To(Succeed())
+
+		// the pointer to the active index can't be written
+		Expect(os.Mkdir(filepath.Join(root, "current.tmp"), 0700)).To(Succeed())
+		Expect(eng.FinishReindex()).ToNot(Succeed())
+		Expect(count(eng, "name:old.txt")).To(Equal(1))
+		Expect(eng.AbortReindex()).To(MatchError(engine.ErrNoReindexRunning))
+
+		dirs, err := filepath.Glob(filepath.Join(root, "bleve-*"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dirs).To(BeEmpty())
+
+		_, err = eng.StartReindex()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("allows only one reindex at a time", func() {
+		_, err := eng.StartReindex()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = eng.StartReindex()
+		Expect(err).To(MatchError(engine.ErrReindexRunning))
+	})
+
+	It("fails to finish without a running reindex", func() {
+		Expect(eng.FinishReindex()).To(MatchError(engine.ErrNoReindexRunning))
+		Expect(eng.AbortReindex()).To(MatchError(engine.ErrNoReindexRunning))
+	})
+})
@@ -0,0 +1,27 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	apiUser "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	provider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+)
+
+// SavedSearchMatched is emitted when a newly indexed resource matches a saved search with an enabled alert
+type SavedSearchMatched struct {
+	UserID       *apiUser.UserId
+	SearchID     string
+	SearchName   string
+	Query        string
+	ResourceID   *provider.ResourceId
+	ResourceName string
+	Timestamp    time.Time
+}
+
+// Unmarshal to fulfill umarshaller interface
+func (SavedSearchMatched) Unmarshal(v []byte) (interface{}, error) {
+	e := SavedSearchMatched{}
+	err := json.Unmarshal(v, &e)
+	return e, err
+}
@@ -14,6 +14,15 @@ var (
 type Metrics struct {
 	// Counter  *prometheus.CounterVec
 	BuildInfo *prometheus.GaugeVec
+
+	// ReindexRunning is 1 while a reindex is running
+	ReindexRunning prometheus.Gauge
+	// ReindexSpacesTotal is the number of spaces of the current reindex
+	ReindexSpacesTotal prometheus.Gauge
+	// ReindexSpacesDone is the number of spaces which have been reindexed
+	ReindexSpacesDone prometheus.Gauge
+	// ReindexResources is the number of resources in the new index
+	ReindexResources prometheus.Gauge
 }
 
 // New initializes the available metrics.
@@ -25,9 +34,37 @@ func New() *Metrics {
 			Name:      "build_info",
 			Help:      "Build information",
 		}, []string{"version"}),
+		ReindexRunning: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "reindex_running",
+			Help:      "Whether a reindex is running",
+		}),
+		ReindexSpacesTotal: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "reindex_spaces_total",
+			Help:      "Number of spaces to reindex",
+		}),
+		ReindexSpacesDone: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "reindex_spaces_done",
+			Help:      "Number of reindexed spaces",
+		}),
+		ReindexResources: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "reindex_resources",
+			Help:      "Number of resources in the new index",
+		}),
 	}
 
 	_ = prometheus.Register(m.BuildInfo)
+	_ = prometheus.Register(m.ReindexRunning)
+	_ = prometheus.Register(m.ReindexSpacesTotal)
+	_ = prometheus.Register(m.ReindexSpacesDone)
+	_ = prometheus.Register(m.ReindexResources)
 	// TODO: implement metrics
 	return m
 }
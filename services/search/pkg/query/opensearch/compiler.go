@@ -0,0 +1,289 @@
+package opensearch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/ast"
+	"github.com/opencloud-eu/opencloud/pkg/kql"
+)
+
+var _fields = map[string]string{
+	"rootid":    "RootID",
+	"path":      "Path",
+	"id":        "ID",
+	"name":      "Name",
+	"size":      "Size",
+	"mtime":     "Mtime",
+	"mediatype": "MimeType",
+	"type":      "Type",
+	"tag":       "Tags",
+	"tags":      "Tags",
+	"content":   "Content",
+	"hidden":    "Hidden",
+}
+
+// _rangeOperators maps the KQL comparison operators to the OpenSearch range query parameters,
+// the order matters, longer operators must be checked first.
+var _rangeOperators = []struct {
+	operator  string
+	parameter string
+}{
+	{">=", "gte"},
+	{"<=", "lte"},
+	{">", "gt"},
+	{"<", "lt"},
+}
+
+// Compiler represents a KQL query search string to the OpenSearch query DSL formatter.
+type Compiler struct{}
+
+// Compile implements the query formatter which converts the KQL query search string to the OpenSearch query DSL.
+func (c Compiler) Compile(givenAst *ast.Ast) (Query, error) {
+	q, err := compile(givenAst)
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func compile(a *ast.Ast) (Query, error) {
+	q, err := walk(a.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := q["bool"]; ok {
+		return q, nil
+	}
+	return boolQuery("must", q), nil
+}
+
+// walk compiles a flat list of nodes, the AND operator binds stronger than the OR operator,
+// groups are compiled recursively and can be used to change the precedence.
+func walk(nodes []ast.Node) (Query, error) {
+	var disjuncts, conjuncts []Query
+	var negate bool
+	for _, node := range nodes {
+		var q Query
+		switch n := node.(type) {
+		case *ast.OperatorNode:
+			switch n.Value {
+			case kql.BoolNOT:
+				negate = !negate
+			case kql.BoolOR:
+				if len(conjuncts) > 0 {
+					disjuncts = append(disjuncts, conjunction(conjuncts))
+				}
+				conjuncts = nil
+			}
+			continue
+		case *ast.StringNode:
+			q = stringQuery(getField(n.Key), n.Value)
+		case *ast.DateTimeNode:
+			var err error
+			q, err = dateTimeQuery(getField(n.Key), n)
+			if err != nil {
+				return nil, err
+			}
+		case *ast.BooleanNode:
+			q = Query{"term": Query{getField(n.Key): n.Value}}
+		case *ast.GroupNode:
+			if n.Key != "" {
+				n = normalizeGroupingProperty(n)
+			}
+			var err error
+			q, err = walk(n.Nodes)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if q == nil {
+			continue
+		}
+
+		if negate {
+			q = boolQuery("must_not", q)
+			negate = false
+		}
+
+		conjuncts = append(conjuncts, q)
+	}
+
+	if len(conjuncts) > 0 {
+		disjuncts = append(disjuncts, conjunction(conjuncts))
+	}
+
+	switch len(disjuncts) {
+	case 0:
+		return nil, fmt.Errorf("can not compile the query")
+	case 1:
+		return disjuncts[0], nil
+	default:
+		return disjunction(disjuncts), nil
+	}
+}
+
+func conjunction(queries []Query) Query {
+	if len(queries) == 1 {
+		return queries[0]
+	}
+	return boolQuery("must", queries...)
+}
+
+func disjunction(queries []Query) Query {
+	q := boolQuery("should", queries...)
+	q["bool"].(Query)["minimum_should_match"] = 1
+	return q
+}
+
+func boolQuery(occur string, queries ...Query) Query {
+	return Query{"bool": Query{occur: queries}}
+}
+
+func stringQuery(k, v string) Query {
+	switch k {
+	case "MimeType":
+		return mimeType(k, v)
+	case "Content":
+		return Query{"match": Query{k: Query{"query": v, "operator": "and"}}}
+	case "Size":
+		for _, ro := range _rangeOperators {
+			if !strings.HasPrefix(v, ro.operator) {
+				continue
+			}
+			if size, err := strconv.ParseUint(strings.TrimPrefix(v, ro.operator), 10, 64); err == nil {
+				return Query{"range": Query{k: Query{ro.parameter: size}}}
+			}
+		}
+		return Query{"term": Query{k: v}}
+	case "Hidden":
+		return Query{"term": Query{k: v}}
+	}
+
+	if strings.ContainsAny(v, "*?") {
+		return Query{"wildcard": Query{k: Query{"value": v, "case_insensitive": true}}}
+	}
+
+	return Query{"term": Query{k: v}}
+}
+
+func dateTimeQuery(k string, n *ast.DateTimeNode) (Query, error) {
+	if n.Operator == nil {
+		return nil, fmt.Errorf("the date time restriction of '%s' has no operator", k)
+	}
+
+	v := n.Value.Format(time.RFC3339Nano)
+	switch n.Operator.Value {
+	case ">":
+		return Query{"range": Query{k: Query{"gt": v}}}, nil
+	case ">=":
+		return Query{"range": Query{k: Query{"gte": v}}}, nil
+	case "<":
+		return Query{"range": Query{k: Query{"lt": v}}}, nil
+	case "<=":
+		return Query{"range": Query{k: Query{"lte": v}}}, nil
+	case ":", "=":
+		from, to := dateTimeUnit(n.Value)
+		return Query{"range": Query{k: Query{
+			"gte": from.Format(time.RFC3339Nano),
+			"lt":  to.Format(time.RFC3339Nano),
+		}}}, nil
+	default:
+		return nil, fmt.Errorf("the date time restriction of '%s' has the unsupported operator '%s'", k, n.Operator.Value)
+	}
+}
+
+// dateTimeUnit returns the range covered by the given time, it depends on the
+// precision of the time. A date without a time covers the whole day.
+func dateTimeUnit(t time.Time) (time.Time, time.Time) {
+	switch ns := t.Nanosecond(); {
+	case t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && ns == 0:
+		return t, t.AddDate(0, 0, 1)
+	case ns == 0:
+		return t, t.Add(time.Second)
+	case ns%int(time.Millisecond) == 0:
+		return t, t.Add(time.Millisecond)
+	case ns%int(time.Microsecond) == 0:
+		return t, t.Add(time.Microsecond)
+	default:
+		return t, t.Add(time.Nanosecond)
+	}
+}
+
+func getField(name string) string {
+	if name == "" {
+		return "Name"
+	}
+	if _, ok := _fields[strings.ToLower(name)]; ok {
+		return _fields[strings.ToLower(name)]
+	}
+	return name
+}
+
+func normalizeGroupingProperty(group *ast.GroupNode) *ast.GroupNode {
+	for _, n := range group.Nodes {
+		if onode, ok := n.(*ast.StringNode); ok {
+			onode.Key = group.Key
+		}
+	}
+	return group
+}
+
+func mimeType(k, v string) Query {
+	switch strings.ToLower(v) {
+	case "file":
+		return boolQuery("must_not", Query{"term": Query{k: "httpd/unix-directory"}})
+	case "folder":
+		return Query{"term": Query{k: "httpd/unix-directory"}}
+	case "document":
+		return Query{"terms": Query{k: []string{
+			"application/msword",
+			"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+			"application/vnd.openxmlformats-officedocument.wordprocessingml.form",
+			"application/vnd.oasis.opendocument.text",
+			"text/plain",
+			"text/markdown",
+			"application/rtf",
+			"application/vnd.apple.pages",
+		}}}
+	case "spreadsheet":
+		return Query{"terms": Query{k: []string{
+			"application/vnd.ms-excel",
+			"application/vnd.oasis.opendocument.spreadsheet",
+			"text/csv",
+			"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			"application/vnd.apple.numbers",
+		}}}
+	case "presentation":
+		return Query{"terms": Query{k: []string{
+			"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+			"application/vnd.oasis.opendocument.presentation",
+			"application/vnd.ms-powerpoint",
+			"application/vnd.apple.keynote",
+		}}}
+	case "pdf":
+		return Query{"term": Query{k: "application/pdf"}}
+	case "image", "video", "audio":
+		return Query{"prefix": Query{k: strings.ToLower(v) + "/"}}
+	case "archive":
+		return Query{"terms": Query{k: []string{
+			"application/zip",
+			"application/gzip",
+			"application/x-gzip",
+			"application/x-7z-compressed",
+			"application/x-rar-compressed",
+			"application/x-tar",
+			"application/x-bzip2",
+			"application/x-bzip",
+			"application/x-tgz",
+		}}}
+	default:
+		if strings.ContainsAny(v, "*?") {
+			return Query{"wildcard": Query{k: Query{"value": v, "case_insensitive": true}}}
+		}
+		return Query{"term": Query{k: v}}
+	}
+}
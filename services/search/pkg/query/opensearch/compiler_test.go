@@ -0,0 +1,273 @@
+package opensearch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/ast"
+	tAssert "github.com/stretchr/testify/assert"
+)
+
+func Test_compile(t *testing.T) {
+	mtime := time.Date(2023, 9, 5, 8, 42, 11, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		args    *ast.Ast
+		want    Query
+		wantErr bool
+	}{
+		{
+			name: `federated`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.StringNode{Value: "federated"},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"term": Query{"Name": "federated"}},
+			}}},
+		},
+		{
+			name: `name:*fed*`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.StringNode{Key: "name", Value: "*fed*"},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"wildcard": Query{"Name": Query{"value": "*fed*", "case_insensitive": true}}},
+			}}},
+		},
+		{
+			name: `"John Smith" Jane`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.StringNode{Key: "name", Value: "John Smith"},
+					&ast.OperatorNode{Value: "AND"},
+					&ast.StringNode{Key: "name", Value: "Jane"},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"term": Query{"Name": "John Smith"}},
+				{"term": Query{"Name": "Jane"}},
+			}}},
+		},
+		{
+			name: `a OR b AND c`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.StringNode{Value: "a"},
+					&ast.OperatorNode{Value: "OR"},
+					&ast.StringNode{Value: "b"},
+					&ast.OperatorNode{Value: "AND"},
+					&ast.StringNode{Value: "c"},
+				},
+			},
+			want: Query{"bool": Query{
+				"should": []Query{
+					{"term": Query{"Name": "a"}},
+					{"bool": Query{"must": []Query{
+						{"term": Query{"Name": "b"}},
+						{"term": Query{"Name": "c"}},
+					}}},
+				},
+				"minimum_should_match": 1,
+			}},
+		},
+		{
+			name: `(a OR b) AND c`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.GroupNode{Nodes: []ast.Node{
+						&ast.StringNode{Value: "a"},
+						&ast.OperatorNode{Value: "OR"},
+						&ast.StringNode{Value: "b"},
+					}},
+					&ast.OperatorNode{Value: "AND"},
+					&ast.StringNode{Value: "c"},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"bool": Query{
+					"should": []Query{
+						{"term": Query{"Name": "a"}},
+						{"term": Query{"Name": "b"}},
+					},
+					"minimum_should_match": 1,
+				}},
+				{"term": Query{"Name": "c"}},
+			}}},
+		},
+		{
+			name: `a AND NOT b`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.StringNode{Value: "a"},
+					&ast.OperatorNode{Value: "AND"},
+					&ast.OperatorNode{Value: "NOT"},
+					&ast.StringNode{Value: "b"},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"term": Query{"Name": "a"}},
+				{"bool": Query{"must_not": []Query{
+					{"term": Query{"Name": "b"}},
+				}}},
+			}}},
+		},
+		{
+			name: `tag:(foo bar)`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.GroupNode{Key: "tag", Nodes: []ast.Node{
+						&ast.StringNode{Value: "foo"},
+						&ast.OperatorNode{Value: "AND"},
+						&ast.StringNode{Value: "bar"},
+					}},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"term": Query{"Tags": "foo"}},
+				{"term": Query{"Tags": "bar"}},
+			}}},
+		},
+		{
+			name: `content:"some words"`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.StringNode{Key: "content", Value: "some words"},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"match": Query{"Content": Query{"query": "some words", "operator": "and"}}},
+			}}},
+		},
+		{
+			name: `size:>=1024`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.StringNode{Key: "size", Value: ">=1024"},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"range": Query{"Size": Query{"gte": uint64(1024)}}},
+			}}},
+		},
+		{
+			name: `mtime>=2023-09-05T08:42:11Z`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.DateTimeNode{Key: "mtime", Operator: &ast.OperatorNode{Value: ">="}, Value: mtime},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"range": Query{"Mtime": Query{"gte": "2023-09-05T08:42:11Z"}}},
+			}}},
+		},
+		{
+			name: `mtime:2023-09-05T08:42:11Z`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.DateTimeNode{Key: "mtime", Operator: &ast.OperatorNode{Value: ":"}, Value: mtime},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"range": Query{"Mtime": Query{"gte": "2023-09-05T08:42:11Z", "lt": "2023-09-05T08:42:12Z"}}},
+			}}},
+		},
+		{
+			name: `mtime:2023-09-05`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.DateTimeNode{Key: "mtime", Operator: &ast.OperatorNode{Value: ":"}, Value: time.Date(2023, 9, 5, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"range": Query{"Mtime": Query{"gte": "2023-09-05T00:00:00Z", "lt": "2023-09-06T00:00:00Z"}}},
+			}}},
+		},
+		{
+			name: `mtime=2023-09-05T08:42:11.5Z`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.DateTimeNode{Key: "mtime", Operator: &ast.OperatorNode{Value: "="}, Value: mtime.Add(500 * time.Millisecond)},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"range": Query{"Mtime": Query{"gte": "2023-09-05T08:42:11.5Z", "lt": "2023-09-05T08:42:11.501Z"}}},
+			}}},
+		},
+		{
+			name: `mtime without operator`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.DateTimeNode{Key: "mtime", Value: mtime},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: `mtime with unsupported operator`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.DateTimeNode{Key: "mtime", Operator: &ast.OperatorNode{Value: "AND"}, Value: mtime},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: `hidden:true`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.BooleanNode{Key: "hidden", Value: true},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"term": Query{"Hidden": true}},
+			}}},
+		},
+		{
+			name: `mediatype:image`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.StringNode{Key: "mediatype", Value: "image"},
+				},
+			},
+			want: Query{"bool": Query{"must": []Query{
+				{"prefix": Query{"MimeType": "image/"}},
+			}}},
+		},
+		{
+			name: `mediatype:file`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{
+					&ast.StringNode{Key: "mediatype", Value: "file"},
+				},
+			},
+			want: Query{"bool": Query{"must_not": []Query{
+				{"term": Query{"MimeType": "httpd/unix-directory"}},
+			}}},
+		},
+		{
+			name: `empty`,
+			args: &ast.Ast{
+				Nodes: []ast.Node{},
+			},
+			wantErr: true,
+		},
+	}
+
+	assert := tAssert.New(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compiler{}.Compile(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(tt.want, got)
+		})
+	}
+}
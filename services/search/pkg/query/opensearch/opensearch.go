@@ -0,0 +1,36 @@
+// Package opensearch provides the ability to work with OpenSearch (and Elasticsearch) query DSL queries.
+package opensearch
+
+import (
+	"github.com/opencloud-eu/opencloud/pkg/kql"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/query"
+)
+
+// Query represents a single OpenSearch query DSL clause,
+// it gets serialized as is into the query part of a search request.
+type Query map[string]interface{}
+
+// Creator is combines a Builder and a Compiler which is used to Create the query.
+type Creator[T any] struct {
+	builder  query.Builder
+	compiler query.Compiler[T]
+}
+
+// Create implements the Creator interface
+func (c Creator[T]) Create(qs string) (T, error) {
+	var t T
+	builderAst, err := c.builder.Build(qs)
+	if err != nil {
+		return t, err
+	}
+
+	t, err = c.compiler.Compile(builderAst)
+	if err != nil {
+		return t, err
+	}
+
+	return t, nil
+}
+
+// DefaultCreator exposes a kql to OpenSearch query creator.
+var DefaultCreator = Creator[Query]{kql.Builder{}, Compiler{}}
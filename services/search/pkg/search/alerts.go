@@ -0,0 +1,188 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	user "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	provider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/jellydator/ttlcache/v2"
+	"github.com/opencloud-eu/reva/v2/pkg/events"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
+	"github.com/opencloud-eu/reva/v2/pkg/storagespace"
+	"github.com/opencloud-eu/reva/v2/pkg/utils"
+	micrometadata "go-micro.dev/v4/metadata"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/pkg/middleware"
+	searchsvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/search/v0"
+	settingssvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/settings/v0"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/engine"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/event"
+	"github.com/opencloud-eu/opencloud/services/settings/pkg/store/defaults"
+)
+
+// _savedSearchesTTL is the time the saved searches of a user are cached
+const _savedSearchesTTL = time.Minute
+
+// SavedSearch is a search query stored in the settings of a user
+type SavedSearch struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	Alert bool   `json:"alert"`
+}
+
+// Alerter notifies users when a resource starts to match one of their saved searches
+type Alerter interface {
+	// Watch remembers which saved searches match the resource before it gets indexed,
+	// the returned function has to be called after indexing and notifies about the new matches
+	Watch(ctx context.Context, info *provider.ResourceInfo) func()
+}
+
+// SavedSearchAlerter is the Alerter which reads the saved searches from the settings service
+// and publishes SavedSearchMatched events, which are turned into notifications by the userlog service.
+type SavedSearchAlerter struct {
+	logger          log.Logger
+	gatewaySelector pool.Selectable[gateway.GatewayAPIClient]
+	engine          engine.Engine
+	valueService    settingssvc.ValueService
+	publisher       events.Publisher
+	savedSearches   *ttlcache.Cache
+}
+
+// NewSavedSearchAlerter creates a new SavedSearchAlerter instance.
+func NewSavedSearchAlerter(gatewaySelector pool.Selectable[gateway.GatewayAPIClient], eng engine.Engine, valueService settingssvc.ValueService, publisher events.Publisher, logger log.Logger) (*SavedSearchAlerter, error) {
+	cache := ttlcache.NewCache()
+	if err := cache.SetTTL(_savedSearchesTTL); err != nil {
+		return nil, err
+	}
+	cache.SkipTTLExtensionOnHit(true)
+
+	return &SavedSearchAlerter{
+		logger:          logger,
+		gatewaySelector: gatewaySelector,
+		engine:          eng,
+		valueService:    valueService,
+		publisher:       publisher,
+		savedSearches:   cache,
+	}, nil
+}
+
+// alertingSearch is a saved search with an enabled alert of a user
+type alertingSearch struct {
+	userID string
+	SavedSearch
+}
+
+// Watch implements the Alerter interface
+func (a *SavedSearchAlerter) Watch(ctx context.Context, info *provider.ResourceInfo) func() {
+	noop := func() {}
+
+	gatewayClient, err := a.gatewaySelector.Next()
+	if err != nil {
+		a.logger.Error().Err(err).Msg("could not get reva gatewayClient")
+		return noop
+	}
+
+	spaceID := storagespace.FormatStorageID(info.GetId().GetStorageId(), info.GetId().GetSpaceId())
+	members, err := utils.GetSpaceMembers(ctx, spaceID, gatewayClient, utils.ViewerRole)
+	if err != nil {
+		a.logger.Debug().Err(err).Str("spaceID", spaceID).Msg("could not get space members for saved search alerts")
+		return noop
+	}
+
+	var searches []alertingSearch
+	for _, userID := range members {
+		for _, s := range a.userSearches(ctx, userID) {
+			if s.Alert && s.Query != "" {
+				searches = append(searches, alertingSearch{userID: userID, SavedSearch: s})
+			}
+		}
+	}
+	if len(searches) == 0 {
+		return noop
+	}
+
+	id := storagespace.FormatResourceID(info.GetId())
+	matchedBefore := make([]bool, len(searches))
+	for i, s := range searches {
+		matchedBefore[i] = a.matches(ctx, id, s.Query)
+	}
+
+	return func() {
+		for i, s := range searches {
+			if matchedBefore[i] || !a.matches(ctx, id, s.Query) {
+				continue
+			}
+
+			if err := events.Publish(ctx, a.publisher, event.SavedSearchMatched{
+				UserID:       &user.UserId{OpaqueId: s.userID},
+				SearchID:     s.ID,
+				SearchName:   s.Name,
+				Query:        s.Query,
+				ResourceID:   info.GetId(),
+				ResourceName: info.GetName(),
+				Timestamp:    time.Now(),
+			}); err != nil {
+				a.logger.Error().Err(err).Str("userID", s.userID).Str("searchID", s.ID).Msg("could not publish saved search alert")
+			}
+		}
+	}
+}
+
+// matches checks if the indexed resource matches the query
+func (a *SavedSearchAlerter) matches(ctx context.Context, id, query string) bool {
+	query, _ = ParseScope(query)
+	res, err := a.engine.Search(ctx, &searchsvc.SearchIndexRequest{
+		Query:    "id:" + id + " AND (" + query + ")",
+		PageSize: 1,
+	})
+	if err != nil {
+		a.logger.Debug().Err(err).Str("query", query).Msg("could not evaluate saved search")
+		return false
+	}
+	return len(res.GetMatches()) > 0
+}
+
+// userSearches returns the saved searches of a user
+func (a *SavedSearchAlerter) userSearches(ctx context.Context, userID string) []SavedSearch {
+	if cached, err := a.savedSearches.Get(userID); err == nil {
+		return cached.([]SavedSearch)
+	}
+
+	searches, err := GetSavedSearches(ctx, a.valueService, userID)
+	if err != nil {
+		a.logger.Debug().Err(err).Str("userID", userID).Msg("could not get saved searches")
+	}
+
+	_ = a.savedSearches.Set(userID, searches)
+	return searches
+}
+
+// GetSavedSearches reads the saved searches of a user from the settings service
+func GetSavedSearches(ctx context.Context, vs settingssvc.ValueService, userID string) ([]SavedSearch, error) {
+	resp, err := vs.GetValueByUniqueIdentifiers(
+		micrometadata.Set(ctx, middleware.AccountID, userID),
+		&settingssvc.GetValueByUniqueIdentifiersRequest{
+			AccountUuid: userID,
+			SettingId:   defaults.SettingUUIDProfileSavedSearches,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := resp.GetValue().GetValue().GetStringValue()
+	if raw == "" {
+		return nil, nil
+	}
+
+	var searches []SavedSearch
+	if err := json.Unmarshal([]byte(raw), &searches); err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
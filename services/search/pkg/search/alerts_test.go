@@ -0,0 +1,134 @@
+package search_test
+
+import (
+	"context"
+
+	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
+	userv1beta1 "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	sprovider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/status"
+	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
+	cs3mocks "github.com/opencloud-eu/reva/v2/tests/cs3mocks/mocks"
+	"github.com/stretchr/testify/mock"
+	microevents "go-micro.dev/v4/events"
+	"google.golang.org/grpc"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	searchmsg "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/search/v0"
+	settingsmsg "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/settings/v0"
+	searchsvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/search/v0"
+	settingssvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/settings/v0"
+	settingsMocks "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/settings/v0/mocks"
+	engineMocks "github.com/opencloud-eu/opencloud/services/search/pkg/engine/mocks"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/event"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/search"
+)
+
+type publisher struct {
+	published []interface{}
+}
+
+func (p *publisher) Publish(_ string, msg interface{}, _ ...microevents.PublishOption) error {
+	p.published = append(p.published, msg)
+	return nil
+}
+
+var _ = Describe("SavedSearchAlerter", func() {
+	var (
+		alerter       *search.SavedSearchAlerter
+		gatewayClient *cs3mocks.GatewayAPIClient
+		indexClient   *engineMocks.Engine
+		valueService  *settingsMocks.ValueService
+		pub           *publisher
+
+		ri = &sprovider.ResourceInfo{
+			Id:   &sprovider.ResourceId{StorageId: "storageid", SpaceId: "spaceid", OpaqueId: "opaqueid"},
+			Name: "invoice.pdf",
+		}
+	)
+
+	savedSearches := func(v string) {
+		valueService.On("GetValueByUniqueIdentifiers", mock.Anything, mock.MatchedBy(func(req *settingssvc.GetValueByUniqueIdentifiersRequest) bool {
+			return req.AccountUuid == "user"
+		})).Return(&settingssvc.GetValueResponse{
+			Value: &settingsmsg.ValueWithIdentifier{
+				Value: &settingsmsg.Value{Value: &settingsmsg.Value_StringValue{StringValue: v}},
+			},
+		}, nil)
+	}
+
+	matches := func(before, after bool) {
+		res := func(match bool) *searchsvc.SearchIndexResponse {
+			if !match {
+				return &searchsvc.SearchIndexResponse{}
+			}
+			return &searchsvc.SearchIndexResponse{Matches: []*searchmsg.Match{{}}}
+		}
+		indexClient.On("Search", mock.Anything, mock.Anything).Return(res(before), nil).Once()
+		indexClient.On("Search", mock.Anything, mock.Anything).Return(res(after), nil).Once()
+	}
+
+	BeforeEach(func() {
+		pool.RemoveSelector("GatewaySelector" + "eu.opencloud.api.gateway")
+		gatewayClient = &cs3mocks.GatewayAPIClient{}
+		gatewaySelector := pool.GetSelector[gateway.GatewayAPIClient](
+			"GatewaySelector",
+			"eu.opencloud.api.gateway",
+			func(cc grpc.ClientConnInterface) gateway.GatewayAPIClient {
+				return gatewayClient
+			},
+		)
+		indexClient = &engineMocks.Engine{}
+		valueService = &settingsMocks.ValueService{}
+		pub = &publisher{}
+
+		var err error
+		alerter, err = search.NewSavedSearchAlerter(gatewaySelector, indexClient, valueService, pub, log.NewLogger())
+		Expect(err).ToNot(HaveOccurred())
+
+		gatewayClient.On("ListStorageSpaces", mock.Anything, mock.Anything).Return(&sprovider.ListStorageSpacesResponse{
+			Status: status.NewOK(context.Background()),
+			StorageSpaces: []*sprovider.StorageSpace{{
+				SpaceType: "personal",
+				Owner:     &userv1beta1.User{Id: &userv1beta1.UserId{OpaqueId: "user"}},
+			}},
+		}, nil)
+	})
+
+	It("notifies about resources which start to match a saved search", func() {
+		savedSearches(`[{"id":"1","name":"Invoices","query":"tag:invoice","alert":true}]`)
+		matches(false, true)
+
+		alerter.Watch(context.Background(), ri)()
+
+		Expect(pub.published).To(HaveLen(1))
+		e := pub.published[0].(event.SavedSearchMatched)
+		Expect(e.UserID.GetOpaqueId()).To(Equal("user"))
+		Expect(e.SearchID).To(Equal("1"))
+		Expect(e.SearchName).To(Equal("Invoices"))
+		Expect(e.ResourceName).To(Equal("invoice.pdf"))
+		indexClient.AssertCalled(GinkgoT(), "Search", mock.Anything, mock.MatchedBy(func(req *searchsvc.SearchIndexRequest) bool {
+			return req.Query == "id:storageid$spaceid!opaqueid AND (tag:invoice)"
+		}))
+	})
+
+	It("doesn't notify about resources which matched before", func() {
+		savedSearches(`[{"id":"1","name":"Invoices","query":"tag:invoice","alert":true}]`)
+		matches(true, true)
+
+		alerter.Watch(context.Background(), ri)()
+
+		Expect(pub.published).To(BeEmpty())
+	})
+
+	It("ignores saved searches without alert", func() {
+		savedSearches(`[{"id":"1","name":"Invoices","query":"tag:invoice"}]`)
+
+		alerter.Watch(context.Background(), ri)()
+
+		Expect(pub.published).To(BeEmpty())
+		indexClient.AssertNotCalled(GinkgoT(), "Search", mock.Anything, mock.Anything)
+	})
+})
@@ -89,6 +89,10 @@ func HandleEvents(s Searcher, stream raw.Stream, cfg *config.Config, logger log.
 					case events.FileUploaded:
 						indexSpaceDebouncer.Debounce(getSpaceID(ev.Ref), e.Ack)
 					case events.UploadReady:
+						// index the file right away once postprocessing is done, saved search alerts are only sent for single items
+						if !ev.Failed {
+							s.UpsertItem(ev.FileRef)
+						}
 						indexSpaceDebouncer.Debounce(getSpaceID(ev.FileRef), e.Ack)
 					case events.SpaceRenamed:
 						indexSpaceDebouncer.Debounce(ev.ID, e.Ack)
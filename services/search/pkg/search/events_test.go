@@ -56,5 +56,6 @@ var _ = DescribeTable("events",
 	Entry("TagsAdded", []string{"UpsertItem"}, events.TagsAdded{}, false),
 	Entry("TagsRemoved", []string{"UpsertItem"}, events.TagsRemoved{}, false),
 	Entry("FileUploaded", []string{"IndexSpace"}, events.FileUploaded{}, false),
-	Entry("UploadReady", []string{"IndexSpace"}, events.UploadReady{ExecutingUser: &userv1beta1.User{}}, true),
+	Entry("UploadReady", []string{"UpsertItem", "IndexSpace"}, events.UploadReady{ExecutingUser: &userv1beta1.User{}}, true),
+	Entry("failed UploadReady", []string{"IndexSpace"}, events.UploadReady{ExecutingUser: &userv1beta1.User{}, Failed: true}, true),
 )
@@ -0,0 +1,201 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	rpc "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
+	provider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
+	"github.com/opencloud-eu/reva/v2/pkg/errtypes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	searchmsg "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/search/v0"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/engine"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/metrics"
+)
+
+// the states of a reindex
+const (
+	ReindexStateIdle     = "idle"
+	ReindexStateRunning  = "running"
+	ReindexStateFinished = "finished"
+	ReindexStateFailed   = "failed"
+	ReindexStateAborted  = "aborted"
+)
+
+// Reindexer rebuilds the whole index in the background and swaps it in once it is complete.
+// The engine of the service has to implement engine.Reindexer.
+type Reindexer struct {
+	service *Service
+	metrics *metrics.Metrics
+
+	mu     sync.Mutex
+	status *searchmsg.ReindexStatus
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReindexer creates a new Reindexer instance, the metrics are optional.
+func NewReindexer(s *Service, m *metrics.Metrics) *Reindexer {
+	return &Reindexer{
+		service: s,
+		metrics: m,
+		status:  &searchmsg.ReindexStatus{State: ReindexStateIdle},
+	}
+}
+
+// Start starts a new reindex, it returns an error if the engine doesn't support reindexing or if a reindex is already running.
+func (r *Reindexer) Start() error {
+	ri, ok := r.service.engine.(engine.Reindexer)
+	if !ok {
+		return errtypes.NotSupported("the search engine doesn't support reindexing")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status.GetState() == ReindexStateRunning {
+		return engine.ErrReindexRunning
+	}
+
+	eng, err := ri.StartReindex()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	r.status = &searchmsg.ReindexStatus{
+		State:     ReindexStateRunning,
+		StartedAt: timestamppb.Now(),
+	}
+	r.updateMetrics()
+
+	go r.run(ctx, ri, eng, r.done)
+	return nil
+}
+
+// Abort stops the running reindex and drops the new index, the active index stays untouched.
+func (r *Reindexer) Abort() error {
+	r.mu.Lock()
+	if r.status.GetState() != ReindexStateRunning {
+		r.mu.Unlock()
+		return engine.ErrNoReindexRunning
+	}
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+
+	cancel()
+	<-done
+	return nil
+}
+
+// Status returns the status of the current or last reindex.
+func (r *Reindexer) Status() *searchmsg.ReindexStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return proto.Clone(r.status).(*searchmsg.ReindexStatus)
+}
+
+// run indexes all spaces into the new index and swaps it in on success
+func (r *Reindexer) run(ctx context.Context, ri engine.Reindexer, eng engine.Engine, done chan struct{}) {
+	defer close(done)
+	logger := r.service.logger
+
+	err := r.indexSpaces(ctx, eng)
+	switch {
+	case err == nil:
+		err = ri.FinishReindex()
+	default:
+		if abortErr := ri.AbortReindex(); abortErr != nil {
+			logger.Error().Err(abortErr).Msg("could not drop the new index")
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.FinishedAt = timestamppb.Now()
+	switch {
+	case err == nil:
+		r.status.State = ReindexStateFinished
+		logger.Info().Int64("spaces", r.status.GetSpacesDone()).Uint64("resources", r.status.GetResources()).Msg("reindex finished")
+	case errors.Is(err, context.Canceled):
+		r.status.State = ReindexStateAborted
+		logger.Info().Msg("reindex aborted")
+	default:
+		r.status.State = ReindexStateFailed
+		r.status.Error = err.Error()
+		logger.Error().Err(err).Msg("reindex failed")
+	}
+	r.updateMetrics()
+}
+
+// indexSpaces lists all spaces and indexes them one by one into the given engine
+func (r *Reindexer) indexSpaces(ctx context.Context, eng engine.Engine) error {
+	s := r.service
+	ownerCtx, err := getAuthContext(s.serviceAccountID, s.gatewaySelector, s.serviceAccountSecret, s.logger)
+	if err != nil {
+		return err
+	}
+
+	gatewayClient, err := s.gatewaySelector.Next()
+	if err != nil {
+		return err
+	}
+
+	resp, err := gatewayClient.ListStorageSpaces(ownerCtx, &provider.ListStorageSpacesRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus().GetCode() != rpc.Code_CODE_OK {
+		return errors.New(resp.GetStatus().GetMessage())
+	}
+
+	spaces := resp.GetStorageSpaces()
+	r.mu.Lock()
+	r.status.SpacesTotal = int64(len(spaces))
+	r.updateMetrics()
+	r.mu.Unlock()
+
+	for _, space := range spaces {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := s.indexSpace(ctx, eng, space.GetId()); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return err
+		}
+
+		count, _ := eng.DocCount()
+		r.mu.Lock()
+		r.status.SpacesDone++
+		r.status.Resources = count
+		r.updateMetrics()
+		r.mu.Unlock()
+	}
+
+	return nil
+}
+
+// updateMetrics publishes the status as metrics, the caller has to hold the lock
+func (r *Reindexer) updateMetrics() {
+	if r.metrics == nil {
+		return
+	}
+
+	running := 0.0
+	if r.status.GetState() == ReindexStateRunning {
+		running = 1
+	}
+	r.metrics.ReindexRunning.Set(running)
+	r.metrics.ReindexSpacesTotal.Set(float64(r.status.GetSpacesTotal()))
+	r.metrics.ReindexSpacesDone.Set(float64(r.status.GetSpacesDone()))
+	r.metrics.ReindexResources.Set(float64(r.status.GetResources()))
+}
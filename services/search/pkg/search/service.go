@@ -59,6 +59,7 @@ type Service struct {
 	gatewaySelector pool.Selectable[gateway.GatewayAPIClient]
 	engine          engine.Engine
 	extractor       content.Extractor
+	alerter         Alerter
 
 	serviceAccountID     string
 	serviceAccountSecret string
@@ -67,12 +68,14 @@ type Service struct {
 var errSkipSpace error
 
 // NewService creates a new Provider instance.
-func NewService(gatewaySelector pool.Selectable[gateway.GatewayAPIClient], eng engine.Engine, extractor content.Extractor, logger log.Logger, cfg *config.Config) *Service {
+// The alerter is optional, saved search alerts are disabled if it is nil.
+func NewService(gatewaySelector pool.Selectable[gateway.GatewayAPIClient], eng engine.Engine, extractor content.Extractor, alerter Alerter, logger log.Logger, cfg *config.Config) *Service {
 	var s = &Service{
 		gatewaySelector: gatewaySelector,
 		engine:          eng,
 		logger:          logger,
 		extractor:       extractor,
+		alerter:         alerter,
 
 		serviceAccountID:     cfg.ServiceAccount.ServiceAccountID,
 		serviceAccountSecret: cfg.ServiceAccount.ServiceAccountSecret,
@@ -235,10 +238,12 @@ func (s *Service) Search(ctx context.Context, req *searchsvc.SearchRequest) (*se
 		return nil, err
 	}
 
+	facets := make([][]*searchmsg.Facet, 0, len(responses))
 	for _, res := range responses {
 		if res == nil {
 			continue
 		}
+		facets = append(facets, res.Facets)
 		total += res.TotalMatches
 		for _, match := range res.Matches {
 			matches = append(matches, match)
@@ -258,6 +263,7 @@ func (s *Service) Search(ctx context.Context, req *searchsvc.SearchRequest) (*se
 	return &searchsvc.SearchResponse{
 		Matches:      matches,
 		TotalMatches: total,
+		Facets:       engine.MergeFacets(facets...),
 	}, nil
 }
 
@@ -364,6 +370,7 @@ func (s *Service) searchIndex(ctx context.Context, req *searchsvc.SearchRequest,
 			Path:       searchPathPrefix,
 		},
 		PageSize: req.PageSize,
+		Facets:   req.Facets,
 	}
 	start := time.Now()
 	res, err := s.engine.Search(ctx, searchRequest)
@@ -404,11 +411,37 @@ func (s *Service) searchIndex(ctx context.Context, req *searchsvc.SearchRequest,
 
 	res.Matches = matches
 
+	// the engine counts the matches per space root, use the space the user knows instead
+	for _, facet := range res.Facets {
+		if facet.Name != engine.FacetSpace {
+			continue
+		}
+		bucket := &searchmsg.FacetBucket{Name: space.GetId().GetOpaqueId(), Label: space.GetName()}
+		if space.SpaceType == _spaceTypeGrant {
+			bucket.Name = mountpointID
+			bucket.Label = rootName
+		}
+		for _, b := range facet.Buckets {
+			bucket.Count += b.Count
+		}
+		facet.Buckets = nil
+		if bucket.Count > 0 {
+			facet.Buckets = []*searchmsg.FacetBucket{bucket}
+		}
+	}
+
 	return res, nil
 }
 
 // IndexSpace (re)indexes all resources of a given space.
 func (s *Service) IndexSpace(spaceID *provider.StorageSpaceId) error {
+	return s.indexSpace(context.Background(), s.engine, spaceID)
+}
+
+// indexSpace (re)indexes all resources of a given space into the given engine,
+// the walk stops as soon as ctx is done. No saved search alerts are sent, they
+// are only sent when single items are indexed because of an event.
+func (s *Service) indexSpace(ctx context.Context, eng engine.Engine, spaceID *provider.StorageSpaceId) error {
 	ownerCtx, err := getAuthContext(s.serviceAccountID, s.gatewaySelector, s.serviceAccountSecret, s.logger)
 	if err != nil {
 		return err
@@ -432,6 +465,10 @@ func (s *Service) IndexSpace(spaceID *provider.StorageSpaceId) error {
 			return err
 		}
 
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		if info == nil {
 			return nil
 		}
@@ -442,7 +479,7 @@ func (s *Service) IndexSpace(spaceID *provider.StorageSpaceId) error {
 		}
 		s.logger.Debug().Str("path", ref.Path).Msg("Walking tree")
 
-		searchRes, err := s.engine.Search(ownerCtx, &searchsvc.SearchIndexRequest{
+		searchRes, err := eng.Search(ownerCtx, &searchsvc.SearchIndexRequest{
 			Query: "id:" + storagespace.FormatResourceID(info.Id) + ` mtime>=` + utils.TSToTime(info.Mtime).Format(time.RFC3339Nano),
 		})
 
@@ -455,7 +492,7 @@ func (s *Service) IndexSpace(spaceID *provider.StorageSpaceId) error {
 			return nil
 		}
 
-		s.UpsertItem(ref)
+		s.upsertItem(eng, ref, false)
 
 		return nil
 	})
@@ -464,7 +501,7 @@ func (s *Service) IndexSpace(spaceID *provider.StorageSpaceId) error {
 		return err
 	}
 
-	logDocCount(s.engine, s.logger)
+	logDocCount(eng, s.logger)
 
 	return nil
 }
@@ -479,6 +516,11 @@ func (s *Service) TrashItem(rID *provider.ResourceId) {
 
 // UpsertItem indexes or stores Resource data fields.
 func (s *Service) UpsertItem(ref *provider.Reference) {
+	s.upsertItem(s.engine, ref, true)
+}
+
+// upsertItem indexes the resource in the given engine, users are notified about new matches of their saved searches if alert is set.
+func (s *Service) upsertItem(eng engine.Engine, ref *provider.Reference, alert bool) {
 	ctx, stat, path := s.resInfo(ref)
 	if ctx == nil || stat == nil || path == "" {
 		return
@@ -507,10 +549,16 @@ func (s *Service) UpsertItem(ref *provider.Reference) {
 		r.ParentID = storagespace.FormatResourceID(parentID)
 	}
 
-	if err = s.engine.Upsert(r.ID, r); err != nil {
+	notify := func() {}
+	if alert && s.alerter != nil {
+		notify = s.alerter.Watch(ctx, stat.Info)
+	}
+
+	if err = eng.Upsert(r.ID, r); err != nil {
 		s.logger.Error().Err(err).Msg("error adding updating the resource in the index")
 	} else {
-		logDocCount(s.engine, s.logger)
+		logDocCount(eng, s.logger)
+		notify()
 	}
 
 	// determine if metadata needs to be stored in storage as well
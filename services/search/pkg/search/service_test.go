@@ -90,7 +90,7 @@ var _ = Describe("Searchprovider", func() {
 		indexClient = &engineMocks.Engine{}
 		extractor = &contentMocks.Extractor{}
 
-		s = search.NewService(gatewaySelector, indexClient, extractor, logger, &config.Config{})
+		s = search.NewService(gatewaySelector, indexClient, extractor, nil, logger, &config.Config{})
 
 		gatewayClient.On("Authenticate", mock.Anything, mock.Anything).Return(&gateway.AuthenticateResponse{
 			Status: status.NewOK(ctx),
@@ -110,7 +110,7 @@ var _ = Describe("Searchprovider", func() {
 
 	Describe("New", func() {
 		It("returns a new instance", func() {
-			s := search.NewService(gatewaySelector, indexClient, extractor, logger, &config.Config{})
+			s := search.NewService(gatewaySelector, indexClient, extractor, nil, logger, &config.Config{})
 			Expect(s).ToNot(BeNil())
 		})
 	})
@@ -374,6 +374,10 @@ var _ = Describe("Searchprovider", func() {
 							req.Ref.ResourceId.SpaceId == grantSpace.Root.SpaceId
 					})).Return(&searchsvc.SearchIndexResponse{
 						TotalMatches: 2,
+						Facets: []*searchmsg.Facet{
+							{Name: "mediatype", Buckets: []*searchmsg.FacetBucket{{Name: "pdf", Count: 2}}},
+							{Name: "space", Buckets: []*searchmsg.FacetBucket{{Name: "storageproviderid$spaceid!spaceid", Count: 2}}},
+						},
 						Matches: []*searchmsg.Match{
 							{
 								Score: 2,
@@ -418,6 +422,10 @@ var _ = Describe("Searchprovider", func() {
 							req.Ref.ResourceId.SpaceId == personalSpace.Root.SpaceId
 					})).Return(&searchsvc.SearchIndexResponse{
 						TotalMatches: 1,
+						Facets: []*searchmsg.Facet{
+							{Name: "mediatype", Buckets: []*searchmsg.FacetBucket{{Name: "pdf", Count: 1}}},
+							{Name: "space", Buckets: []*searchmsg.FacetBucket{{Name: "storageid$personalspace!personalspace", Count: 1}}},
+						},
 						Matches: []*searchmsg.Match{
 							{
 								Score: 1,
@@ -480,6 +488,30 @@ var _ = Describe("Searchprovider", func() {
 					ids := []string{res.Matches[0].Entity.Id.OpaqueId, res.Matches[1].Entity.Id.OpaqueId}
 					Expect(ids).To(Equal([]string{"grant-shared-id", "foo-id"}))
 				})
+
+				It("passes the facets to the index and merges the facets of all spaces", func() {
+					res, err := s.Search(ctx, &searchsvc.SearchRequest{
+						Query:  "foo",
+						Facets: []string{"mediatype", "space"},
+					})
+					Expect(err).ToNot(HaveOccurred())
+					indexClient.AssertCalled(GinkgoT(), "Search", mock.Anything, mock.MatchedBy(func(req *searchsvc.SearchIndexRequest) bool {
+						return len(req.Facets) == 2 && req.Facets[0] == "mediatype" && req.Facets[1] == "space"
+					}))
+
+					Expect(res.Facets).To(HaveLen(2))
+					Expect(res.Facets[0].Name).To(Equal("mediatype"))
+					Expect(res.Facets[0].Buckets).To(HaveLen(1))
+					Expect(res.Facets[0].Buckets[0].Count).To(Equal(int64(3)))
+
+					Expect(res.Facets[1].Name).To(Equal("space"))
+					Expect(res.Facets[1].Buckets).To(HaveLen(2))
+					Expect(res.Facets[1].Buckets[0].Name).To(Equal(mountpointSpace.Id.OpaqueId))
+					Expect(res.Facets[1].Buckets[0].Count).To(Equal(int64(2)))
+					Expect(res.Facets[1].Buckets[1].Name).To(Equal(personalSpace.Id.OpaqueId))
+					Expect(res.Facets[1].Buckets[1].Label).To(Equal(personalSpace.Name))
+					Expect(res.Facets[1].Buckets[1].Count).To(Equal(int64(1)))
+				})
 			})
 		})
 	})
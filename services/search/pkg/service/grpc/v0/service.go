@@ -14,6 +14,7 @@ import (
 	revactx "github.com/opencloud-eu/reva/v2/pkg/ctx"
 	"github.com/opencloud-eu/reva/v2/pkg/errtypes"
 	"github.com/opencloud-eu/reva/v2/pkg/events/raw"
+	"github.com/opencloud-eu/reva/v2/pkg/events/stream"
 	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
 	"github.com/opencloud-eu/reva/v2/pkg/token"
 	"github.com/opencloud-eu/reva/v2/pkg/token/manager/jwt"
@@ -26,10 +27,12 @@ import (
 	"github.com/opencloud-eu/opencloud/pkg/registry"
 	v0 "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/search/v0"
 	searchsvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/search/v0"
+	settingssvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/settings/v0"
 	"github.com/opencloud-eu/opencloud/services/search/pkg/config"
 	"github.com/opencloud-eu/opencloud/services/search/pkg/content"
 	"github.com/opencloud-eu/opencloud/services/search/pkg/engine"
 	"github.com/opencloud-eu/opencloud/services/search/pkg/query/bleve"
+	"github.com/opencloud-eu/opencloud/services/search/pkg/query/opensearch"
 	"github.com/opencloud-eu/opencloud/services/search/pkg/search"
 )
 
@@ -44,16 +47,23 @@ func NewHandler(opts ...Option) (searchsvc.SearchProviderHandler, func(), error)
 	var eng engine.Engine
 	switch cfg.Engine.Type {
 	case "bleve":
-		idx, err := engine.NewBleveIndex(cfg.Engine.Bleve.Datapath)
+		bg, err := engine.NewBlueGreenBleveEngine(cfg.Engine.Bleve.Datapath, bleve.DefaultCreator)
 		if err != nil {
 			return nil, teardown, err
 		}
 
 		teardown = func() {
-			_ = idx.Close()
+			_ = bg.Close()
 		}
 
-		eng = engine.NewBleveEngine(idx, bleve.DefaultCreator)
+		eng = bg
+	case "opensearch":
+		openSearch := engine.NewOpenSearchEngine(cfg.Engine.OpenSearch, opensearch.DefaultCreator)
+		if err := openSearch.EnsureIndex(context.Background()); err != nil {
+			return nil, teardown, err
+		}
+
+		eng = openSearch
 	default:
 		return nil, teardown, fmt.Errorf("unknown search engine: %s", cfg.Engine.Type)
 	}
@@ -75,14 +85,36 @@ func NewHandler(opts ...Option) (searchsvc.SearchProviderHandler, func(), error)
 		if extractor, err = content.NewTikaExtractor(selector, logger, cfg); err != nil {
 			return nil, teardown, err
 		}
+	case "native":
+		if extractor, err = content.NewNativeExtractor(selector, logger, cfg); err != nil {
+			return nil, teardown, err
+		}
 	default:
 		return nil, teardown, fmt.Errorf("unknown search extractor: %s", cfg.Extractor.Type)
 	}
 
-	ss := search.NewService(selector, eng, extractor, logger, cfg)
-
 	// setup event handling
 
+	publisher, err := stream.NatsFromConfig(cfg.Service.Name, false, stream.NatsConfig{
+		Endpoint:             cfg.Events.Endpoint,
+		Cluster:              cfg.Events.Cluster,
+		TLSInsecure:          cfg.Events.TLSInsecure,
+		TLSRootCACertificate: cfg.Events.TLSRootCACertificate,
+		EnableTLS:            cfg.Events.EnableTLS,
+		AuthUsername:         cfg.Events.AuthUsername,
+		AuthPassword:         cfg.Events.AuthPassword,
+	})
+	if err != nil {
+		return nil, teardown, err
+	}
+
+	alerter, err := search.NewSavedSearchAlerter(selector, eng, settingssvc.NewValueService("eu.opencloud.api.settings", cfg.GrpcClient), publisher, logger)
+	if err != nil {
+		return nil, teardown, err
+	}
+
+	ss := search.NewService(selector, eng, extractor, alerter, logger, cfg)
+
 	stream, err := raw.FromConfig(context.Background(), cfg.Service.Name, raw.Config{
 		Endpoint:             cfg.Events.Endpoint,
 		Cluster:              cfg.Events.Cluster,
@@ -119,6 +151,7 @@ func NewHandler(opts ...Option) (searchsvc.SearchProviderHandler, func(), error)
 		id:           cfg.GRPC.Namespace + "." + cfg.Service.Name,
 		log:          logger,
 		searcher:     ss,
+		reindexer:    search.NewReindexer(ss, options.Metrics),
 		cache:        cache,
 		tokenManager: tokenManager,
 		gws:          selector,
@@ -131,6 +164,7 @@ type Service struct {
 	id           string
 	log          log.Logger
 	searcher     search.Searcher
+	reindexer    *search.Reindexer
 	cache        *ttlcache.Cache
 	tokenManager token.Manager
 	gws          *pool.Selector[gateway.GatewayAPIClient]
@@ -216,6 +250,38 @@ func (s Service) IndexSpace(_ context.Context, in *searchsvc.IndexSpaceRequest,
 	return nil
 }
 
+// Reindex starts or aborts a rebuild of the whole index in the background.
+func (s Service) Reindex(_ context.Context, in *searchsvc.ReindexRequest, out *searchsvc.ReindexResponse) error {
+	var err error
+	if in.GetAbort() {
+		err = s.reindexer.Abort()
+	} else {
+		err = s.reindexer.Start()
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, engine.ErrReindexRunning), errors.Is(err, engine.ErrNoReindexRunning):
+		return merrors.Conflict(s.id, "%s", err.Error())
+	default:
+		switch err.(type) {
+		case errtypes.NotSupported:
+			return merrors.BadRequest(s.id, "%s", err.Error())
+		default:
+			return merrors.InternalServerError(s.id, "%s", err.Error())
+		}
+	}
+
+	out.Status = s.reindexer.Status()
+	return nil
+}
+
+// GetReindexStatus returns the status of the current or last reindex.
+func (s Service) GetReindexStatus(_ context.Context, _ *searchsvc.GetReindexStatusRequest, out *searchsvc.GetReindexStatusResponse) error {
+	out.Status = s.reindexer.Status()
+	return nil
+}
+
 // FromCache pulls a search result from cache
 func (s Service) FromCache(key string) (*searchsvc.SearchResponse, bool) {
 	v, err := s.cache.Get(key)
@@ -45,6 +45,14 @@ const (
 	SettingUUIDProfileEventSpaceDeleted = "094ceca9-5a00-40ba-bb1a-bbc7bccd39ee"
 	// SettingUUIDProfileEventPostprocessingStepFinished is the hardcoded setting UUID for the send in mail setting
 	SettingUUIDProfileEventPostprocessingStepFinished = "fe0a3011-d886-49c8-b797-33d02fa426ef"
+	// SettingUUIDProfileSavedSearches is the hardcoded setting UUID for the saved searches of a user
+	SettingUUIDProfileSavedSearches = "5a7b8c23-1e4d-4b8a-9c3f-0d6e2f1a8b47"
+	// SettingUUIDProfileNotificationWebhookURL is the hardcoded setting UUID for the webhook url notifications are sent to
+	SettingUUIDProfileNotificationWebhookURL = "c9d3e0f4-2b7a-4e61-8f15-6a0b3d9e7c21"
+	// SettingUUIDProfileNotificationMatrixRoom is the hardcoded setting UUID for the matrix room notifications are sent to
+	SettingUUIDProfileNotificationMatrixRoom = "4e8a1b6c-9d2f-4a73-b058-e17c3f6d2a94"
+	// SettingUUIDProfileNotificationPushTopic is the hardcoded setting UUID for the push topic notifications are sent to
+	SettingUUIDProfileNotificationPushTopic = "a62f7d19-5c8e-4b30-9e4a-3d1b8c7f5e06"
 )
 
 // GenerateBundlesDefaultRoles bootstraps the default roles.
@@ -130,6 +138,10 @@ func generateBundleAdminRole() *settingsmsg.Bundle {
 			ProfileEventSpaceDisabledPermission(Own),
 			ProfileEventSpaceDeletedPermission(Own),
 			ProfileEventPostprocessingStepFinishedPermission(Own),
+			ProfileSavedSearchesPermission(Own),
+			ProfileNotificationWebhookPermission(Own),
+			ProfileNotificationMatrixPermission(Own),
+			ProfileNotificationPushPermission(Own),
 			GroupManagementPermission(All),
 			LanguageManagementPermission(All),
 			ListFavoritesPermission(Own),
@@ -173,6 +185,10 @@ func generateBundleSpaceAdminRole() *settingsmsg.Bundle {
 			ProfileEventSpaceDisabledPermission(Own),
 			ProfileEventSpaceDeletedPermission(Own),
 			ProfileEventPostprocessingStepFinishedPermission(Own),
+			ProfileSavedSearchesPermission(Own),
+			ProfileNotificationWebhookPermission(Own),
+			ProfileNotificationMatrixPermission(Own),
+			ProfileNotificationPushPermission(Own),
 			LanguageManagementPermission(Own),
 			ListFavoritesPermission(Own),
 			ListSpacesPermission(All),
@@ -211,6 +227,10 @@ func generateBundleUserRole() *settingsmsg.Bundle {
 			ProfileEventSpaceDisabledPermission(Own),
 			ProfileEventSpaceDeletedPermission(Own),
 			ProfileEventPostprocessingStepFinishedPermission(Own),
+			ProfileSavedSearchesPermission(Own),
+			ProfileNotificationWebhookPermission(Own),
+			ProfileNotificationMatrixPermission(Own),
+			ProfileNotificationPushPermission(Own),
 			LanguageManagementPermission(Own),
 			ListFavoritesPermission(Own),
 			SelfManagementPermission(Own),
@@ -302,6 +322,9 @@ func generateBundleProfileRequest() *settingsmsg.Bundle {
 						Options: []*settingsmsg.MultiChoiceCollectionOption{
 							&optionInAppTrue,
 							&optionMailTrue,
+							&optionWebhookFalse,
+							&optionMatrixFalse,
+							&optionPushFalse,
 						},
 					},
 				},
@@ -336,6 +359,9 @@ func generateBundleProfileRequest() *settingsmsg.Bundle {
 						Options: []*settingsmsg.MultiChoiceCollectionOption{
 							&optionInAppTrue,
 							&optionMailTrue,
+							&optionWebhookFalse,
+							&optionMatrixFalse,
+							&optionPushFalse,
 						},
 					},
 				},
@@ -353,6 +379,9 @@ func generateBundleProfileRequest() *settingsmsg.Bundle {
 						Options: []*settingsmsg.MultiChoiceCollectionOption{
 							&optionInAppTrue,
 							&optionMailTrue,
+							&optionWebhookFalse,
+							&optionMatrixFalse,
+							&optionPushFalse,
 						},
 					},
 				},
@@ -370,6 +399,9 @@ func generateBundleProfileRequest() *settingsmsg.Bundle {
 						Options: []*settingsmsg.MultiChoiceCollectionOption{
 							&optionInAppTrue,
 							&optionMailTrue,
+							&optionWebhookFalse,
+							&optionMatrixFalse,
+							&optionPushFalse,
 						},
 					},
 				},
@@ -387,6 +419,9 @@ func generateBundleProfileRequest() *settingsmsg.Bundle {
 						Options: []*settingsmsg.MultiChoiceCollectionOption{
 							&optionInAppTrue,
 							&optionMailTrue,
+							&optionWebhookFalse,
+							&optionMatrixFalse,
+							&optionPushFalse,
 						},
 					},
 				},
@@ -442,6 +477,46 @@ func generateBundleProfileRequest() *settingsmsg.Bundle {
 					},
 				},
 			},
+			{
+				Id:          SettingUUIDProfileSavedSearches,
+				Name:        "saved-searches",
+				DisplayName: "Saved Searches",
+				Description: "The saved search queries of the user as JSON list",
+				Resource: &settingsmsg.Resource{
+					Type: settingsmsg.Resource_TYPE_USER,
+				},
+				Value: &settingsmsg.Setting_StringValue{StringValue: &settingsmsg.String{Default: "[]"}},
+			},
+			{
+				Id:          SettingUUIDProfileNotificationWebhookURL,
+				Name:        "notification-webhook-url",
+				DisplayName: TemplateNotificationWebhookURL,
+				Description: TemplateNotificationWebhookURLDescription,
+				Resource: &settingsmsg.Resource{
+					Type: settingsmsg.Resource_TYPE_USER,
+				},
+				Value: &settingsmsg.Setting_StringValue{StringValue: &settingsmsg.String{}},
+			},
+			{
+				Id:          SettingUUIDProfileNotificationMatrixRoom,
+				Name:        "notification-matrix-room",
+				DisplayName: TemplateNotificationMatrixRoom,
+				Description: TemplateNotificationMatrixRoomDescription,
+				Resource: &settingsmsg.Resource{
+					Type: settingsmsg.Resource_TYPE_USER,
+				},
+				Value: &settingsmsg.Setting_StringValue{StringValue: &settingsmsg.String{}},
+			},
+			{
+				Id:          SettingUUIDProfileNotificationPushTopic,
+				Name:        "notification-push-topic",
+				DisplayName: TemplateNotificationPushTopic,
+				Description: TemplateNotificationPushTopicDescription,
+				Resource: &settingsmsg.Resource{
+					Type: settingsmsg.Resource_TYPE_USER,
+				},
+				Value: &settingsmsg.Setting_StringValue{StringValue: &settingsmsg.String{}},
+			},
 		},
 	}
 }
@@ -523,6 +598,42 @@ var optionMailFalseDisabled = settingsmsg.MultiChoiceCollectionOption{
 	},
 }
 
+var optionWebhookFalse = settingsmsg.MultiChoiceCollectionOption{
+	Key:          "webhook",
+	DisplayValue: "Webhook",
+	Value: &settingsmsg.MultiChoiceCollectionOptionValue{
+		Option: &settingsmsg.MultiChoiceCollectionOptionValue_BoolValue{
+			BoolValue: &settingsmsg.Bool{
+				Default: false,
+			},
+		},
+	},
+}
+
+var optionMatrixFalse = settingsmsg.MultiChoiceCollectionOption{
+	Key:          "matrix",
+	DisplayValue: "Matrix",
+	Value: &settingsmsg.MultiChoiceCollectionOptionValue{
+		Option: &settingsmsg.MultiChoiceCollectionOptionValue_BoolValue{
+			BoolValue: &settingsmsg.Bool{
+				Default: false,
+			},
+		},
+	},
+}
+
+var optionPushFalse = settingsmsg.MultiChoiceCollectionOption{
+	Key:          "push",
+	DisplayValue: "Push",
+	Value: &settingsmsg.MultiChoiceCollectionOptionValue{
+		Option: &settingsmsg.MultiChoiceCollectionOptionValue_BoolValue{
+			BoolValue: &settingsmsg.Bool{
+				Default: false,
+			},
+		},
+	},
+}
+
 // TODO: languageSetting needed?
 var languageSetting = settingsmsg.Setting_SingleChoiceValue{
 	SingleChoiceValue: &settingsmsg.SingleChoiceList{
@@ -390,6 +390,82 @@ func ProfileEventPostprocessingStepFinishedPermission(c settingsmsg.Permission_C
 	}
 }
 
+// ProfileSavedSearchesPermission is the permission to manage the saved searches
+func ProfileSavedSearchesPermission(c settingsmsg.Permission_Constraint) *settingsmsg.Setting {
+	return &settingsmsg.Setting{
+		Id:          "b3f1c5d2-6a4e-4f0b-8d97-2c5e1a9f3b68",
+		Name:        "SavedSearches.ReadWrite",
+		DisplayName: "Saved Searches",
+		Resource: &settingsmsg.Resource{
+			Type: settingsmsg.Resource_TYPE_SETTING,
+			Id:   SettingUUIDProfileSavedSearches,
+		},
+		Value: &settingsmsg.Setting_PermissionValue{
+			PermissionValue: &settingsmsg.Permission{
+				Operation:  settingsmsg.Permission_OPERATION_READWRITE,
+				Constraint: c,
+			},
+		},
+	}
+}
+
+// ProfileNotificationWebhookPermission is the permission to set the webhook url for notifications
+func ProfileNotificationWebhookPermission(c settingsmsg.Permission_Constraint) *settingsmsg.Setting {
+	return &settingsmsg.Setting{
+		Id:          "e5b1f3a7-8c2d-4f96-a0e4-7b3c9d1f6a52",
+		Name:        "NotificationWebhook.ReadWrite",
+		DisplayName: "Notification Webhook",
+		Resource: &settingsmsg.Resource{
+			Type: settingsmsg.Resource_TYPE_SETTING,
+			Id:   SettingUUIDProfileNotificationWebhookURL,
+		},
+		Value: &settingsmsg.Setting_PermissionValue{
+			PermissionValue: &settingsmsg.Permission{
+				Operation:  settingsmsg.Permission_OPERATION_READWRITE,
+				Constraint: c,
+			},
+		},
+	}
+}
+
+// ProfileNotificationMatrixPermission is the permission to set the matrix room for notifications
+func ProfileNotificationMatrixPermission(c settingsmsg.Permission_Constraint) *settingsmsg.Setting {
+	return &settingsmsg.Setting{
+		Id:          "0f7c2e4b-3a9d-4d18-b6e5-9c1a8f2d4b73",
+		Name:        "NotificationMatrix.ReadWrite",
+		DisplayName: "Notification Matrix Room",
+		Resource: &settingsmsg.Resource{
+			Type: settingsmsg.Resource_TYPE_SETTING,
+			Id:   SettingUUIDProfileNotificationMatrixRoom,
+		},
+		Value: &settingsmsg.Setting_PermissionValue{
+			PermissionValue: &settingsmsg.Permission{
+				Operation:  settingsmsg.Permission_OPERATION_READWRITE,
+				Constraint: c,
+			},
+		},
+	}
+}
+
+// ProfileNotificationPushPermission is the permission to set the push topic for notifications
+func ProfileNotificationPushPermission(c settingsmsg.Permission_Constraint) *settingsmsg.Setting {
+	return &settingsmsg.Setting{
+		Id:          "7a3d9f1e-6b4c-4e27-8d50-2f8e1c6b9a34",
+		Name:        "NotificationPush.ReadWrite",
+		DisplayName: "Notification Push Topic",
+		Resource: &settingsmsg.Resource{
+			Type: settingsmsg.Resource_TYPE_SETTING,
+			Id:   SettingUUIDProfileNotificationPushTopic,
+		},
+		Value: &settingsmsg.Setting_PermissionValue{
+			PermissionValue: &settingsmsg.Permission{
+				Operation:  settingsmsg.Permission_OPERATION_READWRITE,
+				Constraint: c,
+			},
+		},
+	}
+}
+
 // GroupManagementPermission is the permission to manage groups
 func GroupManagementPermission(c settingsmsg.Permission_Constraint) *settingsmsg.Setting {
 	return &settingsmsg.Setting{
@@ -52,4 +52,16 @@ var (
 	TemplateIntervalWeekly = l10n.Template("Weekly")
 	// translation for the 'never' email interval option
 	TemplateIntervalNever = l10n.Template("Never")
+	// name of the notification option 'Webhook URL'
+	TemplateNotificationWebhookURL = l10n.Template("Webhook URL")
+	// description of the notification option 'Webhook URL'
+	TemplateNotificationWebhookURLDescription = l10n.Template("Notifications with the 'Webhook' option are posted to this URL")
+	// name of the notification option 'Matrix Room'
+	TemplateNotificationMatrixRoom = l10n.Template("Matrix room")
+	// description of the notification option 'Matrix Room'
+	TemplateNotificationMatrixRoomDescription = l10n.Template("Notifications with the 'Matrix' option are sent to this room ID")
+	// name of the notification option 'Push Topic'
+	TemplateNotificationPushTopic = l10n.Template("Push topic")
+	// description of the notification option 'Push Topic'
+	TemplateNotificationPushTopicDescription = l10n.Template("Notifications with the 'Push' option are sent to this topic")
 )
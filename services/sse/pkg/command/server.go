@@ -10,6 +10,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
+	"github.com/opencloud-eu/opencloud/pkg/kv"
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	"github.com/opencloud-eu/opencloud/pkg/tracing"
 	"github.com/opencloud-eu/opencloud/services/sse/pkg/config"
@@ -57,11 +58,24 @@ func Server(cfg *config.Config) *cli.Command {
 					return err
 				}
 
+				st, err := kv.New(kv.Options{
+					Store:        cfg.Store.Store,
+					Nodes:        cfg.Store.Nodes,
+					Database:     cfg.Store.Database,
+					TTL:          cfg.Store.TTL,
+					AuthUsername: cfg.Store.AuthUsername,
+					AuthPassword: cfg.Store.AuthPassword,
+				})
+				if err != nil {
+					return err
+				}
+
 				server, err := http.Server(
 					http.Logger(logger),
 					http.Context(ctx),
 					http.Config(cfg),
 					http.Consumer(natsStream),
+					http.Store(st),
 					http.RegisteredEvents(_registeredEvents),
 					http.TracerProvider(tracerProvider),
 				)
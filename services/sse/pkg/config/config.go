@@ -18,6 +18,10 @@ type Config struct {
 	Service           Service       `yaml:"-"`
 	KeepAliveInterval time.Duration `yaml:"keepalive_interval" env:"SSE_KEEPALIVE_INTERVAL" desc:"To prevent intermediate proxies from closing the SSE connection, send periodic SSE comments to keep it open." introductionVersion:"1.0.0"`
 
+	BacklogSize           int   `yaml:"backlog_size" env:"SSE_BACKLOG_SIZE" desc:"The number of events kept per user to replay them to clients reconnecting with the 'Last-Event-ID' header. Set to 0 to disable the replay." introductionVersion:"%%NEXT%%"`
+	MaxConnectionsPerUser int   `yaml:"max_connections_per_user" env:"SSE_MAX_CONNECTIONS_PER_USER" desc:"The maximum number of concurrent SSE connections per user and instance of the service. Further connections are rejected with status 429. Set to 0 for no limit." introductionVersion:"%%NEXT%%"`
+	Store                 Store `yaml:"store"`
+
 	Events       Events
 	HTTP         HTTP          `yaml:"http"`
 	TokenManager *TokenManager `yaml:"token_manager"`
@@ -25,6 +29,16 @@ type Config struct {
 	Context context.Context `yaml:"-" json:"-"`
 }
 
+// Store configures the store holding the event backlog
+type Store struct {
+	Store        string        `yaml:"store" env:"OC_PERSISTENT_STORE;SSE_STORE" desc:"The type of the store. Supported values are: 'memory' and 'nats-js-kv'. When running multiple instances of the service, the 'nats-js-kv' store is required for clients to catch up after reconnecting to a different instance. See the text description for details." introductionVersion:"%%NEXT%%"`
+	Nodes        []string      `yaml:"nodes" env:"OC_PERSISTENT_STORE_NODES;SSE_STORE_NODES" desc:"A list of nodes to access the configured store. This has no effect when 'memory' store is configured. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Database     string        `yaml:"database" env:"SSE_STORE_DATABASE" desc:"The name of the bucket the event backlog is stored in." introductionVersion:"%%NEXT%%"`
+	TTL          time.Duration `yaml:"ttl" env:"SSE_STORE_TTL" desc:"Time to live for the event backlog of a user in the store. Defaults to '1h'. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	AuthUsername string        `yaml:"username" env:"OC_PERSISTENT_STORE_AUTH_USERNAME;SSE_STORE_AUTH_USERNAME" desc:"The username to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+	AuthPassword string        `yaml:"password" env:"OC_PERSISTENT_STORE_AUTH_PASSWORD;SSE_STORE_AUTH_PASSWORD" desc:"The password to authenticate with the store. Only applies when store type 'nats-js-kv' is configured." introductionVersion:"%%NEXT%%"`
+}
+
 // Service defines the available service configuration.
 type Service struct {
 	Name string `yaml:"-"`
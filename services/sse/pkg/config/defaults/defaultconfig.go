@@ -2,6 +2,7 @@ package defaults
 
 import (
 	"strings"
+	"time"
 
 	"github.com/opencloud-eu/opencloud/services/sse/pkg/config"
 )
@@ -24,6 +25,14 @@ func DefaultConfig() *config.Config {
 		Service: config.Service{
 			Name: "sse",
 		},
+		KeepAliveInterval:     30 * time.Second,
+		BacklogSize:           100,
+		MaxConnectionsPerUser: 20,
+		Store: config.Store{
+			Store:    "memory",
+			Database: "sse-backlog",
+			TTL:      time.Hour,
+		},
 		Events: config.Events{
 			Endpoint: "127.0.0.1:9233",
 			Cluster:  "opencloud-cluster",
@@ -87,5 +96,7 @@ func Sanitize(cfg *config.Config) {
 	if cfg.HTTP.Root != "/" {
 		cfg.HTTP.Root = strings.TrimSuffix(cfg.HTTP.Root, "/")
 	}
-
+	if cfg.BacklogSize < 0 {
+		cfg.BacklogSize = 0
+	}
 }
@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 
+	"github.com/opencloud-eu/opencloud/pkg/kv"
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	"github.com/opencloud-eu/opencloud/services/sse/pkg/config"
 	"github.com/opencloud-eu/reva/v2/pkg/events"
@@ -19,6 +20,7 @@ type Options struct {
 	Config           *config.Config
 	Consumer         events.Consumer
 	RegisteredEvents []events.Unmarshaller
+	Store            kv.Store
 	TracerProvider   trace.TracerProvider
 }
 
@@ -74,3 +76,10 @@ func TracerProvider(val trace.TracerProvider) Option {
 		o.TracerProvider = val
 	}
 }
+
+// Store provides a function to set the store holding the event backlog
+func Store(val kv.Store) Option {
+	return func(o *Options) {
+		o.Store = val
+	}
+}
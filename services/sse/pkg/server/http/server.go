@@ -83,7 +83,8 @@ func Server(opts ...Option) (http.Service, error) {
 		return http.Service{}, err
 	}
 
-	handle, err := svc.NewSSE(options.Config, options.Logger, ch, mux)
+	backlog := svc.NewBacklog(options.Store, options.Config.BacklogSize)
+	handle, err := svc.NewSSE(options.Config, options.Logger, ch, mux, backlog)
 	if err != nil {
 		return http.Service{}, err
 	}
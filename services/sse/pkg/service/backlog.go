@@ -0,0 +1,122 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+)
+
+// Event is a server-sent event addressed to a single user
+type Event struct {
+	// ID is unique and increasing per user, clients send it back as Last-Event-ID when reconnecting
+	ID uint64 `json:"id"`
+	// EventID is the id of the event on the event bus, it is used to store every event only once
+	EventID string `json:"eventId"`
+	Type    string `json:"type"`
+	Data    []byte `json:"data"`
+}
+
+// backlogRecord is the stored backlog of a user
+type backlogRecord struct {
+	LastID uint64  `json:"lastId"`
+	Events []Event `json:"events"`
+}
+
+// Backlog keeps the last events of every user in the store, so clients can catch up after reconnecting.
+// The backlog of a user is updated with a revision check, so the instances sharing the store agree on the
+// ids of the events. All instances receive every event, the bus event id makes sure it is added only once
+// as long as it is still in the backlog.
+type Backlog struct {
+	store kv.Store
+	size  int
+}
+
+// NewBacklog returns a new Backlog which keeps up to size events per user. The events expire after the
+// TTL of the store.
+func NewBacklog(store kv.Store, size int) *Backlog {
+	return &Backlog{
+		store: store,
+		size:  size,
+	}
+}
+
+// Append assigns the next id to the event and adds it to the backlog of the user.
+// If the event has already been added, the stored event is returned. Nothing is stored
+// if the backlog is disabled.
+func (b *Backlog) Append(userID string, ev Event) (Event, error) {
+	if b.size <= 0 {
+		return ev, nil
+	}
+
+	stored := ev
+	err := kv.Modify(b.store, userID, func(value []byte) ([]byte, error) {
+		rec, err := decodeBacklog(value)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range rec.Events {
+			if ev.EventID != "" && e.EventID == ev.EventID {
+				stored = e
+				return nil, nil
+			}
+		}
+
+		stored = ev
+		stored.ID = rec.LastID + 1
+		rec.LastID = stored.ID
+
+		rec.Events = append(rec.Events, stored)
+		if len(rec.Events) > b.size {
+			rec.Events = rec.Events[len(rec.Events)-b.size:]
+		}
+		return json.Marshal(rec)
+	})
+	if err != nil {
+		return ev, err
+	}
+	return stored, nil
+}
+
+// Since returns the events of the user with an id greater than the given one and the id of the
+// latest event added to the backlog. The ids start again at 1 when the backlog of the user expired,
+// all events are returned if the given id is greater than the latest one.
+func (b *Backlog) Since(userID string, id uint64) ([]Event, uint64, error) {
+	if b.size <= 0 {
+		return nil, 0, nil
+	}
+
+	value, _, err := b.store.Get(userID)
+	switch {
+	case errors.Is(err, kv.ErrNotFound):
+		return nil, 0, nil
+	case err != nil:
+		return nil, 0, err
+	}
+
+	rec, err := decodeBacklog(value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if id > rec.LastID {
+		// the backlog expired since the client received the event
+		return rec.Events, rec.LastID, nil
+	}
+	for i, ev := range rec.Events {
+		if ev.ID > id {
+			return rec.Events[i:], rec.LastID, nil
+		}
+	}
+	return nil, rec.LastID, nil
+}
+
+func decodeBacklog(value []byte) (backlogRecord, error) {
+	var rec backlogRecord
+	if value == nil {
+		return rec, nil
+	}
+	err := json.Unmarshal(value, &rec)
+	return rec, err
+}
@@ -1,11 +1,14 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/r3labs/sse/v2"
 
 	revactx "github.com/opencloud-eu/reva/v2/pkg/ctx"
 	"github.com/opencloud-eu/reva/v2/pkg/events"
@@ -16,24 +19,27 @@ import (
 
 // SSE defines implements the business logic for Service.
 type SSE struct {
-	c         *config.Config
-	l         log.Logger
-	m         *chi.Mux
-	sse       *sse.Server
-	evChannel <-chan events.Event
+	c           *config.Config
+	l           log.Logger
+	m           *chi.Mux
+	backlog     *Backlog
+	subscribers *Subscribers
+	evChannel   <-chan events.Event
 }
 
 // NewSSE returns a service implementation for Service.
-func NewSSE(c *config.Config, l log.Logger, ch <-chan events.Event, mux *chi.Mux) (SSE, error) {
+func NewSSE(c *config.Config, l log.Logger, ch <-chan events.Event, mux *chi.Mux, backlog *Backlog) (SSE, error) {
 	s := SSE{
-		c:         c,
-		l:         l,
-		m:         mux,
-		sse:       sse.New(),
-		evChannel: ch,
+		c:           c,
+		l:           l,
+		m:           mux,
+		backlog:     backlog,
+		subscribers: NewSubscribers(c.MaxConnectionsPerUser),
+		evChannel:   ch,
 	}
 	mux.Route("/ocs/v2.php/apps/notifications/api/v1/notifications", func(r chi.Router) {
 		r.Get("/sse", s.HandleSSE)
+		r.Get("/ws", s.HandleWebSocket)
 	})
 
 	go s.ListenForEvents()
@@ -54,50 +60,171 @@ func (s SSE) ListenForEvents() {
 			s.l.Error().Interface("event", ev).Msg("unhandled event")
 		case events.SendSSE:
 			for _, uid := range ev.UserIDs {
-				s.sse.Publish(uid, &sse.Event{
-					Event: []byte(ev.Type),
-					Data:  ev.Message,
+				stored, err := s.backlog.Append(uid, Event{
+					EventID: e.ID,
+					Type:    ev.Type,
+					Data:    ev.Message,
 				})
+				if err != nil {
+					// the event is still delivered to the connected clients, it just can't be replayed
+					s.l.Error().Err(err).Str("userid", uid).Msg("sse: could not add event to the backlog")
+				}
+				s.subscribers.Publish(uid, stored)
 			}
 		}
 	}
 }
 
-// HandleSSE is the GET handler for events
+// HandleSSE is the GET handler for events. Clients reconnecting with the Last-Event-ID header
+// receive the events they missed first, as long as they are still in the backlog.
 func (s SSE) HandleSSE(w http.ResponseWriter, r *http.Request) {
+	uid, ok := s.userID(w, r)
+	if !ok {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported!", http.StatusInternalServerError)
+		return
+	}
+
+	lastID, err := parseLastEventID(r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		http.Error(w, "Last-Event-ID must be a number!", http.StatusBadRequest)
+		return
+	}
+
+	// subscribe before reading the backlog, so no event gets lost in between
+	sub, ok := s.subscribers.Subscribe(uid)
+	if !ok {
+		http.Error(w, "Too many connections", http.StatusTooManyRequests)
+		return
+	}
+	defer s.subscribers.Unsubscribe(uid, sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	s.stream(r.Context(), uid, sub, lastID, sseWriter{w: w, flusher: flusher})
+}
+
+// eventWriter sends events to a client using one of the supported transports
+type eventWriter interface {
+	WriteEvent(ev Event) error
+	KeepAlive() error
+}
+
+// stream sends the events missed since lastID and all new events of the subscriber until
+// the context is done, the client is gone or it can't keep up.
+func (s SSE) stream(ctx context.Context, uid string, sub *Subscriber, lastID uint64, ew eventWriter) {
+	if lastID != 0 {
+		missed, latestID, err := s.backlog.Since(uid, lastID)
+		if err != nil {
+			s.l.Error().Err(err).Str("userid", uid).Msg("sse: could not read the backlog")
+		} else {
+			// events up to the latest id were either missed or dropped from the backlog already,
+			// newer events are sent below
+			lastID = latestID
+		}
+		for _, ev := range missed {
+			if err := ew.WriteEvent(ev); err != nil {
+				return
+			}
+		}
+	}
+
+	var heartbeat <-chan time.Time
+	if s.c.KeepAliveInterval != 0 {
+		ticker := time.NewTicker(s.c.KeepAliveInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-sub.events:
+			if !ok {
+				// the client was too slow, it has to reconnect and catch up via Last-Event-ID
+				return
+			}
+			if ev.ID != 0 && ev.ID <= lastID {
+				continue // already sent from the backlog
+			}
+			if err := ew.WriteEvent(ev); err != nil {
+				return
+			}
+		case <-heartbeat:
+			if err := ew.KeepAlive(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// userID returns the id of the user in the request context, it responds with an error if there is none
+func (s SSE) userID(w http.ResponseWriter, r *http.Request) (string, bool) {
 	u, ok := revactx.ContextGetUser(r.Context())
 	if !ok {
 		s.l.Error().Msg("sse: no user in context")
 		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return "", false
 	}
 
 	uid := u.GetId().GetOpaqueId()
 	if uid == "" {
 		s.l.Error().Msg("sse: user in context is broken")
 		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return "", false
 	}
+	return uid, true
+}
 
-	stream := s.sse.CreateStream(uid)
-	stream.AutoReplay = false
+// parseLastEventID parses the id of the last event a client received, an empty id means none
+func parseLastEventID(id string) (uint64, error) {
+	if id == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(id, 10, 64)
+}
 
-	if s.c.KeepAliveInterval != 0 {
-		ticker := time.NewTicker(s.c.KeepAliveInterval)
-		defer ticker.Stop()
-		go func() {
-			for range ticker.C {
-				s.sse.Publish(uid, &sse.Event{
-					Comment: []byte("keepalive"),
-				})
-			}
-		}()
+// sseWriter sends the events in the text/event-stream format
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// WriteEvent writes the event and flushes it to the client
+func (sw sseWriter) WriteEvent(ev Event) error {
+	var buf bytes.Buffer
+	if ev.ID != 0 {
+		fmt.Fprintf(&buf, "id: %d\n", ev.ID)
+	}
+	if ev.Type != "" {
+		fmt.Fprintf(&buf, "event: %s\n", ev.Type)
+	}
+	for _, line := range bytes.Split(ev.Data, []byte("\n")) {
+		fmt.Fprintf(&buf, "data: %s\n", line)
 	}
+	buf.WriteString("\n")
 
-	// add stream to URL
-	q := r.URL.Query()
-	q.Set("stream", uid)
-	r.URL.RawQuery = q.Encode()
+	if _, err := sw.w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	sw.flusher.Flush()
+	return nil
+}
 
-	s.sse.ServeHTTP(w, r)
+// KeepAlive writes a comment, which is ignored by the clients
+func (sw sseWriter) KeepAlive() error {
+	if _, err := sw.w.Write([]byte(": keepalive\n\n")); err != nil {
+		return err
+	}
+	sw.flusher.Flush()
+	return nil
 }
@@ -0,0 +1,288 @@
+package service_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	user "github.com/cs3org/go-cs3apis/cs3/identity/user/v1beta1"
+	"github.com/go-chi/chi/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	revactx "github.com/opencloud-eu/reva/v2/pkg/ctx"
+	"github.com/opencloud-eu/reva/v2/pkg/events"
+	"golang.org/x/net/websocket"
+
+	"github.com/opencloud-eu/opencloud/pkg/kv"
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/sse/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/sse/pkg/service"
+)
+
+var _ = Describe("Backlog", func() {
+	var backlog *service.Backlog
+
+	BeforeEach(func() {
+		backlog = service.NewBacklog(kv.NewMemoryStore(time.Hour), 2)
+	})
+
+	It("assigns increasing ids", func() {
+		first, err := backlog.Append("user", service.Event{EventID: "1"})
+		Expect(err).ToNot(HaveOccurred())
+		second, err := backlog.Append("user", service.Event{EventID: "2"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(second.ID).To(BeNumerically(">", first.ID))
+	})
+
+	It("stores every event only once", func() {
+		first, err := backlog.Append("user", service.Event{EventID: "1"})
+		Expect(err).ToNot(HaveOccurred())
+		again, err := backlog.Append("user", service.Event{EventID: "1"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(again.ID).To(Equal(first.ID))
+		evs, _, err := backlog.Since("user", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(evs).To(HaveLen(1))
+	})
+
+	It("keeps the configured number of events", func() {
+		for i := range 3 {
+			_, err := backlog.Append("user", service.Event{EventID: strconv.Itoa(i)})
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		evs, _, err := backlog.Since("user", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(evs).To(HaveLen(2))
+		Expect(evs[0].EventID).To(Equal("1"))
+		Expect(evs[1].EventID).To(Equal("2"))
+	})
+
+	It("agrees on the ids with other instances sharing the store", func() {
+		st := kv.NewMemoryStore(time.Hour)
+		instances := []*service.Backlog{service.NewBacklog(st, 10), service.NewBacklog(st, 10)}
+
+		// every instance receives every event
+		var wg sync.WaitGroup
+		ids := make([][]uint64, len(instances))
+		for i, b := range instances {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for j := range 5 {
+					ev, err := b.Append("user", service.Event{EventID: strconv.Itoa(j)})
+					Expect(err).ToNot(HaveOccurred())
+					ids[i] = append(ids[i], ev.ID)
+				}
+			}()
+		}
+		wg.Wait()
+
+		Expect(ids[0]).To(Equal(ids[1]))
+		evs, latest, err := instances[0].Since("user", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(evs).To(HaveLen(5))
+		Expect(latest).To(Equal(uint64(5)))
+	})
+
+	It("replays everything if the client's id is newer than the backlog", func() {
+		_, err := backlog.Append("user", service.Event{EventID: "1"})
+		Expect(err).ToNot(HaveOccurred())
+
+		evs, latest, err := backlog.Since("user", 42)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(evs).To(HaveLen(1))
+		Expect(latest).To(Equal(uint64(1)))
+	})
+
+	It("stores nothing if the backlog is disabled", func() {
+		st := kv.NewMemoryStore(time.Hour)
+		disabled := service.NewBacklog(st, 0)
+
+		ev, err := disabled.Append("user", service.Event{EventID: "1"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ev.ID).To(BeZero())
+		Expect(st.Keys("")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("SSE", func() {
+	var (
+		backlog *service.Backlog
+		server  *httptest.Server
+		ch      chan events.Event
+	)
+
+	connect := func(ctx context.Context, lastEventID string) *http.Response {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/ocs/v2.php/apps/notifications/api/v1/notifications/sse", nil)
+		Expect(err).ToNot(HaveOccurred())
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		res, err := http.DefaultClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(res.Body.Close)
+		return res
+	}
+
+	readEvent := func(r *bufio.Reader) string {
+		var ev strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			Expect(err).ToNot(HaveOccurred())
+			if line == "\n" {
+				return ev.String()
+			}
+			ev.WriteString(line)
+		}
+	}
+
+	BeforeEach(func() {
+		backlog = service.NewBacklog(kv.NewMemoryStore(time.Hour), 10)
+		ch = make(chan events.Event)
+
+		cfg := &config.Config{MaxConnectionsPerUser: 1}
+		cfg.HTTP.CORS.AllowedOrigins = []string{"https://cloud.example.com"}
+		sse, err := service.NewSSE(cfg, log.NopLogger(), ch, chi.NewMux(), backlog)
+		Expect(err).ToNot(HaveOccurred())
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := revactx.ContextSetUser(r.Context(), &user.User{Id: &user.UserId{OpaqueId: "user"}})
+			sse.ServeHTTP(w, r.WithContext(ctx))
+		}))
+		DeferCleanup(func() {
+			server.CloseClientConnections()
+			server.Close()
+			close(ch)
+		})
+	})
+
+	It("replays the events after the Last-Event-ID", func() {
+		first, err := backlog.Append("user", service.Event{EventID: "1", Type: "postprocessing-finished", Data: []byte(`{"id":"1"}`)})
+		Expect(err).ToNot(HaveOccurred())
+		second, err := backlog.Append("user", service.Event{EventID: "2", Type: "postprocessing-finished", Data: []byte(`{"id":"2"}`)})
+		Expect(err).ToNot(HaveOccurred())
+
+		res := connect(context.Background(), strconv.FormatUint(first.ID, 10))
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		Expect(res.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+		Expect(readEvent(bufio.NewReader(res.Body))).To(Equal("id: " + strconv.FormatUint(second.ID, 10) + "\nevent: postprocessing-finished\ndata: {\"id\":\"2\"}\n"))
+	})
+
+	It("sends new events to connected clients", func() {
+		res := connect(context.Background(), "")
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		ch <- events.Event{ID: "1", Event: events.SendSSE{UserIDs: []string{"user"}, Type: "file-touched", Message: []byte("{}")}}
+
+		Expect(readEvent(bufio.NewReader(res.Body))).To(MatchRegexp(`^id: \d+\nevent: file-touched\ndata: {}\n$`))
+	})
+
+	It("rejects connections over the limit", func() {
+		res := connect(context.Background(), "")
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		res = connect(context.Background(), "")
+		Expect(res.StatusCode).To(Equal(http.StatusTooManyRequests))
+	})
+
+	It("rejects an invalid Last-Event-ID", func() {
+		res := connect(context.Background(), "abc")
+		Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
+
+var _ = Describe("WebSocket", func() {
+	var (
+		backlog *service.Backlog
+		server  *httptest.Server
+		ch      chan events.Event
+	)
+
+	dial := func(origin, lastEventID string) (*websocket.Conn, error) {
+		cfg, err := websocket.NewConfig("ws"+strings.TrimPrefix(server.URL, "http")+"/ocs/v2.php/apps/notifications/api/v1/notifications/ws", origin)
+		Expect(err).ToNot(HaveOccurred())
+		if lastEventID != "" {
+			cfg.Header.Set("Last-Event-ID", lastEventID)
+		}
+		conn, err := websocket.DialConfig(cfg)
+		if err == nil {
+			DeferCleanup(conn.Close)
+		}
+		return conn, err
+	}
+
+	receive := func(conn *websocket.Conn) map[string]any {
+		var msg map[string]any
+		Expect(conn.SetReadDeadline(time.Now().Add(5 * time.Second))).To(Succeed())
+		Expect(websocket.JSON.Receive(conn, &msg)).To(Succeed())
+		return msg
+	}
+
+	BeforeEach(func() {
+		backlog = service.NewBacklog(kv.NewMemoryStore(time.Hour), 10)
+		ch = make(chan events.Event)
+
+		cfg := &config.Config{MaxConnectionsPerUser: 1}
+		cfg.HTTP.CORS.AllowedOrigins = []string{"https://cloud.example.com"}
+		sse, err := service.NewSSE(cfg, log.NopLogger(), ch, chi.NewMux(), backlog)
+		Expect(err).ToNot(HaveOccurred())
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := revactx.ContextSetUser(r.Context(), &user.User{Id: &user.UserId{OpaqueId: "user"}})
+			sse.ServeHTTP(w, r.WithContext(ctx))
+		}))
+		DeferCleanup(func() {
+			server.CloseClientConnections()
+			server.Close()
+			close(ch)
+		})
+	})
+
+	It("sends the missed and new events as JSON messages", func() {
+		first, err := backlog.Append("user", service.Event{EventID: "1", Type: "postprocessing-finished", Data: []byte(`{"id":"1"}`)})
+		Expect(err).ToNot(HaveOccurred())
+		second, err := backlog.Append("user", service.Event{EventID: "2", Type: "postprocessing-finished", Data: []byte(`{"id":"2"}`)})
+		Expect(err).ToNot(HaveOccurred())
+
+		conn, err := dial("https://cloud.example.com", strconv.FormatUint(first.ID, 10))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(receive(conn)).To(Equal(map[string]any{
+			"id":    float64(second.ID),
+			"event": "postprocessing-finished",
+			"data":  `{"id":"2"}`,
+		}))
+
+		ch <- events.Event{ID: "3", Event: events.SendSSE{UserIDs: []string{"user"}, Type: "file-touched", Message: []byte("{}")}}
+
+		msg := receive(conn)
+		Expect(msg["event"]).To(Equal("file-touched"))
+		Expect(msg["data"]).To(Equal("{}"))
+		Expect(msg["id"]).To(BeNumerically(">", second.ID))
+	})
+
+	It("shares the connection limit with SSE", func() {
+		_, err := dial("https://cloud.example.com", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		res, err := http.Get(server.URL + "/ocs/v2.php/apps/notifications/api/v1/notifications/sse")
+		Expect(err).ToNot(HaveOccurred())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusTooManyRequests))
+	})
+
+	It("rejects origins which are not allowed", func() {
+		_, err := dial("https://evil.example.com", "")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"sync"
+)
+
+// _subscriberBuffer is the number of events buffered for a connection before it is dropped
+const _subscriberBuffer = 64
+
+// Subscriber is a single connection of a user
+type Subscriber struct {
+	events chan Event
+}
+
+// Subscribers keeps track of the connections of all users
+type Subscribers struct {
+	limit int
+
+	mu   sync.Mutex
+	subs map[string]map[*Subscriber]struct{}
+}
+
+// NewSubscribers returns a new Subscribers instance which allows up to limit connections per user, 0 means unlimited.
+func NewSubscribers(limit int) *Subscribers {
+	return &Subscribers{
+		limit: limit,
+		subs:  make(map[string]map[*Subscriber]struct{}),
+	}
+}
+
+// Subscribe adds a new connection for the user, it returns false if the user reached the connection limit.
+func (s *Subscribers) Subscribe(userID string) (*Subscriber, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.limit > 0 && len(s.subs[userID]) >= s.limit {
+		return nil, false
+	}
+
+	sub := &Subscriber{events: make(chan Event, _subscriberBuffer)}
+	if s.subs[userID] == nil {
+		s.subs[userID] = make(map[*Subscriber]struct{})
+	}
+	s.subs[userID][sub] = struct{}{}
+	return sub, true
+}
+
+// Unsubscribe removes the connection of the user
+func (s *Subscribers) Unsubscribe(userID string, sub *Subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(userID, sub)
+}
+
+// Count returns the number of connections of the user
+func (s *Subscribers) Count(userID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.subs[userID])
+}
+
+// Publish sends the event to all connections of the user. Connections which can't keep up
+// are closed, the clients reconnect and receive the missed events from the backlog.
+func (s *Subscribers) Publish(userID string, ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subs[userID] {
+		select {
+		case sub.events <- ev:
+		default:
+			s.remove(userID, sub)
+		}
+	}
+}
+
+// remove closes and removes the connection, the caller has to hold the lock
+func (s *Subscribers) remove(userID string, sub *Subscriber) {
+	if _, ok := s.subs[userID][sub]; !ok {
+		return
+	}
+
+	close(sub.events)
+	delete(s.subs[userID], sub)
+	if len(s.subs[userID]) == 0 {
+		delete(s.subs, userID)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// _wsWriteTimeout is the time a WebSocket client has to accept a message before the connection is closed
+const _wsWriteTimeout = 10 * time.Second
+
+// ErrOriginNotAllowed is returned when a browser opens a WebSocket from an origin which is not allowed by the CORS settings
+var ErrOriginNotAllowed = errors.New("origin not allowed")
+
+// wsMessage is the JSON representation of an event sent over WebSockets
+type wsMessage struct {
+	ID    uint64 `json:"id,omitempty"`
+	Event string `json:"event"`
+	Data  string `json:"data"`
+}
+
+// HandleWebSocket is the GET handler for clients which can't use SSE, e.g. because a proxy buffers the stream.
+// It sends the same events as JSON text messages. Clients reconnecting with the Last-Event-ID header or the
+// lastEventId query parameter receive the events they missed first, as long as they are still in the backlog.
+func (s SSE) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	uid, ok := s.userID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, ok := w.(http.Hijacker); !ok {
+		http.Error(w, "WebSockets unsupported!", http.StatusInternalServerError)
+		return
+	}
+
+	id := r.Header.Get("Last-Event-ID")
+	if id == "" {
+		id = r.URL.Query().Get("lastEventId")
+	}
+	lastID, err := parseLastEventID(id)
+	if err != nil {
+		http.Error(w, "Last-Event-ID must be a number!", http.StatusBadRequest)
+		return
+	}
+
+	// subscribe before the upgrade, so clients over the limit get a proper status code
+	sub, ok := s.subscribers.Subscribe(uid)
+	if !ok {
+		http.Error(w, "Too many connections", http.StatusTooManyRequests)
+		return
+	}
+	defer s.subscribers.Unsubscribe(uid, sub)
+
+	websocket.Server{
+		Handshake: s.checkOrigin,
+		Handler: func(conn *websocket.Conn) {
+			// the connection is hijacked, so the request context is not canceled when the client goes away
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+
+			go func() {
+				defer cancel()
+				// clients are not expected to send anything, reading handles pings and the close frame
+				_, _ = io.Copy(io.Discard, conn)
+			}()
+
+			s.stream(ctx, uid, sub, lastID, wsWriter{conn: conn})
+		},
+	}.ServeHTTP(w, r)
+}
+
+// checkOrigin rejects WebSockets opened by browsers on origins which are not allowed by the CORS settings.
+// Other clients don't send an Origin header.
+func (s SSE) checkOrigin(_ *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	allowed := s.c.HTTP.CORS.AllowedOrigins
+	if slices.Contains(allowed, "*") || slices.Contains(allowed, origin) {
+		return nil
+	}
+	return ErrOriginNotAllowed
+}
+
+// wsWriter sends the events as WebSocket messages
+type wsWriter struct {
+	conn *websocket.Conn
+}
+
+// WriteEvent sends the event as JSON text message
+func (ww wsWriter) WriteEvent(ev Event) error {
+	msg, err := json.Marshal(wsMessage{
+		ID:    ev.ID,
+		Event: ev.Type,
+		Data:  string(ev.Data),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := ww.conn.SetWriteDeadline(time.Now().Add(_wsWriteTimeout)); err != nil {
+		return err
+	}
+	_, err = ww.conn.Write(msg)
+	return err
+}
+
+// KeepAlive sends a ping frame, which is answered by the clients automatically
+func (ww wsWriter) KeepAlive() error {
+	if err := ww.conn.SetWriteDeadline(time.Now().Add(_wsWriteTimeout)); err != nil {
+		return err
+	}
+
+	ww.conn.PayloadType = websocket.PingFrame
+	defer func() { ww.conn.PayloadType = websocket.TextFrame }()
+	_, err := ww.conn.Write(nil)
+	return err
+}
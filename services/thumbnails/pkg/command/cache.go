@@ -0,0 +1,197 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/opencloud-eu/reva/v2/pkg/bytesize"
+	"github.com/urfave/cli/v2"
+
+	"github.com/opencloud-eu/opencloud/pkg/config/configlog"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config/parser"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/logging"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
+)
+
+// Cache wraps the thumbnail cache related sub-commands.
+func Cache(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "manage the thumbnail cache",
+		Before: func(c *cli.Context) error {
+			return configlog.ReturnFatal(parser.ParseConfig(cfg))
+		},
+		Subcommands: []*cli.Command{
+			cacheStats(cfg),
+			cachePrune(cfg),
+			cachePurge(cfg),
+		},
+	}
+}
+
+func cacheStats(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "stats",
+		Usage: "Print the number and the total size of the cached thumbnails",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output as json",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			cache, err := openCache(cfg)
+			if err != nil {
+				return err
+			}
+
+			stats, err := storage.GetStats(c.Context, cache)
+			if err != nil {
+				return err
+			}
+
+			if c.Bool("json") {
+				return printJSON(stats)
+			}
+
+			table := tablewriter.NewTable(os.Stdout, tablewriter.WithHeaderAutoFormat(tw.Off))
+			table.Header([]string{"Storage", "Thumbnails", "Size", "Oldest access", "Newest access"})
+			_ = table.Append([]string{
+				cfg.Thumbnail.Storage,
+				strconv.Itoa(stats.Count),
+				bytesize.ByteSize(stats.Size).String(),
+				formatTime(stats.Oldest),
+				formatTime(stats.Newest),
+			})
+			return table.Render()
+		},
+	}
+}
+
+func cachePrune(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "prune",
+		Usage: "Remove thumbnails which exceed the given limits, defaults to the configured storage limits",
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "older-than",
+				Usage: "remove thumbnails which were not used within the given duration, e.g. 720h",
+			},
+			&cli.StringFlag{
+				Name:  "max-size",
+				Usage: "remove the least recently used thumbnails until the cache fits the given size, e.g. 2GB",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "only print the thumbnails which would be removed",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output as json",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			eviction := cfg.Thumbnail.StorageEviction
+			if c.IsSet("older-than") {
+				eviction.TTL = c.Duration("older-than")
+			}
+			if c.IsSet("max-size") {
+				eviction.MaxSize = c.String("max-size")
+			}
+
+			limits, err := storage.NewLimits(eviction)
+			if err != nil {
+				return fmt.Errorf("invalid max size: %w", err)
+			}
+			if !limits.Enabled() {
+				return errors.New("no limits given, use --older-than or --max-size or configure the storage limits")
+			}
+
+			cache, err := openCache(cfg)
+			if err != nil {
+				return err
+			}
+
+			evicted, err := storage.Evict(c.Context, cache, limits, c.Bool("dry-run"))
+			if err != nil {
+				return err
+			}
+
+			if c.Bool("json") {
+				return printJSON(evicted)
+			}
+
+			var size uint64
+			for _, e := range evicted {
+				size += e.Size
+			}
+
+			verb := "Removed"
+			if c.Bool("dry-run") {
+				verb = "Would remove"
+			}
+			fmt.Printf("%s %d thumbnails (%s)\n", verb, len(evicted), bytesize.ByteSize(size))
+			return nil
+		},
+	}
+}
+
+func cachePurge(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "purge",
+		Usage: "Remove all cached thumbnails",
+		Action: func(c *cli.Context) error {
+			cache, err := openCache(cfg)
+			if err != nil {
+				return err
+			}
+
+			count, err := storage.Purge(c.Context, cache)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Removed %d thumbnails\n", count)
+			return nil
+		},
+	}
+}
+
+// openCache opens the configured thumbnail storage for maintenance.
+func openCache(cfg *config.Config) (storage.Cache, error) {
+	logger := logging.Configure(cfg.Service.Name, cfg.Log)
+
+	s, err := storage.New(cfg.Thumbnail, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, ok := s.(storage.Cache)
+	if !ok {
+		return nil, fmt.Errorf("the thumbnail storage '%s' does not support cache maintenance", cfg.Thumbnail.Storage)
+	}
+	return cache, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func printJSON(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
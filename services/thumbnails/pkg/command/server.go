@@ -16,6 +16,7 @@ import (
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/server/debug"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/server/grpc"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/server/http"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
 	"github.com/urfave/cli/v2"
 )
 
@@ -50,6 +51,12 @@ func Server(cfg *config.Config) *cli.Command {
 
 			m.BuildInfo.WithLabelValues(version.GetString()).Set(1)
 
+			thumbnailStorage, err := storage.New(cfg.Thumbnail, logger)
+			if err != nil {
+				logger.Error().Err(err).Msg("Failed to initialize thumbnail storage")
+				return err
+			}
+
 			service := grpc.NewService(
 				grpc.Logger(logger),
 				grpc.Context(ctx),
@@ -60,6 +67,7 @@ func Server(cfg *config.Config) *cli.Command {
 				grpc.Metrics(m),
 				grpc.TraceProvider(traceProvider),
 				grpc.MaxConcurrentRequests(cfg.GRPC.MaxConcurrentRequests),
+				grpc.ThumbnailStorage(thumbnailStorage),
 			)
 
 			gr.Add(service.Run, func(_ error) {
@@ -100,6 +108,7 @@ func Server(cfg *config.Config) *cli.Command {
 				http.Metrics(m),
 				http.Namespace(cfg.HTTP.Namespace),
 				http.TraceProvider(traceProvider),
+				http.ThumbnailStorage(thumbnailStorage),
 			)
 			if err != nil {
 				logger.Info().
@@ -126,6 +135,23 @@ func Server(cfg *config.Config) *cli.Command {
 				cancel()
 			})
 
+			limits, err := storage.NewLimits(cfg.Thumbnail.StorageEviction)
+			if err != nil {
+				logger.Error().Err(err).Msg("Failed to parse the thumbnail storage limits")
+				return err
+			}
+
+			// the limits are enforced by listing and removing the thumbnails
+			cache, ok := thumbnailStorage.(storage.Cache)
+			if ok && limits.Enabled() && cfg.Thumbnail.StorageEviction.Interval > 0 {
+				evictor := storage.NewCacheEvictor(cache, limits, logger)
+				gr.Add(func() error {
+					return storage.RunEviction(ctx, evictor, cfg.Thumbnail.StorageEviction.Interval, logger)
+				}, func(_ error) {
+					cancel()
+				})
+			}
+
 			return gr.Run()
 		},
 	}
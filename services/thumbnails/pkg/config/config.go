@@ -3,6 +3,7 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"github.com/opencloud-eu/opencloud/pkg/shared"
 	"go-micro.dev/v4/client"
@@ -34,10 +35,53 @@ type FileSystemStorage struct {
 	RootDirectory string `yaml:"root_directory" env:"THUMBNAILS_FILESYSTEMSTORAGE_ROOT" desc:"The directory where the filesystem storage will store the thumbnails. If not defined, the root directory derives from $OC_BASE_DATA_PATH/thumbnails." introductionVersion:"1.0.0"`
 }
 
+// S3Storage defines the available s3 storage configuration.
+type S3Storage struct {
+	Endpoint  string `yaml:"endpoint" env:"THUMBNAILS_S3STORAGE_ENDPOINT" desc:"Endpoint of the S3 compatible object storage, e.g. 'https://s3.example.com'." introductionVersion:"%%NEXT%%"`
+	Region    string `yaml:"region" env:"THUMBNAILS_S3STORAGE_REGION" desc:"Region of the S3 bucket." introductionVersion:"%%NEXT%%"`
+	AccessKey string `yaml:"access_key" env:"THUMBNAILS_S3STORAGE_ACCESS_KEY" desc:"Access key for the S3 bucket." introductionVersion:"%%NEXT%%"`
+	SecretKey string `yaml:"secret_key" env:"THUMBNAILS_S3STORAGE_SECRET_KEY" desc:"Secret key for the S3 bucket." introductionVersion:"%%NEXT%%"`
+	Bucket    string `yaml:"bucket" env:"THUMBNAILS_S3STORAGE_BUCKET" desc:"Name of the S3 bucket. The bucket must exist." introductionVersion:"%%NEXT%%"`
+	Prefix    string `yaml:"prefix" env:"THUMBNAILS_S3STORAGE_PREFIX" desc:"Prefix for all thumbnail objects. Allows sharing the bucket with other applications." introductionVersion:"%%NEXT%%"`
+	Insecure  bool   `yaml:"insecure" env:"OC_INSECURE;THUMBNAILS_S3STORAGE_INSECURE" desc:"Ignore untrusted SSL certificates when connecting to the S3 endpoint." introductionVersion:"%%NEXT%%"`
+}
+
+// NATSStorage defines the available NATS JetStream object store configuration.
+type NATSStorage struct {
+	Nodes        []string `yaml:"nodes" env:"THUMBNAILS_NATSSTORAGE_NODES" desc:"A list of NATS nodes to connect to. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Bucket       string   `yaml:"bucket" env:"THUMBNAILS_NATSSTORAGE_BUCKET" desc:"Name of the object store bucket. The bucket is created if it does not exist." introductionVersion:"%%NEXT%%"`
+	AuthUsername string   `yaml:"username" env:"THUMBNAILS_NATSSTORAGE_AUTH_USERNAME" desc:"The username to authenticate with NATS." introductionVersion:"%%NEXT%%"`
+	AuthPassword string   `yaml:"password" env:"THUMBNAILS_NATSSTORAGE_AUTH_PASSWORD" desc:"The password to authenticate with NATS." introductionVersion:"%%NEXT%%"`
+}
+
+// StorageEviction defines the limits which are enforced on the thumbnail storage.
+type StorageEviction struct {
+	MaxSize  string        `yaml:"max_size" env:"THUMBNAILS_STORAGE_MAX_SIZE" desc:"The maximum total size of all cached thumbnails. The least recently used thumbnails are removed when the limit is exceeded. 0 means unlimited. Usable common abbreviations: [KB, KiB, MB, MiB, GB, GiB, TB, TiB, PB, PiB, EB, EiB], example: 2GB." introductionVersion:"%%NEXT%%"`
+	TTL      time.Duration `yaml:"ttl" env:"THUMBNAILS_STORAGE_TTL" desc:"Time to live for cached thumbnails. Thumbnails which were not used within this time are removed. 0 means thumbnails are kept forever. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Interval time.Duration `yaml:"interval" env:"THUMBNAILS_STORAGE_EVICTION_INTERVAL" desc:"The interval in which the storage limits are enforced. 0 disables the eviction. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+}
+
+// Converter defines the external converter which renders previews of documents and videos.
+type Converter struct {
+	Type             string        `yaml:"type" env:"THUMBNAILS_CONVERTER_TYPE" desc:"The type of the external converter which renders previews of documents and videos. Supported values are 'command' and 'http'. Leave empty to disable thumbnails of documents and videos." introductionVersion:"%%NEXT%%"`
+	Command          string        `yaml:"command" env:"THUMBNAILS_CONVERTER_COMMAND" desc:"The command to run when using the 'command' converter. The placeholders '{input}', '{output}' and '{mimetype}' are replaced with the path of the file, the path where the command must write the PNG image and the mimetype of the file. Arguments are separated by spaces. Example: 'ffmpeg -ss 1 -i {input} -frames:v 1 -f image2 -c:v png {output}'." introductionVersion:"%%NEXT%%"`
+	URL              string        `yaml:"url" env:"THUMBNAILS_CONVERTER_URL" desc:"The URL of the conversion service when using the 'http' converter. The file is sent as body of a POST request with its mimetype as content type. The service must respond with a PNG image." introductionVersion:"%%NEXT%%"`
+	Insecure         bool          `yaml:"insecure" env:"OC_INSECURE;THUMBNAILS_CONVERTER_INSECURE" desc:"Ignore untrusted SSL certificates when connecting to the conversion service." introductionVersion:"%%NEXT%%"`
+	MimeTypes        []string      `yaml:"mime_types" env:"THUMBNAILS_CONVERTER_MIMETYPES" desc:"The mimetypes of the files which are converted with the external converter. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	Timeout          time.Duration `yaml:"timeout" env:"THUMBNAILS_CONVERTER_TIMEOUT" desc:"The maximum time a single conversion may take. See the Environment Variable Types description for more details." introductionVersion:"%%NEXT%%"`
+	MaxConcurrent    int           `yaml:"max_concurrent" env:"THUMBNAILS_CONVERTER_MAX_CONCURRENT" desc:"The maximum number of conversions running at the same time. Further requests wait until a conversion finished or the timeout is reached. 0 means unlimited." introductionVersion:"%%NEXT%%"`
+	MaxInputFileSize string        `yaml:"max_input_file_size" env:"THUMBNAILS_CONVERTER_MAX_INPUT_FILE_SIZE" desc:"The maximum file size of a document or video which is converted. Usable common abbreviations: [KB, KiB, MB, MiB, GB, GiB, TB, TiB, PB, PiB, EB, EiB], example: 2GB. If empty, the value of THUMBNAILS_MAX_INPUT_IMAGE_FILE_SIZE is used." introductionVersion:"%%NEXT%%"`
+}
+
 // Thumbnail defines the available thumbnail related configuration.
 type Thumbnail struct {
 	Resolutions           []string          `yaml:"resolutions" env:"THUMBNAILS_RESOLUTIONS" desc:"The supported list of target resolutions in the format WidthxHeight like 32x32. You can define any resolution as required. See the Environment Variable Types description for more details." introductionVersion:"1.0.0"`
+	Storage               string            `yaml:"storage" env:"THUMBNAILS_STORAGE" desc:"The storage used to cache the generated thumbnails. Supported values are 'filesystem', 's3' and 'nats-js-os'. Use 's3' or 'nats-js-os' to share the cache between multiple thumbnails instances." introductionVersion:"%%NEXT%%"`
 	FileSystemStorage     FileSystemStorage `yaml:"filesystem_storage"`
+	S3Storage             S3Storage         `yaml:"s3_storage"`
+	NATSStorage           NATSStorage       `yaml:"nats_storage"`
+	StorageEviction       StorageEviction   `yaml:"storage_eviction"`
+	Converter             Converter         `yaml:"converter"`
 	WebdavAllowInsecure   bool              `yaml:"webdav_allow_insecure" env:"OC_INSECURE;THUMBNAILS_WEBDAVSOURCE_INSECURE" desc:"Ignore untrusted SSL certificates when connecting to the webdav source." introductionVersion:"1.0.0"`
 	CS3AllowInsecure      bool              `yaml:"cs3_allow_insecure" env:"OC_INSECURE;THUMBNAILS_CS3SOURCE_INSECURE" desc:"Ignore untrusted SSL certificates when connecting to the CS3 source." introductionVersion:"1.0.0"`
 	RevaGateway           string            `yaml:"reva_gateway" env:"OC_REVA_GATEWAY" desc:"CS3 gateway used to look up user metadata" introductionVersion:"1.0.0"`
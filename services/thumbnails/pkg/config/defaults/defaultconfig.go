@@ -3,6 +3,7 @@ package defaults
 import (
 	"path"
 	"strings"
+	"time"
 
 	"github.com/opencloud-eu/opencloud/pkg/config/defaults"
 	"github.com/opencloud-eu/opencloud/pkg/shared"
@@ -48,9 +49,43 @@ func DefaultConfig() *config.Config {
 		},
 		Thumbnail: config.Thumbnail{
 			Resolutions: []string{"16x16", "32x32", "64x64", "128x128", "1080x1920", "1920x1080", "2160x3840", "3840x2160", "4320x7680", "7680x4320"},
+			Storage:     "filesystem",
 			FileSystemStorage: config.FileSystemStorage{
 				RootDirectory: path.Join(defaults.BaseDataPath(), "thumbnails"),
 			},
+			S3Storage: config.S3Storage{
+				Region: "default",
+				Bucket: "thumbnails",
+			},
+			NATSStorage: config.NATSStorage{
+				Nodes:  []string{"127.0.0.1:9233"},
+				Bucket: "thumbnails",
+			},
+			StorageEviction: config.StorageEviction{
+				MaxSize:  "0",
+				Interval: time.Hour,
+			},
+			Converter: config.Converter{
+				MimeTypes: []string{
+					"application/pdf",
+					"application/vnd.oasis.opendocument.text",
+					"application/vnd.oasis.opendocument.spreadsheet",
+					"application/vnd.oasis.opendocument.presentation",
+					"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+					"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+					"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+					"application/msword",
+					"application/vnd.ms-excel",
+					"application/vnd.ms-powerpoint",
+					"video/mp4",
+					"video/webm",
+					"video/quicktime",
+					"video/x-matroska",
+				},
+				Timeout:          30 * time.Second,
+				MaxConcurrent:    2,
+				MaxInputFileSize: "1GB",
+			},
 			WebdavAllowInsecure:   false,
 			RevaGateway:           shared.DefaultRevaConfig().Address,
 			CS3AllowInsecure:      false,
@@ -105,4 +140,7 @@ func Sanitize(cfg *config.Config) {
 	if len(cfg.Thumbnail.Resolutions) == 1 && strings.Contains(cfg.Thumbnail.Resolutions[0], ",") {
 		cfg.Thumbnail.Resolutions = strings.Split(cfg.Thumbnail.Resolutions[0], ",")
 	}
+	if len(cfg.Thumbnail.Converter.MimeTypes) == 1 && strings.Contains(cfg.Thumbnail.Converter.MimeTypes[0], ",") {
+		cfg.Thumbnail.Converter.MimeTypes = strings.Split(cfg.Thumbnail.Converter.MimeTypes[0], ",")
+	}
 }
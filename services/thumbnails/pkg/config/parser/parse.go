@@ -2,6 +2,7 @@ package parser
 
 import (
 	"errors"
+	"fmt"
 
 	occfg "github.com/opencloud-eu/opencloud/pkg/config"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
@@ -34,6 +35,19 @@ func ParseConfig(cfg *config.Config) error {
 }
 
 // Validate can validate the configuration
-func Validate(_ *config.Config) error {
+func Validate(cfg *config.Config) error {
+	switch c := cfg.Thumbnail.Converter; c.Type {
+	case "":
+	case "command":
+		if c.Command == "" {
+			return errors.New("the command of the thumbnail converter is not configured")
+		}
+	case "http":
+		if c.URL == "" {
+			return errors.New("the url of the thumbnail converter is not configured")
+		}
+	default:
+		return fmt.Errorf("unknown thumbnail converter: %s", c.Type)
+	}
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/metrics"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -23,6 +24,7 @@ type Options struct {
 	Namespace             string
 	TraceProvider         trace.TracerProvider
 	MaxConcurrentRequests int
+	ThumbnailStorage      storage.Storage
 }
 
 // newOptions initializes the available default options.
@@ -98,3 +100,10 @@ func MaxConcurrentRequests(val int) Option {
 		o.MaxConcurrentRequests = val
 	}
 }
+
+// ThumbnailStorage provides a function to set the ThumbnailStorage option.
+func ThumbnailStorage(val storage.Storage) Option {
+	return func(o *Options) {
+		o.ThumbnailStorage = val
+	}
+}
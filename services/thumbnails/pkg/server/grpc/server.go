@@ -8,8 +8,8 @@ import (
 	thumbnailssvc "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/services/thumbnails/v0"
 	svc "github.com/opencloud-eu/opencloud/services/thumbnails/pkg/service/grpc/v0"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/service/grpc/v0/decorators"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/converter"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/imgsource"
-	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
 	"github.com/opencloud-eu/reva/v2/pkg/bytesize"
 	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
 )
@@ -63,20 +63,22 @@ func NewService(opts ...Option) grpc.Service {
 		return grpc.Service{}
 	}
 
+	conv, err := converter.New(tconf.Converter)
+	if err != nil {
+		options.Logger.Error().Err(err).Msg("could not create thumbnail converter")
+		return grpc.Service{}
+	}
+
 	var thumbnail decorators.DecoratedService
 	{
 		thumbnail = svc.NewService(
 			svc.Config(options.Config),
 			svc.Logger(options.Logger),
 			svc.ThumbnailSource(imgsource.NewWebDavSource(tconf, b)),
-			svc.ThumbnailStorage(
-				storage.NewFileSystemStorage(
-					tconf.FileSystemStorage,
-					options.Logger,
-				),
-			),
+			svc.ThumbnailStorage(options.ThumbnailStorage),
 			svc.CS3Source(imgsource.NewCS3Source(tconf, gatewaySelector, b)),
 			svc.GatewaySelector(gatewaySelector),
+			svc.Converter(conv),
 		)
 		thumbnail = decorators.NewInstrument(thumbnail, options.Metrics)
 		thumbnail = decorators.NewLogging(thumbnail, options.Logger)
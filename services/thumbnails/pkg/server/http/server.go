@@ -9,7 +9,6 @@ import (
 	"github.com/opencloud-eu/opencloud/pkg/service/http"
 	"github.com/opencloud-eu/opencloud/pkg/version"
 	svc "github.com/opencloud-eu/opencloud/services/thumbnails/pkg/service/http/v0"
-	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
 	"go-micro.dev/v4"
 )
 
@@ -53,12 +52,7 @@ func Server(opts ...Option) (http.Service, error) {
 			),
 			opencloudmiddleware.Logger(options.Logger),
 		),
-		svc.ThumbnailStorage(
-			storage.NewFileSystemStorage(
-				options.Config.Thumbnail.FileSystemStorage,
-				options.Logger,
-			),
-		),
+		svc.ThumbnailStorage(options.ThumbnailStorage),
 	)
 
 	{
@@ -6,6 +6,7 @@ import (
 	gateway "github.com/cs3org/go-cs3apis/cs3/gateway/v1beta1"
 	"github.com/opencloud-eu/opencloud/pkg/log"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/converter"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/imgsource"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
 	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
@@ -23,6 +24,7 @@ type Options struct {
 	ImageSource      imgsource.Source
 	CS3Source        imgsource.Source
 	GatewaySelector  pool.Selectable[gateway.GatewayAPIClient]
+	Converter        converter.Converter
 }
 
 // newOptions initializes the available default options.
@@ -77,3 +79,10 @@ func GatewaySelector(val pool.Selectable[gateway.GatewayAPIClient]) Option {
 		o.GatewaySelector = val
 	}
 }
+
+// Converter provides a function to set the converter for documents and videos
+func Converter(val converter.Converter) Option {
+	return func(o *Options) {
+		o.Converter = val
+	}
+}
@@ -2,6 +2,7 @@ package svc
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
@@ -12,6 +13,7 @@ import (
 	rpc "github.com/cs3org/go-cs3apis/cs3/rpc/v1beta1"
 	provider "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/opencloud-eu/reva/v2/pkg/bytesize"
 	revactx "github.com/opencloud-eu/reva/v2/pkg/ctx"
 	"github.com/opencloud-eu/reva/v2/pkg/rgrpc/todo/pool"
 	"github.com/opencloud-eu/reva/v2/pkg/storagespace"
@@ -27,7 +29,9 @@ import (
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/service/grpc/v0/decorators"
 	tjwt "github.com/opencloud-eu/opencloud/services/thumbnails/pkg/service/jwt"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/converter"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/imgsource"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
 )
 
 // NewService returns a service implementation for Service.
@@ -38,6 +42,14 @@ func NewService(opts ...Option) decorators.DecoratedService {
 	if err != nil {
 		logger.Fatal().Err(err).Msg("resolutions not configured correctly")
 	}
+	var maxConvertedFileSize uint64
+	if size := options.Config.Thumbnail.Converter.MaxInputFileSize; size != "" {
+		b, err := bytesize.Parse(size)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("converter max input file size not configured correctly")
+		}
+		maxConvertedFileSize = b.Bytes()
+	}
 	svc := Thumbnail{
 		serviceID: options.Config.GRPC.Namespace + "." + options.Config.Service.Name,
 		manager: thumbnail.NewSimpleManager(
@@ -56,6 +68,10 @@ func NewService(opts ...Option) decorators.DecoratedService {
 		},
 		dataEndpoint:   options.Config.Thumbnail.DataEndpoint,
 		transferSecret: options.Config.Thumbnail.TransferSecret,
+		converter:      options.Converter,
+		storage:        options.ThumbnailStorage,
+
+		maxConvertedFileSize: maxConvertedFileSize,
 	}
 
 	return svc
@@ -72,6 +88,10 @@ type Thumbnail struct {
 	logger           log.Logger
 	selector         pool.Selectable[gateway.GatewayAPIClient]
 	preprocessorOpts PreprocessorOpts
+	converter        converter.Converter
+	storage          storage.Storage
+
+	maxConvertedFileSize uint64
 }
 
 // PreprocessorOpts holds the options for the preprocessor
@@ -123,21 +143,63 @@ func (g Thumbnail) checkThumbnail(req *thumbnailssvc.GetThumbnailRequest, sRes *
 		return "", tr, merrors.Forbidden(g.serviceID, "no download permission")
 	}
 
-	tType := thumbnail.GetExtForMime(sRes.GetInfo().GetMimeType())
-	if tType == "" {
-		tType = req.GetThumbnailType().String()
-	}
+	tType := thumbnail.GetOutputType(sRes.GetInfo().GetMimeType(), req.GetThumbnailType().String())
 	tr, err := thumbnail.PrepareRequest(int(req.GetWidth()), int(req.GetHeight()), tType, sRes.GetInfo().GetChecksum().GetSum(), req.GetProcessor())
 	if err != nil {
 		return "", tr, merrors.BadRequest(g.serviceID, "%s", err.Error())
 	}
 
+	if g.isConverted(sRes.GetInfo().GetMimeType()) {
+		tr.Generator = thumbnail.NewConverterGenerator(g.converter, g.storage, tr.Generator)
+	}
+
 	if key, exists := g.manager.CheckThumbnail(tr); exists {
 		return key, tr, nil
 	}
 	return "", tr, nil
 }
 
+// isConverted returns true if files of the mimetype are converted by the external converter
+func (g Thumbnail) isConverted(mimeType string) bool {
+	return g.converter != nil && g.converter.Supports(mimeType)
+}
+
+// sourceContext returns the context to get the file from the source. The files
+// which are converted by the external converter have their own size limit.
+func (g Thumbnail) sourceContext(ctx context.Context, mimeType string) context.Context {
+	if g.maxConvertedFileSize != 0 && g.isConverted(mimeType) {
+		return imgsource.ContextSetMaxFileSize(ctx, g.maxConvertedFileSize)
+	}
+	return ctx
+}
+
+// convert returns the input of the generator for the file
+func (g Thumbnail) convert(ctx context.Context, r io.Reader, sRes *provider.StatResponse) (interface{}, error) {
+	if g.isConverted(sRes.GetInfo().GetMimeType()) {
+		// the converter generator renders the preview when it is needed
+		return thumbnail.NewDocument(ctx, sRes.GetInfo().GetMimeType(), sRes.GetInfo().GetChecksum().GetSum(), r), nil
+	}
+
+	ppOpts := map[string]interface{}{
+		"fontFileMap": g.preprocessorOpts.TxtFontFileMap,
+	}
+	pp := preprocessor.ForType(sRes.GetInfo().GetMimeType(), ppOpts)
+	return pp.Convert(r)
+}
+
+// generate generates the thumbnail and maps the errors of the generation
+func (g Thumbnail) generate(tr thumbnail.Request, img interface{}) (string, error) {
+	key, err := g.manager.Generate(tr, img)
+	switch {
+	case errors.Is(err, terrors.ErrImageTooLarge):
+		return "", merrors.Forbidden(g.serviceID, "%s", err.Error())
+	case errors.Is(err, converter.ErrConversionFailed):
+		g.logger.Error().Err(err).Msg("could not convert file")
+		return "", merrors.InternalServerError(g.serviceID, "could not convert file")
+	}
+	return key, err
+}
+
 func (g Thumbnail) handleCS3Source(ctx context.Context, req *thumbnailssvc.GetThumbnailRequest) (string, error) {
 	src := req.GetCs3Source()
 	sRes, err := g.stat(src.GetPath(), src.GetAuthorization())
@@ -155,7 +217,7 @@ func (g Thumbnail) handleCS3Source(ctx context.Context, req *thumbnailssvc.GetTh
 	}
 
 	ctx = imgsource.ContextSetAuthorization(ctx, src.GetAuthorization())
-	r, err := g.cs3Source.Get(ctx, src.GetPath())
+	r, err := g.cs3Source.Get(g.sourceContext(ctx, sRes.GetInfo().GetMimeType()), src.GetPath())
 	switch {
 	case errors.Is(err, terrors.ErrImageTooLarge):
 		return "", merrors.Forbidden(g.serviceID, "%s", err.Error())
@@ -164,20 +226,12 @@ func (g Thumbnail) handleCS3Source(ctx context.Context, req *thumbnailssvc.GetTh
 	}
 
 	defer r.Close()
-	ppOpts := map[string]interface{}{
-		"fontFileMap": g.preprocessorOpts.TxtFontFileMap,
-	}
-	pp := preprocessor.ForType(sRes.GetInfo().GetMimeType(), ppOpts)
-	img, err := pp.Convert(r)
+	img, err := g.convert(ctx, r, sRes)
 	if img == nil || err != nil {
 		return "", merrors.InternalServerError(g.serviceID, "could not get image")
 	}
 
-	key, err = g.manager.Generate(tr, img)
-	if errors.Is(err, terrors.ErrImageTooLarge) {
-		return "", merrors.Forbidden(g.serviceID, "%s", err.Error())
-	}
-	return key, err
+	return g.generate(tr, img)
 }
 
 func (g Thumbnail) handleWebdavSource(ctx context.Context, req *thumbnailssvc.GetThumbnailRequest) (string, error) {
@@ -248,7 +302,7 @@ func (g Thumbnail) handleWebdavSource(ctx context.Context, req *thumbnailssvc.Ge
 	params.Add("expiration", expiration)
 	imgURL.RawQuery = params.Encode()
 
-	r, err := g.webdavSource.Get(ctx, imgURL.String())
+	r, err := g.webdavSource.Get(g.sourceContext(ctx, sRes.GetInfo().GetMimeType()), imgURL.String())
 	switch {
 	case errors.Is(err, terrors.ErrImageTooLarge):
 		return "", merrors.Forbidden(g.serviceID, "%s", err.Error())
@@ -256,20 +310,12 @@ func (g Thumbnail) handleWebdavSource(ctx context.Context, req *thumbnailssvc.Ge
 		return "", merrors.InternalServerError(g.serviceID, "could not get image from source: %s", err.Error())
 	}
 	defer r.Close()
-	ppOpts := map[string]interface{}{
-		"fontFileMap": g.preprocessorOpts.TxtFontFileMap,
-	}
-	pp := preprocessor.ForType(sRes.GetInfo().GetMimeType(), ppOpts)
-	img, err := pp.Convert(r)
+	img, err := g.convert(ctx, r, sRes)
 	if img == nil || err != nil {
 		return "", merrors.InternalServerError(g.serviceID, "could not get image")
 	}
 
-	key, err = g.manager.Generate(tr, img)
-	if errors.Is(err, terrors.ErrImageTooLarge) {
-		return "", merrors.Forbidden(g.serviceID, "%s", err.Error())
-	}
-	return key, err
+	return g.generate(tr, img)
 }
 
 func (g Thumbnail) stat(path, auth string) (*provider.StatResponse, error) {
@@ -319,7 +365,7 @@ func (g Thumbnail) stat(path, auth string) (*provider.StatResponse, error) {
 		g.logger.Error().Msg("resource info is missing checksum")
 		return nil, merrors.NotFound(g.serviceID, "resource info is missing a checksum")
 	}
-	if !thumbnail.IsMimeTypeSupported(rsp.GetInfo().GetMimeType()) {
+	if !thumbnail.IsMimeTypeSupported(rsp.GetInfo().GetMimeType()) && !g.isConverted(rsp.GetInfo().GetMimeType()) {
 		return nil, merrors.NotFound(g.serviceID, "Unsupported file type")
 	}
 	return rsp, nil
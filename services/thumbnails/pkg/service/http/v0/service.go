@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"path"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
@@ -104,8 +105,12 @@ func (s Thumbnails) GetThumbnail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	// the thumbnails are stored with the extension of their format
+	if mimeType := thumbnail.GetMimeForExt(path.Ext(key)); mimeType != "" {
+		w.Header().Set("Content-Type", mimeType)
+	}
 	w.Header().Set("Content-Length", strconv.Itoa(len(thumbnailBytes)))
+	w.WriteHeader(http.StatusOK)
 	if _, err = w.Write(thumbnailBytes); err != nil {
 		logger.Error().
 			Err(err).
@@ -0,0 +1,87 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// waitDelay is the time to wait for the output of the command after it was
+// killed. Commands which fork, like a headless office, might leave processes
+// behind which keep the output open.
+var waitDelay = 5 * time.Second
+
+// CommandConverter renders previews by running a local command, e.g. a
+// headless office or ffmpeg.
+type CommandConverter struct {
+	args []string
+}
+
+// NewCommandConverter creates a new CommandConverter. The arguments of the
+// command are separated by spaces. The placeholders {input}, {output} and
+// {mimetype} are replaced with the path of the file, the path where the
+// command must write the PNG image and the mimetype of the file.
+func NewCommandConverter(command string) CommandConverter {
+	return CommandConverter{args: strings.Fields(command)}
+}
+
+// Convert writes the file to a temporary directory, runs the command and
+// returns the image written by the command.
+func (c CommandConverter) Convert(ctx context.Context, r io.Reader, mimeType string) ([]byte, error) {
+	if len(c.args) == 0 {
+		return nil, fmt.Errorf("%w: no command configured", ErrConversionFailed)
+	}
+
+	dir, err := os.MkdirTemp("", "thumbnail-convert-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input")
+	output := filepath.Join(dir, "output.png")
+
+	f, err := os.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	replacer := strings.NewReplacer("{input}", input, "{output}", output, "{mimetype}", mimeType)
+	args := make([]string, len(c.args))
+	for i, arg := range c.args {
+		args[i] = replacer.Replace(arg)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+	// kill all processes started by the command when the context is done
+	setProcessGroup(cmd)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %s: %w: %s", ErrConversionFailed, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	img, err := os.ReadFile(output)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s didn't write an image: %w", ErrConversionFailed, args[0], err)
+	}
+	return img, nil
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package converter
+
+import "os/exec"
+
+// setProcessGroup does nothing, only the command itself is killed when it is
+// cancelled.
+func setProcessGroup(_ *exec.Cmd) {}
@@ -0,0 +1,109 @@
+// Package converter renders previews of documents and videos with external tools.
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+)
+
+// ErrConversionFailed is returned if the external converter couldn't render a preview
+var ErrConversionFailed = errors.New("thumbnails: conversion failed")
+
+// Converter renders a preview image of a file.
+type Converter interface {
+	// Convert reads the file and returns the preview as PNG image.
+	Convert(ctx context.Context, r io.Reader, mimeType string) ([]byte, error)
+	// Supports returns true if files of the mimetype can be converted.
+	Supports(mimeType string) bool
+}
+
+// New creates the converter which is selected by the given configuration.
+// nil is returned if no converter is configured.
+func New(cfg config.Converter) (Converter, error) {
+	var c converter
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case "command":
+		c = NewCommandConverter(cfg.Command)
+	case "http":
+		c = NewHTTPConverter(cfg.URL, cfg.Insecure)
+	default:
+		return nil, fmt.Errorf("unknown thumbnail converter: %s", cfg.Type)
+	}
+	return NewLimited(c, cfg.MimeTypes, cfg.Timeout, cfg.MaxConcurrent), nil
+}
+
+// converter is implemented by the actual converters, the limits and the
+// supported mimetypes are handled by Limited.
+type converter interface {
+	Convert(ctx context.Context, r io.Reader, mimeType string) ([]byte, error)
+}
+
+// Limited runs a converter for the supported mimetypes only. The duration of
+// a conversion and the number of conversions running at the same time are
+// limited, because external converters are usually expensive.
+type Limited struct {
+	next      converter
+	mimeTypes map[string]struct{}
+	timeout   time.Duration
+	slots     chan struct{}
+}
+
+// NewLimited creates a new Limited converter. A timeout or maxConcurrent of 0
+// means unlimited.
+func NewLimited(next converter, mimeTypes []string, timeout time.Duration, maxConcurrent int) *Limited {
+	l := &Limited{
+		next:      next,
+		mimeTypes: make(map[string]struct{}, len(mimeTypes)),
+		timeout:   timeout,
+	}
+	for _, m := range mimeTypes {
+		l.mimeTypes[strings.ToLower(strings.TrimSpace(m))] = struct{}{}
+	}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	return l
+}
+
+// Supports returns true if files of the mimetype can be converted.
+func (l *Limited) Supports(mimeType string) bool {
+	mimeType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+	_, ok := l.mimeTypes[mimeType]
+	return ok
+}
+
+// Convert waits for a free slot and runs the converter within the timeout.
+func (l *Limited) Convert(ctx context.Context, r io.Reader, mimeType string) ([]byte, error) {
+	if !l.Supports(mimeType) {
+		return nil, fmt.Errorf("%w: unsupported mimetype %s", ErrConversionFailed, mimeType)
+	}
+
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+			defer func() { <-l.slots }()
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: no free conversion slot: %w", ErrConversionFailed, ctx.Err())
+		}
+	}
+
+	return l.next.Convert(ctx, r, mimeType)
+}
@@ -0,0 +1,135 @@
+package converter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/converter"
+)
+
+type blockingConverter struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c blockingConverter) Convert(ctx context.Context, _ io.Reader, _ string) ([]byte, error) {
+	c.started <- struct{}{}
+	select {
+	case <-c.release:
+		return []byte("img"), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestNew(t *testing.T) {
+	c, err := converter.New(config.Converter{})
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	_, err = converter.New(config.Converter{Type: "unknown"})
+	assert.Error(t, err)
+
+	c, err = converter.New(config.Converter{Type: "command", Command: "cp {input} {output}", MimeTypes: []string{"application/pdf"}})
+	require.NoError(t, err)
+	assert.True(t, c.Supports("application/pdf"))
+	assert.True(t, c.Supports("Application/PDF; charset=binary"))
+	assert.False(t, c.Supports("video/mp4"))
+}
+
+func TestLimited_UnsupportedMimeType(t *testing.T) {
+	c := converter.NewLimited(converter.NewCommandConverter("true"), []string{"application/pdf"}, 0, 0)
+	_, err := c.Convert(context.Background(), strings.NewReader("data"), "video/mp4")
+	assert.ErrorIs(t, err, converter.ErrConversionFailed)
+}
+
+func TestLimited_MaxConcurrent(t *testing.T) {
+	next := blockingConverter{started: make(chan struct{}, 2), release: make(chan struct{})}
+	c := converter.NewLimited(next, []string{"application/pdf"}, 50*time.Millisecond, 1)
+
+	done := make(chan error)
+	go func() {
+		_, err := c.Convert(context.Background(), strings.NewReader("data"), "application/pdf")
+		done <- err
+	}()
+	<-next.started
+
+	// the only slot is taken, so the second conversion times out while waiting
+	_, err := c.Convert(context.Background(), strings.NewReader("data"), "application/pdf")
+	assert.ErrorIs(t, err, converter.ErrConversionFailed)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the first conversion is cancelled by the timeout as well
+	assert.ErrorIs(t, <-done, context.DeadlineExceeded)
+}
+
+func TestCommandConverter(t *testing.T) {
+	c := converter.NewCommandConverter("cp {input} {output}")
+	img, err := c.Convert(context.Background(), strings.NewReader("data"), "application/pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(img))
+
+	c = converter.NewCommandConverter("sh -c exit")
+	_, err = c.Convert(context.Background(), strings.NewReader("data"), "application/pdf")
+	assert.ErrorIs(t, err, converter.ErrConversionFailed)
+
+	c = converter.NewCommandConverter("false")
+	_, err = c.Convert(context.Background(), strings.NewReader("data"), "application/pdf")
+	assert.ErrorIs(t, err, converter.ErrConversionFailed)
+}
+
+func TestCommandConverter_Timeout(t *testing.T) {
+	c := converter.NewCommandConverter("sleep 5")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Convert(ctx, strings.NewReader("data"), "video/mp4")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestCommandConverter_TimeoutKillsChildren(t *testing.T) {
+	// the command forks like a headless office, the child keeps stderr open
+	script := filepath.Join(t.TempDir(), "convert.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nsleep 30 &\nsleep 30\n"), 0o700))
+
+	c := converter.NewCommandConverter(script)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.Convert(ctx, strings.NewReader("data"), "application/pdf")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestHTTPConverter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/pdf" {
+			http.Error(w, "unsupported", http.StatusUnsupportedMediaType)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(append([]byte("preview of "), b...))
+	}))
+	defer srv.Close()
+
+	c := converter.NewHTTPConverter(srv.URL, false)
+	img, err := c.Convert(context.Background(), strings.NewReader("data"), "application/pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "preview of data", string(img))
+
+	_, err = c.Convert(context.Background(), strings.NewReader("data"), "video/mp4")
+	assert.ErrorIs(t, err, converter.ErrConversionFailed)
+}
@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxPreviewSize is the maximum size of a preview returned by the conversion service
+const maxPreviewSize = 50 << 20
+
+// HTTPConverter renders previews with a conversion service.
+type HTTPConverter struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPConverter creates a new HTTPConverter which sends the files to the url.
+func NewHTTPConverter(url string, insecure bool) HTTPConverter {
+	return HTTPConverter{
+		url: url,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: insecure, //nolint:gosec
+				},
+			},
+		},
+	}
+}
+
+// Convert posts the file to the conversion service and returns the image of
+// the response.
+func (c HTTPConverter) Convert(ctx context.Context, r io.Reader, mimeType string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mimeType)
+	req.Header.Set("Accept", "image/png")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConversionFailed, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("%w: conversion service responded with %d: %s", ErrConversionFailed, res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	img, err := io.ReadAll(io.LimitReader(res.Body, maxPreviewSize+1))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("%w: %w", ErrConversionFailed, err)
+	case len(img) > maxPreviewSize:
+		return nil, fmt.Errorf("%w: the preview is too large", ErrConversionFailed)
+	}
+	return img, nil
+}
@@ -16,6 +16,7 @@ const (
 	typeGgs  = "ggs"
 	typeGgp  = "ggp"
 	typeWebp = "webp"
+	typeAvif = "avif"
 )
 
 // Encoder encodes the thumbnail to a specific format.
@@ -56,8 +57,10 @@ func EncoderForType(fileType string) (Encoder, error) {
 	switch strings.ToLower(fileType) {
 	case typePng, typeGgs, typeGgp:
 		return PngEncoder{}, nil
-	case typeJpg, typeJpeg, typeWebp:
+	case typeJpg, typeJpeg:
 		return JpegEncoder{}, nil
+	case typeWebp, typeAvif:
+		return encoderForModernType(strings.ToLower(fileType))
 	case typeGif:
 		return GifEncoder{}, nil
 	default:
@@ -79,3 +82,42 @@ func GetExtForMime(fileType string) string {
 		return ""
 	}
 }
+
+// GetOutputType returns the type of the thumbnail for a file of the given mimetype.
+// The requested type is used if it is one of the negotiable types like webp, which
+// are only requested by clients accepting them. Gif thumbnails are kept to preserve
+// the animation. Webp and avif are encoded as jpg if they can't be used.
+func GetOutputType(mimeType, requested string) string {
+	ext := GetExtForMime(mimeType)
+	requested = strings.ToLower(requested)
+	_, negotiable := negotiableTypes[requested]
+
+	switch {
+	case negotiable && ext != typeGif:
+		return requested
+	case ext == typeWebp, ext == "" && (requested == typeWebp || requested == typeAvif):
+		return typeJpg
+	case ext != "":
+		return ext
+	default:
+		return requested
+	}
+}
+
+// GetMimeForExt returns the mimetype of a thumbnail with the given extension.
+func GetMimeForExt(ext string) string {
+	switch strings.TrimPrefix(strings.ToLower(ext), ".") {
+	case typePng:
+		return "image/png"
+	case typeJpg, typeJpeg:
+		return "image/jpeg"
+	case typeGif:
+		return "image/gif"
+	case typeWebp:
+		return "image/webp"
+	case typeAvif:
+		return "image/avif"
+	default:
+		return ""
+	}
+}
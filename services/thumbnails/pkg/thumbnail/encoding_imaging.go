@@ -11,6 +11,10 @@ import (
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/errors"
 )
 
+// negotiableTypes contains the types a client can request in addition to the type of the
+// source file. There are no pure Go encoders for webp and avif, they need libvips.
+var negotiableTypes = map[string]struct{}{}
+
 // PngEncoder encodes to png
 type PngEncoder struct{}
 
@@ -54,3 +58,12 @@ func (e JpegEncoder) Types() []string {
 func (e JpegEncoder) MimeType() string {
 	return "image/jpeg"
 }
+
+// encoderForModernType returns the encoder for webp and avif. Without libvips webp
+// images are encoded as jpg and avif is not supported.
+func encoderForModernType(fileType string) (Encoder, error) {
+	if fileType == typeWebp {
+		return JpegEncoder{}, nil
+	}
+	return nil, errors.ErrNoEncoderForType
+}
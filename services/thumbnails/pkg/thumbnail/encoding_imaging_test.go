@@ -0,0 +1,25 @@
+//go:build !enable_vips
+
+package thumbnail
+
+import "testing"
+
+func TestGetOutputType_WithoutVips(t *testing.T) {
+	tests := []struct {
+		mimeType, requested, want string
+	}{
+		{"image/png", "WEBP", "png"},
+		{"image/jpeg", "AVIF", "jpeg"},
+		{"text/plain", "WEBP", "jpg"},
+		{"text/plain", "AVIF", "jpg"},
+	}
+	for _, tt := range tests {
+		if got := GetOutputType(tt.mimeType, tt.requested); got != tt.want {
+			t.Errorf("GetOutputType(%q, %q) = %q, want %q", tt.mimeType, tt.requested, got, tt.want)
+		}
+	}
+
+	if _, err := EncoderForType("avif"); err == nil {
+		t.Error("avif can't be encoded without libvips")
+	}
+}
@@ -20,3 +20,37 @@ func TestEncoderForType(t *testing.T) {
 		}
 	}
 }
+
+func TestGetOutputType(t *testing.T) {
+	tests := []struct {
+		mimeType, requested, want string
+	}{
+		{"image/png", "JPG", "png"},
+		{"image/gif", "WEBP", "gif"},
+		{"text/plain", "PNG", "png"},
+		{"text/plain", "JPG", "jpg"},
+		{"image/webp", "JPG", "jpg"},
+	}
+	for _, tt := range tests {
+		if got := GetOutputType(tt.mimeType, tt.requested); got != tt.want {
+			t.Errorf("GetOutputType(%q, %q) = %q, want %q", tt.mimeType, tt.requested, got, tt.want)
+		}
+	}
+}
+
+func TestGetMimeForExt(t *testing.T) {
+	table := map[string]string{
+		".png":  "image/png",
+		"jpeg":  "image/jpeg",
+		".JPG":  "image/jpeg",
+		".gif":  "image/gif",
+		".webp": "image/webp",
+		".avif": "image/avif",
+		".txt":  "",
+	}
+	for ext, want := range table {
+		if got := GetMimeForExt(ext); got != want {
+			t.Errorf("GetMimeForExt(%q) = %q, want %q", ext, got, want)
+		}
+	}
+}
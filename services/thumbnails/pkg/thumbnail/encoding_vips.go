@@ -9,6 +9,13 @@ import (
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/errors"
 )
 
+// negotiableTypes contains the types a client can request in addition to the type of the
+// source file.
+var negotiableTypes = map[string]struct{}{
+	typeWebp: {},
+	typeAvif: {},
+}
+
 // PngEncoder encodes to png
 type PngEncoder struct{}
 
@@ -64,3 +71,67 @@ func (e JpegEncoder) Types() []string {
 func (e JpegEncoder) MimeType() string {
 	return "image/jpeg"
 }
+
+// WebpEncoder encodes to webp
+type WebpEncoder struct{}
+
+// Encode encodes to webp
+func (e WebpEncoder) Encode(w io.Writer, img interface{}) error {
+	m, ok := img.(*vips.ImageRef)
+	if !ok {
+		return errors.ErrInvalidType
+	}
+
+	buf, _, err := m.ExportWebp(vips.NewWebpExportParams())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// Types returns the webp suffix.
+func (e WebpEncoder) Types() []string {
+	return []string{typeWebp}
+}
+
+// MimeType returns the mimetype for webp files.
+func (e WebpEncoder) MimeType() string {
+	return "image/webp"
+}
+
+// AvifEncoder encodes to avif
+type AvifEncoder struct{}
+
+// Encode encodes to avif
+func (e AvifEncoder) Encode(w io.Writer, img interface{}) error {
+	m, ok := img.(*vips.ImageRef)
+	if !ok {
+		return errors.ErrInvalidType
+	}
+
+	buf, _, err := m.ExportAvif(vips.NewAvifExportParams())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// Types returns the avif suffix.
+func (e AvifEncoder) Types() []string {
+	return []string{typeAvif}
+}
+
+// MimeType returns the mimetype for avif files.
+func (e AvifEncoder) MimeType() string {
+	return "image/avif"
+}
+
+// encoderForModernType returns the encoder for webp and avif.
+func encoderForModernType(fileType string) (Encoder, error) {
+	if fileType == typeAvif {
+		return AvifEncoder{}, nil
+	}
+	return WebpEncoder{}, nil
+}
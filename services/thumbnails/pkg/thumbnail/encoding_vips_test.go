@@ -0,0 +1,62 @@
+//go:build enable_vips
+
+package thumbnail
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/davidbyttow/govips/v2/vips"
+)
+
+func TestGetOutputType_WithVips(t *testing.T) {
+	tests := []struct {
+		mimeType, requested, want string
+	}{
+		{"image/png", "WEBP", "webp"},
+		{"image/jpeg", "AVIF", "avif"},
+		{"image/webp", "WEBP", "webp"},
+		{"text/plain", "WEBP", "webp"},
+	}
+	for _, tt := range tests {
+		if got := GetOutputType(tt.mimeType, tt.requested); got != tt.want {
+			t.Errorf("GetOutputType(%q, %q) = %q, want %q", tt.mimeType, tt.requested, got, tt.want)
+		}
+	}
+
+	if e, _ := EncoderForType("webp"); e != (WebpEncoder{}) {
+		t.Error("expected the webp encoder")
+	}
+	if e, _ := EncoderForType("avif"); e != (AvifEncoder{}) {
+		t.Error("expected the avif encoder")
+	}
+}
+
+func TestWebpEncoder_Size(t *testing.T) {
+	// webp is only negotiated because its thumbnails are smaller than the jpg and png ones
+	for _, src := range []string{"../../testdata/test.jpg", "../../testdata/test.png"} {
+		for _, size := range []int{36, 128, 1280} {
+			sizes := map[string]int{}
+			for _, e := range []Encoder{JpegEncoder{}, PngEncoder{}, WebpEncoder{}} {
+				m, err := vips.NewImageFromFile(src)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := m.Thumbnail(size, size, vips.InterestingNone); err != nil {
+					t.Fatal(err)
+				}
+
+				var buf bytes.Buffer
+				if err := e.Encode(&buf, m); err != nil {
+					t.Fatalf("%s: Encode() error = %v", e.MimeType(), err)
+				}
+				sizes[e.MimeType()] = buf.Len()
+				m.Close()
+			}
+
+			if sizes["image/webp"] >= sizes["image/jpeg"] || sizes["image/webp"] >= sizes["image/png"] {
+				t.Errorf("%s %dpx: webp is not smaller than jpg and png: %v", src, size, sizes)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io"
+
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/errors"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/preprocessor"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/converter"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
+)
+
+// previewCharacteristic is used in the storage key of the previews rendered by a converter.
+const previewCharacteristic = "preview"
+
+// Document is a file which needs to be converted to an image before a thumbnail can be generated.
+type Document struct {
+	ctx      context.Context
+	mimeType string
+	checksum string
+	content  io.Reader
+	preview  interface{}
+}
+
+// NewDocument creates a new Document. The content is only read if there is no cached preview for the checksum.
+func NewDocument(ctx context.Context, mimeType, checksum string, content io.Reader) *Document {
+	return &Document{
+		ctx:      ctx,
+		mimeType: mimeType,
+		checksum: checksum,
+		content:  content,
+	}
+}
+
+// ConverterGenerator generates thumbnails of documents and videos. The files are converted to
+// a preview image by an external converter, the preview is then processed by the image generator.
+type ConverterGenerator struct {
+	converter converter.Converter
+	storage   storage.Storage
+	generator Generator
+}
+
+// NewConverterGenerator creates a new ConverterGenerator. The previews are cached in the storage,
+// so every file version is only converted once.
+func NewConverterGenerator(c converter.Converter, s storage.Storage, generator Generator) ConverterGenerator {
+	return ConverterGenerator{
+		converter: c,
+		storage:   s,
+		generator: generator,
+	}
+}
+
+// ProcessorID returns the processor identification.
+func (g ConverterGenerator) ProcessorID() string {
+	return g.generator.ProcessorID()
+}
+
+// Generate generates a thumbnail from the preview of the document.
+func (g ConverterGenerator) Generate(size image.Rectangle, img interface{}) (interface{}, error) {
+	preview, err := g.preview(img)
+	if err != nil {
+		return nil, err
+	}
+	return g.generator.Generate(size, preview)
+}
+
+// Dimensions returns the dimensions of the preview of the document.
+func (g ConverterGenerator) Dimensions(img interface{}) (image.Rectangle, error) {
+	preview, err := g.preview(img)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	return g.generator.Dimensions(preview)
+}
+
+// preview returns the decoded preview of the document. It is loaded from the storage or
+// rendered by the converter.
+func (g ConverterGenerator) preview(img interface{}) (interface{}, error) {
+	doc, ok := img.(*Document)
+	if !ok {
+		return nil, errors.ErrInvalidType
+	}
+	if doc.preview != nil {
+		return doc.preview, nil
+	}
+
+	key := g.storage.BuildKey(storage.Request{
+		Checksum:       doc.checksum,
+		Types:          []string{typePng},
+		Characteristic: previewCharacteristic,
+	})
+
+	var data []byte
+	if g.storage.Stat(key) {
+		b, err := g.storage.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	} else {
+		b, err := g.converter.Convert(doc.ctx, doc.content, doc.mimeType)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.storage.Put(key, b); err != nil {
+			return nil, err
+		}
+		data = b
+	}
+
+	preview, err := preprocessor.ImageDecoder{}.Convert(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	doc.preview = preview
+	return preview, nil
+}
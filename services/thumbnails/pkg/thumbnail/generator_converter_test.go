@@ -0,0 +1,58 @@
+package thumbnail
+
+import (
+	"context"
+	"image"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
+)
+
+type pngConverter struct {
+	calls int
+}
+
+func (c *pngConverter) Convert(_ context.Context, _ io.Reader, _ string) ([]byte, error) {
+	c.calls++
+	return os.ReadFile("../../testdata/test.png")
+}
+
+func (c *pngConverter) Supports(_ string) bool {
+	return true
+}
+
+func TestConverterGenerator(t *testing.T) {
+	st := storage.NewFileSystemStorage(config.FileSystemStorage{RootDirectory: t.TempDir()}, log.NopLogger())
+	conv := &pngConverter{}
+	inner, err := NewSimpleGenerator(typePng, "")
+	require.NoError(t, err)
+	g := NewConverterGenerator(conv, st, inner)
+	assert.Equal(t, inner.ProcessorID(), g.ProcessorID())
+
+	doc := NewDocument(context.Background(), "application/pdf", "120EA8A25E5D487BF68B5F7096440019", strings.NewReader("pdf"))
+	dimensions, err := g.Dimensions(doc)
+	require.NoError(t, err)
+	assert.False(t, dimensions.Empty())
+
+	thumb, err := g.Generate(image.Rect(0, 0, 16, 16), doc)
+	require.NoError(t, err)
+	assert.NotNil(t, thumb)
+	assert.Equal(t, 1, conv.calls)
+
+	// the preview of the same file version is taken from the storage
+	doc = NewDocument(context.Background(), "application/pdf", "120EA8A25E5D487BF68B5F7096440019", strings.NewReader("pdf"))
+	_, err = g.Dimensions(doc)
+	require.NoError(t, err)
+	assert.Equal(t, 1, conv.calls)
+
+	_, err = g.Dimensions(image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	assert.Error(t, err)
+}
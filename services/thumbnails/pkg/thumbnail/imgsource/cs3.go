@@ -58,8 +58,13 @@ func (s CS3) Get(ctx context.Context, path string) (io.ReadCloser, error) {
 		}
 	}
 
+	maxSize := s.maxImageFileSize
+	if size, ok := ContextGetMaxFileSize(ctx); ok {
+		maxSize = size
+	}
+
 	ctx = metadata.AppendToOutgoingContext(context.Background(), revactx.TokenHeader, auth)
-	err = s.checkImageFileSize(ctx, ref)
+	err = s.checkImageFileSize(ctx, ref, maxSize)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +118,7 @@ func (s CS3) Get(ctx context.Context, path string) (io.ReadCloser, error) {
 	return resp.Body, nil
 }
 
-func (s CS3) checkImageFileSize(ctx context.Context, ref provider.Reference) error {
+func (s CS3) checkImageFileSize(ctx context.Context, ref provider.Reference, maxSize uint64) error {
 	gwc, err := s.gatewaySelector.Next()
 	if err != nil {
 		return err
@@ -125,7 +130,7 @@ func (s CS3) checkImageFileSize(ctx context.Context, ref provider.Reference) err
 	if stat.GetStatus().GetCode() != rpc.Code_CODE_OK {
 		return fmt.Errorf("could not stat image: %s", stat.GetStatus().GetMessage())
 	}
-	if stat.GetInfo().GetSize() > s.maxImageFileSize {
+	if stat.GetInfo().GetSize() > maxSize {
 		return errors.ErrImageTooLarge
 	}
 	return nil
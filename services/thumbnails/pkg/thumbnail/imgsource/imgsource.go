@@ -9,6 +9,7 @@ type key int
 
 const (
 	auth key = iota
+	maxFileSize
 )
 
 // Source defines the interface for image sources
@@ -29,3 +30,15 @@ func ContextGetAuthorization(ctx context.Context) (string, bool) {
 	}
 	return val.(string), true
 }
+
+// ContextSetMaxFileSize puts the maximum size of the file into the context,
+// it replaces the limit configured for the source.
+func ContextSetMaxFileSize(parent context.Context, size uint64) context.Context {
+	return context.WithValue(parent, maxFileSize, size)
+}
+
+// ContextGetMaxFileSize gets the maximum size of the file from the context.
+func ContextGetMaxFileSize(ctx context.Context) (uint64, bool) {
+	val, ok := ctx.Value(maxFileSize).(uint64)
+	return val, ok
+}
@@ -67,7 +67,11 @@ func (s WebDav) Get(ctx context.Context, url string) (io.ReadCloser, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, `could not parse content length of webdav response "%s"`, url)
 	}
-	if c > s.maxImageFileSize {
+	maxSize := s.maxImageFileSize
+	if size, ok := ContextGetMaxFileSize(ctx); ok {
+		maxSize = size
+	}
+	if c > maxSize {
 		return nil, thumbnailerErrors.ErrImageTooLarge
 	}
 
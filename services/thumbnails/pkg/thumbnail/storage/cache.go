@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/opencloud-eu/reva/v2/pkg/bytesize"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+)
+
+// Entry describes a cached thumbnail.
+type Entry struct {
+	Key  string `json:"key"`
+	Size uint64 `json:"size"`
+	// LastAccess is the time the thumbnail was used the last time,
+	// storages which do not track the access report the time the thumbnail was stored.
+	LastAccess time.Time `json:"last_access"`
+}
+
+// Cache is implemented by storages which are able to list and remove the cached thumbnails,
+// it is used to enforce the storage limits and for maintenance tasks.
+type Cache interface {
+	List(ctx context.Context) ([]Entry, error)
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Stats summarizes the content of a Cache.
+type Stats struct {
+	Count  int       `json:"count"`
+	Size   uint64    `json:"size"`
+	Oldest time.Time `json:"oldest,omitempty"`
+	Newest time.Time `json:"newest,omitempty"`
+}
+
+// Limits are the size and age limits of a Cache.
+type Limits struct {
+	// MaxSize is the maximum total size of all thumbnails, 0 means unlimited.
+	MaxSize uint64
+	// TTL is the maximum time a thumbnail is kept without being accessed, 0 means forever.
+	TTL time.Duration
+}
+
+// NewLimits creates the Limits from the given configuration.
+func NewLimits(cfg config.StorageEviction) (Limits, error) {
+	l := Limits{TTL: cfg.TTL}
+	if cfg.MaxSize != "" {
+		b, err := bytesize.Parse(cfg.MaxSize)
+		if err != nil {
+			return l, err
+		}
+		l.MaxSize = b.Bytes()
+	}
+
+	return l, nil
+}
+
+// Enabled returns if any limit is set.
+func (l Limits) Enabled() bool {
+	return l.MaxSize > 0 || l.TTL > 0
+}
+
+// GetStats returns the statistics of the given Cache.
+func GetStats(ctx context.Context, c Cache) (Stats, error) {
+	entries, err := c.List(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{Count: len(entries)}
+	for _, e := range entries {
+		stats.Size += e.Size
+		if stats.Oldest.IsZero() || e.LastAccess.Before(stats.Oldest) {
+			stats.Oldest = e.LastAccess
+		}
+		if e.LastAccess.After(stats.Newest) {
+			stats.Newest = e.LastAccess
+		}
+	}
+
+	return stats, nil
+}
+
+// Evict removes all thumbnails which exceed the given limits and returns the removed entries.
+// Expired thumbnails are always removed, afterward the least recently used thumbnails are removed
+// until the total size fits the limit. If dryRun is set, nothing gets removed.
+func Evict(ctx context.Context, c Cache, l Limits, dryRun bool) ([]Entry, error) {
+	if !l.Enabled() {
+		return nil, nil
+	}
+
+	entries, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	evicted := l.evictionCandidates(entries, time.Now())
+	if dryRun || len(evicted) == 0 {
+		return evicted, nil
+	}
+
+	keys := make([]string, 0, len(evicted))
+	for _, e := range evicted {
+		keys = append(keys, e.Key)
+	}
+
+	return evicted, c.Delete(ctx, keys...)
+}
+
+// Purge removes all thumbnails and returns the number of removed thumbnails.
+func Purge(ctx context.Context, c Cache) (int, error) {
+	entries, err := c.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+
+	return len(keys), c.Delete(ctx, keys...)
+}
+
+// NewCacheEvictor returns an Evictor which enforces the given limits on the Cache.
+func NewCacheEvictor(c Cache, l Limits, logger log.Logger) Evictor {
+	return cacheEvictor{cache: c, limits: l, logger: logger}
+}
+
+// cacheEvictor enforces the limits on a Cache
+type cacheEvictor struct {
+	cache  Cache
+	limits Limits
+	logger log.Logger
+}
+
+// Evict implements the Evictor interface
+func (e cacheEvictor) Evict(ctx context.Context) error {
+	evicted, err := Evict(ctx, e.cache, e.limits, false)
+	if err != nil {
+		return err
+	}
+	if len(evicted) > 0 {
+		e.logger.Debug().Int("count", len(evicted)).Msg("evicted thumbnails from storage")
+	}
+	return nil
+}
+
+// evictionCandidates returns all entries which have to be removed to stay within the limits.
+func (l Limits) evictionCandidates(entries []Entry, now time.Time) []Entry {
+	var evicted []Entry
+	var total uint64
+	remaining := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if l.TTL > 0 && now.Sub(e.LastAccess) > l.TTL {
+			evicted = append(evicted, e)
+			continue
+		}
+		total += e.Size
+		remaining = append(remaining, e)
+	}
+
+	if l.MaxSize == 0 || total <= l.MaxSize {
+		return evicted
+	}
+
+	slices.SortFunc(remaining, func(a, b Entry) int {
+		return a.LastAccess.Compare(b.LastAccess)
+	})
+
+	for _, e := range remaining {
+		if total <= l.MaxSize {
+			break
+		}
+		evicted = append(evicted, e)
+		total -= e.Size
+	}
+
+	return evicted
+}
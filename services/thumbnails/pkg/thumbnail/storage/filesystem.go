@@ -1,11 +1,13 @@
 package storage
 
 import (
+	"context"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -14,7 +16,15 @@ import (
 )
 
 const (
-	filesDir = "files"
+	filesDir   = "files"
+	tmpPattern = "tmpthumb"
+
+	// accessTimeResolution is the time after which the last access of a thumbnail is updated again,
+	// it saves a write for every read of frequently used thumbnails.
+	accessTimeResolution = 5 * time.Minute
+	// createRetries is the number of attempts to create a thumbnail in a directory which is
+	// removed concurrently, because its last thumbnail was evicted.
+	createRetries = 3
 )
 
 // NewFileSystemStorage creates a new instance of FileSystem
@@ -43,13 +53,20 @@ func (s FileSystem) Stat(key string) bool {
 // Get returns the file content for the given key
 func (s FileSystem) Get(key string) ([]byte, error) {
 	img := filepath.Join(s.root, filesDir, key)
-	content, err := os.ReadFile(img)
+	content, modTime, err := readFile(img)
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
 			s.logger.Debug().Str("err", err.Error()).Str("key", key).Msg("could not load thumbnail from store")
 		}
 		return nil, err
 	}
+
+	// the modification time is used to track the last access of a thumbnail for the eviction
+	if now := time.Now(); now.Sub(modTime) > accessTimeResolution {
+		if err := os.Chtimes(img, now, now); err != nil {
+			s.logger.Debug().Str("err", err.Error()).Str("key", key).Msg("could not update the access time of the thumbnail")
+		}
+	}
 	return content, nil
 }
 
@@ -57,12 +74,9 @@ func (s FileSystem) Get(key string) ([]byte, error) {
 func (s FileSystem) Put(key string, img []byte) error {
 	imgPath := filepath.Join(s.root, filesDir, key)
 	dir := filepath.Dir(imgPath)
-	if err := os.MkdirAll(dir, 0700); err != nil {
-		return errors.Wrapf(err, "error while creating directory %s", dir)
-	}
 
 	if _, err := os.Stat(imgPath); os.IsNotExist(err) {
-		f, err := os.CreateTemp(dir, "tmpthumb")
+		f, err := createTemp(dir)
 		if err != nil {
 			return errors.Wrapf(err, "could not create temporary file for \"%s\"", key)
 		}
@@ -94,6 +108,45 @@ func (s FileSystem) Put(key string, img []byte) error {
 	return nil
 }
 
+// readFile returns the content and the modification time of a file
+func readFile(path string) ([]byte, time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return content, info.ModTime(), nil
+}
+
+// createTemp creates a temporary file in the directory, the directory is created if needed.
+// Delete removes directories once they are empty, so the directory might vanish in between.
+func createTemp(dir string) (*os.File, error) {
+	var err error
+	for i := 0; i < createRetries; i++ {
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "error while creating directory %s", dir)
+		}
+
+		var f *os.File
+		if f, err = os.CreateTemp(dir, tmpPattern); !errors.Is(err, fs.ErrNotExist) {
+			return f, err
+		}
+	}
+	return nil, err
+}
+
 // BuildKey generate the unique key for a thumbnail.
 // The key is structure as follows:
 //
@@ -103,16 +156,64 @@ func (s FileSystem) Put(key string, img []byte) error {
 //
 // The key also represents the path to the thumbnail in the filesystem under the configured root directory.
 func (s FileSystem) BuildKey(r Request) string {
-	checksum := r.Checksum
-	filetype := r.Types[0]
+	return filepath.FromSlash(buildKey(r))
+}
+
+// List returns all thumbnails in the file system, the last access is the modification time of the file.
+func (s FileSystem) List(_ context.Context) ([]Entry, error) {
+	root := filepath.Join(s.root, filesDir)
+
+	var entries []Entry
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return nil
+		case err != nil:
+			return err
+		case d.IsDir(), strings.HasPrefix(d.Name(), tmpPattern):
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
 
-	parts := []string{strconv.Itoa(r.Resolution.Dx()), "x", strconv.Itoa(r.Resolution.Dy())}
+		key, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
 
-	if r.Characteristic != "" {
-		parts = append(parts, "-", r.Characteristic)
+		entries = append(entries, Entry{Key: key, Size: uint64(info.Size()), LastAccess: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list thumbnails")
 	}
 
-	parts = append(parts, ".", filetype)
+	return entries, nil
+}
 
-	return filepath.Join(checksum[:2], checksum[2:4], checksum[4:], strings.Join(parts, ""))
+// Delete removes the thumbnails for the given keys from the file system,
+// directories which become empty are removed as well.
+func (s FileSystem) Delete(_ context.Context, keys ...string) error {
+	root := filepath.Join(s.root, filesDir)
+	for _, k := range keys {
+		p := filepath.Join(root, k)
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return errors.Wrapf(err, "could not remove thumbnail \"%s\"", k)
+		}
+
+		// remove the empty parent directories, os.Remove fails for non-empty directories
+		for dir := filepath.Dir(p); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+			if err := os.Remove(dir); err != nil {
+				break
+			}
+		}
+	}
+
+	return nil
 }
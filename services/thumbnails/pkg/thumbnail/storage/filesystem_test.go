@@ -2,10 +2,17 @@ package storage_test
 
 import (
 	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	tAssert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
 	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
 )
 
@@ -50,6 +57,15 @@ func TestFileSystem_BuildKey(t *testing.T) {
 			},
 			want: "12/0E/A8A25E5D487BF68B5F7096440019/2x2-fill.png",
 		},
+		{
+			r: storage.Request{
+				Checksum:       "120EA8A25E5D487BF68B5F7096440019",
+				Types:          []string{"webp"},
+				Resolution:     image.Rect(1, 2, 3, 4),
+				Characteristic: "fill",
+			},
+			want: "12/0E/A8A25E5D487BF68B5F7096440019/2x2-fill.webp",
+		},
 	}
 
 	s := storage.FileSystem{}
@@ -63,3 +79,79 @@ func TestFileSystem_BuildKey(t *testing.T) {
 	}
 
 }
+
+func TestFileSystem_Cache(t *testing.T) {
+	root := t.TempDir()
+	s := storage.NewFileSystemStorage(config.FileSystemStorage{RootDirectory: root}, log.NopLogger())
+
+	now := time.Now()
+	for i, k := range []string{"12/0E/A8A2/1x1.png", "12/0E/A8A2/2x2.png", "34/0E/A8A2/1x1.png"} {
+		require.NoError(t, s.Put(k, make([]byte, 10)))
+		// the first thumbnail was used least recently
+		_, err := s.Get(k)
+		require.NoError(t, err)
+		tm := now.Add(time.Duration(i-3) * time.Minute)
+		require.NoError(t, os.Chtimes(filepath.Join(root, "files", k), tm, tm))
+	}
+
+	stats, err := storage.GetStats(t.Context(), s)
+	require.NoError(t, err)
+	tAssert.Equal(t, 3, stats.Count)
+	tAssert.Equal(t, uint64(30), stats.Size)
+
+	evicted, err := storage.Evict(t.Context(), s, storage.Limits{MaxSize: 20}, true)
+	require.NoError(t, err)
+	require.Len(t, evicted, 1)
+	tAssert.True(t, s.Stat("12/0E/A8A2/1x1.png"), "dry run must not remove thumbnails")
+
+	evicted, err = storage.Evict(t.Context(), s, storage.Limits{MaxSize: 20}, false)
+	require.NoError(t, err)
+	require.Len(t, evicted, 1)
+	tAssert.Equal(t, "12/0E/A8A2/1x1.png", filepath.ToSlash(evicted[0].Key))
+	tAssert.False(t, s.Stat("12/0E/A8A2/1x1.png"))
+
+	evicted, err = storage.Evict(t.Context(), s, storage.Limits{TTL: 90 * time.Second}, false)
+	require.NoError(t, err)
+	require.Len(t, evicted, 1)
+	tAssert.Equal(t, "12/0E/A8A2/2x2.png", filepath.ToSlash(evicted[0].Key))
+
+	_, err = os.Stat(filepath.Join(root, "files", "12"))
+	tAssert.ErrorIs(t, err, fs.ErrNotExist, "empty directories must be removed")
+
+	count, err := storage.Purge(t.Context(), s)
+	require.NoError(t, err)
+	tAssert.Equal(t, 1, count)
+}
+
+func TestFileSystem_AccessTime(t *testing.T) {
+	root := t.TempDir()
+	s := storage.NewFileSystemStorage(config.FileSystemStorage{RootDirectory: root}, log.NopLogger())
+	key := "12/0E/A8A2/1x1.png"
+	path := filepath.Join(root, "files", key)
+	require.NoError(t, s.Put(key, make([]byte, 10)))
+
+	lastAccess := func() time.Time {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		return info.ModTime()
+	}
+
+	// recent accesses are not recorded again
+	recent := time.Now().Add(-time.Minute).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(path, recent, recent))
+	_, err := s.Get(key)
+	require.NoError(t, err)
+	tAssert.True(t, lastAccess().Equal(recent))
+
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(path, old, old))
+	_, err = s.Get(key)
+	require.NoError(t, err)
+	tAssert.True(t, lastAccess().After(recent))
+
+	// the eviction removes the directories of the last thumbnail, they are created again
+	require.NoError(t, storage.NewCacheEvictor(s, storage.Limits{MaxSize: 1}, log.NopLogger()).Evict(t.Context()))
+	tAssert.False(t, s.Stat(key))
+	require.NoError(t, s.Put(key, make([]byte, 10)))
+	tAssert.True(t, s.Stat(key))
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+)
+
+// NewNATSStorage creates a new instance of NATS,
+// the object store bucket is created if it does not exist yet.
+func NewNATSStorage(cfg config.NATSStorage, eviction config.StorageEviction, logger log.Logger) (*NATS, error) {
+	l, err := NewLimits(eviction)
+	if err != nil {
+		return nil, err
+	}
+
+	natsOptions := nats.Options{
+		Servers:  cfg.Nodes,
+		User:     cfg.AuthUsername,
+		Password: cfg.AuthPassword,
+	}
+	conn, err := natsOptions.Connect()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to nats")
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := js.ObjectStore(cfg.Bucket)
+	if err != nil {
+		if !errors.Is(err, nats.ErrStreamNotFound) {
+			return nil, errors.Wrapf(err, "failed to get bucket (%s)", cfg.Bucket)
+		}
+
+		// the ttl is enforced by nats, the size limit has to be handled by us
+		// because the object store would reject new objects once the limit is reached
+		store, err = js.CreateObjectStore(&nats.ObjectStoreConfig{
+			Bucket: cfg.Bucket,
+			TTL:    l.TTL,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create bucket (%s)", cfg.Bucket)
+		}
+	}
+
+	return &NATS{
+		store:  store,
+		logger: logger,
+	}, nil
+}
+
+// NATS represents a storage for the thumbnails using a NATS JetStream object store,
+// it allows multiple thumbnails instances to share one cache.
+type NATS struct {
+	store  nats.ObjectStore
+	logger log.Logger
+}
+
+// Stat returns if an object for the given key exists in the object store
+func (s *NATS) Stat(key string) bool {
+	if _, err := s.store.GetInfo(key); err != nil {
+		return false
+	}
+	return true
+}
+
+// Get returns the object content for the given key
+func (s *NATS) Get(key string) ([]byte, error) {
+	content, err := s.store.GetBytes(key)
+	if err != nil {
+		if errors.Is(err, nats.ErrObjectNotFound) {
+			return nil, fs.ErrNotExist
+		}
+		s.logger.Debug().Str("err", err.Error()).Str("key", key).Msg("could not load thumbnail from store")
+		return nil, err
+	}
+	return content, nil
+}
+
+// Put stores image data in the object store for the given key
+func (s *NATS) Put(key string, img []byte) error {
+	if _, err := s.store.PutBytes(key, img); err != nil {
+		return errors.Wrapf(err, "could not store thumbnail \"%s\"", key)
+	}
+	return nil
+}
+
+// BuildKey generate the unique key for a thumbnail.
+// The key is structured the same way as for the FileSystem storage.
+func (s *NATS) BuildKey(r Request) string {
+	return buildKey(r)
+}
+
+// List returns all thumbnails in the object store, the last access is the time the object was stored.
+func (s *NATS) List(ctx context.Context) ([]Entry, error) {
+	infos, err := s.store.List(nats.Context(ctx))
+	if err != nil {
+		if errors.Is(err, nats.ErrNoObjectsFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "could not list thumbnails")
+	}
+
+	entries := make([]Entry, 0, len(infos))
+	for _, info := range infos {
+		entries = append(entries, Entry{Key: info.Name, Size: info.Size, LastAccess: info.ModTime})
+	}
+	return entries, nil
+}
+
+// Delete removes the thumbnails for the given keys from the object store
+func (s *NATS) Delete(_ context.Context, keys ...string) error {
+	for _, k := range keys {
+		if err := s.store.Delete(k); err != nil && !errors.Is(err, nats.ErrObjectNotFound) {
+			return errors.Wrapf(err, "could not remove thumbnail \"%s\"", k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package storage_test
+
+import (
+	"image"
+	"io/fs"
+	"net"
+	"testing"
+	"time"
+
+	nserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
+)
+
+func newNATSServer(t testing.TB) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+
+	server, err := nserver.NewServer(&nserver.Options{
+		Host:      "127.0.0.1",
+		Port:      port,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	go server.Start()
+	t.Cleanup(server.Shutdown)
+	require.True(t, server.ReadyForConnections(5*time.Second))
+
+	return server.Addr().String()
+}
+
+func TestNATS(t *testing.T) {
+	addr := newNATSServer(t)
+
+	t.Run("stores and loads thumbnails", func(t *testing.T) {
+		s, err := storage.NewNATSStorage(config.NATSStorage{Nodes: []string{addr}, Bucket: "roundtrip"}, config.StorageEviction{}, log.NopLogger())
+		require.NoError(t, err)
+
+		key := s.BuildKey(storage.Request{
+			Checksum:   "120EA8A25E5D487BF68B5F7096440019",
+			Types:      []string{"png"},
+			Resolution: image.Rect(0, 0, 32, 32),
+		})
+		assert.Equal(t, "12/0E/A8A25E5D487BF68B5F7096440019/32x32.png", key)
+		assert.False(t, s.Stat(key))
+
+		_, err = s.Get(key)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+
+		require.NoError(t, s.Put(key, []byte("thumbnail")))
+		assert.True(t, s.Stat(key))
+
+		img, err := s.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("thumbnail"), img)
+	})
+
+	t.Run("evicts the oldest thumbnails when the size limit is exceeded", func(t *testing.T) {
+		s, err := storage.NewNATSStorage(config.NATSStorage{Nodes: []string{addr}, Bucket: "eviction"}, config.StorageEviction{}, log.NopLogger())
+		require.NoError(t, err)
+
+		for _, k := range []string{"a", "b", "c"} {
+			require.NoError(t, s.Put(k, make([]byte, 10)))
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		evicted, err := storage.Evict(t.Context(), s, storage.Limits{MaxSize: 25}, false)
+		require.NoError(t, err)
+		require.Len(t, evicted, 1)
+		assert.Equal(t, "a", evicted[0].Key)
+		assert.False(t, s.Stat("a"))
+		assert.True(t, s.Stat("b"))
+		assert.True(t, s.Stat("c"))
+	})
+
+	t.Run("purges all thumbnails", func(t *testing.T) {
+		s, err := storage.NewNATSStorage(config.NATSStorage{Nodes: []string{addr}, Bucket: "purge"}, config.StorageEviction{TTL: time.Hour}, log.NopLogger())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put("a", make([]byte, 10)))
+		require.NoError(t, s.Put("b", make([]byte, 10)))
+
+		count, err := storage.Purge(t.Context(), s)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		stats, err := storage.GetStats(t.Context(), s)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats.Count)
+	})
+
+	t.Run("fails with an invalid size limit", func(t *testing.T) {
+		_, err := storage.NewNATSStorage(config.NATSStorage{Nodes: []string{addr}, Bucket: "invalid"}, config.StorageEviction{MaxSize: "lots"}, log.NopLogger())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/errors"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+)
+
+// NewS3Storage creates a new instance of S3
+func NewS3Storage(cfg config.S3Storage, logger log.Logger) (*S3, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse s3 endpoint")
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: endpoint.Scheme == "https",
+		Region: cfg.Region,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: cfg.Insecure, //nolint:gosec
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create s3 client")
+	}
+
+	return &S3{
+		client: client,
+		bucket: cfg.Bucket,
+		prefix: strings.Trim(cfg.Prefix, "/"),
+		logger: logger,
+	}, nil
+}
+
+// S3 represents a storage for the thumbnails using a S3 compatible object storage,
+// it allows multiple thumbnails instances to share one cache.
+type S3 struct {
+	client *minio.Client
+	bucket string
+	prefix string
+	logger log.Logger
+}
+
+// Stat returns if an object for the given key exists in the bucket
+func (s *S3) Stat(key string) bool {
+	if _, err := s.client.StatObject(context.Background(), s.bucket, s.objectName(key), minio.StatObjectOptions{}); err != nil {
+		return false
+	}
+	return true
+}
+
+// Get returns the object content for the given key
+func (s *S3) Get(key string) ([]byte, error) {
+	obj, err := s.client.GetObject(context.Background(), s.bucket, s.objectName(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	content, err := io.ReadAll(obj)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, fs.ErrNotExist
+		}
+		s.logger.Debug().Str("err", err.Error()).Str("key", key).Msg("could not load thumbnail from store")
+		return nil, err
+	}
+	return content, nil
+}
+
+// Put stores image data in the bucket for the given key
+func (s *S3) Put(key string, img []byte) error {
+	_, err := s.client.PutObject(context.Background(), s.bucket, s.objectName(key), bytes.NewReader(img), int64(len(img)), minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not store thumbnail \"%s\"", key)
+	}
+	return nil
+}
+
+// BuildKey generate the unique key for a thumbnail.
+// The key is structured the same way as for the FileSystem storage.
+func (s *S3) BuildKey(r Request) string {
+	return buildKey(r)
+}
+
+// List returns all thumbnails in the bucket, the last access is the time the object was stored.
+func (s *S3) List(ctx context.Context) ([]Entry, error) {
+	var entries []Entry
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.listPrefix(), Recursive: true}) {
+		if obj.Err != nil {
+			return nil, errors.Wrap(obj.Err, "could not list thumbnails")
+		}
+		entries = append(entries, Entry{
+			Key:        strings.TrimPrefix(obj.Key, s.listPrefix()),
+			Size:       uint64(obj.Size),
+			LastAccess: obj.LastModified,
+		})
+	}
+	return entries, nil
+}
+
+// Delete removes the thumbnails for the given keys from the bucket
+func (s *S3) Delete(ctx context.Context, keys ...string) error {
+	for _, k := range keys {
+		if err := s.client.RemoveObject(ctx, s.bucket, s.objectName(k), minio.RemoveObjectOptions{}); err != nil {
+			return errors.Wrapf(err, "could not remove thumbnail \"%s\"", k)
+		}
+	}
+	return nil
+}
+
+func (s *S3) objectName(key string) string {
+	return path.Join(s.prefix, key)
+}
+
+func (s *S3) listPrefix() string {
+	if s.prefix == "" {
+		return ""
+	}
+	return s.prefix + "/"
+}
@@ -0,0 +1,221 @@
+package storage_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"image"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/thumbnail/storage"
+)
+
+type s3Object struct {
+	data    []byte
+	modTime time.Time
+}
+
+// fakeS3 implements the parts of the S3 API used by the storage, with path style bucket names.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]s3Object
+	now     time.Time
+}
+
+func newS3Server(t testing.TB) (*fakeS3, string) {
+	f := &fakeS3{objects: map[string]s3Object{}, now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv.URL
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	obj, ok := f.objects[name]
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		f.list(w, strings.TrimSuffix(name, "/"), r.URL.Query().Get("prefix"))
+	case r.Method == http.MethodPut:
+		data, err := readS3Body(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.now = f.now.Add(time.Minute)
+		f.objects[name] = s3Object{data: data, modTime: f.now}
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	case !ok:
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusNotFound)
+		if r.Method == http.MethodGet {
+			_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+		}
+	default:
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", obj.modTime.Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(obj.data)
+		}
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, bucket, prefix string) {
+	type content struct {
+		Key          string
+		LastModified time.Time
+		Size         int
+		ETag         string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		IsTruncated bool
+		Contents    []content
+	}{Name: bucket, Prefix: prefix}
+
+	for name, obj := range f.objects {
+		key, ok := strings.CutPrefix(name, bucket+"/")
+		if ok && strings.HasPrefix(key, prefix) {
+			result.Contents = append(result.Contents, content{Key: key, LastModified: obj.modTime, Size: len(obj.data), ETag: `"etag"`})
+		}
+	}
+	slices.SortFunc(result.Contents, func(a, b content) int { return strings.Compare(a.Key, b.Key) })
+	result.KeyCount = len(result.Contents)
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+// readS3Body returns the uploaded data, decoding the aws-chunked encoding of signed streaming uploads.
+func readS3Body(r *http.Request) ([]byte, error) {
+	if r.Header.Get("X-Amz-Decoded-Content-Length") == "" {
+		return io.ReadAll(r.Body)
+	}
+
+	var data bytes.Buffer
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		n, err := strconv.ParseInt(size, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return data.Bytes(), nil
+		}
+		if _, err := io.CopyN(&data, br, n); err != nil {
+			return nil, err
+		}
+		if _, err := br.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func TestS3(t *testing.T) {
+	server, endpoint := newS3Server(t)
+	newStorage := func(prefix string) *storage.S3 {
+		s, err := storage.NewS3Storage(config.S3Storage{
+			Endpoint:  endpoint,
+			Region:    "us-east-1",
+			AccessKey: "access",
+			SecretKey: "secret",
+			Bucket:    "thumbnails",
+			Prefix:    prefix,
+		}, log.NopLogger())
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run("stores and loads thumbnails", func(t *testing.T) {
+		s := newStorage("/cache/")
+
+		key := s.BuildKey(storage.Request{
+			Checksum:   "120EA8A25E5D487BF68B5F7096440019",
+			Types:      []string{"png"},
+			Resolution: image.Rect(0, 0, 32, 32),
+		})
+		assert.Equal(t, "12/0E/A8A25E5D487BF68B5F7096440019/32x32.png", key)
+		assert.False(t, s.Stat(key))
+
+		_, err := s.Get(key)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+
+		require.NoError(t, s.Put(key, []byte("thumbnail")))
+		assert.True(t, s.Stat(key))
+
+		img, err := s.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("thumbnail"), img)
+
+		server.mu.Lock()
+		_, ok := server.objects["thumbnails/cache/"+key]
+		server.mu.Unlock()
+		assert.True(t, ok, "the object name has to contain the prefix")
+	})
+
+	t.Run("evicts the oldest thumbnails when the size limit is exceeded", func(t *testing.T) {
+		s := newStorage("eviction")
+
+		for _, k := range []string{"a", "b", "c"} {
+			require.NoError(t, s.Put(k, make([]byte, 10)))
+		}
+
+		evicted, err := storage.Evict(t.Context(), s, storage.Limits{MaxSize: 25}, false)
+		require.NoError(t, err)
+		require.Len(t, evicted, 1)
+		assert.Equal(t, "a", evicted[0].Key)
+		assert.False(t, s.Stat("a"))
+		assert.True(t, s.Stat("b"))
+		assert.True(t, s.Stat("c"))
+	})
+
+	t.Run("purges only the thumbnails with its prefix", func(t *testing.T) {
+		s := newStorage("purge")
+		require.NoError(t, s.Put("a", make([]byte, 10)))
+		require.NoError(t, s.Put("b", make([]byte, 10)))
+		other := newStorage("other")
+		require.NoError(t, other.Put("a", make([]byte, 10)))
+
+		count, err := storage.Purge(t.Context(), s)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		stats, err := storage.GetStats(t.Context(), s)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats.Count)
+		assert.True(t, other.Stat("a"))
+	})
+
+	t.Run("fails with an invalid endpoint", func(t *testing.T) {
+		_, err := storage.NewS3Storage(config.S3Storage{Endpoint: "http://[::1"}, log.NopLogger())
+		assert.Error(t, err)
+	})
+}
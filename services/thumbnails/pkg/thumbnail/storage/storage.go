@@ -1,7 +1,16 @@
 package storage
 
 import (
+	"context"
+	"fmt"
 	"image"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opencloud-eu/opencloud/pkg/log"
+	"github.com/opencloud-eu/opencloud/services/thumbnails/pkg/config"
 )
 
 // Request combines different attributes needed for storage operations.
@@ -30,3 +39,56 @@ type Storage interface {
 	Put(key string, img []byte) error
 	BuildKey(r Request) string
 }
+
+// Evictor removes cached thumbnails to stay within the configured size and age limits.
+type Evictor interface {
+	Evict(ctx context.Context) error
+}
+
+// New creates the storage which is selected by the given configuration.
+func New(cfg config.Thumbnail, logger log.Logger) (Storage, error) {
+	switch cfg.Storage {
+	case "", "filesystem":
+		return NewFileSystemStorage(cfg.FileSystemStorage, logger), nil
+	case "s3":
+		return NewS3Storage(cfg.S3Storage, logger)
+	case "nats-js-os":
+		return NewNATSStorage(cfg.NATSStorage, cfg.StorageEviction, logger)
+	default:
+		return nil, fmt.Errorf("unknown thumbnail storage: %s", cfg.Storage)
+	}
+}
+
+// RunEviction enforces the storage limits in the given interval until the context is done.
+func RunEviction(ctx context.Context, e Evictor, interval time.Duration, logger log.Logger) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := e.Evict(ctx); err != nil {
+				logger.Error().Err(err).Msg("could not evict thumbnails from storage")
+			}
+		}
+	}
+}
+
+// buildKey generates the unique, slash separated key for a thumbnail.
+// See FileSystem.BuildKey for the structure of the key.
+func buildKey(r Request) string {
+	checksum := r.Checksum
+	filetype := r.Types[0]
+
+	parts := []string{strconv.Itoa(r.Resolution.Dx()), "x", strconv.Itoa(r.Resolution.Dy())}
+
+	if r.Characteristic != "" {
+		parts = append(parts, "-", r.Characteristic)
+	}
+
+	parts = append(parts, ".", filetype)
+
+	return path.Join(checksum[:2], checksum[2:4], checksum[4:], strings.Join(parts, ""))
+}
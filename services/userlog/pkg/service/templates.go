@@ -54,6 +54,11 @@ var (
 		Message: l10n.Template("Access to {resource} expired"),
 	}
 
+	SavedSearchMatched = NotificationTemplate{
+		Subject: l10n.Template("New search result"),
+		Message: l10n.Template("{resource} matches your saved search {search}"),
+	}
+
 	PlatformDeprovision = NotificationTemplate{
 		Subject: l10n.Template("Instance will be shut down and deprovisioned"),
 		Message: l10n.Template("Attention! The instance will be shut down and deprovisioned on {date}. Download all your data before that date as no access past that date is possible."),
@@ -67,6 +72,7 @@ var _placeholders = map[string]string{
 	"{resource}": "{{ .resourcename }}",
 	"{virus}":    "{{ .virusdescription }}",
 	"{date}":     "{{ .date }}",
+	"{search}":   "{{ .searchname }}",
 }
 
 // NotificationTemplate is the data structure for the notifications
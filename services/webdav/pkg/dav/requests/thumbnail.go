@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	providerv1beta1 "github.com/cs3org/go-cs3apis/cs3/storage/provider/v1beta1"
 	"github.com/go-chi/chi/v5"
@@ -13,6 +14,7 @@ import (
 	"github.com/opencloud-eu/reva/v2/pkg/utils"
 
 	"github.com/opencloud-eu/opencloud/services/webdav/pkg/constants"
+	"github.com/opencloud-eu/opencloud/services/webdav/pkg/net"
 )
 
 const (
@@ -40,8 +42,15 @@ type ThumbnailRequest struct {
 	Processor string
 	// The Identifier from the requested URL
 	Identifier string
+	// The optional image format accepted by the client, like webp. It is
+	// negotiated from the Accept header and empty if the client accepts none.
+	Format string
 }
 
+// formats are the optional image formats of the thumbnails in the order of
+// preference if the client accepts several with the same quality.
+var formats = []string{"webp", "avif"}
+
 func addMissingStorageID(id string) string {
 	rid := &providerv1beta1.ResourceId{}
 	rid.StorageId, rid.SpaceId, rid.OpaqueId, _ = storagespace.SplitID(id)
@@ -78,9 +87,44 @@ func ParseThumbnailRequest(r *http.Request) (*ThumbnailRequest, error) {
 		Processor:       q.Get("processor"),
 		PublicLinkToken: chi.URLParam(r, "token"),
 		Identifier:      id,
+		Format:          negotiateFormat(r.Header.Values(net.HeaderAccept)),
 	}, nil
 }
 
+// negotiateFormat returns the optional image format with the highest quality
+// in the Accept header. Wildcards are ignored, because clients accepting image/*
+// don't necessarily support the optional formats.
+func negotiateFormat(accept []string) string {
+	qualities := make(map[string]float64, len(formats))
+	for _, h := range accept {
+		for _, entry := range strings.Split(h, ",") {
+			params := strings.Split(entry, ";")
+			format, ok := strings.CutPrefix(strings.ToLower(strings.TrimSpace(params[0])), "image/")
+			if !ok {
+				continue
+			}
+
+			q := 1.0
+			for _, p := range params[1:] {
+				if v, ok := strings.CutPrefix(strings.TrimSpace(p), "q="); ok {
+					if f, err := strconv.ParseFloat(v, 64); err == nil {
+						q = f
+					}
+				}
+			}
+			qualities[format] = q
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, f := range formats {
+		if q := qualities[f]; q > bestQ {
+			best, bestQ = f, q
+		}
+	}
+	return best
+}
+
 func parseDimensions(q url.Values) (int64, int64, error) {
 	width, err := parseDimension(q.Get("x"), "width", DefaultWidth)
 	if err != nil {
@@ -2,6 +2,7 @@ package net
 
 // Common HTTP headers.
 const (
+	HeaderAccept                     = "Accept"
 	HeaderAcceptRanges               = "Accept-Ranges"
 	HeaderAccessControlAllowHeaders  = "Access-Control-Allow-Headers"
 	HeaderAccessControlExposeHeaders = "Access-Control-Expose-Headers"
@@ -14,6 +15,7 @@ const (
 	HeaderLocation                   = "Location"
 	HeaderRange                      = "Range"
 	HeaderIfMatch                    = "If-Match"
+	HeaderVary                       = "Vary"
 )
 
 // webdav headers
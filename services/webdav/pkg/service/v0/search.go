@@ -56,6 +56,7 @@ func (g Webdav) Search(w http.ResponseWriter, r *http.Request) {
 	req := &searchsvc.SearchRequest{
 		Query:    rep.SearchFiles.Search.Pattern,
 		PageSize: int32(rep.SearchFiles.Search.Limit),
+		Facets:   rep.SearchFiles.Facets,
 	}
 
 	// Limit search to the according space when searching /dav/spaces/
@@ -93,7 +94,7 @@ func (g Webdav) Search(w http.ResponseWriter, r *http.Request) {
 
 func (g Webdav) sendSearchResponse(rsp *searchsvc.SearchResponse, w http.ResponseWriter, r *http.Request) {
 	logger := g.log.SubloggerWithRequestID(r.Context())
-	responsesXML, err := multistatusResponse(r.Context(), g.config.OpenCloudPublicURL, rsp.Matches)
+	responsesXML, err := multistatusResponse(r.Context(), g.config.OpenCloudPublicURL, rsp.Matches, rsp.Facets)
 	if err != nil {
 		logger.Error().Err(err).Msg("error formatting propfind")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -110,8 +111,8 @@ func (g Webdav) sendSearchResponse(rsp *searchsvc.SearchResponse, w http.Respons
 	}
 }
 
-// multistatusResponse converts a list of matches into a multistatus response string
-func multistatusResponse(ctx context.Context, publicURL string, matches []*searchmsg.Match) ([]byte, error) {
+// multistatusResponse converts a list of matches and the facets into a multistatus response string
+func multistatusResponse(ctx context.Context, publicURL string, matches []*searchmsg.Match, facets []*searchmsg.Facet) ([]byte, error) {
 	responses := make([]*propfind.ResponseXML, 0, len(matches))
 	for i := range matches {
 		res, err := matchToPropResponse(ctx, publicURL, matches[i])
@@ -121,8 +122,24 @@ func multistatusResponse(ctx context.Context, publicURL string, matches []*searc
 		responses = append(responses, res)
 	}
 
-	msr := propfind.NewMultiStatusResponseXML()
+	msr := searchMultiStatusResponseXML{MultiStatusResponseXML: propfind.NewMultiStatusResponseXML()}
 	msr.Responses = responses
+	if len(facets) > 0 {
+		msr.Facets = &facetsXML{}
+	}
+	for _, f := range facets {
+		fx := facetXML{Name: f.GetName(), Missing: f.GetMissing()}
+		for _, b := range f.GetBuckets() {
+			fx.Buckets = append(fx.Buckets, facetBucketXML{
+				Name:  b.GetName(),
+				Count: b.GetCount(),
+				Label: b.GetLabel(),
+				From:  b.GetFrom(),
+				To:    b.GetTo(),
+			})
+		}
+		msr.Facets.Facets = append(msr.Facets.Facets, fx)
+	}
 	msg, err := xml.Marshal(msr)
 	if err != nil {
 		return nil, err
@@ -244,6 +261,7 @@ type reportSearchFiles struct {
 	Lang    string                  `xml:"xml:lang,attr,omitempty"`
 	Prop    Props                   `xml:"DAV: prop"`
 	Search  reportSearchFilesSearch `xml:"search"`
+	Facets  []string                `xml:"facets>facet"`
 }
 type reportSearchFilesSearch struct {
 	Pattern string `xml:"pattern"`
@@ -251,6 +269,31 @@ type reportSearchFilesSearch struct {
 	Offset  int    `xml:"offset"`
 }
 
+// searchMultiStatusResponseXML is a multistatus response with the facets of the search
+type searchMultiStatusResponseXML struct {
+	*propfind.MultiStatusResponseXML
+
+	Facets *facetsXML `xml:"oc:facets,omitempty"`
+}
+
+type facetsXML struct {
+	Facets []facetXML `xml:"oc:facet"`
+}
+
+type facetXML struct {
+	Name    string           `xml:"name,attr"`
+	Missing int64            `xml:"missing,attr"`
+	Buckets []facetBucketXML `xml:"oc:bucket"`
+}
+
+type facetBucketXML struct {
+	Name  string `xml:"name,attr"`
+	Count int64  `xml:"count,attr"`
+	Label string `xml:"label,attr,omitempty"`
+	From  string `xml:"from,attr,omitempty"`
+	To    string `xml:"to,attr,omitempty"`
+}
+
 type reportFilterFiles struct {
 	XMLName xml.Name               `xml:"filter-files"`
 	Lang    string                 `xml:"xml:lang,attr,omitempty"`
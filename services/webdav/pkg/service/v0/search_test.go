@@ -0,0 +1,47 @@
+package svc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	searchmsg "github.com/opencloud-eu/opencloud/protogen/gen/opencloud/messages/search/v0"
+)
+
+func TestMultistatusResponseFacets(t *testing.T) {
+	t.Run("renders the facets", func(t *testing.T) {
+		facets := []*searchmsg.Facet{
+			{
+				Name:    "mediatype",
+				Missing: 2,
+				Buckets: []*searchmsg.FacetBucket{
+					{Name: "image/png", Count: 3},
+				},
+			},
+			{
+				Name: "mtime",
+				Buckets: []*searchmsg.FacetBucket{
+					{Name: "today", Count: 1, Label: "Today", From: "2024-01-01T00:00:00Z", To: "2024-01-02T00:00:00Z"},
+				},
+			},
+		}
+
+		msg, err := multistatusResponse(context.Background(), "https://localhost:9200", nil, facets)
+		require.NoError(t, err)
+
+		body := string(msg)
+		assert.Contains(t, body, `<d:multistatus xmlns:s="http://sabredav.org/ns" xmlns:d="DAV:" xmlns:oc="http://owncloud.org/ns">`)
+		assert.Contains(t, body, `<oc:facets>`+
+			`<oc:facet name="mediatype" missing="2"><oc:bucket name="image/png" count="3"></oc:bucket></oc:facet>`+
+			`<oc:facet name="mtime" missing="0"><oc:bucket name="today" count="1" label="Today" from="2024-01-01T00:00:00Z" to="2024-01-02T00:00:00Z"></oc:bucket></oc:facet>`+
+			`</oc:facets>`)
+	})
+
+	t.Run("omits the facets if none were requested", func(t *testing.T) {
+		msg, err := multistatusResponse(context.Background(), "https://localhost:9200", nil, nil)
+		require.NoError(t, err)
+		assert.NotContains(t, string(msg), "oc:facets")
+	})
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/opencloud-eu/opencloud/services/webdav/pkg/config"
 	"github.com/opencloud-eu/opencloud/services/webdav/pkg/constants"
 	"github.com/opencloud-eu/opencloud/services/webdav/pkg/dav/requests"
+	"github.com/opencloud-eu/opencloud/services/webdav/pkg/net"
 )
 
 var (
@@ -238,7 +239,7 @@ func (g Webdav) SpacesThumbnail(w http.ResponseWriter, r *http.Request) {
 	fullPath := filepath.Join(tr.Identifier, tr.Filepath)
 	rsp, err := g.thumbnailsClient.GetThumbnail(r.Context(), &thumbnailssvc.GetThumbnailRequest{
 		Filepath:      strings.TrimLeft(tr.Filepath, "/"),
-		ThumbnailType: extensionToThumbnailType(strings.TrimLeft(tr.Extension, ".")),
+		ThumbnailType: thumbnailType(tr),
 		Width:         tr.Width,
 		Height:        tr.Height,
 		Processor:     tr.Processor,
@@ -336,7 +337,7 @@ func (g Webdav) Thumbnail(w http.ResponseWriter, r *http.Request) {
 	fullPath := filepath.Join(templates.WithUser(user, g.config.WebdavNamespace), tr.Filepath)
 	rsp, err := g.thumbnailsClient.GetThumbnail(r.Context(), &thumbnailssvc.GetThumbnailRequest{
 		Filepath:      strings.TrimLeft(tr.Filepath, "/"),
-		ThumbnailType: extensionToThumbnailType(strings.TrimLeft(tr.Extension, ".")),
+		ThumbnailType: thumbnailType(tr),
 		Width:         tr.Width,
 		Height:        tr.Height,
 		Processor:     tr.Processor,
@@ -386,7 +387,7 @@ func (g Webdav) PublicThumbnail(w http.ResponseWriter, r *http.Request) {
 
 	rsp, err := g.thumbnailsClient.GetThumbnail(r.Context(), &thumbnailssvc.GetThumbnailRequest{
 		Filepath:      strings.TrimLeft(tr.Filepath, "/"),
-		ThumbnailType: extensionToThumbnailType(strings.TrimLeft(tr.Extension, ".")),
+		ThumbnailType: thumbnailType(tr),
 		Width:         tr.Width,
 		Height:        tr.Height,
 		Processor:     tr.Processor,
@@ -431,7 +432,7 @@ func (g Webdav) PublicThumbnailHead(w http.ResponseWriter, r *http.Request) {
 
 	_, err = g.thumbnailsClient.GetThumbnail(r.Context(), &thumbnailssvc.GetThumbnailRequest{
 		Filepath:      strings.TrimLeft(tr.Filepath, "/"),
-		ThumbnailType: extensionToThumbnailType(strings.TrimLeft(tr.Extension, ".")),
+		ThumbnailType: thumbnailType(tr),
 		Width:         tr.Width,
 		Height:        tr.Height,
 		Processor:     tr.Processor,
@@ -497,14 +498,29 @@ func (g Webdav) sendThumbnailResponse(rsp *thumbnailssvc.GetThumbnailResponse, w
 		return
 	}
 
+	w.Header().Set(net.HeaderContentType, dlRsp.Header.Get(net.HeaderContentType))
+	// the format of the thumbnail depends on the Accept header
+	w.Header().Add(net.HeaderVary, net.HeaderAccept)
 	w.WriteHeader(http.StatusOK)
-	w.Header().Set("Content-Type", dlRsp.Header.Get("Content-Type"))
 	_, err = io.Copy(w, dlRsp.Body)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to write thumbnail to response writer")
 	}
 }
 
+// thumbnailType returns the format negotiated with the client or the format
+// matching the extension of the file
+func thumbnailType(tr *requests.ThumbnailRequest) thumbnailsmsg.ThumbnailType {
+	switch tr.Format {
+	case "webp":
+		return thumbnailsmsg.ThumbnailType_WEBP
+	case "avif":
+		return thumbnailsmsg.ThumbnailType_AVIF
+	default:
+		return extensionToThumbnailType(strings.TrimLeft(tr.Extension, "."))
+	}
+}
+
 func extensionToThumbnailType(ext string) thumbnailsmsg.ThumbnailType {
 	switch strings.ToUpper(ext) {
 	case "GIF":